@@ -1,8 +1,10 @@
 package main
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -38,6 +40,25 @@ func StartAPI(host string) {
 	}
 }
 
+// apiRequireKey refuses requests that don't send the configured API key as a bearer token
+func apiRequireKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := getBotData().BotOptions.API.Key
+		if key == "" {
+			render.Status(r, http.StatusForbidden)
+			render.JSON(w, r, errAPI("this endpoint requires config:{botOptions:{api:{key}}} to be set"))
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			render.Status(r, http.StatusUnauthorized)
+			render.JSON(w, r, errAPI("invalid API key"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func APIRouter() *chi.Mux {
 	router := chi.NewRouter()
 	router.Use(
@@ -50,6 +71,7 @@ func APIRouter() *chi.Mux {
 	router.Route("/api", func(r chi.Router) {
 		r.Mount("/v0", APIv0())
 	})
+	router.Post("/interactions", interactionsEndpoint)
 
 	return router
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/bwmarrin/discordgo"
@@ -25,6 +27,13 @@ func APIv0() *chi.Mux {
 	//Guild starboard endpoint
 	router.Get("/guild/{guildID}/starboard", v0GetGuildStarboard) //Retrieves all starboard settings and entries
 
+	//Guild bundle endpoint, which requires the API key
+	router.Group(func(r chi.Router) {
+		r.Use(apiRequireKey)
+		r.Get("/guild/{guildID}/export", v0GetGuildExport)   //Retrieves a bundle of a particular guild's settings
+		r.Post("/guild/{guildID}/import", v0PostGuildImport) //Applies a bundle to a particular guild, remapping IDs with ?map=old:new
+	})
+
 	//Guild invite link generation endpoint
 	router.Get("/guild/{guildID}/invite/{key}", v0GetGuildInvite) //Retrieves a new one-user invite link for the specified guild
 
@@ -59,7 +68,7 @@ func v0GetGuild(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	guild, err := botData.DiscordSession.Guild(guildID)
+	guild, err := getBotData().DiscordSession.Guild(guildID)
 	if err != nil {
 		render.JSON(w, r, errAPI("guildID invalid"))
 		return
@@ -75,12 +84,13 @@ func v0GetGuildSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, ok := guildSettings[guildID]; !ok {
+	settings, ok := guildSettings.Snapshot(guildID)
+	if !ok {
 		render.JSON(w, r, errAPI("specified guildID has no settings"))
 		return
 	}
 
-	render.JSON(w, r, guildSettings[guildID])
+	render.JSON(w, r, settings)
 }
 
 func v0PutGuildSetting(w http.ResponseWriter, r *http.Request) {
@@ -94,12 +104,72 @@ func v0GetGuildStarboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, ok := starboards[guildID]; !ok {
+	starboard, ok := starboards.Snapshot(guildID)
+	if !ok {
 		render.JSON(w, r, errAPI("specified guildID has no starboard data"))
 		return
 	}
 
-	render.JSON(w, r, starboards[guildID])
+	render.JSON(w, r, starboard)
+}
+
+func v0GetGuildExport(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	if guildID == "" {
+		render.JSON(w, r, errAPI("guildID must not be empty"))
+		return
+	}
+
+	bundle, err := exportGuildBundle(guildID)
+	if err != nil {
+		render.JSON(w, r, errAPI("guildID invalid", err))
+		return
+	}
+
+	render.JSON(w, r, bundle)
+}
+
+func v0PostGuildImport(w http.ResponseWriter, r *http.Request) {
+	guildID := chi.URLParam(r, "guildID")
+	if guildID == "" {
+		render.JSON(w, r, errAPI("guildID must not be empty"))
+		return
+	}
+
+	idMap, err := parseBundleIDMap(r.URL.Query()["map"])
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI("invalid map", err))
+		return
+	}
+
+	bundleJSON, err := ioutil.ReadAll(io.LimitReader(r.Body, GuildBundleMaxSize+1))
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI("error reading bundle", err))
+		return
+	}
+	bundle, err := parseGuildBundle(bundleJSON)
+	if err != nil {
+		render.Status(r, http.StatusBadRequest)
+		render.JSON(w, r, errAPI("invalid bundle", err))
+		return
+	}
+
+	unlock, err := lockGuildSettings(r.Context(), guildID)
+	if err != nil {
+		render.Status(r, http.StatusServiceUnavailable)
+		render.JSON(w, r, errAPI("guild settings are busy", err))
+		return
+	}
+	result, err := importGuildBundle(guildID, bundle, idMap)
+	unlock()
+	if err != nil {
+		render.JSON(w, r, errAPI("guildID invalid", err))
+		return
+	}
+
+	render.JSON(w, r, result)
 }
 
 func v0GetGuildInvite(w http.ResponseWriter, r *http.Request) {
@@ -115,12 +185,13 @@ func v0GetGuildInvite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, ok := guildSettings[guildID]; !ok {
+	settings, ok := guildSettings.Snapshot(guildID)
+	if !ok {
 		render.JSON(w, r, errAPI("specified guildID has no settings"))
 		return
 	}
 
-	if key != guildSettings[guildID].APIInviteKey {
+	if key != settings.APIInviteKey {
 		render.JSON(w, r, errAPI("specified key is invalid"))
 		return
 	}
@@ -130,7 +201,7 @@ func v0GetGuildInvite(w http.ResponseWriter, r *http.Request) {
 		MaxUses: 1,    //Only one use
 	}
 
-	invite, err := botData.DiscordSession.ChannelInviteCreate(guildSettings[guildID].APIInviteChannel, inviteSettings)
+	invite, err := getBotData().DiscordSession.ChannelInviteCreate(settings.APIInviteChannel, inviteSettings)
 	if err != nil {
 		render.JSON(w, r, errAPI("error generating invite", err))
 		return
@@ -146,7 +217,7 @@ func v0GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := getBotData().DiscordSession.User(userID)
 	if err != nil {
 		render.JSON(w, r, errAPI("userID invalid"))
 		return
@@ -162,12 +233,13 @@ func v0GetUserSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, ok := userSettings[userID]; !ok {
+	settings, ok := userSettings.Snapshot(userID)
+	if !ok {
 		render.JSON(w, r, errAPI("specified userID has no settings"))
 		return
 	}
 
-	render.JSON(w, r, userSettings[userID])
+	render.JSON(w, r, settings)
 }
 
 func v0PutUserSetting(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Argument types understood by the typed argument parser
+const (
+	ArgTypeString     = "string"     //Any value
+	ArgTypeNumber     = "number"     //A whole number, optionally within Min and Max
+	ArgTypeDecimal    = "decimal"    //A decimal number, optionally within Min and Max
+	ArgTypePercentage = "percentage" //A number with an optional trailing %, optionally within Min and Max
+	ArgTypeBoolean    = "boolean"    //true/false, yes/no, enable/disable, on/off
+	ArgTypeUser       = "user"       //A user mention or user ID
+	ArgTypeRole       = "role"       //A role mention, role ID or role name within the current guild
+	ArgTypeChannel    = "channel"    //A channel mention, channel ID or channel name within the current guild
+	ArgTypeDuration   = "duration"   //A duration such as 30s, 5m, 1h30m, 2d or 1w, optionally within Min and Max seconds
+	ArgTypeURL        = "url"        //A URL with a host, defaulting to http if no scheme is given
+	ArgTypeChoice     = "choice"     //One of the values in Choices
+)
+
+var (
+	regexpArgUser    = regexp.MustCompile(`^<@!?([0-9]+)>$`)
+	regexpArgRole    = regexp.MustCompile(`^<@&([0-9]+)>$`)
+	regexpArgChannel = regexp.MustCompile(`^<#([0-9]+)>$`)
+	regexpArgID      = regexp.MustCompile(`^[0-9]{15,21}$`)
+	regexpArgDays    = regexp.MustCompile(`([0-9]+)([dw])`)
+)
+
+// ParsedArguments holds the typed values of a command's arguments after parsing, where key = argument name
+type ParsedArguments struct {
+	values map[string]*ParsedArgument
+}
+
+// ParsedArgument holds the raw and typed values supplied for a single argument
+type ParsedArgument struct {
+	Argument *CommandArgument //The argument declaration the values were parsed with
+	Raw      []string         //The raw values supplied with the argument
+	Values   []interface{}    //The typed values supplied with the argument, in the same order as Raw
+}
+
+// ArgumentError describes why a value could not be parsed for an argument
+type ArgumentError struct {
+	Argument *CommandArgument //The argument that failed to parse, or nil if there was no argument left to parse into
+	Value    string           //The offending value, if any
+	Reason   string           //Why the value was rejected
+}
+
+func (err *ArgumentError) Error() string {
+	if err.Argument == nil {
+		return "unexpected value ``" + err.Value + "``"
+	}
+	if err.Value == "" {
+		return "**" + err.Argument.Name + "**: " + err.Reason
+	}
+	return "**" + err.Argument.Name + "**: ``" + err.Value + "`` " + err.Reason
+}
+
+// argumentKind normalizes the free-form ArgType of an argument into one of the known argument types
+func argumentKind(argType string) string {
+	switch strings.ToLower(argType) {
+	case ArgTypeNumber, "number(s)", "id", "seconds":
+		return ArgTypeNumber
+	case ArgTypeDecimal:
+		return ArgTypeDecimal
+	case ArgTypePercentage:
+		return ArgTypePercentage
+	case ArgTypeBoolean, "enable/disable":
+		return ArgTypeBoolean
+	case ArgTypeUser, "mention", "user id", "mention/id", "mention/user id":
+		return ArgTypeUser
+	case ArgTypeRole:
+		return ArgTypeRole
+	case ArgTypeChannel:
+		return ArgTypeChannel
+	case ArgTypeDuration:
+		return ArgTypeDuration
+	case ArgTypeURL:
+		return ArgTypeURL
+	case ArgTypeChoice:
+		return ArgTypeChoice
+	}
+	return ArgTypeString
+}
+
+// parseArguments resolves the supplied values against the command's declared arguments in order
+//
+// Each argument takes one value, or every following value that parses successfully if Multiple is set.
+// An optional argument is skipped if the next value does not parse as its type, allowing optional
+// leading arguments such as the days in "ban (days) user1 (user2)".
+func parseArguments(command *Command, args []string, env *CommandEnvironment) (*ParsedArguments, error) {
+	return parseArgumentList(command.Arguments, args, env)
+}
+
+// parseSubcommandArguments parses the values given to a subcommand against the arguments it declares, for commands
+// whose Arguments list their subcommands rather than the values they take
+//
+// If the values don't parse, an embed describing why and how to use the command is returned instead.
+func parseSubcommandArguments(arguments []CommandArgument, args []string, env *CommandEnvironment) (*ParsedArguments, *discordgo.MessageEmbed) {
+	parsed, err := parseArgumentList(arguments, args, env)
+	if err != nil {
+		return nil, getCommandUsageError(env.Command, "Command Error - Invalid Argument (IA)", err, env)
+	}
+	return parsed, nil
+}
+
+// parseArgumentList resolves the supplied values against a list of declared arguments in order, see parseArguments
+func parseArgumentList(arguments []CommandArgument, args []string, env *CommandEnvironment) (*ParsedArguments, error) {
+	parsed := &ParsedArguments{values: make(map[string]*ParsedArgument)}
+
+	i := 0
+	for j := range arguments {
+		argument := &arguments[j]
+		parsedArgument := &ParsedArgument{Argument: argument}
+
+		for i < len(args) {
+			value, err := parseArgumentValue(argument, args[i], env)
+			if err != nil {
+				if len(parsedArgument.Values) > 0 || argument.Optional {
+					break
+				}
+				return nil, err
+			}
+			parsedArgument.Raw = append(parsedArgument.Raw, args[i])
+			parsedArgument.Values = append(parsedArgument.Values, value)
+			i++
+
+			if !argument.Multiple {
+				break
+			}
+		}
+
+		if len(parsedArgument.Values) == 0 {
+			if !argument.Optional {
+				return nil, &ArgumentError{Argument: argument, Reason: "is required."}
+			}
+			continue
+		}
+		parsed.values[argument.Name] = parsedArgument
+	}
+
+	if i < len(args) {
+		return nil, &ArgumentError{Value: args[i]}
+	}
+
+	return parsed, nil
+}
+
+// parseArgumentValue parses a single value according to the argument's type
+func parseArgumentValue(argument *CommandArgument, value string, env *CommandEnvironment) (interface{}, error) {
+	kind := argumentKind(argument.ArgType)
+	if len(argument.Choices) > 0 {
+		kind = ArgTypeChoice
+	}
+
+	switch kind {
+	case ArgTypeNumber:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a valid number."}
+		}
+		if err := checkArgumentRange(argument, value, float64(number)); err != nil {
+			return nil, err
+		}
+		return number, nil
+	case ArgTypeDecimal, ArgTypePercentage:
+		number, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a valid " + kind + "."}
+		}
+		if err := checkArgumentRange(argument, value, number); err != nil {
+			return nil, err
+		}
+		return number, nil
+	case ArgTypeBoolean:
+		switch strings.ToLower(value) {
+		case "true", "yes", "y", "on", "enable", "enabled", "1":
+			return true, nil
+		case "false", "no", "n", "off", "disable", "disabled", "0":
+			return false, nil
+		}
+		return nil, &ArgumentError{Argument: argument, Value: value, Reason: "must be either ``true`` or ``false``."}
+	case ArgTypeUser:
+		user, err := resolveArgumentUser(value, env)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a valid user."}
+		}
+		return user, nil
+	case ArgTypeRole:
+		role, err := resolveArgumentRole(value, env)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a role in this server."}
+		}
+		return role, nil
+	case ArgTypeChannel:
+		channel, err := resolveArgumentChannel(value, env)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a channel in this server."}
+		}
+		return channel, nil
+	case ArgTypeDuration:
+		duration, err := parseDuration(value)
+		if err != nil {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a valid duration, try something like ``30s``, ``5m``, ``1h30m`` or ``2d``."}
+		}
+		if err := checkArgumentRange(argument, value, duration.Seconds()); err != nil {
+			return nil, err
+		}
+		return duration, nil
+	case ArgTypeURL:
+		parsedURL, err := url.Parse(value)
+		if err == nil && parsedURL.Scheme == "" {
+			parsedURL, err = url.Parse("http://" + value) //By standard, SSL-enabled sites should automatically redirect to https if needed
+		}
+		if err != nil || parsedURL.Host == "" {
+			return nil, &ArgumentError{Argument: argument, Value: value, Reason: "is not a valid URL."}
+		}
+		return parsedURL, nil
+	case ArgTypeChoice:
+		for _, choice := range argument.Choices {
+			if strings.EqualFold(choice, value) {
+				return choice, nil
+			}
+		}
+		return nil, &ArgumentError{Argument: argument, Value: value, Reason: "must be one of: ``" + strings.Join(argument.Choices, "``, ``") + "``."}
+	}
+
+	return value, nil
+}
+
+// checkArgumentRange checks if a number is within the argument's range, if one is set
+func checkArgumentRange(argument *CommandArgument, value string, number float64) error {
+	if argument.Min == 0 && argument.Max == 0 {
+		return nil
+	}
+	if number < argument.Min || (argument.Max != 0 && number > argument.Max) {
+		if argument.Max == 0 {
+			return &ArgumentError{Argument: argument, Value: value, Reason: "must be at least " + strconv.FormatFloat(argument.Min, 'f', -1, 64) + "."}
+		}
+		return &ArgumentError{Argument: argument, Value: value, Reason: "must be between " + strconv.FormatFloat(argument.Min, 'f', -1, 64) + " and " + strconv.FormatFloat(argument.Max, 'f', -1, 64) + "."}
+	}
+	return nil
+}
+
+// parseDuration parses a Go duration with additional support for days (d) and weeks (w)
+func parseDuration(value string) (time.Duration, error) {
+	extra := time.Duration(0)
+	for _, match := range regexpArgDays.FindAllStringSubmatch(value, -1) {
+		amount, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, err
+		}
+		switch match[2] {
+		case "d":
+			extra += time.Duration(amount) * 24 * time.Hour
+		case "w":
+			extra += time.Duration(amount) * 7 * 24 * time.Hour
+		}
+	}
+	value = regexpArgDays.ReplaceAllString(value, "")
+	if value == "" {
+		return extra, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return duration + extra, nil
+}
+
+func resolveArgumentUser(value string, env *CommandEnvironment) (*discordgo.User, error) {
+	userID := value
+	if matches := regexpArgUser.FindStringSubmatch(value); len(matches) > 1 {
+		userID = matches[1]
+	} else if !regexpArgID.MatchString(value) {
+		return nil, fmt.Errorf("invalid user %s", value)
+	}
+
+	//Mentions are already resolved by Discord, so check them before asking for the user
+	if env.Message != nil {
+		for _, mention := range env.Message.Mentions {
+			if mention.ID == userID {
+				return mention, nil
+			}
+		}
+	}
+	if env.Guild != nil {
+		if member, err := getBotData().DiscordSession.State.Member(env.Guild.ID, userID); err == nil {
+			return member.User, nil
+		}
+	}
+	return getBotData().DiscordSession.User(userID)
+}
+
+func resolveArgumentRole(value string, env *CommandEnvironment) (*discordgo.Role, error) {
+	if env.Guild == nil {
+		return nil, fmt.Errorf("no guild to find role %s in", value)
+	}
+
+	roleID := value
+	if matches := regexpArgRole.FindStringSubmatch(value); len(matches) > 1 {
+		roleID = matches[1]
+	}
+	for _, role := range env.Guild.Roles {
+		if role.ID == roleID {
+			return role, nil
+		}
+	}
+	for _, role := range env.Guild.Roles {
+		if strings.EqualFold(role.Name, value) {
+			return role, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown role %s", value)
+}
+
+func resolveArgumentChannel(value string, env *CommandEnvironment) (*discordgo.Channel, error) {
+	if env.Guild == nil {
+		return nil, fmt.Errorf("no guild to find channel %s in", value)
+	}
+
+	channelID := value
+	if matches := regexpArgChannel.FindStringSubmatch(value); len(matches) > 1 {
+		channelID = matches[1]
+	}
+	for _, channel := range env.Guild.Channels {
+		if channel.ID == channelID {
+			return channel, nil
+		}
+	}
+	for _, channel := range env.Guild.Channels {
+		if strings.EqualFold(channel.Name, strings.TrimPrefix(value, "#")) {
+			return channel, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown channel %s", value)
+}
+
+// Has returns whether or not a value was supplied for the specified argument
+func (parsed *ParsedArguments) Has(name string) bool {
+	if parsed == nil {
+		return false
+	}
+	_, exists := parsed.values[name]
+	return exists
+}
+
+// Get returns the parsed argument with the specified name, or nil if none was supplied
+func (parsed *ParsedArguments) Get(name string) *ParsedArgument {
+	if parsed == nil {
+		return nil
+	}
+	return parsed.values[name]
+}
+
+func (parsed *ParsedArguments) first(name string) interface{} {
+	if argument := parsed.Get(name); argument != nil && len(argument.Values) > 0 {
+		return argument.Values[0]
+	}
+	return nil
+}
+
+// String returns the raw values of the specified argument joined by spaces
+func (parsed *ParsedArguments) String(name string) string {
+	if argument := parsed.Get(name); argument != nil {
+		return strings.Join(argument.Raw, " ")
+	}
+	return ""
+}
+
+// Int returns the first value of the specified number argument
+func (parsed *ParsedArguments) Int(name string) int {
+	number, _ := parsed.first(name).(int)
+	return number
+}
+
+// Ints returns every value of the specified number argument
+func (parsed *ParsedArguments) Ints(name string) []int {
+	numbers := make([]int, 0)
+	if argument := parsed.Get(name); argument != nil {
+		for _, value := range argument.Values {
+			if number, ok := value.(int); ok {
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	return numbers
+}
+
+// Float returns the first value of the specified decimal or percentage argument
+func (parsed *ParsedArguments) Float(name string) float64 {
+	number, _ := parsed.first(name).(float64)
+	return number
+}
+
+// Bool returns the first value of the specified boolean argument
+func (parsed *ParsedArguments) Bool(name string) bool {
+	boolean, _ := parsed.first(name).(bool)
+	return boolean
+}
+
+// Duration returns the first value of the specified duration argument
+func (parsed *ParsedArguments) Duration(name string) time.Duration {
+	duration, _ := parsed.first(name).(time.Duration)
+	return duration
+}
+
+// URL returns the first value of the specified URL argument
+func (parsed *ParsedArguments) URL(name string) *url.URL {
+	parsedURL, _ := parsed.first(name).(*url.URL)
+	return parsedURL
+}
+
+// Choice returns the first value of the specified choice argument as declared in Choices
+func (parsed *ParsedArguments) Choice(name string) string {
+	choice, _ := parsed.first(name).(string)
+	return choice
+}
+
+// User returns the first value of the specified user argument
+func (parsed *ParsedArguments) User(name string) *discordgo.User {
+	user, _ := parsed.first(name).(*discordgo.User)
+	return user
+}
+
+// Users returns every value of the specified user argument
+func (parsed *ParsedArguments) Users(name string) []*discordgo.User {
+	users := make([]*discordgo.User, 0)
+	if argument := parsed.Get(name); argument != nil {
+		for _, value := range argument.Values {
+			if user, ok := value.(*discordgo.User); ok {
+				users = append(users, user)
+			}
+		}
+	}
+	return users
+}
+
+// Role returns the first value of the specified role argument
+func (parsed *ParsedArguments) Role(name string) *discordgo.Role {
+	role, _ := parsed.first(name).(*discordgo.Role)
+	return role
+}
+
+// Channel returns the first value of the specified channel argument
+func (parsed *ParsedArguments) Channel(name string) *discordgo.Channel {
+	channel, _ := parsed.first(name).(*discordgo.Channel)
+	return channel
+}
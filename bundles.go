@@ -0,0 +1,476 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// GuildBundleVersion contains the version of the guild bundle format written by this build
+	GuildBundleVersion = 1
+
+	// GuildBundleMaxSize contains the largest guild bundle in bytes that may be imported
+	GuildBundleMaxSize = 1024 * 1024
+)
+
+var (
+	errBundleVersion = errors.New("the bundle was exported by a newer version of the bot")
+	errBundleEmpty   = errors.New("the bundle has no settings to import")
+)
+
+// GuildBundle holds the settings of a guild that can be moved to another guild or bot instance
+//
+// Channels and roles are referred to by their ID in the guild the bundle was exported from, and their names are
+// kept alongside so they can be matched to the channels and roles of the guild the bundle is imported into.
+type GuildBundle struct {
+	Version   int               `json:"version"`   //The version of the bundle format, see GuildBundleVersion
+	GuildID   string            `json:"guildID"`   //The ID of the guild the bundle was exported from
+	GuildName string            `json:"guildName"` //The name of the guild the bundle was exported from
+	Exported  time.Time         `json:"exported"`  //When the bundle was exported
+	Channels  map[string]string `json:"channels"`  //The names of the channels the settings refer to, where key = channel ID
+	Roles     map[string]string `json:"roles"`     //The names of the roles the settings refer to, where key = role ID
+
+	CustomResponses []CustomResponseQuery `json:"customResponses,omitempty"`
+	RoleMeList      []*RoleMe             `json:"roleMeList,omitempty"`
+	Feeds           []*Feed               `json:"feeds,omitempty"`
+	Starboard       *Starboard            `json:"starboard,omitempty"` //Without its entries, as those are messages in the exported guild
+	LogSettings     *LogSettings          `json:"logSettings,omitempty"`
+	SwearFilter     *SwearFilter          `json:"swearFilter,omitempty"`
+}
+
+// GuildBundleResult holds what was imported from a guild bundle
+type GuildBundleResult struct {
+	Imported   []string //The names of the settings that were imported
+	Remapped   []string //Each channel and role that was matched to one in the guild, as "old -> new"
+	Unresolved []string //Each channel and role that couldn't be matched, whose references were removed
+	Warnings   []string //Anything else that didn't import cleanly, such as feeds that couldn't be fetched
+}
+
+// exportGuildBundle returns a bundle of a guild's settings
+func exportGuildBundle(guildID string) (*GuildBundle, error) {
+	channels, roles, err := getGuildChannelsRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &GuildBundle{
+		Version:  GuildBundleVersion,
+		GuildID:  guildID,
+		Exported: time.Now(),
+		Channels: make(map[string]string),
+		Roles:    make(map[string]string),
+	}
+	if guild, err := getBotData().DiscordSession.State.Guild(guildID); err == nil {
+		bundle.GuildName = guild.Name
+	}
+
+	if settings, exists := guildSettings.Snapshot(guildID); exists {
+		bundle.CustomResponses = settings.CustomResponses
+		for _, roleMe := range settings.RoleMeList {
+			roleMeCopy := *roleMe //Copied as the IDs are walked below
+			bundle.RoleMeList = append(bundle.RoleMeList, &roleMeCopy)
+		}
+		for _, feed := range settings.Feeds {
+			bundle.Feeds = append(bundle.Feeds, &Feed{ChannelID: feed.ChannelID, FeedURL: feed.FeedURL, Frequency: feed.Frequency})
+		}
+		logSettings := settings.LogSettings
+		bundle.LogSettings = &logSettings
+		swearFilter := settings.SwearFilter
+		bundle.SwearFilter = &swearFilter
+	}
+	if starboard, exists := starboards.Snapshot(guildID); exists {
+		starboardCopy := *starboard
+		starboardCopy.StarboardEntries = nil
+		bundle.Starboard = &starboardCopy
+	}
+
+	//Only name the channels and roles the settings refer to
+	names := make(map[string]string)
+	for _, channel := range channels {
+		names[channel.ID] = channel.Name
+	}
+	for _, role := range roles {
+		names[role.ID] = role.Name
+	}
+	bundle.walkIDs(func(id string, isRole bool) string {
+		if name, exists := names[id]; exists {
+			if isRole {
+				bundle.Roles[id] = name
+			} else {
+				bundle.Channels[id] = name
+			}
+		}
+		return id
+	})
+	return bundle, nil
+}
+
+// walkIDs calls remap with every channel and role ID the bundle refers to, replacing each with the ID it returns
+//
+// An empty ID returned by remap removes the reference.
+func (bundle *GuildBundle) walkIDs(remap func(id string, isRole bool) string) {
+	remapList := func(ids []string, isRole bool) []string {
+		remapped := make([]string, 0)
+		for _, id := range ids {
+			if newID := remap(id, isRole); newID != "" {
+				remapped = append(remapped, newID)
+			}
+		}
+		return remapped
+	}
+	remapSingle := func(id string) string {
+		if id == "" {
+			return ""
+		}
+		return remap(id, false)
+	}
+
+	for _, roleMe := range bundle.RoleMeList {
+		roleMe.AddRoles = remapList(roleMe.AddRoles, true)
+		roleMe.RemoveRoles = remapList(roleMe.RemoveRoles, true)
+		roleMe.ChannelIDs = remapList(roleMe.ChannelIDs, false)
+	}
+	for _, feed := range bundle.Feeds {
+		feed.ChannelID = remapSingle(feed.ChannelID)
+	}
+	if bundle.Starboard != nil {
+		bundle.Starboard.ChannelID = remapSingle(bundle.Starboard.ChannelID)
+		bundle.Starboard.NSFWChannelID = remapSingle(bundle.Starboard.NSFWChannelID)
+		bundle.Starboard.BlacklistChannels = remapList(bundle.Starboard.BlacklistChannels, false)
+	}
+	if bundle.LogSettings != nil {
+		bundle.LogSettings.LoggingChannel = remapSingle(bundle.LogSettings.LoggingChannel)
+	}
+}
+
+// Name returns the name of the guild the bundle was exported from, or its ID if the name wasn't known
+func (bundle *GuildBundle) Name() string {
+	if bundle.GuildName != "" {
+		return "**" + bundle.GuildName + "**"
+	}
+	return "``" + bundle.GuildID + "``"
+}
+
+// parseGuildBundle decodes a guild bundle and checks it for any errors before it's imported
+func parseGuildBundle(data []byte) (*GuildBundle, error) {
+	if len(data) > GuildBundleMaxSize {
+		return nil, fmt.Errorf("the bundle is larger than %d KB", GuildBundleMaxSize/1024)
+	}
+
+	bundle := &GuildBundle{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(bundle); err != nil {
+		return nil, fmt.Errorf("the bundle isn't valid JSON: %v", err)
+	}
+	if bundle.Version > GuildBundleVersion {
+		return nil, errBundleVersion
+	}
+	if bundle.Version <= 0 {
+		return nil, errors.New("the bundle has no version")
+	}
+	if len(bundle.CustomResponses) == 0 && len(bundle.RoleMeList) == 0 && len(bundle.Feeds) == 0 && bundle.Starboard == nil && bundle.LogSettings == nil && bundle.SwearFilter == nil {
+		return nil, errBundleEmpty
+	}
+
+	for i, customResponse := range bundle.CustomResponses {
+		expression, err := regexp.Compile(customResponse.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("custom response %d has an invalid expression: %v", i+1, err)
+		}
+		bundle.CustomResponses[i].Regexp = expression
+		if len(customResponse.Responses) == 0 && len(customResponse.CmdResponses) == 0 {
+			return nil, fmt.Errorf("custom response %d has no responses", i+1)
+		}
+		for _, cmdResponse := range customResponse.CmdResponses {
+			if _, exists := getBotData().Commands[cmdResponse.CommandName]; !exists {
+				return nil, fmt.Errorf("custom response %d runs an unknown command %s", i+1, cmdResponse.CommandName)
+			}
+		}
+	}
+	for i, roleMe := range bundle.RoleMeList {
+		if roleMe == nil || len(roleMe.Triggers) == 0 {
+			return nil, fmt.Errorf("roleme %d has no triggers", i+1)
+		}
+	}
+	for i, feed := range bundle.Feeds {
+		if feed == nil {
+			return nil, fmt.Errorf("feed %d is empty", i+1)
+		}
+		feedURL, err := url.Parse(feed.FeedURL)
+		if err != nil || (feedURL.Scheme != "http" && feedURL.Scheme != "https") {
+			return nil, fmt.Errorf("feed %d has an invalid URL", i+1)
+		}
+		if feed.Frequency <= 0 {
+			return nil, fmt.Errorf("feed %d has an invalid frequency", i+1)
+		}
+	}
+	if bundle.Starboard != nil && bundle.Starboard.MinimumStars < 0 {
+		return nil, errors.New("the starboard has an invalid minimum amount of stars")
+	}
+	return bundle, nil
+}
+
+// importGuildBundle applies a bundle to a guild, replacing each of the guild's settings that the bundle has
+//
+// Each channel and role in the bundle is matched to one in the guild by its ID in idMap (where key = old ID), then
+// by having the same ID as one in the guild, then by name. References to channels and roles that can't be matched are removed.
+//
+// The caller must hold the guild's settings lock, which the command worker already holds for Settings commands.
+func importGuildBundle(guildID string, bundle *GuildBundle, idMap map[string]string) (*GuildBundleResult, error) {
+	channels, roles, err := getGuildChannelsRoles(guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GuildBundleResult{}
+	channelIDs, roleIDs := make(map[string]string), make(map[string]string)
+	channelNames, roleNames := make(map[string]string), make(map[string]string)
+	for _, channel := range channels {
+		channelIDs[channel.ID] = channel.ID
+		channelNames[strings.ToLower(channel.Name)] = channel.ID
+	}
+	for _, role := range roles {
+		roleIDs[role.ID] = role.ID
+		roleNames[strings.ToLower(role.Name)] = role.ID
+	}
+
+	resolved := make(map[string]string)
+	bundle.walkIDs(func(id string, isRole bool) string {
+		if newID, exists := resolved[id]; exists {
+			return newID
+		}
+
+		ids, names, bundleNames, kind := channelIDs, channelNames, bundle.Channels, "#"
+		if isRole {
+			ids, names, bundleNames, kind = roleIDs, roleNames, bundle.Roles, "@"
+		}
+		name := bundleNames[id]
+		if name == "" {
+			name = id
+		}
+
+		newID := ""
+		if mapped, exists := ids[idMap[id]]; exists {
+			newID = mapped
+		} else if sameID, exists := ids[id]; exists {
+			newID = sameID
+		} else if namedID, exists := names[strings.ToLower(bundleNames[id])]; exists && bundleNames[id] != "" {
+			newID = namedID
+		}
+
+		resolved[id] = newID
+		if newID == "" {
+			result.Unresolved = append(result.Unresolved, kind+name)
+		} else if newID != id {
+			result.Remapped = append(result.Remapped, kind+name+" -> "+newID)
+		}
+		return newID
+	})
+
+	initializeGuildSettings(guildID)
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		if bundle.CustomResponses != nil {
+			settings.CustomResponses = bundle.CustomResponses
+			result.Imported = append(result.Imported, strconv.Itoa(len(bundle.CustomResponses))+" custom response(s)")
+		}
+		if bundle.RoleMeList != nil {
+			roleMeList := make([]*RoleMe, 0)
+			for i, roleMe := range bundle.RoleMeList {
+				if len(roleMe.AddRoles) == 0 && len(roleMe.RemoveRoles) == 0 {
+					result.Warnings = append(result.Warnings, "Skipped roleme "+strconv.Itoa(i+1)+" as none of its roles exist")
+					continue
+				}
+				roleMeList = append(roleMeList, roleMe)
+			}
+			settings.RoleMeList = roleMeList
+			result.Imported = append(result.Imported, strconv.Itoa(len(roleMeList))+" roleme(s)")
+		}
+		if bundle.Feeds != nil {
+			settings.Feeds = make([]*Feed, 0)
+		}
+	})
+	if bundle.Feeds != nil {
+		added := 0
+		for _, feed := range bundle.Feeds {
+			if feed.ChannelID == "" {
+				result.Warnings = append(result.Warnings, "Skipped the feed "+feed.FeedURL+" as its channel doesn't exist")
+				continue
+			}
+			if err := addFeed(guildID, feed.ChannelID, feed.FeedURL, feed.Frequency); err != nil {
+				result.Warnings = append(result.Warnings, "Skipped the feed "+feed.FeedURL+" as it couldn't be fetched")
+				continue
+			}
+			added++
+		}
+		result.Imported = append(result.Imported, strconv.Itoa(added)+" feed(s)")
+	}
+	if bundle.Starboard != nil {
+		starboard := bundle.Starboard
+		replaced := starboards.Update(guildID, func(existing *Starboard) {
+			starboard.StarboardEntries = existing.StarboardEntries //Keep the entries already posted in this guild
+			*existing = *starboard
+		})
+		if !replaced {
+			starboard.StarboardEntries = make([]StarboardEntry, 0)
+			starboards.Store(guildID, starboard)
+		}
+		result.Imported = append(result.Imported, "starboard")
+	}
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		if bundle.LogSettings != nil {
+			settings.LogSettings = *bundle.LogSettings
+			result.Imported = append(result.Imported, "log settings")
+		}
+		if bundle.SwearFilter != nil {
+			settings.SwearFilter = *bundle.SwearFilter
+			result.Imported = append(result.Imported, "swear filter")
+		}
+	})
+
+	stateSaveGuild(guildID, "")
+	return result, nil
+}
+
+// getGuildChannelsRoles returns the channels and roles of a guild, from the state if it's there
+func getGuildChannelsRoles(guildID string) ([]*discordgo.Channel, []*discordgo.Role, error) {
+	if guild, err := getBotData().DiscordSession.State.Guild(guildID); err == nil {
+		return guild.Channels, guild.Roles, nil
+	}
+
+	channels, err := getBotData().DiscordSession.GuildChannels(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	roles, err := getBotData().DiscordSession.GuildRoles(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return channels, roles, nil
+}
+
+// parseBundleIDMap parses mappings of old channel and role IDs to new ones, each written as old=new with optional mention formatting
+func parseBundleIDMap(mappings []string) (map[string]string, error) {
+	idMap := make(map[string]string)
+	for _, mapping := range mappings {
+		ids := strings.SplitN(mapping, "=", 2)
+		if len(ids) != 2 {
+			ids = strings.SplitN(mapping, ":", 2)
+		}
+		if len(ids) != 2 {
+			return nil, errors.New("``" + mapping + "`` must be written as old=new")
+		}
+		oldID, newID := trimMentionID(ids[0]), trimMentionID(ids[1])
+		if oldID == "" || newID == "" {
+			return nil, errors.New("``" + mapping + "`` must be written as old=new")
+		}
+		idMap[oldID] = newID
+	}
+	return idMap, nil
+}
+
+// trimMentionID returns the ID in a channel or role mention, or the ID itself if it isn't a mention
+func trimMentionID(mention string) string {
+	mention = strings.TrimPrefix(mention, "<")
+	mention = strings.TrimSuffix(mention, ">")
+	return strings.TrimLeft(mention, "#@&")
+}
+
+// getBundleAttachment returns the contents of the guild bundle attached to a command's message or piped into it
+func getBundleAttachment(env *CommandEnvironment) ([]byte, error) {
+	if env.Input != nil && len(env.Input.Files) > 0 {
+		return ioutil.ReadAll(env.Input.Files[0].Reader)
+	}
+	if env.Message == nil || len(env.Message.Attachments) == 0 {
+		return nil, errors.New("attach a bundle exported with the export command")
+	}
+
+	attachment := env.Message.Attachments[0]
+	if attachment.Size > GuildBundleMaxSize {
+		return nil, fmt.Errorf("the bundle is larger than %d KB", GuildBundleMaxSize/1024)
+	}
+	resp, err := httpGetContext(env.Context, attachment.URL)
+	if err != nil {
+		return nil, errors.New("unable to download the bundle")
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(io.LimitReader(resp.Body, GuildBundleMaxSize+1)) //One byte over so oversized bundles are still refused
+}
+
+// commandSettingsServerResponse handles the server settings that respond with more than an embed, such as exporting a bundle
+func commandSettingsServerResponse(args []string, env *CommandEnvironment) *CommandResponse {
+	switch args[0] {
+	case "export":
+		bundle, err := exportGuildBundle(env.Guild.ID)
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Server Settings - Export Error", "Unable to read this server's channels and roles."))
+		}
+		bundleJSON, err := json.MarshalIndent(bundle, "", "\t")
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Server Settings - Export Error", "Unable to encode this server's settings."))
+		}
+		return &CommandResponse{
+			Embeds: []*discordgo.MessageEmbed{NewGenericEmbed(env.Locale(), "Server Settings - Export", "Here are this server's settings. Import them into another server with ``"+env.BotPrefix+env.Command+" import``.")},
+			Files:  []*discordgo.File{{Name: "clinet-" + env.Guild.ID + ".json", ContentType: "application/json", Reader: bytes.NewReader(bundleJSON)}},
+		}
+	case "import":
+		idMap, err := parseBundleIDMap(args[1:])
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbedAdvanced("Server Settings - Import Error", err.Error(), 0x1C1C1C))
+		}
+		bundleJSON, err := getBundleAttachment(env)
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbedAdvanced("Server Settings - Import Error", "Unable to read the bundle: "+err.Error()+".", 0x1C1C1C))
+		}
+		bundle, err := parseGuildBundle(bundleJSON)
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbedAdvanced("Server Settings - Import Error", "Invalid bundle: "+err.Error()+".", 0x1C1C1C))
+		}
+		result, err := importGuildBundle(env.Guild.ID, bundle, idMap)
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Server Settings - Import Error", "Unable to read this server's channels and roles."))
+		}
+		return NewEmbedResponse(result.Embed("Successfully imported the settings exported from " + bundle.Name() + "."))
+	}
+	return NewEmbedResponse(commandSettingsServer(args, env))
+}
+
+// Embed returns an embed describing the result of an import
+func (result *GuildBundleResult) Embed(description string) *discordgo.MessageEmbed {
+	resultEmbed := NewEmbed().
+		SetTitle("Server Settings - Import").
+		SetDescription(description).
+		SetColor(0x1C1C1C)
+	if len(result.Imported) > 0 {
+		resultEmbed.AddField("Imported", strings.Join(result.Imported, "\n"))
+	}
+	if len(result.Remapped) > 0 {
+		resultEmbed.AddField("Matched", truncateLines(result.Remapped, 10))
+	}
+	if len(result.Unresolved) > 0 {
+		resultEmbed.AddField("Not Found (Removed)", truncateLines(result.Unresolved, 10))
+	}
+	if len(result.Warnings) > 0 {
+		resultEmbed.AddField("Warnings", truncateLines(result.Warnings, 10))
+	}
+	return resultEmbed.MessageEmbed
+}
+
+// truncateLines joins up to max lines, noting how many more were left out
+func truncateLines(lines []string, max int) string {
+	if len(lines) <= max {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:max], "\n") + "\n... and " + strconv.Itoa(len(lines)-max) + " more"
+}
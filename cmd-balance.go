@@ -1,7 +1,6 @@
 package main
 
 import (
-	"strconv"
 	"strings"
 	"time"
 
@@ -10,9 +9,9 @@ import (
 )
 
 func commandBalance(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if len(env.Message.Mentions) > 0 {
+	if env.Arguments.Has("users") {
 		var mentions []*discordgo.User
-		for _, mention := range env.Message.Mentions {
+		for _, mention := range env.Arguments.Users("users") {
 			unique := true
 			for _, uniqueMention := range mentions {
 				if uniqueMention.ID == mention.ID {
@@ -26,71 +25,81 @@ func commandBalance(args []string, env *CommandEnvironment) *discordgo.MessageEm
 		}
 		var balances []string
 		for _, mention := range mentions {
-			if _, exists := userSettings[mention.ID]; !exists {
+			if _, exists := userSettings.Load(mention.ID); !exists {
 				balances = append(balances, "<@!"+mention.ID+">: $0")
 			} else {
-				balances = append(balances, "<@!"+mention.ID+">: $"+strconv.Itoa(userSettings[mention.ID].Balance))
+				balances = append(balances, "<@!"+mention.ID+">: $"+env.Locale().FormatNumber(userSettings.Get(mention.ID).Balance))
 			}
 		}
 		return NewGenericEmbedAdvanced("Balance", "The balances of the mentioned users are available below:\n\n"+strings.Join(balances, "\n"), 0x85BB65)
 	}
 
-	if userSettings[env.User.ID].DailyNext.IsZero() {
-		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your first __$200__ daily credits.", 0x85BB65)
+	if userSettings.Get(env.User.ID).DailyNext.IsZero() {
+		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+env.Locale().FormatNumber(userSettings.Get(env.User.ID).Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your first __$200__ daily credits.", 0x85BB65)
 	}
 
-	if time.Now().After(userSettings[env.User.ID].DailyNext) {
-		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your next __$200__ daily credits.", 0x85BB65)
+	if time.Now().After(userSettings.Get(env.User.ID).DailyNext) {
+		return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+env.Locale().FormatNumber(userSettings.Get(env.User.ID).Balance)+"__!\n\nYou may run "+env.BotPrefix+"daily to receive your next __$200__ daily credits.", 0x85BB65)
 	}
 
-	return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+strconv.Itoa(userSettings[env.User.ID].Balance)+"__!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(userSettings[env.User.ID].DailyNext)+".", 0x85BB65)
+	return NewGenericEmbedAdvanced("Balance", "Your current balance is __$"+env.Locale().FormatNumber(userSettings.Get(env.User.ID).Balance)+"__!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(userSettings.Get(env.User.ID).DailyNext)+".", 0x85BB65)
 }
 
 func commandDaily(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if userSettings[env.User.ID].DailyNext.IsZero() {
-		userSettings[env.User.ID].Balance += 5000
-		userSettings[env.User.ID].DailyNext = time.Now().Add(time.Hour * 24)
+	//Check and pay the daily credits under one update, so two dailies at once can't both be paid
+	firstDaily, received := false, false
+	dailyNext := time.Time{}
+	userSettings.Update(env.User.ID, func(settings *UserSettings) {
+		switch {
+		case settings.DailyNext.IsZero():
+			settings.Balance += 5000
+			settings.DailyNext = time.Now().Add(time.Hour * 24)
+			firstDaily, received = true, true
+		case time.Now().After(settings.DailyNext):
+			settings.Balance += 200
+			settings.DailyNext = time.Now().Add(time.Hour * 24)
+			received = true
+		}
+		dailyNext = settings.DailyNext
+	})
+
+	if firstDaily {
 		return NewGenericEmbedAdvanced("Daily", "You received your __$200__ daily credits!\n\nAs a bonus for your first daily, you received an additional __$4800__ credits!", 0x85BB65)
 	}
-
-	if time.Now().After(userSettings[env.User.ID].DailyNext) {
-		userSettings[env.User.ID].Balance += 200
-		userSettings[env.User.ID].DailyNext = time.Now().Add(time.Hour * 24)
+	if received {
 		return NewGenericEmbedAdvanced("Daily", "You received your __$200__ daily credits!", 0x85BB65)
 	}
 
-	return NewGenericEmbedAdvanced("Daily", "You have already received your __$200__ daily credits!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(userSettings[env.User.ID].DailyNext)+".", 0x85BB65)
+	return NewGenericEmbedAdvanced("Daily", "You have already received your __$200__ daily credits!\n\nYou may receive your next __$200__ daily credits approximately "+humanize.Time(dailyNext)+".", 0x85BB65)
 }
 
 func commandTransfer(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if len(env.Message.Mentions) <= 0 {
-		return NewErrorEmbed("Transfer Error", "You must specify a user to transfer credits to.")
-	}
-	if len(env.Message.Mentions) > 1 {
-		return NewErrorEmbed("Transfer Error", "You cannot specify more than one user to transfer credits to.")
-	}
-
-	credits, err := strconv.Atoi(args[0])
-	if err != nil {
-		return NewErrorEmbed("Transfer Error", "``"+args[0]+"`` is not a valid number.")
+	credits := env.Arguments.Int("amount")
+	target := env.Arguments.User("target")
+	if target.ID == env.User.ID {
+		return NewErrorEmbed(env.Locale(), "Transfer Error", "You cannot transfer credits to yourself!")
 	}
-
-	if credits <= 0 {
-		return NewErrorEmbed("Transfer Error", "You cannot transfer less than __$1__ in credits.")
+	if target.Bot {
+		return NewErrorEmbed(env.Locale(), "Transfer Error", "You cannot transfer credits to a bot!")
 	}
 
-	if credits > userSettings[env.User.ID].Balance {
-		return NewErrorEmbed("Transfer Error", "You have insufficient credits to perform this transfer.")
+	//Check and take the credits under one update, so two transfers at once can't both spend the same credits
+	debited := false
+	userSettings.Update(env.User.ID, func(settings *UserSettings) {
+		if credits > settings.Balance {
+			return
+		}
+		settings.Balance -= credits
+		debited = true
+	})
+	if !debited {
+		return NewErrorEmbed(env.Locale(), "Transfer Error", "You have insufficient credits to perform this transfer.")
 	}
 
-	target := env.Message.Mentions[0]
-	if target.Bot {
-		return NewErrorEmbed("Transfer Error", "You cannot transfer credits to a bot!")
-	}
 	initializeUserSettings(target.ID)
+	userSettings.Update(target.ID, func(settings *UserSettings) {
+		settings.Balance += credits
+	})
 
-	userSettings[env.User.ID].Balance -= credits
-	userSettings[target.ID].Balance += credits
-
-	return NewGenericEmbed("Transfer", "Successfully transferred __$"+args[0]+"__ in credits to <@!"+target.ID+">!")
+	return NewGenericEmbed(env.Locale(), "Transfer", "Successfully transferred __$%s__ in credits to <@!%s>!", env.Locale().FormatNumber(credits), target.ID)
 }
@@ -1,85 +1,69 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"os/exec"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
-	soundcloud "github.com/JoshuaDoes/go-soundcloud"
-	wolfram "github.com/JoshuaDoes/go-wolfram"
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/go-github/github"
-	klogger "github.com/koffeinsource/go-klogger"
-	xkcd "github.com/nishanths/go-xkcd"
-	"google.golang.org/api/googleapi/transport"
-	"google.golang.org/api/youtube/v3"
 	git "gopkg.in/src-d/go-git.v4"
 )
 
-func commandReload(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	configFileHandle, err := os.Open(configFile)
-	defer configFileHandle.Close()
-	if err != nil {
-		return NewErrorEmbed("Reload Error", "There was an error loading the bot configuration file.")
-	}
-
-	configParser := json.NewDecoder(configFileHandle)
-	if err = configParser.Decode(&botData); err != nil {
-		return NewErrorEmbed("Reload Error", "There was an error applying the bot configuration to memory. State and/or configuration may be corrupted, consider checking the configuration and restarting the bot process.")
+var (
+	// helpPageArguments contains the page number that may follow help, help search and help for a category
+	helpPageArguments = []CommandArgument{
+		{Name: "page", Description: "The page of commands to view", ArgType: ArgTypeNumber, Optional: true, Min: 1},
 	}
+)
 
-	err = botData.PrepConfig()
+func commandReload(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	reload, err := reloadConfig(configFile)
 	if err != nil {
-		return NewErrorEmbed("Reload Error", "There were some inconsistencies with the bot configuration. State and/or configuration may be corrupted, consider checking the configuration and restarting the bot process.")
+		return NewErrorEmbed(env.Locale(), "Reload Error", "The bot configuration was left as it was, as the configuration file couldn't be loaded: %s", err.Error())
 	}
 
-	if botData.BotOptions.UseDuckDuckGo {
-		botData.BotClients.DuckDuckGo = &duckduckgo.Client{AppName: botData.BotKeys.DuckDuckGoAppName}
+	if len(reload.Changes) == 0 && len(reload.Errors) == 0 {
+		return NewGenericEmbed(env.Locale(), "Reload", "Successfully reloaded the bot configuration, nothing has changed.")
 	}
-	if botData.BotOptions.UseImgur {
-		botData.BotClients.Imgur.HTTPClient = &http.Client{}
-		botData.BotClients.Imgur.Log = &klogger.CLILogger{}
-		botData.BotClients.Imgur.ImgurClientID = botData.BotKeys.ImgurClientID
-	}
-	if botData.BotOptions.UseSoundCloud {
-		botData.BotClients.SoundCloud = &soundcloud.Client{ClientID: botData.BotKeys.SoundCloudClientID}
+
+	changes := reload.Changes
+	if len(changes) > ConfigReloadMaxLines {
+		changes = append(changes[:ConfigReloadMaxLines], "... and "+strconv.Itoa(len(reload.Changes)-ConfigReloadMaxLines)+" more")
 	}
-	if botData.BotOptions.UseWolframAlpha {
-		botData.BotClients.Wolfram = &wolfram.Client{AppID: botData.BotKeys.WolframAppID}
+	reloadEmbed := NewEmbed().
+		SetTitle("Reload").
+		SetDescription("Successfully reloaded the bot configuration.").
+		SetColor(0x1C1C1C)
+	if len(changes) > 0 {
+		reloadEmbed.AddField("Changes", "```diff\n"+strings.Join(changes, "\n")+"```")
 	}
-	if botData.BotOptions.UseXKCD {
-		botData.BotClients.XKCD = xkcd.NewClient()
+	if len(reload.RestartRequired) > 0 {
+		reloadEmbed.AddField("Restart Required", "These only take effect after a restart: ``"+strings.Join(reload.RestartRequired, "``, ``")+"``")
 	}
-	if botData.BotOptions.UseYouTube {
-		httpClient := &http.Client{
-			Transport: &transport.APIKey{Key: botData.BotKeys.YouTubeAPIKey},
+	if len(reload.Errors) > 0 {
+		clientErrors := make([]string, 0)
+		for _, err := range reload.Errors {
+			clientErrors = append(clientErrors, err.Error())
 		}
-		botData.BotClients.YouTube, _ = youtube.New(httpClient)
-	}
-	if botData.BotOptions.UseGitHub {
-		botData.BotClients.GitHub = github.NewClient(nil)
+		reloadEmbed.AddField("Errors", strings.Join(clientErrors, "\n"))
 	}
-
-	return NewGenericEmbed("Reload", "Successfully reloaded the bot configuration.")
+	return reloadEmbed.SetFooter("+ added, - removed, ~ changed").Truncate().MessageEmbed
 }
 
 func commandRestart(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//Tell the user we're restarting
-	botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Restart", "Restarting "+botData.BotName+"..."))
+	getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed(env.Locale(), "Restart", "Restarting "+getBotData().BotName+"..."))
 
 	//Write the current channel ID to a restart file for the bot to read after the restart
 	ioutil.WriteFile(".restart", []byte(env.Channel.ID), 0644)
 
 	//Save the state so it's not lost
 	stateSaveAll()
+	stateClose()
 
 	//Close the bot process, as the MASTER process will open it again
 	os.Exit(0)
@@ -93,7 +77,7 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	output, err := golangver.CombinedOutput()
 	if len(output) <= 0 || err != nil {
-		return NewErrorEmbed("Update Error", "Unable to execute ``go version``. Make sure Go ["+GolangVersion+"] is installed on the host machine.\n\n"+fmt.Sprintf("%s\n```%v```", output, err))
+		return NewErrorEmbed(env.Locale(), "Update Error", "Unable to execute ``go version``. Make sure Go ["+GolangVersion+"] is installed on the host machine.\n\n"+fmt.Sprintf("%s\n```%v```", output, err))
 	}
 
 	//Check if the govvv wrapper is installed
@@ -101,13 +85,13 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	output, _ = govvv.CombinedOutput()
 	if len(output) <= 0 {
-		return NewErrorEmbed("Update Error", "Unable to execute ``govvv``. Make sure govvv is installed on the host machine."+fmt.Sprintf("```%v```", output))
+		return NewErrorEmbed(env.Locale(), "Update Error", "Unable to execute ``govvv``. Make sure govvv is installed on the host machine."+fmt.Sprintf("```%v```", output))
 	}
 
 	//Create a temporary directory to store the git repository in
 	repodir, err := ioutil.TempDir("", "clinetupdate")
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error creating a temporary directory to store the Clinet git repository in.")
+		return NewErrorEmbed(env.Locale(), "Update Error", "Error creating a temporary directory to store the Clinet git repository in.")
 	}
 	defer os.RemoveAll(repodir)
 
@@ -117,42 +101,42 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		Depth: 1,
 	})
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error cloning the git repo.")
+		return NewErrorEmbed(env.Locale(), "Update Error", "Error cloning the git repo.")
 	}
 	ref, err := repo.Head()
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error finding the HEAD of the git repo.")
+		return NewErrorEmbed(env.Locale(), "Update Error", "Error finding the HEAD of the git repo.")
 	}
 	commit, err := repo.CommitObject(ref.Hash())
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Error fetching the HEAD commit of the git repo.")
+		return NewErrorEmbed(env.Locale(), "Update Error", "Error fetching the HEAD commit of the git repo.")
 	}
 	commitHash := commit.Hash.String()
 	if commitHash == GitCommitFull {
 		if len(args) <= 0 || len(args) >= 1 && args[0] != "force" {
-			return NewGenericEmbed("Update", botData.BotName+" is already up to date!")
+			return NewGenericEmbed(env.Locale(), "Update", getBotData().BotName+" is already up to date!")
 		}
 	}
 
 	//Tell the user we're updating
-	botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed("Update", "Updating "+botData.BotName+" to commit ``"+commitHash+"`` from commit ``"+GitCommitFull+"``..."))
+	getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed(env.Locale(), "Update", "Updating "+getBotData().BotName+" to commit ``"+commitHash+"`` from commit ``"+GitCommitFull+"``..."))
 
 	//Build the update
 	outputFile := repodir + "/" + os.Args[0]
 
 	govvvbuild := exec.Command("govvv", "build", "-o", outputFile)
 	govvvbuild.Dir = repodir
-	if !botData.DebugMode {
+	if !getBotData().DebugMode {
 		govvvbuild.Args = append(govvvbuild.Args, "-ldflags=-s -w")
 	}
 
 	output, err = govvvbuild.CombinedOutput()
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Unable to build "+botData.BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%s```", output))
+		return NewErrorEmbed(env.Locale(), "Update Error", "Unable to build "+getBotData().BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%s```", output))
 	}
 
 	if _, err = os.Stat(outputFile); os.IsNotExist(err) {
-		return NewErrorEmbed("Update Error", "Unable to find the updated build of "+botData.BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%v```", err))
+		return NewErrorEmbed(env.Locale(), "Update Error", "Unable to find the updated build of "+getBotData().BotName+" ``"+commitHash+"``.\n\n"+fmt.Sprintf("```%v```", err))
 	}
 
 	os.Rename(os.Args[0], os.Args[0]+".old")
@@ -165,14 +149,14 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	stateSaveAll()
 
 	//Mark updating flag as true so interrupted events (such as voice playback) will notify users that an update interrupted the event
-	botData.Updating = true
+	getBotData().Updating = true
 
 	//Leave all voice channels
-	for _, voiceIDRow := range voiceData {
+	for _, voiceIDRow := range voiceData.All() {
 		if voiceIDRow.IsConnected() {
 			if voiceIDRow.IsStreaming() {
 				//Notify users that an update is occuring
-				botData.DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+botData.BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
+				getBotData().DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+getBotData().BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
 
 				debugLog("> Stopping stream in voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
 				voiceIDRow.Stop()
@@ -183,7 +167,10 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	}
 
 	debugLog("> Disconnecting from Discord...", true)
-	botData.DiscordSession.Close()
+	getBotData().DiscordSession.Close()
+
+	//Release the state store so the new bot process can open it
+	stateClose()
 
 	//Spawn a new bot process that will kill this one
 	botProcess := exec.Command(os.Args[0], "-killold", "true")
@@ -191,10 +178,10 @@ func commandUpdate(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	botProcess.Stderr = os.Stderr
 	err = botProcess.Start()
 	if err != nil {
-		return NewErrorEmbed("Update Error", "Unable to spawn the updated bot process.")
+		return NewErrorEmbed(env.Locale(), "Update Error", "Unable to spawn the updated bot process.")
 	}
 
-	return NewGenericEmbed("Update", "Waiting for update to finish...")
+	return NewGenericEmbed(env.Locale(), "Update", "Waiting for update to finish...")
 }
 
 func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -202,14 +189,14 @@ func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	userID = strings.TrimLeft(userID, "<@!")
 	userID = strings.TrimRight(userID, ">")
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := getBotData().DiscordSession.User(userID)
 	if err != nil {
-		return NewErrorEmbed("Sudo Error", "Invalid user ``"+args[0]+"``.")
+		return NewErrorEmbed(env.Locale(), "Sudo Error", "Invalid user ``"+args[0]+"``.")
 	}
 
-	member, err := botData.DiscordSession.GuildMember(env.Guild.ID, userID)
+	member, err := getBotData().DiscordSession.GuildMember(env.Guild.ID, userID)
 	if err != nil {
-		return NewErrorEmbed("Sudo Error", "Specified user does not exist in current guild.")
+		return NewErrorEmbed(env.Locale(), "Sudo Error", "Specified user does not exist in current guild.")
 	}
 
 	env.User = user
@@ -224,110 +211,100 @@ func commandSudo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 
 func commandAbout(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - About").
-		SetDescription(botData.BotName+" is a Discord bot written in Google's Go programming language, intended for conversation and fact-based queries.").
-		AddField("How can I use "+botData.BotName+" in my server?", "Simply open the Invite Link at the end of this message and follow the on-screen instructions.").
-		AddField("How can I help keep "+botData.BotName+" running?", "The best ways to help keep "+botData.BotName+" running are to either donate using the Donation Link or contribute to the source code using the Source Code Link, both at the end of this message.").
-		AddField("How can I use "+botData.BotName+"?", "There are many ways to make use of "+botData.BotName+".\n1) Type ``"+env.BotPrefix+"help`` and try using some of the available commands.\n2) Ask "+botData.BotName+" a question, ex: ``@"+botData.DiscordSession.State.User.String()+", what time is it?`` or ``@"+botData.DiscordSession.State.User.String()+", what is DiscordApp?``.").
-		AddField("Where can I join the "+botData.BotName+" Discord server?", "If you would like to get help and support with "+botData.BotName+" or experiment with the latest and greatest of "+botData.BotName+", use the Discord Server Invite Link at the end of this message.").
-		AddField("Bot Invite Link", botData.BotInviteURL).
-		AddField("Discord Server Invite Link", botData.BotDiscordURL).
-		AddField("Donation Link", botData.BotDonationURL).
-		AddField("Source Code Link", botData.BotSourceURL).
+		SetTitle(getBotData().BotName+" - About").
+		SetDescription(getBotData().BotName+" is a Discord bot written in Google's Go programming language, intended for conversation and fact-based queries.").
+		AddField("How can I use "+getBotData().BotName+" in my server?", "Simply open the Invite Link at the end of this message and follow the on-screen instructions.").
+		AddField("How can I help keep "+getBotData().BotName+" running?", "The best ways to help keep "+getBotData().BotName+" running are to either donate using the Donation Link or contribute to the source code using the Source Code Link, both at the end of this message.").
+		AddField("How can I use "+getBotData().BotName+"?", "There are many ways to make use of "+getBotData().BotName+".\n1) Type ``"+env.BotPrefix+"help`` and try using some of the available commands.\n2) Ask "+getBotData().BotName+" a question, ex: ``@"+getBotData().DiscordSession.State.User.String()+", what time is it?`` or ``@"+getBotData().DiscordSession.State.User.String()+", what is DiscordApp?``.").
+		AddField("Where can I join the "+getBotData().BotName+" Discord server?", "If you would like to get help and support with "+getBotData().BotName+" or experiment with the latest and greatest of "+getBotData().BotName+", use the Discord Server Invite Link at the end of this message.").
+		AddField("Bot Invite Link", getBotData().BotInviteURL).
+		AddField("Discord Server Invite Link", getBotData().BotDiscordURL).
+		AddField("Donation Link", getBotData().BotDonationURL).
+		AddField("Source Code Link", getBotData().BotSourceURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandInvite(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Invite").
-		SetDescription("Below are the available invite links for "+botData.BotName+".").
-		AddField("Bot Invite", botData.BotInviteURL).
-		AddField("Discord Server (Support/Development/Testing)", botData.BotDiscordURL).
+		SetTitle(getBotData().BotName+" - Invite").
+		SetDescription("Below are the available invite links for "+getBotData().BotName+".").
+		AddField("Bot Invite", getBotData().BotInviteURL).
+		AddField("Discord Server (Support/Development/Testing)", getBotData().BotDiscordURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandDonate(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Donate").
-		SetDescription("Below are the available donation links for "+botData.BotName+".").
-		AddField("PayPal", botData.BotDonationURL).
+		SetTitle(getBotData().BotName+" - Donate").
+		SetDescription("Below are the available donation links for "+getBotData().BotName+".").
+		AddField("PayPal", getBotData().BotDonationURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandSource(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Source Code").
-		SetDescription("Below are the available source code links for "+botData.BotName+".").
-		AddField("GitHub", botData.BotSourceURL).
+		SetTitle(getBotData().BotName+" - Source Code").
+		SetDescription("Below are the available source code links for "+getBotData().BotName+".").
+		AddField("GitHub", getBotData().BotSourceURL).
 		SetColor(0x1C1C1C).MessageEmbed
 }
 func commandHelp(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	//First see if help text is being requested for a particular command
-	if len(args) > 0 {
-		if command, exists := botData.Commands[args[0]]; exists {
-			if command.IsAlternateOf != "" {
-				if commandAlternate, exists := botData.Commands[command.IsAlternateOf]; exists {
-					command = commandAlternate
-				} else {
-					return nil
-				}
-			}
-			return getCommandUsage(args[0], "Help for **"+args[0]+"**", env)
-		}
+	if len(args) == 0 {
+		return getHelpOverview(env)
 	}
 
-	//Before we fetch help text data, we need to have an alphabetical listing of commands
-	var commandMapKeys []string
-	for commandMapKey := range botData.Commands {
-		commandMapKeys = append(commandMapKeys, commandMapKey)
+	//Pages of every command are still available by number
+	if parsed, err := parseArgumentList(helpPageArguments, args[:1], env); err == nil && parsed.Has("page") {
+		return getHelpPage(env, getHelpFields(env, nil), parsed.Int("page"),
+			env.Locale().T("%s - Help", getBotData().BotName), env.Locale().T("A list of commands you have permission to use."), env.BotPrefix+env.Command+" {page}")
 	}
-	sort.Strings(commandMapKeys)
-
-	//Create a dynamic list of fields for the help embed
-	commandFields := []*discordgo.MessageEmbedField{}
 
-	//Iterate over the alphabetically sorted command list and add each listed command to the help embed field list
-	for _, commandName := range commandMapKeys {
-		command := botData.Commands[commandName]
-		if command.IsAlternateOf == "" {
-			if command.IsAdministrative && env.User.ID != botData.BotOwnerID {
-				continue
-			}
-			if command.RequiredPermissions != 0 {
-				if permissionsAllowed, _ := MemberHasPermission(botData.DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
-					continue
-				}
+	if strings.ToLower(args[0]) == "search" {
+		if len(args) < 2 {
+			return NewErrorEmbed(env.Locale(), "Help Error", "You must specify a keyword to search for.")
+		}
+		keywords := args[1:]
+		pageNumber := 1
+		if len(keywords) > 1 {
+			if parsed, err := parseArgumentList(helpPageArguments, keywords[len(keywords)-1:], env); err == nil && parsed.Has("page") {
+				pageNumber = parsed.Int("page")
+				keywords = keywords[:len(keywords)-1]
 			}
-			commandField := &discordgo.MessageEmbedField{Name: env.BotPrefix + commandName, Value: command.HelpText, Inline: true}
-			commandFields = append(commandFields, commandField)
 		}
+		keyword := strings.ToLower(strings.Join(keywords, " "))
+		commandFields := getHelpFields(env, func(commandName string, command *Command) bool {
+			return matchesHelpKeyword(commandName, command, keyword)
+		})
+		if len(commandFields) == 0 {
+			return NewErrorEmbed(env.Locale(), "Help Error", "No commands matched ``%s``.", keyword)
+		}
+		return getHelpPage(env, commandFields, pageNumber,
+			env.Locale().T("%s - Help", getBotData().BotName), env.Locale().T("Commands matching **%s**.", keyword), env.BotPrefix+env.Command+" search "+keyword+" {page}")
 	}
 
-	pageNumber := 1
-	if len(args) > 0 {
-		newPageNumber, err := strconv.Atoi(args[0])
-		if err != nil {
-			return NewErrorEmbed("Help Error", "Invalid command or page number.")
+	if command, exists := getBotData().Commands[strings.ToLower(args[0])]; exists {
+		if command.IsAlternateOf != "" && getBotData().Commands[command.IsAlternateOf] == nil {
+			return nil
 		}
-		pageNumber = newPageNumber
+		return getCommandHelp(strings.ToLower(args[0]), env)
 	}
 
-	//Create the help page and give it the command list
-	helpEmbed, totalPages, err := page(commandFields, pageNumber, botData.BotOptions.HelpMaxResults)
-	if err != nil {
-		return NewErrorEmbed("Help Error", fmt.Sprintf("%v", err))
+	if category := getCommandCategory(args[0]); category != "" {
+		parsed, usageEmbed := parseSubcommandArguments(helpPageArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
+		pageNumber := 1
+		if parsed.Has("page") {
+			pageNumber = parsed.Int("page")
+		}
+		commandFields := getHelpFields(env, func(commandName string, command *Command) bool { return command.Category == category })
+		return getHelpPage(env, commandFields, pageNumber,
+			getBotData().BotName+" - Help - "+CommandCategoryNames[category], "A list of "+CommandCategoryNames[category]+" commands you have permission to use.", env.BotPrefix+env.Command+" "+category+" {page}")
 	}
 
-	//Prepare the help page to look nice
-	helpEmbed.
-		SetTitle(botData.BotName + " - Help").
-		SetDescription("A list of commands you have permission to use.").
-		SetFooter("Page " + strconv.Itoa(pageNumber) + " of " + strconv.Itoa(totalPages) + " | " + env.BotPrefix + env.Command + " {page}").
-		SetColor(0xFAFAFA)
-
-	//Return the help page to the caller
-	return helpEmbed.MessageEmbed
+	return NewErrorEmbed(env.Locale(), "Help Error", "Invalid command, category or page number.")
 }
 func commandVersion(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Version").
+		SetTitle(getBotData().BotName+" - Version").
 		AddField("Build ID", BuildID).
 		AddField("Build Date", BuildDate).
 		AddField("Latest Development", GitCommitMsg).
@@ -337,7 +314,7 @@ func commandVersion(args []string, env *CommandEnvironment) *discordgo.MessageEm
 }
 func commandCredits(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	return NewEmbed().
-		SetTitle(botData.BotName+" - Credits").
+		SetTitle(getBotData().BotName+" - Credits").
 		AddField("Bot Development", "- JoshuaDoes (2018)").
 		AddField("Programming Language", "- Golang").
 		AddField("Golang Libraries", "[duckduckgolang](https://github.com/JoshuaDoes/duckduckgolang), "+
@@ -366,11 +343,11 @@ func commandCredits(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//Create a list of ping test results
-	pingResults := make([]int, botData.BotOptions.MaxPingCount)
-	pingResultsStr := make([]string, botData.BotOptions.MaxPingCount)
+	pingResults := make([]int, getBotData().BotOptions.MaxPingCount)
+	pingResultsStr := make([]string, getBotData().BotOptions.MaxPingCount)
 
 	//Create ping embed
-	pingEmbed := NewGenericEmbed("Ping!", "Waiting for ping...")
+	pingEmbed := NewGenericEmbed(env.Locale(), "Ping!", "Waiting for ping...")
 
 	//Loop through each slice entry of pingResults to store our results
 	for i := 0; i < len(pingResults); i++ {
@@ -378,7 +355,7 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		timeCurrent := int(time.Now().UnixNano() / 1000000)
 
 		//Send ping embed
-		pingMessage, err := botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, pingEmbed)
+		pingMessage, err := getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, pingEmbed)
 		if err != nil {
 			pingResults[i] = -1
 			continue
@@ -388,7 +365,7 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		timeNew := int(time.Now().UnixNano() / 1000000)
 
 		//Delete pingMessage to prevent spam
-		botData.DiscordSession.ChannelMessageDelete(env.Channel.ID, pingMessage.ID)
+		getBotData().DiscordSession.ChannelMessageDelete(env.Channel.ID, pingMessage.ID)
 
 		//Subtract new time from old time to get the ping
 		timeDiff := timeNew - timeCurrent
@@ -431,8 +408,8 @@ func commandPing(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	//Return ping results
 	return NewEmbed().
 		SetTitle("Pong!").
-		SetDescription(fmt.Sprintf("Average ping is ``%dms``. A total of ``%d/%d`` ping tests failed.\n*%s*", pingAverage, jitterCount, botData.BotOptions.MaxPingCount, addonMessage)).
+		SetDescription(fmt.Sprintf("Average ping is ``%dms``. A total of ``%d/%d`` ping tests failed.\n*%s*", pingAverage, jitterCount, getBotData().BotOptions.MaxPingCount, addonMessage)).
 		AddField("Ping Results", strings.Join(pingResultsStr, ", ")).
-		SetFooter(fmt.Sprintf("Ping results are determined by sending %d messages and determining how long it takes for each message to send successfully and return a success code. The average ping is determined by taking the sum of all of the ping results and dividing it by %d.", botData.BotOptions.MaxPingCount, botData.BotOptions.MaxPingCount)).
+		SetFooter(fmt.Sprintf("Ping results are determined by sending %d messages and determining how long it takes for each message to send successfully and return a success code. The average ping is determined by taking the sum of all of the ping results and dividing it by %d.", getBotData().BotOptions.MaxPingCount, getBotData().BotOptions.MaxPingCount)).
 		SetColor(0x1C1C1C).MessageEmbed
 }
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// CustomCommandDefaults contains the category, cooldown and timeout every custom command is checked and ran with, as they have no command of their own
+	CustomCommandDefaults = &Command{Category: CommandCategoryFun, Cooldown: CommandCooldown{User: 3 * time.Second}}
+
+	// CustomCommandAllowedMentions only lets replies to custom commands mention users, so their arguments can't be used to ping everyone or a role
+	CustomCommandAllowedMentions = &MessageAllowedMentions{Parse: []string{"users"}}
+)
+
+// CustomCommand holds a guild-defined prefix command and its reply template
+type CustomCommand struct {
+	Response  string `json:"response"`            //The reply template, see renderCustomCommand for placeholders
+	Embed     bool   `json:"embed,omitempty"`     //Whether or not the reply should be sent as an embed
+	CreatedBy string `json:"createdBy,omitempty"` //The user ID of the custom command's creator
+}
+
+// customCommandNode holds a parsed piece of a custom command template
+type customCommandNode struct {
+	Text        string               //Literal text to output as is
+	Placeholder string               //The placeholder to replace, without braces
+	Condition   string               //The condition of an if block, without the if: prefix
+	Then        []*customCommandNode //The nodes to render if the condition is true
+	Else        []*customCommandNode //The nodes to render if the condition is false
+}
+
+// customCommandContext holds the values available to a custom command template
+type customCommandContext struct {
+	Args []string
+	Env  *CommandEnvironment
+}
+
+func commandCustomCommand(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	settings, exists := guildSettings.Snapshot(env.Guild.ID)
+	if !exists {
+		settings = &GuildSettings{}
+	}
+
+	switch args[0] {
+	case "list":
+		if len(settings.CustomCommands) == 0 {
+			return NewGenericEmbed(env.Locale(), "Custom Commands", "There are no custom commands in this server.")
+		}
+		commandNames := make([]string, 0)
+		for commandName := range settings.CustomCommands {
+			commandNames = append(commandNames, "``"+env.BotPrefix+commandName+"``")
+		}
+		sort.Strings(commandNames)
+		return NewGenericEmbed(env.Locale(), "Custom Commands", strings.Join(commandNames, ", "))
+	case "show":
+		if len(args) < 2 {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "You must specify a custom command to show.")
+		}
+		commandName := strings.ToLower(args[1])
+		customCommand, exists := settings.CustomCommands[commandName]
+		if !exists {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "Unknown custom command ``"+commandName+"``.")
+		}
+		replyType := "text"
+		if customCommand.Embed {
+			replyType = "embed"
+		}
+		return NewEmbed().
+			SetTitle("Custom Commands - "+commandName).
+			AddField("Reply Type", replyType).
+			AddField("Creator", "<@"+customCommand.CreatedBy+">").
+			AddField("Response", "```"+customCommand.Response+"```").
+			SetColor(0x1C1C1C).MessageEmbed
+	case "add", "embed", "edit":
+		if len(args) < 3 {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "You must specify a custom command name and its response.")
+		}
+		commandName := strings.ToLower(args[1])
+		if _, exists := getBotData().Commands[commandName]; exists {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "``"+commandName+"`` is already a built-in command.")
+		}
+		response := strings.Join(args[2:], " ")
+		if _, err := parseCustomCommand(response); err != nil {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "Invalid response template: "+err.Error())
+		}
+
+		existed := false
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			customCommand, exists := settings.CustomCommands[commandName]
+			existed = exists
+			switch {
+			case args[0] == "edit" && exists:
+				customCommand.Response = response
+			case args[0] != "edit" && !exists:
+				if settings.CustomCommands == nil {
+					settings.CustomCommands = make(map[string]*CustomCommand)
+				}
+				settings.CustomCommands[commandName] = &CustomCommand{Response: response, Embed: args[0] == "embed", CreatedBy: env.User.ID}
+			}
+		})
+		if args[0] == "edit" {
+			if !existed {
+				return NewErrorEmbed(env.Locale(), "Custom Commands Error", "Unknown custom command ``"+commandName+"``.")
+			}
+			return NewGenericEmbed(env.Locale(), "Custom Commands", "Successfully edited the custom command ``"+commandName+"``.")
+		}
+		if existed {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "The custom command ``"+commandName+"`` already exists, use edit to change it.")
+		}
+		return NewGenericEmbed(env.Locale(), "Custom Commands", "Successfully added the custom command ``"+env.BotPrefix+commandName+"``.")
+	case "remove":
+		if len(args) < 2 {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "You must specify a custom command to remove.")
+		}
+		commandName := strings.ToLower(args[1])
+		removed := false
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			_, removed = settings.CustomCommands[commandName]
+			delete(settings.CustomCommands, commandName)
+		})
+		if !removed {
+			return NewErrorEmbed(env.Locale(), "Custom Commands Error", "Unknown custom command ``"+commandName+"``.")
+		}
+		return NewGenericEmbed(env.Locale(), "Custom Commands", "Successfully removed the custom command ``"+commandName+"``.")
+	}
+	return NewErrorEmbed(env.Locale(), "Custom Commands Error", "Unknown custom command action ``"+args[0]+"``.")
+}
+
+// getGuildCustomCommand returns a copy of a guild's custom command, or false if the guild has no custom command with that name
+func getGuildCustomCommand(guildID, commandName string) (*CustomCommand, bool) {
+	var customCommand *CustomCommand
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		if existing, exists := settings.CustomCommands[commandName]; exists {
+			customCommandCopy := *existing
+			customCommand = &customCommandCopy
+		}
+	})
+	return customCommand, customCommand != nil
+}
+
+// callGuildCustomCommand runs a custom command after the same checks as any other command, see CustomCommandDefaults
+func callGuildCustomCommand(commandName string, customCommand *CustomCommand, args []string, env *CommandEnvironment) *CommandResponse {
+	if disabledEmbed := checkCommandEnabled(commandName, CustomCommandDefaults, env); disabledEmbed != nil {
+		return NewEmbedResponse(disabledEmbed)
+	}
+	if permissionsEmbed := checkCommandPermissions(commandName, CustomCommandDefaults, env); permissionsEmbed != nil {
+		return NewEmbedResponse(permissionsEmbed)
+	}
+	if cooldownEmbed := checkCooldown(commandName, &CustomCommandDefaults.Cooldown, env); cooldownEmbed != nil {
+		return NewEmbedResponse(cooldownEmbed)
+	}
+	return runCommand(CustomCommandDefaults, args, env, func(env *CommandEnvironment) *CommandResponse {
+		return callCustomCommand(commandName, customCommand, args, env)
+	})
+}
+
+// callCustomCommand renders the reply of a guild's custom command
+func callCustomCommand(commandName string, customCommand *CustomCommand, args []string, env *CommandEnvironment) *CommandResponse {
+	nodes, err := parseCustomCommand(customCommand.Response)
+	if err != nil {
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Custom Command Error", "The response of ``"+commandName+"`` is invalid: "+err.Error()))
+	}
+	response := renderCustomCommand(nodes, &customCommandContext{Args: args, Env: env})
+	if response == "" {
+		return nil
+	}
+
+	if customCommand.Embed {
+		return NewEmbedResponse(NewGenericEmbedAdvanced("", response, 0x1C1C1C))
+	}
+	return &CommandResponse{Content: response, AllowedMentions: CustomCommandAllowedMentions}
+}
+
+// parseCustomCommand parses a custom command template into nodes
+//
+// Placeholders are wrapped in braces, and {if:condition}...{else}...{end} blocks may be nested.
+func parseCustomCommand(template string) ([]*customCommandNode, error) {
+	nodes, rest, terminator, err := parseCustomCommandNodes(template)
+	if err != nil {
+		return nil, err
+	}
+	if terminator != "" {
+		return nil, errors.New("{" + terminator + "} without a matching {if:...}")
+	}
+	if rest != "" {
+		return nil, errors.New("unexpected text after end of template")
+	}
+	return nodes, nil
+}
+
+// parseCustomCommandNodes parses nodes until the end of the template or an {else}/{end}, returning the unparsed remainder and the terminator found
+func parseCustomCommandNodes(template string) (nodes []*customCommandNode, rest, terminator string, err error) {
+	nodes = make([]*customCommandNode, 0)
+	for template != "" {
+		start := strings.Index(template, "{")
+		if start == -1 {
+			nodes = append(nodes, &customCommandNode{Text: template})
+			return nodes, "", "", nil
+		}
+		end := strings.Index(template[start:], "}")
+		if end == -1 {
+			nodes = append(nodes, &customCommandNode{Text: template})
+			return nodes, "", "", nil
+		}
+		end += start
+
+		if start > 0 {
+			nodes = append(nodes, &customCommandNode{Text: template[:start]})
+		}
+		tag := template[start+1 : end]
+		template = template[end+1:]
+
+		switch {
+		case tag == "":
+			nodes = append(nodes, &customCommandNode{Text: "{}"})
+		case tag == "else" || tag == "end":
+			return nodes, template, tag, nil
+		case strings.HasPrefix(tag, "if:"):
+			ifNode := &customCommandNode{Condition: strings.TrimPrefix(tag, "if:")}
+			if strings.TrimSpace(ifNode.Condition) == "" {
+				return nil, "", "", errors.New("{if:} is missing a condition")
+			}
+			ifNode.Then, template, terminator, err = parseCustomCommandNodes(template)
+			if err != nil {
+				return nil, "", "", err
+			}
+			if terminator == "else" {
+				ifNode.Else, template, terminator, err = parseCustomCommandNodes(template)
+				if err != nil {
+					return nil, "", "", err
+				}
+			}
+			if terminator != "end" {
+				return nil, "", "", errors.New("{if:" + ifNode.Condition + "} is missing an {end}")
+			}
+			nodes = append(nodes, ifNode)
+		default:
+			nodes = append(nodes, &customCommandNode{Placeholder: tag})
+		}
+	}
+	return nodes, "", "", nil
+}
+
+// renderCustomCommand renders parsed custom command nodes with the following placeholders:
+//
+//	{user}, {user.name}, {user.id}: the user who ran the command
+//	{channel}, {channel.name}, {channel.id}: the channel the command was ran in
+//	{server}, {server.id}: the server the command was ran in
+//	{args}, {args.count}, {argN}: all arguments, the amount of arguments or the Nth argument
+//	{mention}, {mentions}: the first or all users mentioned, or the user who ran the command if none
+//	{choose:a|b|c}: a random choice
+//	{if:value}, {if:value==other}, {if:value!=other} ... {else} ... {end}: conditionals on placeholder names or literal text
+func renderCustomCommand(nodes []*customCommandNode, ctx *customCommandContext) string {
+	rendered := ""
+	for _, node := range nodes {
+		switch {
+		case node.Condition != "":
+			if ctx.evaluate(node.Condition) {
+				rendered += renderCustomCommand(node.Then, ctx)
+			} else {
+				rendered += renderCustomCommand(node.Else, ctx)
+			}
+		case node.Placeholder != "":
+			if value, ok := ctx.resolve(node.Placeholder); ok {
+				rendered += value
+			} else {
+				rendered += "{" + node.Placeholder + "}"
+			}
+		default:
+			rendered += node.Text
+		}
+	}
+	return rendered
+}
+
+// resolve returns the value of a placeholder, and whether or not the placeholder exists
+func (ctx *customCommandContext) resolve(placeholder string) (string, bool) {
+	env := ctx.Env
+	switch placeholder {
+	case "user":
+		return "<@" + env.User.ID + ">", true
+	case "user.name":
+		return env.User.Username, true
+	case "user.id":
+		return env.User.ID, true
+	case "channel":
+		return "<#" + env.Channel.ID + ">", true
+	case "channel.name":
+		return env.Channel.Name, true
+	case "channel.id":
+		return env.Channel.ID, true
+	case "server":
+		return env.Guild.Name, true
+	case "server.id":
+		return env.Guild.ID, true
+	case "args":
+		return strings.Join(ctx.Args, " "), true
+	case "args.count":
+		return strconv.Itoa(len(ctx.Args)), true
+	case "mention", "mentions":
+		mentions := make([]string, 0)
+		for _, arg := range ctx.Args {
+			if user, err := resolveArgumentUser(arg, env); err == nil {
+				mentions = append(mentions, "<@"+user.ID+">")
+			}
+		}
+		if len(mentions) == 0 {
+			return "<@" + env.User.ID + ">", true
+		}
+		if placeholder == "mention" {
+			return mentions[0], true
+		}
+		return strings.Join(mentions, " "), true
+	}
+
+	if strings.HasPrefix(placeholder, "choose:") {
+		choices := strings.Split(strings.TrimPrefix(placeholder, "choose:"), "|")
+		return choices[rand.Intn(len(choices))], true
+	}
+	if strings.HasPrefix(placeholder, "arg") {
+		if index, err := strconv.Atoi(strings.TrimPrefix(placeholder, "arg")); err == nil && index > 0 {
+			if index > len(ctx.Args) {
+				return "", true
+			}
+			return ctx.Args[index-1], true
+		}
+	}
+	return "", false
+}
+
+// evaluate returns whether or not a condition is true, resolving each side as a placeholder name if possible
+func (ctx *customCommandContext) evaluate(condition string) bool {
+	value := func(side string) string {
+		side = strings.TrimSpace(side)
+		if resolved, ok := ctx.resolve(side); ok {
+			return resolved
+		}
+		return side
+	}
+
+	if sides := strings.SplitN(condition, "!=", 2); len(sides) == 2 {
+		return !strings.EqualFold(value(sides[0]), value(sides[1]))
+	}
+	if sides := strings.SplitN(condition, "==", 2); len(sides) == 2 {
+		return strings.EqualFold(value(sides[0]), value(sides[1]))
+	}
+	return value(condition) != ""
+}
@@ -8,7 +8,7 @@ import (
 
 //Debug commands
 func commandDebug(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	botData.DebugMode = !botData.DebugMode
+	getBotData().DebugMode = !getBotData().DebugMode
 
-	return NewGenericEmbed("Debug Mode", "Debug mode has been set to "+strconv.FormatBool(botData.DebugMode)+".")
+	return NewGenericEmbed(env.Locale(), "Debug Mode", "Debug mode has been set to "+strconv.FormatBool(getBotData().DebugMode)+".")
 }
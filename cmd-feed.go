@@ -40,24 +40,24 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 
 	//isAll := false
 	isSettingChannel := false
-	frequency := botData.BotOptions.FeedFrequency
+	frequency := getBotData().BotOptions.FeedFrequency
 
 	for _, arg := range args {
 		switch arg.Name {
 		case "add":
 			if len(feedsToEdit) > 0 || len(feedsToRemove) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You cannot mix arguments dictating what to do!")
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed to add when using the ``-add`` argument.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You must specify a feed to add when using the ``-add`` argument.")
 			}
 			if _, err := url.ParseRequestURI(arg.Value); err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid URL.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid URL.")
 			}
 
-			for _, feed := range guildSettings[env.Guild.ID].Feeds {
+			for _, feed := range guildSettings.Get(env.Guild.ID).Feeds {
 				if arg.Value == feed.FeedLink {
-					return NewErrorEmbed("Feed Error", "Feed ``"+arg.Value+"`` already exists.")
+					return NewErrorEmbed(env.Locale(), "Feed Error", "Feed ``"+arg.Value+"`` already exists.")
 				}
 			}
 
@@ -65,55 +65,55 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 			feedsToAdd = append(feedsToAdd, arg.Value)
 		case "frequency", "f":
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a post check frequency to use when using the ``-"+arg.Name+"`` argument.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You must specify a post check frequency to use when using the ``-"+arg.Name+"`` argument.")
 			}
 			freq, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid number.")
 			}
-			if freq < botData.BotOptions.FeedFrequency {
-				return NewErrorEmbed("Feed Error", "Frequency must not be lower than "+strconv.Itoa(botData.BotOptions.FeedFrequency)+" seconds.")
+			if freq < getBotData().BotOptions.FeedFrequency {
+				return NewErrorEmbed(env.Locale(), "Feed Error", "Frequency must not be lower than "+strconv.Itoa(getBotData().BotOptions.FeedFrequency)+" seconds.")
 			}
 			frequency = freq
 			//		case "all":
 			//			isAll = true
 		case "list":
 			if len(feedsToAdd) > 0 || len(feedsToEdit) > 0 || len(feedsToRemove) > 0 {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You cannot mix arguments dictating what to do!")
 			}
 			isListing = true
 		case "setchannel":
 			isSettingChannel = true
 		case "edit":
 			if len(feedsToAdd) > 0 || len(feedsToRemove) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You cannot mix arguments dictating what to do!")
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed entry to edit when using the ``-edit`` argument.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You must specify a feed entry to edit when using the ``-edit`` argument.")
 			}
 			entry, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid number.")
 			}
-			if entry > len(guildSettings[env.Guild.ID].Feeds) || entry <= 0 {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
+			if entry > len(guildSettings.Get(env.Guild.ID).Feeds) || entry <= 0 {
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
 			}
 
 			isEditing = true
 			feedsToEdit = append(feedsToEdit, entry)
 		case "remove":
 			if len(feedsToAdd) > 0 || len(feedsToEdit) > 0 || isListing {
-				return NewErrorEmbed("Feed Error", "You cannot mix arguments dictating what to do!")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You cannot mix arguments dictating what to do!")
 			}
 			if arg.Value == "" {
-				return NewErrorEmbed("Feed Error", "You must specify a feed entry to remove when using the ``-remove`` argument.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "You must specify a feed entry to remove when using the ``-remove`` argument.")
 			}
 			entry, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid number.")
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid number.")
 			}
-			if entry > len(guildSettings[env.Guild.ID].Feeds) || entry <= 0 {
-				return NewErrorEmbed("Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
+			if entry > len(guildSettings.Get(env.Guild.ID).Feeds) || entry <= 0 {
+				return NewErrorEmbed(env.Locale(), "Feed Error", "``"+arg.Value+"`` is not a valid feed entry.")
 			}
 
 			isRemoving = true
@@ -122,8 +122,8 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 	}
 
 	if isListing {
-		if len(guildSettings[env.Guild.ID].Feeds) == 0 {
-			return NewGenericEmbed("Feed", "There are no feed entries to list!")
+		if len(guildSettings.Get(env.Guild.ID).Feeds) == 0 {
+			return NewGenericEmbed(env.Locale(), "Feed", "There are no feed entries to list!")
 		}
 
 		feedListEmbed := NewEmbed().
@@ -131,7 +131,7 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 			SetDescription("A list of all available feed entries in this server.").
 			SetColor(0x1C1C1C)
 
-		for i, feedEntry := range guildSettings[env.Guild.ID].Feeds {
+		for i, feedEntry := range guildSettings.Get(env.Guild.ID).Feeds {
 			feedListEmbed.AddField("Entry #"+strconv.Itoa(i+1), "Channel: <#"+feedEntry.ChannelID+">\nFeed: "+feedEntry.FeedLink)
 		}
 
@@ -148,49 +148,56 @@ func commandFeed(args []CommandArgument, env *CommandEnvironment) *discordgo.Mes
 		}
 
 		if len(failedAdds) > 0 {
-			return NewGenericEmbed("Feed", "Some feed entries may have been added successfully, but the following feed entries failed to be processed: \n- "+strings.Join(failedAdds, "\n- "))
+			return NewGenericEmbed(env.Locale(), "Feed", "Some feed entries may have been added successfully, but the following feed entries failed to be processed: \n- "+strings.Join(failedAdds, "\n- "))
 		}
 
-		return NewGenericEmbed("Feed", "Successfully added the specified feed entries.")
+		return NewGenericEmbed(env.Locale(), "Feed", "Successfully added the specified feed entries.")
 	}
 	if isEditing {
-		for _, feedEntry := range feedsToEdit {
-			guildSettings[env.Guild.ID].Feeds[feedEntry-1].Frequency = frequency
-			if isSettingChannel {
-				guildSettings[env.Guild.ID].Feeds[feedEntry-1].ChannelID = env.Channel.ID
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			for _, feedEntry := range feedsToEdit {
+				if feedEntry > len(settings.Feeds) {
+					continue //Removed since it was checked
+				}
+				settings.Feeds[feedEntry-1].Frequency = frequency
+				if isSettingChannel {
+					settings.Feeds[feedEntry-1].ChannelID = env.Channel.ID
+				}
 			}
-		}
+		})
 
-		return NewGenericEmbed("Feed", "Successfully modified the specified feed entries.")
+		return NewGenericEmbed(env.Locale(), "Feed", "Successfully modified the specified feed entries.")
 	}
 	if isRemoving {
-		newFeeds := make([]*Feed, 0)
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			newFeeds := make([]*Feed, 0)
 
-		for i, feedEntry := range guildSettings[env.Guild.ID].Feeds {
-			removed := false
+			for i, feedEntry := range settings.Feeds {
+				removed := false
 
-			for _, removedEntry := range feedsToRemove {
-				if i == (removedEntry - 1) {
-					removed = true
-					break
+				for _, removedEntry := range feedsToRemove {
+					if i == (removedEntry - 1) {
+						removed = true
+						break
+					}
 				}
-			}
 
-			if removed == false {
-				newFeeds = append(newFeeds, feedEntry)
+				if removed == false {
+					newFeeds = append(newFeeds, feedEntry)
+				}
 			}
-		}
 
-		guildSettings[env.Guild.ID].Feeds = newFeeds
+			settings.Feeds = newFeeds
+		})
 
-		return NewGenericEmbed("Feed", "Successfully removed the specified feed entries.")
+		return NewGenericEmbed(env.Locale(), "Feed", "Successfully removed the specified feed entries.")
 	}
 
 	return nil
 }
 
 func addFeed(guildID, channelID, feedURL string, frequency int) error {
-	feed, err := botData.BotClients.FeedParser.ParseURL(feedURL)
+	feed, err := getBotData().BotClients.FeedParser.ParseURL(feedURL)
 	if err != nil {
 		return err
 	}
@@ -200,11 +207,15 @@ func addFeed(guildID, channelID, feedURL string, frequency int) error {
 	wrapFeed.FeedURL = feedURL
 	wrapFeed.Frequency = frequency
 
-	guildSettings[guildID].Feeds = append(guildSettings[guildID].Feeds, wrapFeed)
+	feedPointer := -1
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		settings.Feeds = append(settings.Feeds, wrapFeed)
+		feedPointer = len(settings.Feeds) - 1
+	})
 
 	waitDuration := time.Duration(frequency) * time.Second
 	time.AfterFunc(waitDuration, func() {
-		postFeed(guildID, len(guildSettings[guildID].Feeds)-1, wrapFeed.Title, frequency)
+		postFeed(guildID, feedPointer, wrapFeed.Title, frequency)
 	})
 
 	return nil
@@ -218,18 +229,15 @@ func addFeed(guildID, channelID, feedURL string, frequency int) error {
 // If the comparison fails, it means that the given feedPointer no longer points to its original feed as the original feed was removed.
 // In this case, the postFeed function will not be re-registered for a later call.
 func postFeed(guildID string, feedPointer int, feedTitle string, frequency int) {
-	if len(guildSettings[guildID].Feeds) == 0 {
-		return
-	}
-	if feedPointer < 0 {
-		return
-	}
-	if feedPointer >= len(guildSettings) {
-		return
-	}
-
-	feed := guildSettings[guildID].Feeds[feedPointer]
-	if feed.Title != feedTitle {
+	var current *Feed
+	var feed Feed //A copy, as the channel and frequency may be edited while posting
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		if feedPointer >= 0 && feedPointer < len(settings.Feeds) {
+			current = settings.Feeds[feedPointer]
+			feed = *current
+		}
+	})
+	if current == nil || feed.Title != feedTitle {
 		return
 	}
 
@@ -238,7 +246,7 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 		postFeed(guildID, feedPointer, feed.Title, frequency)
 	})
 
-	newFeed, err := botData.BotClients.FeedParser.ParseURL(feed.FeedURL)
+	newFeed, err := getBotData().BotClients.FeedParser.ParseURL(feed.FeedURL)
 	if err != nil {
 		return
 	}
@@ -273,7 +281,7 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 				AddField(post.Title, content).
 				SetFooter("Updated " + post.Updated).
 				SetColor(0x1C1C1C).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(feed.ChannelID, postEmbed)
+			getBotData().DiscordSession.ChannelMessageSendEmbed(feed.ChannelID, postEmbed)
 		}
 
 		wrapFeed := &Feed{Feed: newFeed}
@@ -281,6 +289,10 @@ func postFeed(guildID string, feedPointer int, feedTitle string, frequency int)
 		wrapFeed.FeedURL = feed.FeedURL
 		wrapFeed.Frequency = feed.Frequency
 
-		guildSettings[guildID].Feeds[feedPointer] = wrapFeed
+		guildSettings.Update(guildID, func(settings *GuildSettings) {
+			if feedPointer < len(settings.Feeds) && settings.Feeds[feedPointer] == current {
+				settings.Feeds[feedPointer] = wrapFeed
+			}
+		})
 	}
 }
@@ -14,7 +14,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 	switch args[0] {
 	case "trend", "trends", "trending":
 		if len(args) <= 1 {
-			return NewErrorEmbed("GitHub Error", "Not enough arguments. Type ``cli$help "+env.Command+"`` for command usage.")
+			return NewErrorEmbed(env.Locale(), "GitHub Error", "Not enough arguments. Type ``cli$help "+env.Command+"`` for command usage.")
 		}
 
 		time := ""
@@ -27,7 +27,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 			case "monthly", "month":
 				time = "monthly"
 			default:
-				return NewErrorEmbed("GitHub Error", "Invalid trending time ``"+args[2]+"``. Type ``cli$help "+env.Command+"`` for command usage.")
+				return NewErrorEmbed(env.Locale(), "GitHub Error", "Invalid trending time ``"+args[2]+"``. Type ``cli$help "+env.Command+"`` for command usage.")
 			}
 		}
 
@@ -40,7 +40,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		case "repo", "repos", "repository", "repositories":
 			projects, err := trending.NewTrending().GetProjects(time, language)
 			if err != nil {
-				return NewErrorEmbed("GitHub Error", "There was an error fetching the trending repositories.")
+				return NewErrorEmbed(env.Locale(), "GitHub Error", "There was an error fetching the trending repositories.")
 			}
 
 			trendingEmbed := NewEmbed().
@@ -62,7 +62,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		case "user", "users":
 			developers, err := trending.NewTrending().GetDevelopers(time, language)
 			if err != nil {
-				return NewErrorEmbed("GitHub Error", "There was an error fetching the trending developers.")
+				return NewErrorEmbed(env.Locale(), "GitHub Error", "There was an error fetching the trending developers.")
 			}
 
 			trendingEmbed := NewEmbed().
@@ -76,7 +76,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 			return trendingEmbed.MessageEmbed
 		default:
-			return NewErrorEmbed("GitHub Error", "Invalid trending type ``"+args[1]+"``. Type ``cli$help "+env.Command+"`` for command usage.")
+			return NewErrorEmbed(env.Locale(), "GitHub Error", "Invalid trending type ``"+args[1]+"``. Type ``cli$help "+env.Command+"`` for command usage.")
 		}
 	default:
 		request := strings.Split(args[0], "/")
@@ -85,7 +85,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		case 1: //Only user was specified
 			user, err := GitHubFetchUser(request[0])
 			if err != nil {
-				return NewErrorEmbed("GitHub Error", "There was an error fetching information about the specified user.")
+				return NewErrorEmbed(env.Locale(), "GitHub Error", "There was an error fetching information about the specified user.")
 			}
 
 			fields := []*discordgo.MessageEmbedField{}
@@ -125,7 +125,7 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 		case 2: //Repo was specified
 			repo, err := GitHubFetchRepo(request[0], request[1])
 			if err != nil {
-				return NewErrorEmbed("GitHub Error", "There was an error fetching information about the specified repo.")
+				return NewErrorEmbed(env.Locale(), "GitHub Error", "There was an error fetching information about the specified repo.")
 			}
 
 			fields := []*discordgo.MessageEmbedField{}
@@ -163,19 +163,19 @@ func commandGitHub(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 			return responseEmbed.MessageEmbed
 		}
 
-		return NewErrorEmbed("GitHub Error", "Not enough arguments. Type ``cli$help "+env.Command+"`` for command usage.")
+		return NewErrorEmbed(env.Locale(), "GitHub Error", "Not enough arguments. Type ``cli$help "+env.Command+"`` for command usage.")
 	}
 }
 
 func GitHubFetchUser(username string) (*github.User, error) {
-	user, _, err := botData.BotClients.GitHub.Users.Get(context.Background(), username)
+	user, _, err := getBotData().BotClients.GitHub.Users.Get(context.Background(), username)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
 func GitHubFetchRepo(owner string, repository string) (*github.Repository, error) {
-	repo, _, err := botData.BotClients.GitHub.Repositories.Get(context.Background(), owner, repository)
+	repo, _, err := getBotData().BotClients.GitHub.Repositories.Get(context.Background(), owner, repository)
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -15,24 +17,61 @@ import (
 	"github.com/go-playground/colors"
 )
 
-func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo.MessageEmbed {
+// fetchPipedImage downloads and decodes an image piped in from the previous command
+func fetchPipedImage(ctx context.Context, imageURL string) (image.Image, error) {
+	resp, err := httpGetContext(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the server responded with %s", resp.Status)
+	}
+	srcImage, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, errors.New("it isn't an image")
+	}
+	return srcImage, nil
+}
+
+func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *CommandResponse {
 	images := make([]image.Image, 0)
 
-	if len(env.Message.Attachments) > 0 {
+	if env.Input != nil {
+		//Images piped in from the previous command take priority over anything in the channel
+		for i, file := range env.Input.Files {
+			srcImage, _, err := image.Decode(file.Reader)
+			if err != nil {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to decode piped file %d as an image.", i+1))
+			}
+			images = append(images, srcImage)
+		}
+		for i, srcImageURL := range env.Input.ImageURLs {
+			srcImage, err := fetchPipedImage(env.Context, srcImageURL)
+			if err != nil {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to fetch piped image %d: %s.", i+1, err.Error()))
+			}
+			images = append(images, srcImage)
+		}
+		if len(images) == 0 {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "The piped output didn't contain any images."))
+		}
+	} else if len(env.Message.Attachments) > 0 {
 		for i, attachment := range env.Message.Attachments {
 			srcImageURL := attachment.URL
-			srcImageHTTP, err := http.Get(srcImageURL)
+			srcImageHTTP, err := httpGetContext(env.Context, srcImageURL)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to fetch attachment %d.", i+1)
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to fetch attachment %d.", i+1))
 			}
 			srcImage, _, err := image.Decode(srcImageHTTP.Body)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to decode attachment %d as an image.", i+1)
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to decode attachment %d as an image.", i+1))
 			}
 			images = append(images, srcImage)
 		}
 	} else {
-		channelMessages, err := botData.DiscordSession.ChannelMessages(env.Channel.ID, 100, "", "", "")
+		channelMessages, err := getBotData().DiscordSession.ChannelMessages(env.Channel.ID, 100, "", "", "")
 		if err == nil {
 			for i := 0; i < len(channelMessages); i++ {
 				if len(channelMessages[i].Embeds) > 0 {
@@ -40,7 +79,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 						if embed.Image != nil {
 							if embed.Image.URL != "" {
 								srcImageURL := embed.Image.URL
-								srcImageHTTP, err := http.Get(srcImageURL)
+								srcImageHTTP, err := httpGetContext(env.Context, srcImageURL)
 								if err != nil {
 									continue
 								}
@@ -56,7 +95,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				if len(channelMessages[i].Attachments) > 0 {
 					for _, attachment := range channelMessages[i].Attachments {
 						srcImageURL := attachment.URL
-						srcImageHTTP, err := http.Get(srcImageURL)
+						srcImageHTTP, err := httpGetContext(env.Context, srcImageURL)
 						if err != nil {
 							continue
 						}
@@ -75,6 +114,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 	}
 
 	if len(images) > 0 {
+		response := &CommandResponse{}
 		for i, srcImage := range images {
 			g := gift.New()
 			var outImage bytes.Buffer
@@ -91,7 +131,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "bg", "bgcolor", "bgcolour", "backgroundcolor", "backgroundcolour":
 					newBackgroundColor, err := colors.Parse(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid background color ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid background color ``"+effect.Value+"``."))
 					}
 					newBackgroundColorRGBA := newBackgroundColor.ToRGBA()
 					alpha := uint8(newBackgroundColorRGBA.A * 0xFF)
@@ -104,14 +144,14 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "brightness":
 					brightness, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid brightness percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid brightness percentage ``"+effect.Value+"``."))
 					}
 					brightness -= 100
 					g.Add(gift.Brightness(float32(brightness)))
 				case "contrast":
 					contrast, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid contrast percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid contrast percentage ``"+effect.Value+"``."))
 					}
 					contrast -= 100
 					g.Add(gift.Contrast(float32(contrast)))
@@ -122,19 +162,19 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "v", "vertical", "up", "down":
 						g.Add(gift.FlipVertical())
 					default:
-						return NewErrorEmbed("Image Error", "Invalid flip direction ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid flip direction ``"+effect.Value+"``."))
 					}
 				case "gamma":
 					gamma, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid gamma percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid gamma percentage ``"+effect.Value+"``."))
 					}
 					gamma /= 100
 					g.Add(gift.Gamma(float32(gamma)))
 				case "gaussian", "gaussianblur":
 					gaussian, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid gaussian blur percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid gaussian blur percentage ``"+effect.Value+"``."))
 					}
 					gaussian /= 100
 					g.Add(gift.GaussianBlur(float32(gaussian)))
@@ -143,7 +183,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "height":
 					newHeight, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid height integer ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid height integer ``"+effect.Value+"``."))
 					}
 					height = newHeight
 				case "interpolation":
@@ -155,14 +195,14 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "nn", "nearestneighbor", "nearestneighbour", "nearest":
 						interpolation = gift.NearestNeighborInterpolation
 					default:
-						return NewErrorEmbed("Image Error", "Invalid interpolation ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid interpolation ``"+effect.Value+"``."))
 					}
 				case "invert":
 					g.Add(gift.Invert())
 				case "pixelate":
 					pixelate, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid pixelation integer ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid pixelation integer ``"+effect.Value+"``."))
 					}
 					g.Add(gift.Pixelate(pixelate))
 				case "resampling":
@@ -178,25 +218,25 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 					case "nn", "nearestneighbor", "nearestneighbour", "nearest":
 						resampling = gift.NearestNeighborResampling
 					default:
-						return NewErrorEmbed("Image Error", "Invalid resampling ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid resampling ``"+effect.Value+"``."))
 					}
 				case "rotate":
 					angle, err := strconv.ParseFloat(effect.Value, 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid rotation angle ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid rotation angle ``"+effect.Value+"``."))
 					}
 					g.Add(gift.Rotate(float32(angle), backgroundColor, interpolation))
 				case "saturation":
 					saturation, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid saturation percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid saturation percentage ``"+effect.Value+"``."))
 					}
 					saturation -= 100
 					g.Add(gift.Saturation(float32(saturation)))
 				case "sepia":
 					sepia, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid sepia percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid sepia percentage ``"+effect.Value+"``."))
 					}
 					g.Add(gift.Sepia(float32(sepia)))
 				case "sobel":
@@ -204,7 +244,7 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "threshold":
 					threshold, err := strconv.ParseFloat(strings.TrimSuffix(effect.Value, "%"), 32)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid threshold percentage ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid threshold percentage ``"+effect.Value+"``."))
 					}
 					g.Add(gift.Threshold(float32(threshold)))
 				case "transpose":
@@ -214,11 +254,11 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 				case "width":
 					newWidth, err := strconv.Atoi(effect.Value)
 					if err != nil {
-						return NewErrorEmbed("Image Error", "Invalid width integer ``"+effect.Value+"``.")
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Invalid width integer ``"+effect.Value+"``."))
 					}
 					width = newWidth
 				default:
-					return NewErrorEmbed("Image Error", "Unknown effect ``"+effect.Name+"``.")
+					return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unknown effect ``"+effect.Name+"``."))
 				}
 			}
 
@@ -229,26 +269,22 @@ func commandImageAdv(args []CommandArgument, env *CommandEnvironment) *discordgo
 
 			err := png.Encode(&outImage, dstImage)
 			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to encode processed image.")
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to encode processed image."))
 			}
-			_, err = botData.DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
-				File: &discordgo.File{
-					Name:   "clinet-processed.png",
-					Reader: &outImage,
-				},
-				Embed: &discordgo.MessageEmbed{
-					Title: "Processed Image",
-					Image: &discordgo.MessageEmbedImage{
-						URL: "attachment://clinet-processed.png",
-					},
+			imageName := fmt.Sprintf("clinet-processed-%d.png", i+1)
+			response.Files = append(response.Files, &discordgo.File{
+				Name:   imageName,
+				Reader: &outImage,
+			})
+			response.Embeds = append(response.Embeds, &discordgo.MessageEmbed{
+				Title: "Processed Image",
+				Image: &discordgo.MessageEmbedImage{
+					URL: "attachment://" + imageName,
 				},
 			})
-			if err != nil {
-				return NewErrorEmbed("Image Error", "Unable to upload processed image %d.", i)
-			}
 		}
-		return nil
+		return response
 	}
 
-	return NewErrorEmbed("Image Error", "Unable to find any attached images or any images in the past 100 messages.")
+	return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Image Error", "Unable to find any attached images or any images in the past 100 messages."))
 }
@@ -10,13 +10,13 @@ import (
 func commandImgur(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	responseEmbed, err := queryImgur(args[0])
 	if err != nil {
-		return NewErrorEmbed("Imgur Error", "There was an error fetching information about the specified URL.")
+		return NewErrorEmbed(env.Locale(), "Imgur Error", "There was an error fetching information about the specified URL.")
 	}
 	return responseEmbed
 }
 
 func queryImgur(url string) (*discordgo.MessageEmbed, error) {
-	imgurInfo, _, err := botData.BotClients.Imgur.GetInfoFromURL(url)
+	imgurInfo, _, err := getBotData().BotClients.Imgur.GetInfoFromURL(url)
 	if err != nil {
 		debugLog("[Imgur] Error getting info from URL ["+url+"]", false)
 		return nil, errors.New("error getting info from URL")
@@ -11,51 +11,51 @@ import (
 )
 
 func commandBotInfo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	guildCount := len(botData.DiscordSession.State.Guilds)
+	guildCount := len(getBotData().DiscordSession.State.Guilds)
 	commandCount := 0
-	for _, command := range botData.Commands {
+	for _, command := range getBotData().Commands {
 		if command.IsAlternateOf == "" {
 			commandCount++
 		}
 	}
 
 	botEmbed := NewEmbed().
-		SetAuthor(botData.BotName, botData.DiscordSession.State.User.AvatarURL("2048")).
-		AddField("Bot Owner", "<@!"+botData.BotOwnerID+">").
+		SetAuthor(getBotData().BotName, getBotData().DiscordSession.State.User.AvatarURL("2048")).
+		AddField("Bot Owner", "<@!"+getBotData().BotOwnerID+">").
 		AddField("Guild Count", strconv.Itoa(guildCount)).
-		AddField("Default Prefix", botData.CommandPrefix).
+		AddField("Default Prefix", getBotData().CommandPrefix).
 		AddField("Command Count", strconv.Itoa(commandCount)).
 		AddField("Uptime", humanize.Time(uptime)).
-		AddField("Debug Mode", strconv.FormatBool(botData.DebugMode)).
+		AddField("Debug Mode", strconv.FormatBool(getBotData().DebugMode)).
 		InlineAllFields().
 		SetColor(0x1C1C1C)
 
 	enabledFeatures := make([]string, 0)
-	if botData.BotOptions.UseCustomResponses {
+	if getBotData().BotOptions.UseCustomResponses {
 		enabledFeatures = append(enabledFeatures, "Custom Responses")
 	}
-	if botData.BotOptions.UseDuckDuckGo {
+	if getBotData().BotOptions.UseDuckDuckGo {
 		enabledFeatures = append(enabledFeatures, "DuckDuckGo")
 	}
-	if botData.BotOptions.UseGitHub {
+	if getBotData().BotOptions.UseGitHub {
 		enabledFeatures = append(enabledFeatures, "GitHub")
 	}
-	if botData.BotOptions.UseImgur {
+	if getBotData().BotOptions.UseImgur {
 		enabledFeatures = append(enabledFeatures, "Imgur")
 	}
-	if botData.BotOptions.UseSoundCloud {
+	if getBotData().BotOptions.UseSoundCloud {
 		enabledFeatures = append(enabledFeatures, "SoundCloud")
 	}
-	if botData.BotOptions.UseSpotify {
+	if getBotData().BotOptions.UseSpotify {
 		enabledFeatures = append(enabledFeatures, "Spotify")
 	}
-	if botData.BotOptions.UseWolframAlpha {
+	if getBotData().BotOptions.UseWolframAlpha {
 		enabledFeatures = append(enabledFeatures, "Wolfram|Alpha")
 	}
-	if botData.BotOptions.UseXKCD {
+	if getBotData().BotOptions.UseXKCD {
 		enabledFeatures = append(enabledFeatures, "xkcd")
 	}
-	if botData.BotOptions.UseYouTube {
+	if getBotData().BotOptions.UseYouTube {
 		enabledFeatures = append(enabledFeatures, "YouTube")
 	}
 	if len(enabledFeatures) > 0 {
@@ -64,7 +64,7 @@ func commandBotInfo(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 	botEmbed.AddField("Reason for Downtime", DowntimeReason)
 
-	return botEmbed.MessageEmbed
+	return redactEmbed(botEmbed.MessageEmbed)
 }
 
 func commandServerInfo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -80,7 +80,7 @@ func commandServerInfo(args []string, env *CommandEnvironment) *discordgo.Messag
 
 	afkChannel := "None"
 	if env.Guild.AfkChannelID != "" {
-		channel, err := botData.DiscordSession.Channel(env.Guild.AfkChannelID)
+		channel, err := getBotData().DiscordSession.Channel(env.Guild.AfkChannelID)
 		if err == nil && channel.Type == discordgo.ChannelTypeGuildVoice {
 			afkChannel = ":speaker: " + channel.Name
 		}
@@ -123,37 +123,23 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 	user := env.User
 	member := env.Member
 	memberFound := true
-	if len(env.Message.Mentions) > 0 {
-		user = env.Message.Mentions[0]
+	if env.Arguments.Has("user") {
+		user = env.Arguments.User("user")
 
-		memberMention, err := botData.DiscordSession.GuildMember(env.Guild.ID, user.ID)
-		if err != nil {
-			memberFound = false
-		}
-		member = memberMention
-	} else if len(args) > 0 {
-		mention := args[0]
-
-		userMention, err := botData.DiscordSession.User(mention)
-		if err != nil {
-			return NewErrorEmbed("User Info Error", "Invalid user ``"+mention+"``.")
-		}
-		user = userMention
-
-		memberMention, err := botData.DiscordSession.GuildMember(env.Guild.ID, user.ID)
+		memberMention, err := getBotData().DiscordSession.GuildMember(env.Guild.ID, user.ID)
 		if err != nil {
 			memberFound = false
 		}
 		member = memberMention
 	}
 
-	timezone := userSettings[env.User.ID].Timezone
+	timezone := userSettings.Get(env.User.ID).Timezone
 	if timezone == "" {
-		return NewErrorEmbed("User Info Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "User Info Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("User Info Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "User Info Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 
 	creationDate := ""
@@ -192,7 +178,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 		if len(member.Roles) > 0 {
 			roles := make([]string, 0)
 			for _, roleID := range member.Roles {
-				role, err := botData.DiscordSession.State.Role(env.Guild.ID, roleID)
+				role, err := getBotData().DiscordSession.State.Role(env.Guild.ID, roleID)
 				if err == nil {
 					roles = append(roles, role.Name)
 				}
@@ -202,7 +188,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 	}
 
 	if memberFound {
-		presence, err := botData.DiscordSession.State.Presence(env.Guild.ID, user.ID)
+		presence, err := getBotData().DiscordSession.State.Presence(env.Guild.ID, user.ID)
 		if err == nil {
 			status := ""
 			switch presence.Status {
@@ -252,7 +238,7 @@ func commandUserInfo(args []string, env *CommandEnvironment) *discordgo.MessageE
 		}
 	}
 
-	if userSettings, found := userSettings[user.ID]; found {
+	if userSettings, found := userSettings.Load(user.ID); found {
 		if userSettings.AboutMe != "" {
 			userInfoEmbed.AddField("About Me", userSettings.AboutMe)
 		}
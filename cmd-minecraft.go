@@ -93,13 +93,13 @@ func GetAPIUsernameHistory(mc *minecraft.Minecraft, uuid string) ([]MCUsernameHi
 }
 
 func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	timezone := userSettings[env.User.ID].Timezone
+	timezone := userSettings.Get(env.User.ID).Timezone
 	if timezone == "" {
-		return NewErrorEmbed("Minecraft Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "Minecraft Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("Minecraft Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "Minecraft Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 
 	switch args[0] {
@@ -110,7 +110,7 @@ func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.Message
 		if err != nil {
 			oldProfileAPI, err := GetAPIOldProfile(minecraftAPI, args[1])
 			if err != nil {
-				return NewErrorEmbed("Minecraft Error", "Invalid or unknown username ``"+args[1]+"``.")
+				return NewErrorEmbed(env.Locale(), "Minecraft Error", "Invalid or unknown username ``"+args[1]+"``.")
 			}
 			profileAPI = *oldProfileAPI
 		}
@@ -163,7 +163,7 @@ func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.Message
 
 		server, err := minepong.Ping(host)
 		if err != nil {
-			return NewErrorEmbed("Minecraft Error", "Invalid or unknown server ``"+args[1]+"``.")
+			return NewErrorEmbed(env.Locale(), "Minecraft Error", "Invalid or unknown server ``"+args[1]+"``.")
 		}
 
 		title := "Minecraft - " + args[1]
@@ -191,7 +191,7 @@ func commandMinecraft(args []string, env *CommandEnvironment) *discordgo.Message
 		return minecraftEmbed.MessageEmbed
 	}
 
-	return NewErrorEmbed("Minecraft Error", "Unknown command ``"+args[1]+"``.")
+	return NewErrorEmbed(env.Locale(), "Minecraft Error", "Unknown command ``"+args[1]+"``.")
 }
 
 func mcFormat(desc interface{}) string {
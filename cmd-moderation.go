@@ -9,140 +9,98 @@ import (
 
 //Moderator commands
 func commandPurge(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	amount, err := strconv.Atoi(args[0])
-	if err != nil {
-		return NewErrorEmbed("Purge Error", "``"+args[0]+"`` is not a valid number.")
-	}
-	if amount <= 0 || amount > 100 {
-		return NewErrorEmbed("Purge Error", "Amount of messages to purge must be between 1 and 100.")
-	}
+	amount := env.Arguments.Int("message count")
 
-	messages, err := botData.DiscordSession.ChannelMessages(env.Channel.ID, amount, env.Message.ID, "", "")
+	messages, err := getBotData().DiscordSession.ChannelMessages(env.Channel.ID, amount, env.Message.ID, "", "")
 	if err != nil {
-		return NewErrorEmbed("Purge Error", "An error occurred fetching the last "+args[0]+" messages.")
+		return NewErrorEmbed(env.Locale(), "Purge Error", "An error occurred fetching the last "+strconv.Itoa(amount)+" messages.")
 	}
 
 	messageIDs := make([]string, 0)
 
-	if len(args) > 1 {
+	if users := env.Arguments.Users("user(s)"); len(users) > 0 {
 		for i := 0; i < len(messages); i++ {
-			for j := 0; j < len(env.Message.Mentions); j++ {
-				if env.Message.Mentions[j].ID == messages[i].Author.ID {
+			for j := 0; j < len(users); j++ {
+				if users[j].ID == messages[i].Author.ID {
 					messageIDs = append(messageIDs, messages[i].ID)
 					break
 				}
 			}
 		}
 
-		err = botData.DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
+		err = getBotData().DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
 		if err != nil {
-			return NewErrorEmbed("Purge Error", "An error occurred deleting the last "+args[0]+" messages from the specified user(s).")
+			return NewErrorEmbed(env.Locale(), "Purge Error", "An error occurred deleting the last "+strconv.Itoa(amount)+" messages from the specified user(s).")
 		}
 
-		return NewGenericEmbed("Purge", "Successfully purged the last "+args[0]+" messages from the specified user(s).")
+		return NewGenericEmbed(env.Locale(), "Purge", env.Locale().N("Successfully purged the last message from the specified user(s).", "Successfully purged the last %d messages from the specified user(s).", amount, amount))
 	}
 
 	for i := 0; i < len(messages); i++ {
 		messageIDs = append(messageIDs, messages[i].ID)
 	}
 
-	err = botData.DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
+	err = getBotData().DiscordSession.ChannelMessagesBulkDelete(env.Channel.ID, messageIDs)
 	if err != nil {
-		return NewErrorEmbed("Purge Error", "An error occurred deleting the last "+args[0]+" messages.")
+		return NewErrorEmbed(env.Locale(), "Purge Error", "An error occurred deleting the last "+strconv.Itoa(amount)+" messages.")
 	}
 
-	return NewGenericEmbed("Purge", "Successfully purged the last "+args[0]+" messages.")
+	return NewGenericEmbed(env.Locale(), "Purge", env.Locale().N("Successfully purged the last message.", "Successfully purged the last %d messages.", amount, amount))
 }
 func commandKick(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if len(env.Message.Mentions) == 0 {
-		NewErrorEmbed("Kick Error", "You must specify which user(s) to kick from the server.")
-	}
-
-	reasonMessage := ""
+	reasonMessage := env.Arguments.String("reason")
 	usersToKick := make([]string, 0)
-	for i, part := range args {
-		if strings.HasPrefix(part, "<@") && strings.HasSuffix(part, ">") {
-			if strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">") == env.User.ID {
-				return NewErrorEmbed("Kick Error", "You can't kick yourself!")
-			}
-			usersToKick = append(usersToKick, strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">"))
-			continue
+	for _, user := range env.Arguments.Users("user(s)") {
+		if user.ID == env.User.ID {
+			return NewErrorEmbed(env.Locale(), "Kick Error", "You can't kick yourself!")
 		}
-		reasonMessage = strings.Join(args[i:], " ")
-		break
-	}
-	if len(usersToKick) == 0 {
-		return NewErrorEmbed("Kick Error", "You must specify which user(s) to kick.")
+		usersToKick = append(usersToKick, user.ID)
 	}
 
 	if reasonMessage == "" {
 		for i := range usersToKick {
-			err := botData.DiscordSession.GuildMemberDelete(env.Guild.ID, usersToKick[i])
+			err := getBotData().DiscordSession.GuildMemberDelete(env.Guild.ID, usersToKick[i])
 			if err != nil {
-				return NewErrorEmbed("Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
+				return NewErrorEmbed(env.Locale(), "Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
 			}
 		}
-		return NewGenericEmbed("Kick", "Successfully kicked the selected user(s).")
+		return NewGenericEmbed(env.Locale(), "Kick", "Successfully kicked the selected user(s).")
 	}
 	for i := range usersToKick {
-		err := botData.DiscordSession.GuildMemberDeleteWithReason(env.Guild.ID, usersToKick[i], reasonMessage)
+		err := getBotData().DiscordSession.GuildMemberDeleteWithReason(env.Guild.ID, usersToKick[i], reasonMessage)
 		if err != nil {
-			return NewErrorEmbed("Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
+			return NewErrorEmbed(env.Locale(), "Kick Error", "An error occurred kicking <@"+usersToKick[i]+">. Please consider manually kicking and report this issue to a developer.")
 		}
 	}
-	return NewGenericEmbed("Kick", "Successfully kicked the selected user(s) for the following reason:\n**"+reasonMessage+"**")
+	return NewGenericEmbed(env.Locale(), "Kick", "Successfully kicked the selected user(s) for the following reason:\n**"+reasonMessage+"**")
 }
 func commandBan(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if len(env.Message.Mentions) == 0 {
-		return NewErrorEmbed("Ban Error", "You must specify which user(s) to ban from the server.")
-	}
-
-	reasonMessage := ""
+	reasonMessage := env.Arguments.String("reason")
+	messagesDaysToDelete := env.Arguments.Int("days")
 	usersToBan := make([]string, 0)
-	messagesDaysToDelete := 0
-	for i, part := range args {
-		if i == 0 {
-			days, err := strconv.Atoi(part)
-			if err == nil {
-				messagesDaysToDelete = days
-				continue
-			} else {
-				return NewErrorEmbed("Ban Error", "``"+part+"`` is not a valid number.")
-			}
+	for _, user := range env.Arguments.Users("user(s)") {
+		if user.ID == env.User.ID {
+			return NewErrorEmbed(env.Locale(), "Ban Error", "You can't ban yourself!")
 		}
-		if strings.HasPrefix(part, "<@") && strings.HasSuffix(part, ">") {
-			if strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">") == env.User.ID {
-				return NewErrorEmbed("Kick Error", "You can't kick yourself!")
-			}
-			usersToBan = append(usersToBan, strings.TrimRight(strings.TrimLeft(strings.TrimLeft(part, "<@"), "!"), ">"))
-			continue
-		}
-		reasonMessage = strings.Join(args[i:], " ")
-		break
-	}
-	if len(usersToBan) == 0 {
-		return NewErrorEmbed("Ban Error", "You must specify which user(s) to ban.")
-	}
-	if messagesDaysToDelete > 7 {
-		return NewErrorEmbed("Ban Error", "You may only delete up to and including 7 days worth of messages from these users.")
+		usersToBan = append(usersToBan, user.ID)
 	}
 
 	if reasonMessage == "" {
 		for i := range usersToBan {
-			err := botData.DiscordSession.GuildBanCreate(env.Guild.ID, usersToBan[i], messagesDaysToDelete)
+			err := getBotData().DiscordSession.GuildBanCreate(env.Guild.ID, usersToBan[i], messagesDaysToDelete)
 			if err != nil {
-				return NewErrorEmbed("Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
+				return NewErrorEmbed(env.Locale(), "Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
 			}
 		}
-		return NewGenericEmbed("Ban", "Successfully banned the selected user(s).")
+		return NewGenericEmbed(env.Locale(), "Ban", "Successfully banned the selected user(s).")
 	}
 	for i := range usersToBan {
-		err := botData.DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
+		err := getBotData().DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
 		if err != nil {
-			return NewErrorEmbed("Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
+			return NewErrorEmbed(env.Locale(), "Ban Error", "An error occurred banning <@"+usersToBan[i]+">. Please consider manually banning and report this issue to a developer.")
 		}
 	}
-	return NewGenericEmbed("Ban", "Successfully banned the selected user(s) for the following reason:\n**"+reasonMessage+"**")
+	return NewGenericEmbed(env.Locale(), "Ban", "Successfully banned the selected user(s) for the following reason:\n**"+reasonMessage+"**")
 }
 func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.MessageEmbed {
 	reasonMessage := ""
@@ -153,28 +111,28 @@ func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.
 		switch args[i].Name {
 		case "days":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify how many days of messages to delete if you use the ``-days`` argument.")
+				return NewErrorEmbed(env.Locale(), "HackBan Error", "You must specify how many days of messages to delete if you use the ``-days`` argument.")
 			}
 			days, err := strconv.Atoi(args[i].Value)
 			if err != nil {
-				return NewErrorEmbed("HackBan Error", "Invalid days ``"+args[i].Value+"``.")
+				return NewErrorEmbed(env.Locale(), "HackBan Error", "Invalid days ``"+args[i].Value+"``.")
 			}
 			messagesDaysToDelete = days
 		case "id":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify the ID of the user to hackban if you use the ``-id`` argument.")
+				return NewErrorEmbed(env.Locale(), "HackBan Error", "You must specify the ID of the user to hackban if you use the ``-id`` argument.")
 			}
 			usersToBan = append(usersToBan, args[i].Value)
 		case "reason":
 			if args[i].Value == "" {
-				return NewErrorEmbed("HackBan Error", "You must specify the reason for hackbanning if you use the ``-reason`` argument.")
+				return NewErrorEmbed(env.Locale(), "HackBan Error", "You must specify the reason for hackbanning if you use the ``-reason`` argument.")
 			}
 			reasonMessage = args[i].Value
 		}
 	}
 
 	if len(usersToBan) == 0 {
-		return NewErrorEmbed("HackBan Error", "You must specify which user IDs to hackban.")
+		return NewErrorEmbed(env.Locale(), "HackBan Error", "You must specify which user IDs to hackban.")
 	}
 
 	if reasonMessage == "" {
@@ -183,17 +141,17 @@ func commandHackBan(args []CommandArgument, env *CommandEnvironment) *discordgo.
 
 	failedBans := make([]string, 0)
 	for i := range usersToBan {
-		err := botData.DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
+		err := getBotData().DiscordSession.GuildBanCreateWithReason(env.Guild.ID, usersToBan[i], reasonMessage, messagesDaysToDelete)
 		if err != nil {
 			failedBans = append(failedBans, usersToBan[i])
 		}
 	}
 
 	if len(failedBans) == len(usersToBan) {
-		return NewErrorEmbed("HackBan Error", "There was an error hackbanning the selected user ID(s).")
+		return NewErrorEmbed(env.Locale(), "HackBan Error", "There was an error hackbanning the selected user ID(s).")
 	}
 	if len(failedBans) < len(usersToBan) && len(failedBans) > 0 {
-		return NewErrorEmbed("HackBan Error", "There was an error hackbanning "+strconv.Itoa(len(failedBans))+" of the selected user ID(s):\n\n- "+strings.Join(failedBans, "\n- "))
+		return NewErrorEmbed(env.Locale(), "HackBan Error", "There was an error hackbanning "+strconv.Itoa(len(failedBans))+" of the selected user ID(s):\n\n- "+strings.Join(failedBans, "\n- "))
 	}
-	return NewGenericEmbed("HackBan", "Successfully hackbanned the selected user ID(s).")
+	return NewGenericEmbed(env.Locale(), "HackBan", "Successfully hackbanned the selected user ID(s).")
 }
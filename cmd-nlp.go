@@ -59,7 +59,7 @@ var posMap = map[string]string{
 func commandNLP(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	document, err := prose.NewDocument(strings.Join(args, " "))
 	if err != nil {
-		return NewErrorEmbed("Natural Language Processing - Error", "There was an error creating a document of your message.")
+		return NewErrorEmbed(env.Locale(), "Natural Language Processing - Error", "There was an error creating a document of your message.")
 	}
 
 	tokens := ""
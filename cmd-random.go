@@ -9,7 +9,6 @@ import (
 	"image/png"
 	"math/rand"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -20,21 +19,21 @@ import (
 
 func commandRoll(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	random := rand.Intn(6) + 1
-	return NewGenericEmbed("Roll", "You rolled a "+strconv.Itoa(random)+"!")
+	return NewGenericEmbed(env.Locale(), "Roll", "You rolled a "+strconv.Itoa(random)+"!")
 }
 func commandDoubleRoll(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	random1 := rand.Intn(6) + 1
 	random2 := rand.Intn(6) + 1
 	randomTotal := random1 + random2
-	return NewGenericEmbed("Double Roll", "You rolled a "+strconv.Itoa(random1)+" and a "+strconv.Itoa(random2)+". The total is "+strconv.Itoa(randomTotal)+"!")
+	return NewGenericEmbed(env.Locale(), "Double Roll", "You rolled a "+strconv.Itoa(random1)+" and a "+strconv.Itoa(random2)+". The total is "+strconv.Itoa(randomTotal)+"!")
 }
 func commandCoinFlip(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	random := rand.Intn(2)
 	switch random {
 	case 0:
-		return NewGenericEmbed("Coin Flip", "The coin landed on heads!")
+		return NewGenericEmbed(env.Locale(), "Coin Flip", "The coin landed on heads!")
 	case 1:
-		return NewGenericEmbed("Coin Flip", "The coin landed on tails!")
+		return NewGenericEmbed(env.Locale(), "Coin Flip", "The coin landed on tails!")
 	}
 	return nil
 }
@@ -47,7 +46,7 @@ func commandHewwo(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 	message = strings.Replace(message, "R", "W", -1)
 	message = strings.Replace(message, "r", "w", -1)
 
-	return NewGenericEmbed("Hewwo", message)
+	return NewGenericEmbed(env.Locale(), "Hewwo", message)
 }
 
 func commandZalgo(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -68,10 +67,10 @@ func commandZalgo(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 	fmt.Fprint(writer, []byte(strings.Join(args, " ")))
 	zalgo := buf.String()
 
-	return NewGenericEmbed("Zalgo", string(zalgo))
+	return NewGenericEmbed(env.Locale(), "Zalgo", string(zalgo))
 }
 
-func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+func commandScreenshot(args []string, env *CommandEnvironment) *CommandResponse {
 	if env.UpdatedMessageEvent {
 		return nil
 	}
@@ -81,24 +80,17 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 		Timeout: timeout,
 	}
 
-	siteURL, err := url.Parse(args[0])
-	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unknown address format ``"+args[0]+"``.")
-	}
-	if siteURL.Scheme == "" {
-		siteURL.Scheme = "http" //By standard, SSL-enabled sites should automatically redirect to https if needed
-	}
-	website := siteURL.String()
+	website := env.Arguments.URL("url").String()
 
 	req, err := http.NewRequest("GET", fmt.Sprintf("https://image.thum.io/get/maxAge/0/width/2000/noanimate/fullpage/%s", website), nil)
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable.")
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable."))
 	}
 	req.Header.Set("User-Agent", "Clinet/"+GitCommitFull)
 
-	resp, err := client.Do(req)
+	resp, err := client.Do(req.WithContext(env.Context))
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable.")
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "The website ``"+args[0]+"`` does not exist or is currently unreachable."))
 	}
 
 	var screenshotImage image.Image
@@ -107,23 +99,23 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 	case "image/gif":
 		gifAnim, err := gif.DecodeAll(resp.Body)
 		if err != nil {
-			return NewErrorEmbed("Screenshot Error", "The API failed to respond with a valid screenshot.")
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "The API failed to respond with a valid screenshot."))
 		}
 		screenshotImage = gifAnim.Image[len(gifAnim.Image)-1]
 	case "image/png", "image/jpeg":
 		srcImage, _, err := image.Decode(resp.Body)
 		if err != nil {
-			return NewErrorEmbed("Screenshot Error", "The API failed to respond with a valid screenshot.")
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "The API failed to respond with a valid screenshot."))
 		}
 		screenshotImage = srcImage
 	default:
-		return NewErrorEmbed("Screenshot Error", "The API failed to respond in an expected way.")
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "The API failed to respond in an expected way."))
 	}
 
 	var outImage bytes.Buffer
 	err = png.Encode(&outImage, screenshotImage)
 	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unexpected error processing screenshot.")
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Screenshot Error", "Unexpected error processing screenshot."))
 	}
 
 	imageName := website
@@ -131,21 +123,21 @@ func commandScreenshot(args []string, env *CommandEnvironment) *discordgo.Messag
 	imageName = strings.Replace(imageName, "/", "_", -1)
 	imageName += fmt.Sprintf("-%d", time.Now().Unix())
 	imageName = "clinet-screenshot_" + imageName + ".png"
-	_, err = botData.DiscordSession.ChannelMessageSendComplex(env.Channel.ID, &discordgo.MessageSend{
-		File: &discordgo.File{
-			Name:   imageName,
-			Reader: &outImage,
+	return &CommandResponse{
+		Files: []*discordgo.File{
+			{
+				Name:   imageName,
+				Reader: &outImage,
+			},
 		},
-		Embed: &discordgo.MessageEmbed{
-			Title:       "Screenshot",
-			Description: website,
-			Image: &discordgo.MessageEmbedImage{
-				URL: "attachment://" + imageName,
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       "Screenshot",
+				Description: website,
+				Image: &discordgo.MessageEmbedImage{
+					URL: "attachment://" + imageName,
+				},
 			},
 		},
-	})
-	if err != nil {
-		return NewErrorEmbed("Screenshot Error", "Unexpected error uploading screenshot.")
 	}
-	return nil
 }
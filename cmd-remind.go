@@ -12,6 +12,18 @@ import (
 	"github.com/olebedev/when"
 )
 
+var (
+	// remindListArguments contains the values taken by remind list
+	remindListArguments = []CommandArgument{
+		{Name: "page", Description: "The page of remind entries to view", ArgType: ArgTypeNumber, Optional: true, Min: 1},
+	}
+
+	// remindRemoveArguments contains the values taken by remind remove
+	remindRemoveArguments = []CommandArgument{
+		{Name: "entries", Description: "The numbers of the remind entries to remove", ArgType: ArgTypeNumber, Multiple: true, Min: 1},
+	}
+)
+
 // RemindEntry stores information about a remind entry
 type RemindEntry struct {
 	UserID    string    `json:"userID"`
@@ -23,28 +35,28 @@ type RemindEntry struct {
 }
 
 func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	timezone := userSettings[env.User.ID].Timezone
+	timezone := userSettings.Get(env.User.ID).Timezone
 	if timezone == "" {
-		return NewErrorEmbed("Remind Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "Remind Error", "Please set a timezone first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 	location, err := tz.LoadLocation(timezone)
 	if err != nil {
-		return NewErrorEmbed("Remind Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+		return NewErrorEmbed(env.Locale(), "Remind Error", "You have an invalid timezone set, please set a new one first!\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 	}
 
 	switch args[0] {
 	case "list":
+		parsed, usageEmbed := parseSubcommandArguments(remindListArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
 		pageNumber := 1
-		if len(args) == 2 {
-			page, err := strconv.Atoi(args[1])
-			if err != nil {
-				return NewErrorEmbed("Remind Error", "Invalid page number ``"+args[0]+"``.")
-			}
-			pageNumber = page
+		if parsed.Has("page") {
+			pageNumber = parsed.Int("page")
 		}
 
 		remindList := make([]*discordgo.MessageEmbedField, 0)
-		for _, entry := range remindEntries {
+		for _, entry := range remindEntries.All() {
 			if entry.UserID == env.User.ID {
 				remindList = append(remindList, &discordgo.MessageEmbedField{
 					Name:  "Entry #" + strconv.Itoa(len(remindList)+1) + " - " + entry.When.String(),
@@ -55,16 +67,22 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 		remindListEmbed, totalPages, err := page(remindList, pageNumber, 10)
 		if totalPages == 0 {
-			return NewGenericEmbed("Remind", "No remind entries were found.")
+			return NewGenericEmbed(env.Locale(), "Remind", "No remind entries were found.")
 		}
 		if err != nil {
-			return NewErrorEmbed("Remind Error", "Invalid page number ``"+strconv.Itoa(pageNumber)+"``.")
+			return NewErrorEmbed(env.Locale(), "Remind Error", "Invalid page number ``"+strconv.Itoa(pageNumber)+"``.")
 		}
 
 		return remindListEmbed.SetTitle("Remind List - Page " + strconv.Itoa(pageNumber) + "/" + strconv.Itoa(totalPages)).MessageEmbed
 	case "delete", "remove":
+		parsed, usageEmbed := parseSubcommandArguments(remindRemoveArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
+		removedNumbers := parsed.Ints("entries")
+
 		remindList := make([]RemindEntry, 0)
-		for _, entry := range remindEntries {
+		for _, entry := range remindEntries.All() {
 			if entry.UserID == env.User.ID {
 				remindList = append(remindList, entry)
 			}
@@ -72,25 +90,17 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 		debugLog(fmt.Sprintf("%v", remindList), true)
 
-		for _, remindEntry := range args[1:] {
-			remindEntryNumber, err := strconv.Atoi(remindEntry)
-			if err != nil {
-				return NewErrorEmbed("Remind Error", "``"+remindEntry+"`` is not a valid number.")
-			}
-			remindEntryNumber--
-
-			if remindEntryNumber >= len(remindList) || remindEntryNumber < 0 {
-				return NewErrorEmbed("Remind Error", "``"+remindEntry+"`` is not a valid remind entry.")
+		for _, remindEntryNumber := range removedNumbers {
+			if remindEntryNumber > len(remindList) {
+				return NewErrorEmbed(env.Locale(), "Remind Error", "``"+strconv.Itoa(remindEntryNumber)+"`` is not a valid remind entry.")
 			}
 		}
 
 		var newRemindList []RemindEntry
 		for remindEntryN, remindEntry := range remindList {
 			keepRemindEntry := true
-			for _, removedRemindEntry := range args[1:] {
-				removedRemindEntryNumber, _ := strconv.Atoi(removedRemindEntry)
-				removedRemindEntryNumber--
-				if remindEntryN == removedRemindEntryNumber {
+			for _, removedRemindEntryNumber := range removedNumbers {
+				if remindEntryN == removedRemindEntryNumber-1 {
 					keepRemindEntry = false
 					break
 				}
@@ -102,32 +112,24 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 		debugLog(fmt.Sprintf("%v", newRemindList), true)
 
-		var newRemindEntries []RemindEntry
-		for _, remindEntry := range remindEntries {
+		removed := remindEntries.Remove(func(remindEntry RemindEntry) bool {
 			if remindEntry.UserID != env.User.ID {
-				newRemindEntries = append(newRemindEntries, remindEntry)
-				continue
+				return false
 			}
-			keepRemindEntry := false
 			for _, remindEntryKeep := range newRemindList {
 				if remindEntry.ChannelID == remindEntryKeep.ChannelID && remindEntry.Message == remindEntryKeep.Message {
-					keepRemindEntry = true
-					break
+					return false
 				}
 			}
-			if keepRemindEntry {
-				newRemindEntries = append(newRemindEntries, remindEntry)
-			}
-		}
-
-		debugLog(fmt.Sprintf("%v", newRemindEntries), true)
+			return true
+		})
 
-		remindEntries = newRemindEntries
+		debugLog(fmt.Sprintf("Removed %d remind entries", removed), true)
 
-		if len(args) > 2 {
-			return NewGenericEmbed("Remind", "Successfully removed the specified remind entries.")
+		if len(removedNumbers) > 1 {
+			return NewGenericEmbed(env.Locale(), "Remind", "Successfully removed the specified remind entries.")
 		}
-		return NewGenericEmbed("Remind", "Successfully removed the specified remind entry.")
+		return NewGenericEmbed(env.Locale(), "Remind", "Successfully removed the specified remind entry.")
 	}
 
 	w := when.EN
@@ -136,15 +138,20 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 
 	r, err := w.Parse(text, now)
 	if err != nil || r == nil {
-		return NewErrorEmbed("Remind Error", "There was an error figuring out what time to remind you with this message at.")
+		return NewErrorEmbed(env.Locale(), "Remind Error", "There was an error figuring out what time to remind you with this message at.")
 	}
 
 	waitDuration := r.Time.In(location).Sub(now)
 	if waitDuration < 0 {
-		return NewErrorEmbed("Remind Error", "That time was "+humanize.Time(r.Time.In(location))+"!")
+		return NewErrorEmbed(env.Locale(), "Remind Error", "That time was "+humanize.Time(r.Time.In(location))+"!")
 	}
 
-	defer remindWhen(env.User.ID, env.Guild.ID, env.Channel.ID, text, now.In(location), r.Time.In(location), now.In(location))
+	guildID := ""
+	if env.Guild != nil {
+		guildID = env.Guild.ID
+	}
+
+	defer remindWhen(env.User.ID, guildID, env.Channel.ID, text, now.In(location), r.Time.In(location), now.In(location))
 
 	return NewEmbed().
 		SetTitle("Remind").
@@ -154,11 +161,11 @@ func commandRemind(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 }
 
 func remindWhen(userID, guildID, channelID, message string, added, when, now time.Time) {
-	remindEntries = append(remindEntries, RemindEntry{UserID: userID, ChannelID: channelID, Message: message, Added: added, When: when})
+	remindEntries.Add(RemindEntry{UserID: userID, ChannelID: channelID, Message: message, Added: added, When: when})
 
 	waitDuration := when.Sub(now)
 	time.AfterFunc(waitDuration, func() {
-		botData.DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		getBotData().DiscordSession.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
 			Content: "<@!" + userID + "> :alarm_clock:",
 			Embed: NewEmbed().
 				SetTitle("Remind").
@@ -167,11 +174,8 @@ func remindWhen(userID, guildID, channelID, message string, added, when, now tim
 				SetColor(0x1C1C1C).MessageEmbed,
 		})
 
-		for i := len(remindEntries) - 1; i >= 0; i-- {
-			if remindEntries[i].UserID == userID && remindEntries[i].Message == message {
-				remindEntries = append(remindEntries[:i], remindEntries[i+1:]...)
-				break
-			}
-		}
+		remindEntries.Remove(func(entry RemindEntry) bool {
+			return entry.UserID == userID && entry.Message == message && entry.When.Equal(when)
+		})
 	})
 }
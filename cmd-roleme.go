@@ -19,9 +19,9 @@ type RoleMe struct {
 
 func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.MessageEmbed {
 	if len(args) == 0 {
-		roleMeList := guildSettings[env.Guild.ID].RoleMeList
+		roleMeList := guildSettings.Get(env.Guild.ID).RoleMeList
 		if len(roleMeList) == 0 {
-			return NewGenericEmbed("RoleMe", "No roleme events found.")
+			return NewGenericEmbed(env.Locale(), "RoleMe", "No roleme events found.")
 		}
 
 		listEmbed := NewEmbed().
@@ -48,32 +48,32 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 		switch strings.ToLower(arg.Name) {
 		case "addrole", "roleadd":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the addrole argument.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must supply a value to the addrole argument.")
 			}
 			role, err := getRole(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding role %s.", arg.Value)
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "Error finding role %s.", arg.Value)
 			}
 			if isStrInSlice(rolesToAdd, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same role to add twice.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify the same role to add twice.")
 			}
 			if isStrInSlice(rolesToRemove, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify a role to add if the role is already specified to be removed.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify a role to add if the role is already specified to be removed.")
 			}
 			rolesToAdd = append(rolesToAdd, role.ID)
 		case "removerole", "roleremove", "deleterole", "roledelete":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the removerole argument.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must supply a value to the removerole argument.")
 			}
 			role, err := getRole(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding role %s.", arg.Value)
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "Error finding role %s.", arg.Value)
 			}
 			if isStrInSlice(rolesToRemove, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same role to remove twice.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify the same role to remove twice.")
 			}
 			if isStrInSlice(rolesToAdd, role.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify a role to remove if the role is already specified to be added.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify a role to remove if the role is already specified to be added.")
 			}
 			rolesToRemove = append(rolesToRemove, role.ID)
 		case "casesensitive":
@@ -85,47 +85,47 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 			}
 		case "channel":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the channel argument.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must supply a value to the channel argument.")
 			}
 			channel, err := getChannel(env.Guild.ID, arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Error finding channel %s.", arg.Value)
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "Error finding channel %s.", arg.Value)
 			}
 			if isStrInSlice(channelIDs, channel.ID) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same channel twice.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify the same channel twice.")
 			}
 			channelIDs = append(channelIDs, channel.ID)
 		case "trigger", "message", "msg":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the trigger argument.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must supply a value to the trigger argument.")
 			}
 			if isStrInSlice(triggers, arg.Value) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same trigger twice.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify the same trigger twice.")
 			}
 			triggers = append(triggers, arg.Value)
 		case "delete", "remove":
 			if arg.Value == "" {
-				return NewErrorEmbed("RoleMe Error", "You must supply a value to the delete argument.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must supply a value to the delete argument.")
 			}
 			entryToDelete, err := strconv.Atoi(arg.Value)
 			if err != nil {
-				return NewErrorEmbed("RoleMe Error", "Invalid entry number ``%s``.", arg.Value)
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "Invalid entry number ``%s``.", arg.Value)
 			}
 			if isIntInSlice(entriesToDelete, entryToDelete) {
-				return NewErrorEmbed("RoleMe Error", "You cannot specify the same event to delete twice.")
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "You cannot specify the same event to delete twice.")
 			}
-			if entryToDelete <= 0 || entryToDelete > len(guildSettings[env.Guild.ID].RoleMeList) {
-				return NewErrorEmbed("RoleMe Error", "Unknown entry number ``%s``.", arg.Value)
+			if entryToDelete <= 0 || entryToDelete > len(guildSettings.Get(env.Guild.ID).RoleMeList) {
+				return NewErrorEmbed(env.Locale(), "RoleMe Error", "Unknown entry number ``%s``.", arg.Value)
 			}
 			entriesToDelete = append(entriesToDelete, entryToDelete-1)
 		default:
-			return NewErrorEmbed("RoleMe Error", "Unknown argument ``%s``.", arg.Name)
+			return NewErrorEmbed(env.Locale(), "RoleMe Error", "Unknown argument ``%s``.", arg.Name)
 		}
 	}
 
 	if len(entriesToDelete) > 0 {
 		newRoleMeList := make([]*RoleMe, 0)
-		for i, roleMe := range guildSettings[env.Guild.ID].RoleMeList {
+		for i, roleMe := range guildSettings.Get(env.Guild.ID).RoleMeList {
 			keepEntry := true
 			for _, entryToDelete := range entriesToDelete {
 				if entryToDelete == i {
@@ -137,14 +137,16 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 				newRoleMeList = append(newRoleMeList, roleMe)
 			}
 		}
-		guildSettings[env.Guild.ID].RoleMeList = newRoleMeList
-		return NewGenericEmbed("RoleMe", "Deleted the specified roleme entries successfully!")
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			settings.RoleMeList = newRoleMeList
+		})
+		return NewGenericEmbed(env.Locale(), "RoleMe", "Deleted the specified roleme entries successfully!")
 	}
 	if len(rolesToAdd) == 0 && len(rolesToRemove) == 0 {
-		return NewErrorEmbed("RoleMe Error", "You must specify either one or more roles to add or one or more roles to remove.")
+		return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must specify either one or more roles to add or one or more roles to remove.")
 	}
 	if len(triggers) == 0 {
-		return NewErrorEmbed("RoleMe Error", "You must specify one or more triggers to trigger this roleme event.")
+		return NewErrorEmbed(env.Locale(), "RoleMe Error", "You must specify one or more triggers to trigger this roleme event.")
 	}
 
 	newRoleMe := &RoleMe{
@@ -155,18 +157,20 @@ func commandRoleMe(args []CommandArgument, env *CommandEnvironment) *discordgo.M
 		ChannelIDs:    channelIDs,
 	}
 
-	for _, roleMe := range guildSettings[env.Guild.ID].RoleMeList {
+	for _, roleMe := range guildSettings.Get(env.Guild.ID).RoleMeList {
 		for _, trigger := range roleMe.Triggers {
 			for _, newTrigger := range newRoleMe.Triggers {
 				if trigger == newTrigger {
-					return NewErrorEmbed("RoleMe Error", "The trigger ``%s`` already exists!", trigger)
+					return NewErrorEmbed(env.Locale(), "RoleMe Error", "The trigger ``%s`` already exists!", trigger)
 				}
 			}
 		}
 	}
 
-	guildSettings[env.Guild.ID].RoleMeList = append(guildSettings[env.Guild.ID].RoleMeList, newRoleMe)
-	return NewGenericEmbed("RoleMe", "Added the roleme event successfully!")
+	guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+		settings.RoleMeList = append(settings.RoleMeList, newRoleMe)
+	})
+	return NewGenericEmbed(env.Locale(), "RoleMe", "Added the roleme event successfully!")
 }
 
 func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
@@ -186,7 +190,7 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 	errCount := 0
 	successCount := 0
 	for _, roleToAdd := range roleMe.AddRoles {
-		err := botData.DiscordSession.GuildMemberRoleAdd(guildID, userID, roleToAdd)
+		err := getBotData().DiscordSession.GuildMemberRoleAdd(guildID, userID, roleToAdd)
 		if err != nil {
 			errCount++
 		} else {
@@ -194,7 +198,7 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 		}
 	}
 	for _, roleToRemove := range roleMe.RemoveRoles {
-		err := botData.DiscordSession.GuildMemberRoleRemove(guildID, userID, roleToRemove)
+		err := getBotData().DiscordSession.GuildMemberRoleRemove(guildID, userID, roleToRemove)
 		if err != nil {
 			errCount++
 		} else {
@@ -202,17 +206,18 @@ func handleRoleMe(roleMe *RoleMe, guildID, channelID, userID string) {
 		}
 	}
 
+	locale := getUserLocale(userID, guildID)
 	if errCount == 0 {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "Edited your roles successfully!"))
+		getBotData().DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed(locale, "RoleMe", "Edited your roles successfully!"))
 	} else if errCount < successCount {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed("RoleMe", "There were some errors editing your roles, but there were more successes!"))
+		getBotData().DiscordSession.ChannelMessageSendEmbed(channelID, NewGenericEmbed(locale, "RoleMe", "There were some errors editing your roles, but there were more successes!"))
 	} else {
-		botData.DiscordSession.ChannelMessageSendEmbed(channelID, NewErrorEmbed("RoleMe Error", "There were some errors editing your roles. :c"))
+		getBotData().DiscordSession.ChannelMessageSendEmbed(channelID, NewErrorEmbed(locale, "RoleMe Error", "There were some errors editing your roles. :c"))
 	}
 }
 
 func getRole(guildID, role string) (*discordgo.Role, error) {
-	guildRoles, err := botData.DiscordSession.GuildRoles(guildID)
+	guildRoles, err := getBotData().DiscordSession.GuildRoles(guildID)
 	if err != nil {
 		return nil, err
 	}
@@ -253,7 +258,7 @@ func getRole(guildID, role string) (*discordgo.Role, error) {
 }
 
 func getChannel(guildID, channel string) (*discordgo.Channel, error) {
-	guildChannels, err := botData.DiscordSession.GuildChannels(guildID)
+	guildChannels, err := getBotData().DiscordSession.GuildChannels(guildID)
 	if err != nil {
 		return nil, err
 	}
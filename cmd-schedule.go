@@ -0,0 +1,382 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"4d63.com/tz"
+	"github.com/bwmarrin/discordgo"
+	"github.com/dustin/go-humanize"
+	"github.com/robfig/cron"
+)
+
+const (
+	// ScheduleMaxPerGuild contains how many scheduled commands a guild may have at once
+	ScheduleMaxPerGuild = 25
+
+	// ScheduleMinInterval contains how often a scheduled command may run at most
+	ScheduleMinInterval = time.Minute
+)
+
+var (
+	// ScheduleTimeFormats contains the formats accepted for a fixed time to run a scheduled command at
+	ScheduleTimeFormats = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04", "15:04"}
+
+	// scheduleListArguments contains the values taken by schedule list
+	scheduleListArguments = []CommandArgument{
+		{Name: "page", Description: "The page of scheduled commands to view", ArgType: ArgTypeNumber, Optional: true, Min: 1},
+	}
+
+	// scheduleRemoveArguments contains the values taken by schedule remove, after any leading # is removed from each ID
+	scheduleRemoveArguments = []CommandArgument{
+		{Name: "IDs", Description: "The IDs of the scheduled commands to remove", ArgType: ArgTypeNumber, Multiple: true, Min: 1},
+	}
+
+	scheduler = &Scheduler{timers: make(map[string]*time.Timer)}
+)
+
+// ScheduledCommand holds a command a guild has scheduled to run on a cron expression or at a fixed time
+type ScheduledCommand struct {
+	ID         int       `json:"id"`                //The ID of the scheduled command in the guild
+	ChannelID  string    `json:"channelID"`         //The channel to run the command in
+	Command    string    `json:"command"`           //The command to run without a prefix, which may be a pipeline
+	Cron       string    `json:"cron,omitempty"`    //The cron expression to run the command on, if it repeats
+	At         time.Time `json:"at,omitempty"`      //The time to run the command at once, if it doesn't repeat
+	Timezone   string    `json:"timezone"`          //The timezone the cron expression or time was written in
+	ExecutorID string    `json:"executorID"`        //The user the command runs as, who must still be allowed to run it
+	Created    time.Time `json:"created"`           //When the command was scheduled
+	LastRun    time.Time `json:"lastRun,omitempty"` //When the command last ran
+}
+
+// Location returns the timezone of the scheduled command, or UTC if it's invalid
+func (schedule *ScheduledCommand) Location() *time.Location {
+	if location, err := tz.LoadLocation(schedule.Timezone); err == nil {
+		return location
+	}
+	return time.UTC
+}
+
+// Describe returns when the scheduled command runs in a readable form
+func (schedule *ScheduledCommand) Describe() string {
+	if schedule.Cron != "" {
+		return "``" + schedule.Cron + "`` (" + schedule.Location().String() + ")"
+	}
+	return schedule.At.In(schedule.Location()).Format("2006-01-02 15:04 MST") + " (" + humanize.Time(schedule.At) + ")"
+}
+
+// locationSchedule runs a cron schedule in the timezone it was written in
+type locationSchedule struct {
+	cron.Schedule
+	location *time.Location
+}
+
+// Next returns the next time the schedule runs after t
+func (schedule locationSchedule) Next(t time.Time) time.Time {
+	return schedule.Schedule.Next(t.In(schedule.location))
+}
+
+// Scheduler runs the scheduled commands of every guild
+//
+// robfig/cron can't remove jobs, so the cron instance is rebuilt whenever a scheduled command is added or removed.
+type Scheduler struct {
+	sync.Mutex
+
+	cron   *cron.Cron
+	timers map[string]*time.Timer //Timers for scheduled commands that run once, where key = guild ID:schedule ID
+}
+
+// Rebuild replaces the running cron instance and timers with ones for every scheduled command
+func (s *Scheduler) Rebuild() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.cron != nil {
+		s.cron.Stop()
+	}
+	for key, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, key)
+	}
+
+	s.cron = cron.New()
+	for guildID := range guildSettings.All() {
+		settings, exists := guildSettings.Snapshot(guildID)
+		if !exists {
+			continue
+		}
+		for _, schedule := range settings.Schedules {
+			guildID, scheduleID := guildID, schedule.ID
+			run := func() { runScheduledCommand(guildID, scheduleID) }
+
+			if schedule.Cron != "" {
+				cronSchedule, err := parseScheduleCron(schedule.Cron, schedule.Location())
+				if err != nil {
+					Error.Printf("Error parsing cron expression of scheduled command %d in guild %s: %v", scheduleID, guildID, err)
+					continue
+				}
+				s.cron.Schedule(cronSchedule, cron.FuncJob(run))
+				continue
+			}
+			s.timers[guildID+":"+strconv.Itoa(scheduleID)] = time.AfterFunc(time.Until(schedule.At), run)
+		}
+	}
+	s.cron.Start()
+}
+
+// parseScheduleCron parses a standard 5 field cron expression or descriptor such as @daily, refusing ones that run too often
+func parseScheduleCron(spec string, location *time.Location) (cron.Schedule, error) {
+	parsed, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+	schedule := locationSchedule{Schedule: parsed, location: location}
+
+	//Check a few runs ahead, as a schedule like "*/5 * * * *" could still run every minute at some point
+	next := schedule.Next(time.Now())
+	for i := 0; i < 5; i++ {
+		after := schedule.Next(next)
+		if after.Sub(next) < ScheduleMinInterval {
+			return nil, errScheduleTooOften
+		}
+		next = after
+	}
+	return schedule, nil
+}
+
+// parseScheduleTime parses a fixed time to run a scheduled command at, where a time of day alone means its next occurrence
+func parseScheduleTime(value string, location *time.Location) (time.Time, error) {
+	for _, format := range ScheduleTimeFormats {
+		at, err := time.ParseInLocation(format, value, location)
+		if err != nil {
+			continue
+		}
+		if format == "15:04" {
+			now := time.Now().In(location)
+			at = time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, location)
+			if !at.After(now) {
+				at = at.AddDate(0, 0, 1)
+			}
+		}
+		return at, nil
+	}
+	return time.Time{}, errScheduleTime
+}
+
+// startScheduledCommand marks the scheduled command with the specified ID in a guild as ran, removing it if it only runs once
+//
+// Returns a copy of the scheduled command, or nil if there is none.
+func startScheduledCommand(guildID string, scheduleID int) *ScheduledCommand {
+	var started *ScheduledCommand
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		for i, schedule := range settings.Schedules {
+			if schedule.ID == scheduleID {
+				schedule.LastRun = time.Now()
+				scheduleCopy := *schedule
+				started = &scheduleCopy
+				if schedule.Cron == "" {
+					settings.Schedules = append(settings.Schedules[:i], settings.Schedules[i+1:]...)
+				}
+				return
+			}
+		}
+	})
+	return started
+}
+
+// removeScheduledCommand removes the scheduled command with the specified ID from a guild, returning whether or not it existed
+func removeScheduledCommand(guildID string, scheduleID int) bool {
+	removed := false
+	guildSettings.Update(guildID, func(settings *GuildSettings) {
+		for i, schedule := range settings.Schedules {
+			if schedule.ID == scheduleID {
+				settings.Schedules = append(settings.Schedules[:i], settings.Schedules[i+1:]...)
+				removed = true
+				return
+			}
+		}
+	})
+	return removed
+}
+
+// runScheduledCommand runs a scheduled command as the user who scheduled it
+func runScheduledCommand(guildID string, scheduleID int) {
+	defer recoverPanic()
+
+	session := getBotData().DiscordSession
+	initializeGuildData(guildID)
+	guildData.Get(guildID).Lock()
+	schedule := startScheduledCommand(guildID, scheduleID)
+	guildData.Get(guildID).Unlock()
+	if schedule == nil {
+		return
+	}
+
+	guild, err := session.State.Guild(guildID)
+	if err != nil {
+		return
+	}
+	channel, err := session.State.Channel(schedule.ChannelID)
+	if err != nil {
+		Error.Printf("Error finding the channel of scheduled command %d in guild %s: %v", scheduleID, guildID, err)
+		return
+	}
+	member, err := session.GuildMember(guildID, schedule.ExecutorID)
+	if err != nil {
+		//Whoever scheduled the command is no longer around to be responsible for it
+		Error.Printf("Error finding the executor of scheduled command %d in guild %s: %v", scheduleID, guildID, err)
+		return
+	}
+
+	prefix := getGuildPrefix(guildID)
+	message := &discordgo.Message{
+		ID:        "schedule-" + strconv.Itoa(scheduleID) + "-" + strconv.FormatInt(schedule.LastRun.UnixNano(), 10),
+		ChannelID: channel.ID,
+		GuildID:   guildID,
+		Author:    member.User,
+		Content:   prefix + schedule.Command,
+	}
+	env := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: member.User, Member: member, BotPrefix: prefix}
+	response := callCommandLine(schedule.Command, env, isPrefixCaseInsensitive(guildID))
+
+	guildData.Get(guildID).Lock()
+	sendCommandResponse(session, message, channel, guild, guildID, response, false)
+	if response == nil || response.FollowUp == nil {
+		delete(guildData.Get(guildID).Queries, message.ID) //Nothing will ever edit or delete the responses to a scheduled run
+	}
+	stateSaveGuild(guildID, member.User.ID)
+	guildData.Get(guildID).Unlock()
+}
+
+func commandSchedule(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	settings := guildSettings.Get(env.Guild.ID)
+
+	switch args[0] {
+	case "add", "at":
+		if len(args) < 3 {
+			return getCommandUsage(env.Command, "Schedule - Command Usage", env)
+		}
+		if len(settings.Schedules) >= ScheduleMaxPerGuild {
+			return NewErrorEmbed(env.Locale(), "Schedule Error", "This server already has the maximum of %d scheduled commands.", ScheduleMaxPerGuild)
+		}
+
+		//The command is stored as it was written, as splitting it into arguments would lose the quotes around them
+		command := strings.TrimSpace(commandLineArguments(env.CommandLine, 3))
+		if command == "" {
+			command = strings.Join(args[2:], " ")
+		}
+		command = strings.TrimPrefix(command, env.BotPrefix)
+
+		commandNames := []string{splitCommand(command)[0]}
+		if steps := splitPipeline(command, env, settings.PrefixCaseInsensitive); steps != nil {
+			commandNames = make([]string, 0)
+			for _, step := range steps {
+				commandNames = append(commandNames, step.Command[0])
+			}
+		}
+		for _, commandName := range commandNames {
+			if settings.PrefixCaseInsensitive {
+				commandName = strings.ToLower(commandName)
+			}
+			if !isKnownCommand(commandName, env) {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "Unknown command ``"+commandName+"``.")
+			}
+			if getOriginalCommandName(commandName) == "schedule" {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "Scheduled commands can't schedule more commands.")
+			}
+		}
+
+		timezone := userSettings.Get(env.User.ID).Timezone
+		location, err := tz.LoadLocation(timezone)
+		if err != nil || timezone == "" {
+			timezone, location = "UTC", time.UTC
+		}
+
+		schedule := &ScheduledCommand{ChannelID: env.Channel.ID, Command: command, Timezone: timezone, ExecutorID: env.User.ID, Created: time.Now()}
+
+		if args[0] == "add" {
+			if _, err := parseScheduleCron(args[1], location); err != nil {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "Invalid cron expression ``"+args[1]+"``: "+err.Error())
+			}
+			schedule.Cron = args[1]
+		} else {
+			at, err := parseScheduleTime(args[1], location)
+			if err != nil {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "Invalid time ``"+args[1]+"``, use a format such as ``2006-01-02 15:04`` or ``15:04``.")
+			}
+			if !at.After(time.Now()) {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "That time was "+humanize.Time(at)+"!")
+			}
+			schedule.At = at
+		}
+
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			schedule.ID = 1
+			for _, scheduled := range settings.Schedules {
+				if scheduled.ID >= schedule.ID {
+					schedule.ID = scheduled.ID + 1
+				}
+			}
+			settings.Schedules = append(settings.Schedules, schedule)
+		})
+		scheduler.Rebuild()
+		return NewEmbed().
+			SetTitle("Schedule").
+			SetDescription("Successfully scheduled ``"+command+"`` to run in this channel as you.").
+			AddField("ID", strconv.Itoa(schedule.ID)).
+			AddField("When", schedule.Describe()).
+			InlineAllFields().
+			SetColor(0x1C1C1C).MessageEmbed
+	case "list":
+		parsed, usageEmbed := parseSubcommandArguments(scheduleListArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
+		pageNumber := 1
+		if parsed.Has("page") {
+			pageNumber = parsed.Int("page")
+		}
+		if len(settings.Schedules) == 0 {
+			return NewErrorEmbed(env.Locale(), "Schedule Error", "There are no scheduled commands in this server.")
+		}
+
+		scheduleList := make([]*discordgo.MessageEmbedField, 0)
+		for _, schedule := range settings.Schedules {
+			value := "``" + schedule.Command + "`` in <#" + schedule.ChannelID + "> as <@" + schedule.ExecutorID + ">"
+			if !schedule.LastRun.IsZero() {
+				value += "\nLast ran " + humanize.Time(schedule.LastRun)
+			}
+			scheduleList = append(scheduleList, &discordgo.MessageEmbedField{Name: "#" + strconv.Itoa(schedule.ID) + " - " + schedule.Describe(), Value: value})
+		}
+
+		scheduleEmbed, totalPages, err := page(scheduleList, pageNumber, getBotData().BotOptions.HelpMaxResults)
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Schedule Error", err.Error())
+		}
+		return scheduleEmbed.
+			SetTitle("Schedule").
+			SetDescription("The commands scheduled to run in this server.").
+			SetFooter("Page " + strconv.Itoa(pageNumber) + " of " + strconv.Itoa(totalPages) + " | " + env.BotPrefix + env.Command + " list {page}").
+			SetColor(0x1C1C1C).MessageEmbed
+	case "remove", "delete":
+		scheduleIDs := make([]string, 0)
+		for _, arg := range args[1:] {
+			scheduleIDs = append(scheduleIDs, strings.TrimPrefix(arg, "#"))
+		}
+		parsed, usageEmbed := parseSubcommandArguments(scheduleRemoveArguments, scheduleIDs, env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
+		removed := make([]string, 0)
+		for _, scheduleID := range parsed.Ints("IDs") {
+			if !removeScheduledCommand(env.Guild.ID, scheduleID) {
+				return NewErrorEmbed(env.Locale(), "Schedule Error", "There is no scheduled command with the ID ``#"+strconv.Itoa(scheduleID)+"``.")
+			}
+			removed = append(removed, "#"+strconv.Itoa(scheduleID))
+		}
+		scheduler.Rebuild()
+		return NewGenericEmbed(env.Locale(), "Schedule", "Successfully removed the scheduled command(s) "+strings.Join(removed, ", ")+".")
+	}
+	return getCommandUsage(env.Command, "Schedule - Command Usage", env)
+}
@@ -16,6 +16,18 @@ var (
 	regexpSwitchFC = regexp.MustCompile(`SW-[0-9]{4}-[0-9]{4}-[0-9]{4}`)
 )
 
+var (
+	// aboutMeViewArguments contains the values taken by user about when viewing another user's about me
+	aboutMeViewArguments = []CommandArgument{
+		{Name: "user", Description: "The user to view the about me of", ArgType: ArgTypeUser},
+	}
+
+	// swearFilterTimeoutArguments contains the values taken by server filter timeout
+	swearFilterTimeoutArguments = []CommandArgument{
+		{Name: "timeout", Description: "The seconds to wait before deleting a warning message, or 0 to disable", ArgType: ArgTypeNumber},
+	}
+)
+
 var (
 	// LogEventsRecommended contains pre-enabled recommended logging events
 	LogEventsRecommended = LogEvents{
@@ -37,24 +49,37 @@ var (
 
 // GuildSettings holds settings specific to a guild
 type GuildSettings struct { //By default this will only be configurable for users in a role with the server admin permission
-	AllowVoice              bool                  `json:"allowVoice,omitempty"`              //Whether voice commands should be usable in this guild
-	BotAdminRoles           []string              `json:"adminRoles,omitempty"`              //An array of role IDs that can admin the bot without the guild administrator permission
-	BotAdminUsers           []string              `json:"adminUsers,omitempty"`              //An array of user IDs that can admin the bot without a guild administrator role
-	BotOptions              BotOptions            `json:"botOptions,omitempty"`              //The bot options to use in this guild (true gets overridden if global bot config is false)
-	BotPrefix               string                `json:"botPrefix",omitempty`               //The bot prefix to use in this guild
-	CustomResponses         []CustomResponseQuery `json:"customResponses,omitempty"`         //An array of custom responses specific to the guild
-	LogSettings             LogSettings           `json:"logSettings,omitempty"`             //Logging settings
-	SwearFilter             SwearFilter           `json:"swearFilter,omitempty"`             //The swear filter settings specific to this guild
-	TipsChannel             string                `json:"tipsChannel,omitempty"`             //The channel to post tip messages to
-	UserJoinMessage         string                `json:"userJoinMessage,omitempty"`         //A message to send when a user joins
-	UserJoinMessageChannel  string                `json:"userJoinMessageChannel,omitempty"`  //The channel to send the user join message to
-	UserLeaveMessage        string                `json:"userLeaveMessage,omitempty"`        //A message to send when a user leaves
-	UserLeaveMessageChannel string                `json:"userLeaveMessageChannel,omitempty"` //The channel to send the user leave message to
-	RoleMeList              []*RoleMe             `json:"roleMeList,omitempty"`              //An array of rolemes specific to this guild
-	AutoSendNowPlaying      bool                  `json:"disableNowPlaying,omitempty"`       //Whether or not the Now Playing embed should be sent each time a new track is automatically started without user interaction
-	APIInviteChannel        string                `json:"apiInviteChannel,omitempty"`        //The channel to use for server-side invite link generation
-	APIInviteKey            string                `json:"apiInviteKey,omitempty"`            //The key to use for server-side invite link generation
-	Feeds                   []*Feed               `json:"feeds,omitempty"`                   //A list of feeds for the current guild
+	AllowVoice              *bool                          `json:"allowVoice,omitempty"`              //Whether voice commands should be usable in this guild (nil = allowed), toggled with the voice category
+	BotAdminRoles           []string                       `json:"adminRoles,omitempty"`              //An array of role IDs that can admin the bot without the guild administrator permission
+	BotAdminUsers           []string                       `json:"adminUsers,omitempty"`              //An array of user IDs that can admin the bot without a guild administrator role
+	BotOptions              BotOptions                     `json:"botOptions,omitempty"`              //The bot options to use in this guild (true gets overridden if global bot config is false)
+	BotPrefix               string                         `json:"botPrefix,omitempty"`               //The bot prefix to use in this guild
+	BotPrefixes             []string                       `json:"botPrefixes,omitempty"`             //The bot prefixes to use in this guild, with the primary prefix first (overrides BotPrefix)
+	PrefixCaseInsensitive   bool                           `json:"prefixCaseInsensitive,omitempty"`   //Whether or not prefixes and command names should be matched case-insensitively
+	MentionPrefix           bool                           `json:"mentionPrefix,omitempty"`           //Whether or not mentioning the bot followed by a command should run the command
+	CustomResponses         []CustomResponseQuery          `json:"customResponses,omitempty"`         //An array of custom responses specific to the guild
+	LogSettings             LogSettings                    `json:"logSettings,omitempty"`             //Logging settings
+	SwearFilter             SwearFilter                    `json:"swearFilter,omitempty"`             //The swear filter settings specific to this guild
+	TipsChannel             string                         `json:"tipsChannel,omitempty"`             //The channel to post tip messages to
+	UserJoinMessage         string                         `json:"userJoinMessage,omitempty"`         //A message to send when a user joins
+	UserJoinMessageChannel  string                         `json:"userJoinMessageChannel,omitempty"`  //The channel to send the user join message to
+	UserLeaveMessage        string                         `json:"userLeaveMessage,omitempty"`        //A message to send when a user leaves
+	UserLeaveMessageChannel string                         `json:"userLeaveMessageChannel,omitempty"` //The channel to send the user leave message to
+	RoleMeList              []*RoleMe                      `json:"roleMeList,omitempty"`              //An array of rolemes specific to this guild
+	AutoSendNowPlaying      bool                           `json:"autoSendNowPlaying,omitempty"`      //Whether or not the Now Playing embed should be sent each time a new track is automatically started without user interaction
+	APIInviteChannel        string                         `json:"apiInviteChannel,omitempty"`        //The channel to use for server-side invite link generation
+	APIInviteKey            string                         `json:"apiInviteKey,omitempty"`            //The key to use for server-side invite link generation
+	Feeds                   []*Feed                        `json:"feeds,omitempty"`                   //A list of feeds for the current guild
+	CommandCooldowns        map[string]*CommandCooldown    `json:"commandCooldowns,omitempty"`        //Cooldown overrides for commands by command name ("query" for natural language queries)
+	CooldownExemptRoles     []string                       `json:"cooldownExemptRoles,omitempty"`     //An array of role IDs that bypass command cooldowns
+	CommandPermissions      map[string]*CommandPermissions `json:"commandPermissions,omitempty"`      //Per-command role, channel and user rules by command name
+	DisabledCommands        []string                       `json:"disabledCommands,omitempty"`        //An array of command names that can't be used in this guild
+	DisabledCategories      []string                       `json:"disabledCategories,omitempty"`      //An array of command categories that can't be used in this guild (voice uses AllowVoice)
+	CategoryChannels        map[string][]string            `json:"categoryChannels,omitempty"`        //Channel IDs each command category is restricted to, where key = category
+	CustomCommands          map[string]*CustomCommand      `json:"customCommands,omitempty"`          //Guild-defined prefix commands, where key = command name
+	DisableSuggestions      bool                           `json:"disableSuggestions,omitempty"`      //Whether or not to stop suggesting similar commands when an unknown command is used
+	Language                string                         `json:"language,omitempty"`                //The language code to respond in for users who haven't set their own language
+	Schedules               []*ScheduledCommand            `json:"schedules,omitempty"`               //Commands scheduled to run on a cron expression or at a fixed time
 }
 
 // UserSettings holds settings specific to a user
@@ -65,6 +90,7 @@ type UserSettings struct {
 	//Basic info
 	AboutMe  string `json:"description,omitempty"` //An aboutme set by the user
 	Timezone string `json:"timezone,omitempty"`    //A timezone set by the user to use in other functions
+	Language string `json:"language,omitempty"`    //The language code to respond to the user in, overriding the guild's language
 
 	//Socials
 	Socials Socials `json:"socials,omitempty"` //Social media, gamertags, etc
@@ -111,20 +137,94 @@ type LogEvents struct {
 func commandSettingsBot(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "prefix":
+		if len(args) > 2 {
+			prefix := args[2]
+			switch args[1] {
+			case "add":
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.BotPrefixes = append(remove(guildPrefixesOf(settings), prefix), prefix)
+					settings.BotPrefix = ""
+				})
+				return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Successfully added the command prefix ``"+escapePrefix(prefix)+"``.")
+			case "remove":
+				problem := ""
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					prefixes := guildPrefixesOf(settings)
+					switch {
+					case !containsString(prefixes, prefix):
+						problem = "``" + escapePrefix(prefix) + "`` is not a command prefix in this server."
+					case len(prefixes) == 1:
+						problem = "You can't remove the only command prefix, set a new one instead."
+					default:
+						settings.BotPrefix = ""
+						settings.BotPrefixes = remove(prefixes, prefix)
+					}
+				})
+				if problem != "" {
+					return NewErrorEmbed(env.Locale(), "Bot Settings - Command Prefix Error", problem)
+				}
+				return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Successfully removed the command prefix ``"+escapePrefix(prefix)+"``.")
+			}
+		}
 		if len(args) > 1 {
-			if args[1] == botData.CommandPrefix {
-				guildSettings[env.Guild.ID].BotPrefix = ""
-			} else {
-				guildSettings[env.Guild.ID].BotPrefix = args[1]
+			switch args[1] {
+			case "reset":
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.BotPrefix = ""
+					settings.BotPrefixes = nil
+				})
+				return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Successfully reset the command prefix to ``"+escapePrefix(getBotData().CommandPrefix)+"``.")
+			case "casesensitive", "caseinsensitive":
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.PrefixCaseInsensitive = args[1] == "caseinsensitive"
+				})
+				if args[1] == "caseinsensitive" {
+					return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Command prefixes and names are now matched case-insensitively.")
+				}
+				return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Command prefixes and names are now matched case-sensitively.")
+			case "mention":
+				mentionPrefix := false
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.MentionPrefix = !settings.MentionPrefix
+					mentionPrefix = settings.MentionPrefix
+				})
+				if mentionPrefix {
+					return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Mentioning "+getBotData().BotName+" followed by a command will now run the command.")
+				}
+				return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Mentioning "+getBotData().BotName+" will now only be used for queries.")
 			}
-			return NewGenericEmbed("Bot Settings - Command Prefix", "Successfully set the command prefix to ``"+strings.Replace(args[1], "`", "\\`", -1)+"``.")
+
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.BotPrefix = ""
+				settings.BotPrefixes = nil
+				if args[1] != getBotData().CommandPrefix {
+					settings.BotPrefixes = []string{args[1]}
+				}
+			})
+			return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", "Successfully set the command prefix to ``"+escapePrefix(args[1])+"``.")
 		}
-		if guildSettings[env.Guild.ID].BotPrefix != "" {
-			return NewGenericEmbed("Bot Settings - Command Prefix", "Current command prefix:\n\n"+guildSettings[env.Guild.ID].BotPrefix)
+
+		prefixes := make([]string, 0)
+		for _, prefix := range getGuildPrefixes(env.Guild.ID) {
+			prefixes = append(prefixes, "``"+escapePrefix(prefix)+"``")
 		}
-		return NewGenericEmbed("Bot Settings - Command Prefix", "Current command prefix:\n\n"+botData.CommandPrefix)
+		prefixInfo := "Current command prefixes:\n\n" + strings.Join(prefixes, ", ")
+		guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+			if settings.PrefixCaseInsensitive {
+				prefixInfo += "\n\nPrefixes and command names are matched case-insensitively."
+			}
+			if settings.MentionPrefix {
+				prefixInfo += "\n\nMentioning " + getBotData().BotName + " followed by a command will run the command."
+			}
+		})
+		return NewGenericEmbed(env.Locale(), "Bot Settings - Command Prefix", prefixInfo)
 	}
-	return NewErrorEmbed("Bot Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return NewErrorEmbed(env.Locale(), "Bot Settings Error", "Error finding the setting ``"+args[0]+"``.")
+}
+
+// escapePrefix escapes backticks in a prefix so it can be displayed in inline code
+func escapePrefix(prefix string) string {
+	return strings.Replace(prefix, "`", "\\`", -1)
 }
 
 func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -133,33 +233,56 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 	switch args[0] {
 	case "about", "aboutme", "description", "desc", "info":
 		if len(args) <= 1 {
-			if userSettings[env.User.ID].AboutMe == "" {
-				return NewErrorEmbed("User Settings - About Me Error", "You must specify an aboutme to view it.")
+			if userSettings.Get(env.User.ID).AboutMe == "" {
+				return NewErrorEmbed(env.Locale(), "User Settings - About Me Error", "You must specify an aboutme to view it.")
 			}
-			return aboutMe(env.User.ID)
+			return aboutMe(env, env.User.ID)
 		}
-		if len(args) == 2 && len(env.Message.Mentions) > 0 {
-			return aboutMe(env.Message.Mentions[0].ID)
+		if len(args) == 2 {
+			if parsed, err := parseArgumentList(aboutMeViewArguments, args[1:], env); err == nil {
+				return aboutMe(env, parsed.User("user").ID)
+			}
 		}
-		userSettings[env.User.ID].AboutMe = strings.Join(args[1:], " ")
-		return NewGenericEmbed("User Settings - About Me", "Successfully set your about me!")
+		userSettings.Update(env.User.ID, func(settings *UserSettings) {
+			settings.AboutMe = strings.Join(args[1:], " ")
+		})
+		return NewGenericEmbed(env.Locale(), "User Settings - About Me", "Successfully set your about me!")
 	case "timezone", "tz":
 		if len(args) <= 1 {
-			if userSettings[env.User.ID].Timezone == "" {
-				return NewErrorEmbed("User Settings - Timezone Error", "You must specify a timezone to view it.")
+			if userSettings.Get(env.User.ID).Timezone == "" {
+				return NewErrorEmbed(env.Locale(), "User Settings - Timezone Error", "You must specify a timezone to view it.")
 			}
-			location, err := tz.LoadLocation(userSettings[env.User.ID].Timezone)
+			location, err := tz.LoadLocation(userSettings.Get(env.User.ID).Timezone)
 			if err != nil {
-				return NewErrorEmbed("User Settings - Timezone Error", "You have an invalid timezone set, please set a new one first.\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
+				return NewErrorEmbed(env.Locale(), "User Settings - Timezone Error", "You have an invalid timezone set, please set a new one first.\n\nEx: ``"+env.BotPrefix+"user timezone America/New_York``")
 			}
-			return NewGenericEmbed("User Settings - Timezone", "Your current timezone is set to ``"+userSettings[env.User.ID].Timezone+"``.\nYour current time is ``"+time.Now().In(location).String()+"``.")
+			return NewGenericEmbed(env.Locale(), "User Settings - Timezone", "Your current timezone is set to ``"+userSettings.Get(env.User.ID).Timezone+"``.\nYour current time is ``"+time.Now().In(location).String()+"``.")
 		}
 		location, err := tz.LoadLocation(args[1])
 		if err != nil {
-			return NewErrorEmbed("User Settings - Timezone Error", "Invalid timezone.")
+			return NewErrorEmbed(env.Locale(), "User Settings - Timezone Error", "Invalid timezone.")
+		}
+		userSettings.Update(env.User.ID, func(settings *UserSettings) {
+			settings.Timezone = args[1]
+		})
+		return NewGenericEmbed(env.Locale(), "User Settings - Timezone", "Successfully set your timezone to ``%s``.\nYour current time is ``%s``.", args[1], time.Now().In(location).String())
+	case "language", "lang":
+		if len(args) <= 1 {
+			return NewGenericEmbed(env.Locale(), "User Settings - Language", "Your current language is **%s**.\n\nAvailable languages:\n%s", env.Locale().Name, strings.Join(getLanguageList(), "\n"))
+		}
+		if args[1] == "reset" {
+			userSettings.Update(env.User.ID, func(settings *UserSettings) {
+				settings.Language = ""
+			})
+			return NewGenericEmbed(env.Locale(), "User Settings - Language", "Successfully reset your language to the server's language.")
 		}
-		userSettings[env.User.ID].Timezone = args[1]
-		return NewGenericEmbed("User Settings - Timezone", "Successfully set your timezone to ``"+args[1]+"``.\nYour current time is ``"+time.Now().In(location).String()+"``.")
+		if _, exists := locales[strings.ToLower(args[1])]; !exists {
+			return NewErrorEmbed(env.Locale(), "User Settings - Language Error", "Unknown language ``%s``.\n\nAvailable languages:\n%s", args[1], strings.Join(getLanguageList(), "\n"))
+		}
+		userSettings.Update(env.User.ID, func(settings *UserSettings) {
+			settings.Language = strings.ToLower(args[1])
+		})
+		return NewGenericEmbed(env.Locale(), "User Settings - Language", "Successfully set your language to **%s**.", env.Locale().Name)
 	case "social", "socials":
 		/*
 		* cli$user social add switchfc SW-0000-0000-0000
@@ -189,51 +312,59 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 		switch args[1] {
 		case "set", "add":
 			if len(args) < 4 {
-				return NewErrorEmbed("User Settings - Socials", "You must specify a social identifier to set it.")
+				return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You must specify a social identifier to set it.")
 			}
 			switch args[2] {
 			case "switchfc":
 				if !regexpSwitchFC.MatchString(args[3]) {
-					return NewErrorEmbed("User Settings - Socials", "Invalid Switch friend code.")
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "Invalid Switch friend code.")
 				}
-				if userSettings[env.User.ID].Socials.SwitchFC == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that Switch friend code.")
+				if userSettings.Get(env.User.ID).Socials.SwitchFC == args[3] {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You have already set that Switch friend code.")
 				}
-				userSettings[env.User.ID].Socials.SwitchFC = args[3]
-				return NewGenericEmbed("User Settings - Socials", "Successfully set your Switch friend code to ``"+args[3]+"``.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.SwitchFC = args[3]
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Successfully set your Switch friend code to ``"+args[3]+"``.")
 			case "nintendoid", "nintyid", "nnid":
-				if userSettings[env.User.ID].Socials.NNID == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that NNID.")
+				if userSettings.Get(env.User.ID).Socials.NNID == args[3] {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You have already set that NNID.")
 				}
-				exists, _, err := botData.BotClients.Ninty.DoesUserExist(args[3])
+				exists, _, err := getBotData().BotClients.Ninty.DoesUserExist(args[3])
 				if err != nil {
-					return NewErrorEmbed("User Settings - Social Error", "There was an error checking if that NNID exists.")
+					return NewErrorEmbed(env.Locale(), "User Settings - Social Error", "There was an error checking if that NNID exists.")
 				}
 				if !exists {
-					return NewErrorEmbed("User Settings - Social Error", "That NNID doesn't exist!")
+					return NewErrorEmbed(env.Locale(), "User Settings - Social Error", "That NNID doesn't exist!")
 				}
-				userSettings[env.User.ID].Socials.NNID = args[3]
-				return NewGenericEmbed("User Settings - Socials", "Successfully set your NNID to ``"+args[3]+"``.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.NNID = args[3]
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Successfully set your NNID to ``"+args[3]+"``.")
 			case "psn":
-				if userSettings[env.User.ID].Socials.PSN == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that PSN.")
+				if userSettings.Get(env.User.ID).Socials.PSN == args[3] {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You have already set that PSN.")
 				}
-				userSettings[env.User.ID].Socials.PSN = args[3]
-				return NewGenericEmbed("User Settings - Socials", "Successfully set your PSN to ``"+args[3]+"``.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.PSN = args[3]
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Successfully set your PSN to ``"+args[3]+"``.")
 			case "xbox", "gamertag":
-				if userSettings[env.User.ID].Socials.Xbox == args[3] {
-					return NewErrorEmbed("User Settings - Socials", "You have already set that Xbox Live gamertag.")
+				if userSettings.Get(env.User.ID).Socials.Xbox == args[3] {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You have already set that Xbox Live gamertag.")
 				}
-				userSettings[env.User.ID].Socials.Xbox = args[3]
-				return NewGenericEmbed("User Settings - Socials", "Successfully set your Xbox Live gamertag to ``"+args[3]+"``.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.Xbox = args[3]
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Successfully set your Xbox Live gamertag to ``"+args[3]+"``.")
 			}
-			return NewErrorEmbed("User Settings - Socials Error", "Unknown social "+args[2]+"``.")
+			return NewErrorEmbed(env.Locale(), "User Settings - Socials Error", "Unknown social "+args[2]+"``.")
 		case "list":
 			socialsEmbed := NewEmbed().
 				SetTitle("Socials").
 				SetDescription("Below are all of the socials you have added.").MessageEmbed
 
-			socials := userSettings[env.User.ID].Socials
+			socials := userSettings.Get(env.User.ID).Socials
 			socialsFields := make([]*discordgo.MessageEmbedField, 0)
 
 			if socials.SwitchFC != "" {
@@ -262,7 +393,7 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 			}
 
 			if len(socialsFields) == 0 {
-				return NewGenericEmbed("User Settings - Socials", "You don't have any socials yet!")
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "You don't have any socials yet!")
 			}
 
 			socialsEmbed.Fields = socialsFields
@@ -274,53 +405,61 @@ func commandSettingsUser(args []string, env *CommandEnvironment) *discordgo.Mess
 			}
 			switch args[2] {
 			case "switchfc":
-				if userSettings[env.User.ID].Socials.SwitchFC == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have a Switch friend code set.")
+				if userSettings.Get(env.User.ID).Socials.SwitchFC == "" {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You don't have a Switch friend code set.")
 				}
-				userSettings[env.User.ID].Socials.SwitchFC = ""
-				return NewGenericEmbed("User Settings - Socials", "Cleared your Switch friend code.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.SwitchFC = ""
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Cleared your Switch friend code.")
 			case "nintendoid", "nintyid", "nnid":
-				if userSettings[env.User.ID].Socials.NNID == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have an NNID set.")
+				if userSettings.Get(env.User.ID).Socials.NNID == "" {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You don't have an NNID set.")
 				}
-				userSettings[env.User.ID].Socials.NNID = ""
-				return NewGenericEmbed("User Settings - Socials", "Cleared your NNID.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.NNID = ""
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Cleared your NNID.")
 			case "psn":
-				if userSettings[env.User.ID].Socials.PSN == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have a PSN set.")
+				if userSettings.Get(env.User.ID).Socials.PSN == "" {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You don't have a PSN set.")
 				}
-				userSettings[env.User.ID].Socials.PSN = ""
-				return NewGenericEmbed("User Settings - Socials", "Cleared your PSN.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.PSN = ""
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Cleared your PSN.")
 			case "xbox":
-				if userSettings[env.User.ID].Socials.Xbox == "" {
-					return NewErrorEmbed("User Settings - Socials", "You don't have an Xbox Live gamertag set.")
+				if userSettings.Get(env.User.ID).Socials.Xbox == "" {
+					return NewErrorEmbed(env.Locale(), "User Settings - Socials", "You don't have an Xbox Live gamertag set.")
 				}
-				userSettings[env.User.ID].Socials.Xbox = ""
-				return NewGenericEmbed("User Settings - Socials", "Cleared your Xbox Live gamertag.")
+				userSettings.Update(env.User.ID, func(settings *UserSettings) {
+					settings.Socials.Xbox = ""
+				})
+				return NewGenericEmbed(env.Locale(), "User Settings - Socials", "Cleared your Xbox Live gamertag.")
 			}
-			return NewErrorEmbed("User Settings - Socials Error", "Unknown social ``"+args[2]+"``.")
+			return NewErrorEmbed(env.Locale(), "User Settings - Socials Error", "Unknown social ``"+args[2]+"``.")
 		case "available", "types":
-			return NewGenericEmbed("User Settings - Socials - Types", "These are the available socials you can use:\n\n"+
+			return NewGenericEmbed(env.Locale(), "User Settings - Socials - Types", "These are the available socials you can use:\n\n"+
 				"``switchfc`` - Nintendo Switch friend code\n"+
 				"``nnid`` - Nintendo Network ID\n"+
 				"``psn`` - PlayStation Network\n"+
 				"``xbox`` - Xbox Live Gamertag",
 			)
 		}
-		return NewErrorEmbed("User Settings - Socials Error", "Unknown socials command ``"+args[1]+"``.")
+		return NewErrorEmbed(env.Locale(), "User Settings - Socials Error", "Unknown socials command ``"+args[1]+"``.")
 	}
-	return NewErrorEmbed("User Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return NewErrorEmbed(env.Locale(), "User Settings Error", "Error finding the setting ``"+args[0]+"``.")
 }
 
-func aboutMe(userID string) *discordgo.MessageEmbed {
-	settings, found := userSettings[userID]
+func aboutMe(env *CommandEnvironment, userID string) *discordgo.MessageEmbed {
+	settings, found := userSettings.Load(userID)
 	if !found {
-		return NewErrorEmbed("About Me - Error", "Error finding the aboutme for <@!"+userID+">.")
+		return NewErrorEmbed(env.Locale(), "About Me - Error", "Error finding the aboutme for <@!"+userID+">.")
 	}
 
-	user, err := botData.DiscordSession.User(userID)
+	user, err := getBotData().DiscordSession.User(userID)
 	if err != nil {
-		return NewErrorEmbed("About Me - Error", "Error finding the user <@!"+userID+">.")
+		return NewErrorEmbed(env.Locale(), "About Me - Error", "Error finding the user <@!"+userID+">.")
 	}
 
 	return NewEmbed().
@@ -332,39 +471,84 @@ func aboutMe(userID string) *discordgo.MessageEmbed {
 func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "joinmsg":
-		guildSettings[env.Guild.ID].UserJoinMessage = strings.Join(args[1:], " ")
-		guildSettings[env.Guild.ID].UserJoinMessageChannel = env.Channel.ID
-		return NewGenericEmbed("Server Settings - Join Message", "Successfully set the join message to this channel.")
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			settings.UserJoinMessage = strings.Join(args[1:], " ")
+			settings.UserJoinMessageChannel = env.Channel.ID
+		})
+		return NewGenericEmbed(env.Locale(), "Server Settings - Join Message", "Successfully set the join message to this channel.")
 	case "leavemsg":
-		guildSettings[env.Guild.ID].UserLeaveMessage = strings.Join(args[1:], " ")
-		guildSettings[env.Guild.ID].UserLeaveMessageChannel = env.Channel.ID
-		return NewGenericEmbed("Server Settings - Leave Message", "Successfully set the leave message to this channel.")
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			settings.UserLeaveMessage = strings.Join(args[1:], " ")
+			settings.UserLeaveMessageChannel = env.Channel.ID
+		})
+		return NewGenericEmbed(env.Locale(), "Server Settings - Leave Message", "Successfully set the leave message to this channel.")
 	case "tips":
 		if len(args) <= 1 {
-			if guildSettings[env.Guild.ID].TipsChannel != "" {
-				return NewGenericEmbed("Server Settings - Tips", "Tips are enabled for this server.")
+			if guildSettings.Get(env.Guild.ID).TipsChannel != "" {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Tips", "Tips are enabled for this server.")
 			}
-			return NewGenericEmbed("Server Settings - Tips", "Tips are disabled for this server.")
+			return NewGenericEmbed(env.Locale(), "Server Settings - Tips", "Tips are disabled for this server.")
 		}
 		switch args[1] {
 		case "enable":
-			guildSettings[env.Guild.ID].TipsChannel = env.Channel.ID
-			return NewGenericEmbed("Server Settings - Tips", "Successfully enabled hourly tips for this channel.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.TipsChannel = env.Channel.ID
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Tips", "Successfully enabled hourly tips for this channel.")
 		case "disable":
-			guildSettings[env.Guild.ID].TipsChannel = ""
-			return NewGenericEmbed("Server Settings - Tips", "Successfully disabled hourly tips for this channel.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.TipsChannel = ""
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Tips", "Successfully disabled hourly tips for this channel.")
 		}
-		return NewErrorEmbed("Server Settings - Tips Error", "Unknown tips command ``"+args[1]+"``.")
+		return NewErrorEmbed(env.Locale(), "Server Settings - Tips Error", "Unknown tips command ``"+args[1]+"``.")
+	case "language", "lang":
+		if len(args) <= 1 {
+			language := getLocale(guildSettings.Get(env.Guild.ID).Language)
+			return NewGenericEmbed(env.Locale(), "Server Settings - Language", "The current language for this server is **%s**.\n\nAvailable languages:\n%s", language.Name, strings.Join(getLanguageList(), "\n"))
+		}
+		language, exists := locales[strings.ToLower(args[1])]
+		if !exists {
+			return NewErrorEmbed(env.Locale(), "Server Settings - Language Error", "Unknown language ``%s``.\n\nAvailable languages:\n%s", args[1], strings.Join(getLanguageList(), "\n"))
+		}
+		guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+			settings.Language = language.Code
+		})
+		return NewGenericEmbed(env.Locale(), "Server Settings - Language", "Successfully set the language for this server to **%s**.", language.Name)
+	case "suggestions":
+		if len(args) <= 1 {
+			if guildSettings.Get(env.Guild.ID).DisableSuggestions {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Suggestions", "Command suggestions are disabled for this server.")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Suggestions", "Command suggestions are enabled for this server.")
+		}
+		switch args[1] {
+		case "enable":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.DisableSuggestions = false
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Suggestions", "Successfully enabled suggesting similar commands when an unknown command is used.")
+		case "disable":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.DisableSuggestions = true
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Suggestions", "Successfully disabled suggesting similar commands when an unknown command is used.")
+		}
+		return NewErrorEmbed(env.Locale(), "Server Settings - Suggestions Error", "Unknown suggestions command ``"+args[1]+"``.")
 	case "autosendnowplaying":
 		switch args[1] {
 		case "enable":
-			guildSettings[env.Guild.ID].AutoSendNowPlaying = true
-			return NewGenericEmbed("Server Settings - Auto Send Now Playing", "Successfully enabled sending now playing messages each time a new track is started without user interaction.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.AutoSendNowPlaying = true
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Auto Send Now Playing", "Successfully enabled sending now playing messages each time a new track is started without user interaction.")
 		case "disable":
-			guildSettings[env.Guild.ID].AutoSendNowPlaying = false
-			return NewGenericEmbed("Server Settings - Auto Send Now Playing", "Successfully disabled sending now playing messages each time a new track is started without user interaction.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.AutoSendNowPlaying = false
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Auto Send Now Playing", "Successfully disabled sending now playing messages each time a new track is started without user interaction.")
 		}
-		return NewErrorEmbed("Server Settings - Auto Send Now Playing Error", "Unknown ASNP command ``"+args[1]+"``.")
+		return NewErrorEmbed(env.Locale(), "Server Settings - Auto Send Now Playing Error", "Unknown ASNP command ``"+args[1]+"``.")
 	case "invitegen":
 		if len(args) < 2 {
 			invitegenHelpCmd := &Command{
@@ -382,19 +566,23 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 
 		switch args[1] {
 		case "setchannel":
-			guildSettings[env.Guild.ID].APIInviteChannel = env.Channel.ID
-			return NewGenericEmbed("Server Settings - API Invite Generation", "Successfully set the channel to use for generating invite links to this channel.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.APIInviteChannel = env.Channel.ID
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - API Invite Generation", "Successfully set the channel to use for generating invite links to this channel.")
 		case "key":
 			if len(args) > 2 {
-				guildSettings[env.Guild.ID].APIInviteKey = strings.Join(args[2:], " ")
-				return NewGenericEmbed("Server Settings - API Invite Generation", "Successfully set the key to use for generating invite links to ``"+guildSettings[env.Guild.ID].APIInviteKey+"``.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.APIInviteKey = strings.Join(args[2:], " ")
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - API Invite Generation", "Successfully set the key to use for generating invite links to ``"+guildSettings.Get(env.Guild.ID).APIInviteKey+"``.")
 			}
-			if guildSettings[env.Guild.ID].APIInviteKey == "" {
-				return NewGenericEmbed("Server Settings - API Invite Generation", "No key is currently set for generating invite links!")
+			if guildSettings.Get(env.Guild.ID).APIInviteKey == "" {
+				return NewGenericEmbed(env.Locale(), "Server Settings - API Invite Generation", "No key is currently set for generating invite links!")
 			}
-			return NewGenericEmbed("Server Settings - API Invite Generation", "The current key for generating invite links is ``"+guildSettings[env.Guild.ID].APIInviteKey+"``.")
+			return NewGenericEmbed(env.Locale(), "Server Settings - API Invite Generation", "The current key for generating invite links is ``"+guildSettings.Get(env.Guild.ID).APIInviteKey+"``.")
 		}
-		return NewErrorEmbed("Server Settings - API Invite Generation Error", "Unknown invitegen command ``"+args[1]+"``.")
+		return NewErrorEmbed(env.Locale(), "Server Settings - API Invite Generation Error", "Unknown invitegen command ``"+args[1]+"``.")
 	case "filter":
 		if len(args) < 2 {
 			filterHelpCmd := &Command{
@@ -414,16 +602,20 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 
 		switch args[1] {
 		case "enable":
-			guildSettings[env.Guild.ID].SwearFilter.Enabled = true
-			return NewGenericEmbed("Server Settings - Swear Filter", "Successfully enabled the swear filter.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.SwearFilter.Enabled = true
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully enabled the swear filter.")
 		case "disable":
-			guildSettings[env.Guild.ID].SwearFilter.Enabled = false
-			return NewGenericEmbed("Server Settings - Swear Filter", "Successfully disabled the swear filter.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.SwearFilter.Enabled = false
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully disabled the swear filter.")
 		case "words":
 			if len(args) < 3 {
 				words := "No words are in the swear filter!"
-				if len(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords) > 0 {
-					words = strings.Join(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords, ", ")
+				if len(guildSettings.Get(env.Guild.ID).SwearFilter.BlacklistedWords) > 0 {
+					words = strings.Join(guildSettings.Get(env.Guild.ID).SwearFilter.BlacklistedWords, ", ")
 				}
 				wordListEmbed := NewEmbed().
 					SetTitle("Server Settings - Swear Filter").
@@ -434,38 +626,47 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			switch args[2] {
 			case "add":
 				if len(args) < 4 {
-					return NewErrorEmbed("Server Settings - Swear Filter Error", "You must specify one or more words to add to the filter.")
+					return NewErrorEmbed(env.Locale(), "Server Settings - Swear Filter Error", "You must specify one or more words to add to the filter.")
 				}
-				guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = append(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords, args[3:]...)
-				return NewGenericEmbed("Server Settings - Swear Filter", "Successfully added the provided words to the filter.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.SwearFilter.BlacklistedWords = append(settings.SwearFilter.BlacklistedWords, args[3:]...)
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully added the provided words to the filter.")
 			case "remove":
 				if len(args) < 4 {
-					return NewErrorEmbed("Server Settings - Swear Filter Error", "You must specify one or more words to remove from the filter.")
-				}
-				for _, word := range guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords {
-					guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = remove(guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords, word)
+					return NewErrorEmbed(env.Locale(), "Server Settings - Swear Filter Error", "You must specify one or more words to remove from the filter.")
 				}
-				return NewGenericEmbed("Server Settings - Swear Filter", "Successfully removed the provided words from the filter.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					for _, word := range settings.SwearFilter.BlacklistedWords {
+						settings.SwearFilter.BlacklistedWords = remove(settings.SwearFilter.BlacklistedWords, word)
+					}
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully removed the provided words from the filter.")
 			case "clear":
-				guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = make([]string, 0)
-				return NewGenericEmbed("Server Settings - Swear Filter", "Successfully cleared all words from the filter.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.SwearFilter.BlacklistedWords = make([]string, 0)
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully cleared all words from the filter.")
 			}
 		case "timeout":
 			if len(args) < 3 {
-				if guildSettings[env.Guild.ID].SwearFilter.WarningDeleteTimeout == 0 {
-					return NewGenericEmbed("Server Settings - Swear Filter", "The timeout for deleting warning messages is disabled.")
+				if guildSettings.Get(env.Guild.ID).SwearFilter.WarningDeleteTimeout == 0 {
+					return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "The timeout for deleting warning messages is disabled.")
 				}
-				timeout := strconv.Itoa(int(guildSettings[env.Guild.ID].SwearFilter.WarningDeleteTimeout))
-				return NewGenericEmbed("Server Settings - Swear Filter", "The current timeout for deleting warning messages is set to "+timeout+" seconds.")
+				timeout := strconv.Itoa(int(guildSettings.Get(env.Guild.ID).SwearFilter.WarningDeleteTimeout))
+				return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "The current timeout for deleting warning messages is set to "+timeout+" seconds.")
 			}
-			timeout, err := strconv.Atoi(args[2])
-			if err != nil {
-				return NewErrorEmbed("Server Settings - Swear Filter Error", "``"+args[2]+"`` is not a valid number.")
+			parsed, usageEmbed := parseSubcommandArguments(swearFilterTimeoutArguments, args[2:], env)
+			if usageEmbed != nil {
+				return usageEmbed
 			}
-			guildSettings[env.Guild.ID].SwearFilter.WarningDeleteTimeout = time.Duration(timeout)
-			return NewGenericEmbed("Server Settings - Swear Filter", "Successfully set he timeout for deleting warning messages to "+args[2]+" seconds.")
+			timeout := parsed.Int("timeout")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.SwearFilter.WarningDeleteTimeout = time.Duration(timeout)
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Swear Filter", "Successfully set he timeout for deleting warning messages to "+strconv.Itoa(timeout)+" seconds.")
 		}
-		return NewErrorEmbed("Server Settings - Swear Filter Error", "Unknown filter command ``"+args[1]+"``.")
+		return NewErrorEmbed(env.Locale(), "Server Settings - Swear Filter Error", "Unknown filter command ``"+args[1]+"``.")
 	case "log":
 		if len(args) < 2 {
 			logHelpCmd := &Command{
@@ -484,14 +685,18 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 			return getCustomCommandUsage(logHelpCmd, "server log", "Server Settings - Log Help", env)
 		}
 
-		LoggingEventsTmp := &guildSettings[env.Guild.ID].LogSettings.LoggingEvents
+		LoggingEventsTmp := &guildSettings.Get(env.Guild.ID).LogSettings.LoggingEvents
 
 		switch args[1] {
 		case "set":
-			guildSettings[env.Guild.ID].LogSettings.LoggingChannel = env.Channel.ID
-			return NewGenericEmbed("Server Settings - Log", "Successfully set the logging channel to this channel.")
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.LogSettings.LoggingChannel = env.Channel.ID
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully set the logging channel to this channel.")
 		case "enable":
-			guildSettings[env.Guild.ID].LogSettings.LoggingEnabled = true
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.LogSettings.LoggingEnabled = true
+			})
 
 			if len(args) == 3 {
 				switch args[2] {
@@ -502,27 +707,35 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 					for _, event := range fields {
 						err := event.Set(true)
 						if err != nil {
-							return NewErrorEmbed("Server Settings - Log", "Unable to enable all logging events.")
+							return NewErrorEmbed(env.Locale(), "Server Settings - Log", "Unable to enable all logging events.")
 						}
 					}
 
-					guildSettings[env.Guild.ID].LogSettings.LoggingEvents = *LoggingEventsTmp
+					guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+						settings.LogSettings.LoggingEvents = *LoggingEventsTmp
+					})
 
-					if guildSettings[env.Guild.ID].LogSettings.LoggingChannel == "" {
-						guildSettings[env.Guild.ID].LogSettings.LoggingChannel = env.Channel.ID
-						return NewGenericEmbed("Server Settings - Log", "Successfully enabled all logging events and set the logging channel to this channel.")
+					if guildSettings.Get(env.Guild.ID).LogSettings.LoggingChannel == "" {
+						guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+							settings.LogSettings.LoggingChannel = env.Channel.ID
+						})
+						return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully enabled all logging events and set the logging channel to this channel.")
 					}
 
-					return NewGenericEmbed("Server Settings - Log", "Successfully enabled all logging events.")
+					return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully enabled all logging events.")
 				case "recommended":
-					guildSettings[env.Guild.ID].LogSettings.LoggingEvents = LogEventsRecommended
+					guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+						settings.LogSettings.LoggingEvents = LogEventsRecommended
+					})
 
-					if guildSettings[env.Guild.ID].LogSettings.LoggingChannel == "" {
-						guildSettings[env.Guild.ID].LogSettings.LoggingChannel = env.Channel.ID
-						return NewGenericEmbed("Server Settings - Log", "Successfully toggled all logging events to their recommended states and set the logging channel to this channel.")
+					if guildSettings.Get(env.Guild.ID).LogSettings.LoggingChannel == "" {
+						guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+							settings.LogSettings.LoggingChannel = env.Channel.ID
+						})
+						return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully toggled all logging events to their recommended states and set the logging channel to this channel.")
 					}
 
-					return NewGenericEmbed("Server Settings - Log", "Successfully toggled all logging events to their recommended states.")
+					return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully toggled all logging events to their recommended states.")
 				}
 			}
 
@@ -547,10 +760,12 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 				}
 			}
 
-			guildSettings[env.Guild.ID].LogSettings.LoggingEvents = *LoggingEventsTmp
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.LogSettings.LoggingEvents = *LoggingEventsTmp
+			})
 
 			responseMessage := "Successfully enabled logging"
-			if guildSettings[env.Guild.ID].LogSettings.LoggingChannel != "" {
+			if guildSettings.Get(env.Guild.ID).LogSettings.LoggingChannel != "" {
 				responseMessage += "."
 			} else {
 				responseMessage += " and set the logging channel to this channel."
@@ -564,11 +779,13 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 					responseMessage += "\nFailed to find the following events: " + strings.Join(eventsFailed, ", ")
 				}
 			}
-			return NewGenericEmbed("Server Settings - Log", responseMessage)
+			return NewGenericEmbed(env.Locale(), "Server Settings - Log", responseMessage)
 		case "disable":
 			if len(args) == 3 && args[2] == "all" {
-				guildSettings[env.Guild.ID].LogSettings.LoggingEvents = LogEvents{}
-				return NewGenericEmbed("Server Settings - Log", "Successfully disabled all logging events.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.LogSettings.LoggingEvents = LogEvents{}
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully disabled all logging events.")
 			}
 
 			eventsToDisable := make([]string, 0)
@@ -591,11 +808,15 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 					}
 				}
 			} else {
-				guildSettings[env.Guild.ID].LogSettings.LoggingEnabled = false
-				return NewGenericEmbed("Server Settings - Log", "Successfully disabled logging.")
+				guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+					settings.LogSettings.LoggingEnabled = false
+				})
+				return NewGenericEmbed(env.Locale(), "Server Settings - Log", "Successfully disabled logging.")
 			}
 
-			guildSettings[env.Guild.ID].LogSettings.LoggingEvents = *LoggingEventsTmp
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.LogSettings.LoggingEvents = *LoggingEventsTmp
+			})
 
 			responseMessage := ""
 			if len(eventsToDisable) > 0 {
@@ -606,53 +827,562 @@ func commandSettingsServer(args []string, env *CommandEnvironment) *discordgo.Me
 					responseMessage += "\nFailed to find the following events: " + strings.Join(eventsFailed, ", ")
 				}
 			}
-			return NewGenericEmbed("Server Settings - Log", responseMessage)
+			return NewGenericEmbed(env.Locale(), "Server Settings - Log", responseMessage)
 		case "events":
 			responseMessage := "__Event states__\n"
 
-			events := structs.New(guildSettings[env.Guild.ID].LogSettings.LoggingEvents)
+			events := structs.New(guildSettings.Get(env.Guild.ID).LogSettings.LoggingEvents)
 			eventFields := events.Fields()
 
 			for _, event := range eventFields {
 				responseMessage += "\n" + event.Name() + ": **" + strconv.FormatBool(event.Value().(bool)) + "**"
 			}
 
-			return NewGenericEmbed("Server Settings - Log", responseMessage)
+			return NewGenericEmbed(env.Locale(), "Server Settings - Log", responseMessage)
+		}
+		return NewErrorEmbed(env.Locale(), "Server Settings - Log Error", "Unknown log command ``"+args[1]+"``.")
+	case "cooldown":
+		if len(args) < 2 {
+			cooldownHelpCmd := &Command{
+				HelpText: "Manages command cooldowns for this server.",
+				RequiredArguments: []string{
+					"setting (value(s))",
+				},
+				Arguments: []CommandArgument{
+					{Name: "list", Description: "Lists the cooldown overrides and exempt roles", ArgType: "this"},
+					{Name: "set", Description: "Overrides the user, channel or guild cooldown of a command (0 disables it, query sets natural language queries)", ArgType: "command user/channel/guild duration"},
+					{Name: "unset", Description: "Removes the cooldown overrides of a command", ArgType: "command"},
+					{Name: "exempt", Description: "Exempts a role from all cooldowns", ArgType: "role"},
+					{Name: "unexempt", Description: "Removes a role's exemption from cooldowns", ArgType: "role"},
+				},
+			}
+			return getCustomCommandUsage(cooldownHelpCmd, "server cooldown", "Server Settings - Cooldown Help", env)
+		}
+
+		switch args[1] {
+		case "list":
+			cooldownList := ""
+			for commandName, cooldown := range guildSettings.Get(env.Guild.ID).CommandCooldowns {
+				cooldownList += "\n**" + commandName + "**: user " + cooldown.User.String() + ", channel " + cooldown.Channel.String() + ", guild " + cooldown.Guild.String()
+			}
+			if cooldownList == "" {
+				cooldownList = "\nNo cooldown overrides are set, so every command uses its default cooldown."
+			}
+			exemptRoles := make([]string, 0)
+			for _, roleID := range guildSettings.Get(env.Guild.ID).CooldownExemptRoles {
+				exemptRoles = append(exemptRoles, "<@&"+roleID+">")
+			}
+			if len(exemptRoles) == 0 {
+				exemptRoles = append(exemptRoles, "None")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Cooldown", "__Cooldown overrides__"+cooldownList+"\n\n__Exempt roles__\n"+strings.Join(exemptRoles, ", "))
+		case "set":
+			if len(args) < 5 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "You must specify a command, a scope (user, channel or guild) and a duration.")
+			}
+			commandName := strings.ToLower(args[2])
+			defaultCooldown := QueryCooldown
+			if commandName != "query" {
+				command, exists := getBotData().Commands[commandName]
+				if !exists {
+					return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "Unknown command ``"+args[2]+"``.")
+				}
+				if command.IsAlternateOf != "" {
+					commandName = command.IsAlternateOf
+					command = getBotData().Commands[commandName]
+				}
+				defaultCooldown = command.Cooldown
+			}
+			duration, err := parseDuration(args[4])
+			if err != nil || duration < 0 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "``"+args[4]+"`` is not a valid duration.")
+			}
+
+			scope := strings.ToLower(args[3])
+			switch scope {
+			case "user", "channel", "guild", "server":
+			default:
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "Unknown cooldown scope ``"+args[3]+"``, expected user, channel or guild.")
+			}
+
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				if settings.CommandCooldowns == nil {
+					settings.CommandCooldowns = make(map[string]*CommandCooldown)
+				}
+				cooldown, exists := settings.CommandCooldowns[commandName]
+				if !exists {
+					cooldown = &defaultCooldown
+					settings.CommandCooldowns[commandName] = cooldown
+				}
+				switch scope {
+				case "user":
+					cooldown.User = duration
+				case "channel":
+					cooldown.Channel = duration
+				case "guild", "server":
+					cooldown.Guild = duration
+				}
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Cooldown", "Successfully set the "+strings.ToLower(args[3])+" cooldown of ``"+commandName+"`` to "+duration.String()+".")
+		case "unset":
+			if len(args) < 3 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "You must specify a command to remove the cooldown overrides of.")
+			}
+			commandName := getOriginalCommandName(args[2])
+			removed := false
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				_, removed = settings.CommandCooldowns[commandName]
+				delete(settings.CommandCooldowns, commandName)
+			})
+			if !removed {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "No cooldown overrides are set for ``"+commandName+"``.")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Cooldown", "Successfully removed the cooldown overrides of ``"+commandName+"``.")
+		case "exempt", "unexempt":
+			if len(args) < 3 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "You must specify a role.")
+			}
+			role, err := resolveArgumentRole(strings.Join(args[2:], " "), env)
+			if err != nil {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "Unknown role ``"+strings.Join(args[2:], " ")+"``.")
+			}
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.CooldownExemptRoles = remove(settings.CooldownExemptRoles, role.ID)
+				if args[1] == "exempt" {
+					settings.CooldownExemptRoles = append(settings.CooldownExemptRoles, role.ID)
+				}
+			})
+			if args[1] == "unexempt" {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Cooldown", "Successfully removed the cooldown exemption of <@&"+role.ID+">.")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Cooldown", "Successfully exempted <@&"+role.ID+"> from command cooldowns.")
+		}
+		return NewErrorEmbed(env.Locale(), "Server Settings - Cooldown Error", "Unknown cooldown command ``"+args[1]+"``.")
+	case "permissions":
+		if len(args) < 2 {
+			permissionsHelpCmd := &Command{
+				HelpText: "Manages bot admins and who can use each command in this server.",
+				RequiredArguments: []string{
+					"setting (value(s))",
+				},
+				Arguments: []CommandArgument{
+					{Name: "list", Description: "Lists the bot admins, or the rules of a command", ArgType: "this/command"},
+					{Name: "admin", Description: "Grants or revokes bot admin rights for a role or user (guild administrators only)", ArgType: "add/remove role/user"},
+					{Name: "allow", Description: "Allows a role, channel or user to use a command", ArgType: "command role/channel/user"},
+					{Name: "deny", Description: "Denies a role, channel or user from using a command", ArgType: "command role/channel/user"},
+					{Name: "clear", Description: "Removes a role, channel or user from a command's rules", ArgType: "command role/channel/user"},
+					{Name: "reset", Description: "Removes all rules of a command", ArgType: "command"},
+				},
+			}
+			return getCustomCommandUsage(permissionsHelpCmd, "server permissions", "Server Settings - Permissions Help", env)
+		}
+
+		settings, exists := guildSettings.Snapshot(env.Guild.ID)
+		if !exists {
+			settings = &GuildSettings{}
 		}
-		return NewErrorEmbed("Server Settings - Log Error", "Unknown log command ``"+args[1]+"``.")
+		switch args[1] {
+		case "list":
+			if len(args) < 3 {
+				admins := make([]string, 0)
+				for _, roleID := range settings.BotAdminRoles {
+					admins = append(admins, formatPermissionTarget("role", roleID))
+				}
+				for _, userID := range settings.BotAdminUsers {
+					admins = append(admins, formatPermissionTarget("user", userID))
+				}
+				if len(admins) == 0 {
+					admins = append(admins, "None")
+				}
+				commandNames := make([]string, 0)
+				for commandName := range settings.CommandPermissions {
+					commandNames = append(commandNames, "``"+commandName+"``")
+				}
+				if len(commandNames) == 0 {
+					commandNames = append(commandNames, "None")
+				}
+				return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "__Bot admins__\n"+strings.Join(admins, ", ")+"\n\n__Commands with rules__\n"+strings.Join(commandNames, ", "))
+			}
+			commandName := getOriginalCommandName(args[2])
+			permissions, exists := settings.CommandPermissions[commandName]
+			if !exists {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "No rules are set for ``"+commandName+"``.")
+			}
+			rules := ""
+			ruleLists := []struct {
+				name       string
+				targetType string
+				targetIDs  []string
+			}{
+				{"Allowed users", "user", permissions.AllowedUsers},
+				{"Denied users", "user", permissions.DeniedUsers},
+				{"Allowed channels", "channel", permissions.AllowedChannels},
+				{"Denied channels", "channel", permissions.DeniedChannels},
+				{"Allowed roles", "role", permissions.AllowedRoles},
+				{"Denied roles", "role", permissions.DeniedRoles},
+			}
+			for _, ruleList := range ruleLists {
+				if len(ruleList.targetIDs) == 0 {
+					continue
+				}
+				targets := make([]string, 0)
+				for _, targetID := range ruleList.targetIDs {
+					targets = append(targets, formatPermissionTarget(ruleList.targetType, targetID))
+				}
+				rules += "\n**" + ruleList.name + "**: " + strings.Join(targets, ", ")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "__Rules for ``"+commandName+"``__"+rules)
+		case "admin":
+			if !isGuildAdmin(env) {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Only guild administrators can manage bot admins.")
+			}
+			if len(args) < 4 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "You must specify add or remove followed by a role or user.")
+			}
+			if args[2] != "add" && args[2] != "remove" {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Unknown admin command ``"+args[2]+"``.")
+			}
+			targetType, targetID := resolvePermissionTarget(strings.Join(args[3:], " "), env)
+			if targetType != "role" && targetType != "user" {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Unknown role or user ``"+strings.Join(args[3:], " ")+"``.")
+			}
+			listed := false
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				if targetType == "role" {
+					listed = containsString(settings.BotAdminRoles, targetID)
+					settings.BotAdminRoles = remove(settings.BotAdminRoles, targetID)
+					if args[2] == "add" {
+						settings.BotAdminRoles = append(settings.BotAdminRoles, targetID)
+					}
+				} else {
+					listed = containsString(settings.BotAdminUsers, targetID)
+					settings.BotAdminUsers = remove(settings.BotAdminUsers, targetID)
+					if args[2] == "add" {
+						settings.BotAdminUsers = append(settings.BotAdminUsers, targetID)
+					}
+				}
+			})
+			if args[2] == "remove" && !listed {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", formatPermissionTarget(targetType, targetID)+" isn't a bot admin.")
+			}
+			if args[2] == "add" {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully granted bot admin rights to "+formatPermissionTarget(targetType, targetID)+".")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully revoked bot admin rights from "+formatPermissionTarget(targetType, targetID)+".")
+		case "allow", "deny", "clear":
+			if len(args) < 4 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "You must specify a command followed by a role, channel or user.")
+			}
+			commandName := getOriginalCommandName(args[2])
+			command, exists := getBotData().Commands[commandName]
+			if !exists {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Unknown command ``"+args[2]+"``.")
+			}
+			//An allow rule skips the command's required permissions, so only those who hold every permission may grant it
+			if args[1] == "allow" && command.RequiredPermissions != 0 && !isGuildAdmin(env) {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Only guild administrators can allow others to use ``"+commandName+"``, as it requires permissions of its own.")
+			}
+			targetType, targetID := resolvePermissionTarget(strings.Join(args[3:], " "), env)
+			if targetType == "" {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Unknown role, channel or user ``"+strings.Join(args[3:], " ")+"``.")
+			}
+
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				if settings.CommandPermissions == nil {
+					settings.CommandPermissions = make(map[string]*CommandPermissions)
+				}
+				permissions, exists := settings.CommandPermissions[commandName]
+				if !exists {
+					permissions = &CommandPermissions{}
+					settings.CommandPermissions[commandName] = permissions
+				}
+				switch args[1] {
+				case "allow":
+					permissions.Allow(targetType, targetID)
+				case "deny":
+					permissions.Deny(targetType, targetID)
+				default:
+					permissions.Clear(targetType, targetID)
+				}
+				if permissions.IsEmpty() {
+					delete(settings.CommandPermissions, commandName)
+				}
+			})
+			target := formatPermissionTarget(targetType, targetID)
+			switch args[1] {
+			case "allow":
+				return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully allowed "+target+" to use ``"+commandName+"``.")
+			case "deny":
+				return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully denied "+target+" from using ``"+commandName+"``.")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully removed "+target+" from the rules of ``"+commandName+"``.")
+		case "reset":
+			if len(args) < 3 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "You must specify a command to remove the rules of.")
+			}
+			commandName := getOriginalCommandName(args[2])
+			removed := false
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				_, removed = settings.CommandPermissions[commandName]
+				delete(settings.CommandPermissions, commandName)
+			})
+			if !removed {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "No rules are set for ``"+commandName+"``.")
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Permissions", "Successfully removed all rules of ``"+commandName+"``.")
+		}
+		return NewErrorEmbed(env.Locale(), "Server Settings - Permissions Error", "Unknown permissions command ``"+args[1]+"``.")
+	case "commands":
+		if len(args) < 2 {
+			commandsHelpCmd := &Command{
+				HelpText: "Enables, disables or restricts commands and command categories in this server.",
+				RequiredArguments: []string{
+					"setting (value(s))",
+				},
+				Arguments: []CommandArgument{
+					{Name: "list", Description: "Lists the disabled commands and categories and any channel restrictions", ArgType: "this"},
+					{Name: "enable", Description: "Enables a command or category", ArgType: "command/category"},
+					{Name: "disable", Description: "Disables a command or category", ArgType: "command/category"},
+					{Name: "channels", Description: "Restricts a command or category to channels (this channel if none are given), or clears the restriction", ArgType: "command/category add/remove/clear (channels)"},
+				},
+			}
+			return getCustomCommandUsage(commandsHelpCmd, "server commands", "Server Settings - Commands Help", env)
+		}
+
+		if args[1] == "list" {
+			settings, exists := guildSettings.Snapshot(env.Guild.ID)
+			if !exists {
+				settings = &GuildSettings{}
+			}
+			disabled := make([]string, 0)
+			for _, category := range CommandCategories {
+				if isCategoryDisabled(settings, category) {
+					disabled = append(disabled, "``"+category+"`` (category)")
+				}
+			}
+			for _, commandName := range settings.DisabledCommands {
+				disabled = append(disabled, "``"+commandName+"``")
+			}
+			if len(disabled) == 0 {
+				disabled = append(disabled, "None")
+			}
+			restrictions := ""
+			for _, category := range CommandCategories {
+				if channels := settings.CategoryChannels[category]; len(channels) > 0 {
+					restrictedChannels := make([]string, 0)
+					for _, channelID := range channels {
+						restrictedChannels = append(restrictedChannels, formatPermissionTarget("channel", channelID))
+					}
+					restrictions += "\n**" + category + "** (category): " + strings.Join(restrictedChannels, ", ")
+				}
+			}
+			for commandName, permissions := range settings.CommandPermissions {
+				if len(permissions.AllowedChannels) > 0 {
+					restrictedChannels := make([]string, 0)
+					for _, channelID := range permissions.AllowedChannels {
+						restrictedChannels = append(restrictedChannels, formatPermissionTarget("channel", channelID))
+					}
+					restrictions += "\n**" + commandName + "**: " + strings.Join(restrictedChannels, ", ")
+				}
+			}
+			if restrictions == "" {
+				restrictions = "\nNone"
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Commands", "__Disabled__\n"+strings.Join(disabled, ", ")+"\n\n__Channel restrictions__"+restrictions)
+		}
+		if len(args) < 3 {
+			return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "You must specify a command or category.")
+		}
+
+		target := strings.ToLower(args[2])
+		isCategory := false
+		for _, category := range CommandCategories {
+			if target == category {
+				isCategory = true
+				break
+			}
+		}
+		if isCategory {
+			if !isCategoryToggleable(target) {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "The ``"+target+"`` category can't be disabled or restricted.")
+			}
+		} else {
+			target = getOriginalCommandName(target)
+			command, exists := getBotData().Commands[target]
+			if !exists {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "Unknown command or category ``"+args[2]+"``.")
+			}
+			if !isCategoryToggleable(command.Category) {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "``"+target+"`` can't be disabled or restricted.")
+			}
+		}
+
+		switch args[1] {
+		case "enable", "disable":
+			enable := args[1] == "enable"
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				if isCategory && target == CommandCategoryVoice {
+					settings.AllowVoice = &enable
+				} else if isCategory {
+					settings.DisabledCategories = remove(settings.DisabledCategories, target)
+					if !enable {
+						settings.DisabledCategories = append(settings.DisabledCategories, target)
+					}
+				} else {
+					settings.DisabledCommands = remove(settings.DisabledCommands, target)
+					if !enable {
+						settings.DisabledCommands = append(settings.DisabledCommands, target)
+					}
+				}
+			})
+			return NewGenericEmbed(env.Locale(), "Server Settings - Commands", "Successfully "+args[1]+"d ``"+target+"``.")
+		case "channels":
+			if len(args) < 4 {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "You must specify add, remove or clear.")
+			}
+
+			channelIDs := []string{env.Channel.ID}
+			switch args[3] {
+			case "add", "remove":
+				if len(args) > 4 {
+					channelIDs = make([]string, 0)
+					for _, value := range args[4:] {
+						channel, err := resolveArgumentChannel(value, env)
+						if err != nil {
+							return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "Unknown channel ``"+value+"``.")
+						}
+						channelIDs = append(channelIDs, channel.ID)
+					}
+				}
+			case "clear":
+			default:
+				return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "Unknown channels command ``"+args[3]+"``.")
+			}
+
+			var channels []string
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				if isCategory {
+					channels = append(channels, settings.CategoryChannels[target]...)
+				} else if permissions, exists := settings.CommandPermissions[target]; exists {
+					channels = append(channels, permissions.AllowedChannels...)
+				}
+
+				switch args[3] {
+				case "clear":
+					channels = nil
+				default:
+					for _, channelID := range channelIDs {
+						channels = remove(channels, channelID)
+						if args[3] == "add" {
+							channels = append(channels, channelID)
+						}
+					}
+				}
+
+				if isCategory {
+					if settings.CategoryChannels == nil {
+						settings.CategoryChannels = make(map[string][]string)
+					}
+					settings.CategoryChannels[target] = append([]string{}, channels...) //The response lists channels after the lock is released
+					if len(channels) == 0 {
+						delete(settings.CategoryChannels, target)
+					}
+				} else {
+					if settings.CommandPermissions == nil {
+						settings.CommandPermissions = make(map[string]*CommandPermissions)
+					}
+					if _, exists := settings.CommandPermissions[target]; !exists {
+						settings.CommandPermissions[target] = &CommandPermissions{}
+					}
+					settings.CommandPermissions[target].AllowedChannels = append([]string{}, channels...)
+					if settings.CommandPermissions[target].IsEmpty() {
+						delete(settings.CommandPermissions, target)
+					}
+				}
+			})
+
+			if len(channels) == 0 {
+				return NewGenericEmbed(env.Locale(), "Server Settings - Commands", "``"+target+"`` can now be used in any channel.")
+			}
+			restrictedChannels := make([]string, 0)
+			for _, channelID := range channels {
+				restrictedChannels = append(restrictedChannels, formatPermissionTarget("channel", channelID))
+			}
+			return NewGenericEmbed(env.Locale(), "Server Settings - Commands", "``"+target+"`` can now only be used in "+strings.Join(restrictedChannels, ", ")+".")
+		}
+		return NewErrorEmbed(env.Locale(), "Server Settings - Commands Error", "Unknown commands command ``"+args[1]+"``.")
 	case "reset":
 		if len(args) < 2 {
-			return NewErrorEmbed("Server Settings - Reset Error", "You must specify a setting to reset.")
+			return NewErrorEmbed(env.Locale(), "Server Settings - Reset Error", "You must specify a setting to reset.")
 		}
 		switch args[1] {
 		case "joinmsg":
-			guildSettings[env.Guild.ID].UserJoinMessage = ""
-			guildSettings[env.Guild.ID].UserJoinMessageChannel = ""
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.UserJoinMessage = ""
+				settings.UserJoinMessageChannel = ""
+			})
 		case "leavemsg":
-			guildSettings[env.Guild.ID].UserLeaveMessage = ""
-			guildSettings[env.Guild.ID].UserLeaveMessageChannel = ""
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.UserLeaveMessage = ""
+				settings.UserLeaveMessageChannel = ""
+			})
 		case "log":
-			guildSettings[env.Guild.ID].LogSettings.LoggingChannel = ""
-			guildSettings[env.Guild.ID].LogSettings.LoggingEnabled = false
-			guildSettings[env.Guild.ID].LogSettings.LoggingEvents = LogEvents{}
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.LogSettings.LoggingChannel = ""
+				settings.LogSettings.LoggingEnabled = false
+				settings.LogSettings.LoggingEvents = LogEvents{}
+			})
 		case "filter":
-			guildSettings[env.Guild.ID].SwearFilter.Enabled = false
-			guildSettings[env.Guild.ID].SwearFilter.BlacklistedWords = make([]string, 0)
-			guildSettings[env.Guild.ID].SwearFilter.DisableNormalize = false
-			guildSettings[env.Guild.ID].SwearFilter.DisableSpacedTab = false
-			guildSettings[env.Guild.ID].SwearFilter.DisableMultiWhitespaceStripping = false
-			guildSettings[env.Guild.ID].SwearFilter.DisableZeroWidthStripping = false
-			guildSettings[env.Guild.ID].SwearFilter.DisableSpacedBypass = false
-			guildSettings[env.Guild.ID].SwearFilter.WarningDeleteTimeout = time.Duration(0)
-			guildSettings[env.Guild.ID].SwearFilter.AllowAdminBypass = false
-			guildSettings[env.Guild.ID].SwearFilter.AllowBotOwnerBypass = false
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.SwearFilter.Enabled = false
+				settings.SwearFilter.BlacklistedWords = make([]string, 0)
+				settings.SwearFilter.DisableNormalize = false
+				settings.SwearFilter.DisableSpacedTab = false
+				settings.SwearFilter.DisableMultiWhitespaceStripping = false
+				settings.SwearFilter.DisableZeroWidthStripping = false
+				settings.SwearFilter.DisableSpacedBypass = false
+				settings.SwearFilter.WarningDeleteTimeout = time.Duration(0)
+				settings.SwearFilter.AllowAdminBypass = false
+				settings.SwearFilter.AllowBotOwnerBypass = false
+			})
+		case "suggestions":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.DisableSuggestions = false
+			})
+		case "language", "lang":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.Language = ""
+			})
 		case "invitegen":
-			guildSettings[env.Guild.ID].APIInviteChannel = ""
-			guildSettings[env.Guild.ID].APIInviteKey = ""
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.APIInviteChannel = ""
+				settings.APIInviteKey = ""
+			})
+		case "cooldown":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.CommandCooldowns = nil
+				settings.CooldownExemptRoles = nil
+			})
+		case "commands":
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.AllowVoice = nil
+				settings.DisabledCommands = nil
+				settings.DisabledCategories = nil
+				settings.CategoryChannels = nil
+			})
+		case "permissions":
+			if !isGuildAdmin(env) {
+				return NewErrorEmbed(env.Locale(), "Server Settings - Reset Error", "Only guild administrators can reset bot admins and command rules.")
+			}
+			guildSettings.Update(env.Guild.ID, func(settings *GuildSettings) {
+				settings.BotAdminRoles = nil
+				settings.BotAdminUsers = nil
+				settings.CommandPermissions = nil
+			})
 		default:
-			return NewErrorEmbed("Server Settings - Reset Error", "Error finding the setting ``"+args[1]+"``.")
+			return NewErrorEmbed(env.Locale(), "Server Settings - Reset Error", "Error finding the setting ``"+args[1]+"``.")
 		}
-		return NewGenericEmbed("Server Settings - Reset", "Successfully reset the settings for ``"+args[1]+"``.")
+		return NewGenericEmbed(env.Locale(), "Server Settings - Reset", "Successfully reset the settings for ``"+args[1]+"``.")
 	}
-	return NewErrorEmbed("Server Settings Error", "Error finding the setting ``"+args[0]+"``.")
+	return NewErrorEmbed(env.Locale(), "Server Settings Error", "Error finding the setting ``"+args[0]+"``.")
 }
@@ -0,0 +1,595 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	// SnapshotDir contains the directory state snapshots are written to
+	SnapshotDir = "state/snapshots"
+
+	// SnapshotTimeFormat contains the format of the time in a snapshot's name
+	SnapshotTimeFormat = "20060102-150405"
+
+	// SnapshotDiffMaxLines contains how many lines of a diff are shown in Discord
+	SnapshotDiffMaxLines = 20
+
+	// Defaults for snapshot configuration options that weren't set
+	DefaultSnapshotInterval  = 60 //Minutes
+	DefaultSnapshotRetention = 24
+)
+
+// SnapshotGuildBuckets contains the buckets holding a guild's settings, which are restored when restoring a single guild
+var SnapshotGuildBuckets = []string{StateBucketGuildSettings, StateBucketStarboards}
+
+var (
+	// snapshotListArguments contains the values taken by snapshot list
+	snapshotListArguments = []CommandArgument{
+		{Name: "page", Description: "The page of snapshots to view", ArgType: ArgTypeNumber, Optional: true, Min: 1},
+	}
+)
+
+var (
+	errSnapshotName    = errors.New("invalid snapshot name")
+	errSnapshotSchema  = errors.New("the snapshot is from an older schema version, so only all of it can be restored")
+	errSnapshotNoGuild = errors.New("the snapshot has no settings for that guild")
+)
+
+// StateSnapshot holds every entry of the state at a point in time, where key = bucket and the value's key = entry key
+type StateSnapshot map[string]map[string][]byte
+
+// SnapshotInfo holds the details of a snapshot archive
+type SnapshotInfo struct {
+	Name    string    //The name of the snapshot, which is its file name without the extension
+	Path    string    //The path to the snapshot archive
+	Created time.Time //When the snapshot was taken
+	Size    int64     //The size of the archive in bytes
+}
+
+// stateDump returns a copy of every entry in the state store
+func stateDump() (StateSnapshot, error) {
+	if stateStore == nil {
+		return nil, errors.New("the state store isn't open")
+	}
+
+	snapshot := make(StateSnapshot)
+	for _, bucket := range StateBuckets {
+		snapshot[bucket] = make(map[string][]byte)
+		err := stateStore.Load(bucket, func(key string, data []byte) error {
+			snapshot[bucket][key] = append([]byte{}, data...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// createSnapshot writes everything in the state store to a new snapshot archive, then removes the oldest snapshots past the retention
+//
+// The bot should save the state first so the snapshot includes what hasn't been saved yet.
+func createSnapshot() (*SnapshotInfo, error) {
+	snapshot, err := stateDump()
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+	for bucket, entries := range snapshot {
+		for key, data := range entries {
+			file, err := zipWriter.Create(bucket + "/" + key + ".json")
+			if err != nil {
+				return nil, err
+			}
+			if _, err := file.Write(data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(SnapshotDir, 0744); err != nil {
+		return nil, err
+	}
+	created := time.Now()
+	info := &SnapshotInfo{Name: "snapshot-" + created.Format(SnapshotTimeFormat), Created: created, Size: int64(archive.Len())}
+	info.Path = filepath.Join(SnapshotDir, info.Name+".zip")
+	if err := writeFileAtomic(info.Path, archive.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+
+	if err := pruneSnapshots(getBotData().BotOptions.Snapshots.Retention); err != nil {
+		Error.Printf("Error removing old snapshots: %s\n", err)
+	}
+	return info, nil
+}
+
+// pruneSnapshots removes the oldest snapshots until only the specified amount are left
+func pruneSnapshots(retention int) error {
+	snapshots, err := listSnapshots()
+	if err != nil {
+		return err
+	}
+	for i := retention; i < len(snapshots); i++ {
+		Debug.Printf("Removing old snapshot %s\n", snapshots[i].Name)
+		if err := os.Remove(snapshots[i].Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listSnapshots returns every snapshot, newest first
+func listSnapshots() ([]*SnapshotInfo, error) {
+	files, err := filepath.Glob(filepath.Join(SnapshotDir, "snapshot-*.zip"))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*SnapshotInfo, 0)
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".zip")
+		created, err := time.ParseInLocation(SnapshotTimeFormat, strings.TrimPrefix(name, "snapshot-"), time.Local)
+		if err != nil {
+			continue //Not a snapshot we wrote
+		}
+		stat, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, &SnapshotInfo{Name: name, Path: file, Created: created, Size: stat.Size()})
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.After(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// loadSnapshot reads every entry of a snapshot archive, where name may leave out the snapshot- prefix
+func loadSnapshot(name string) (StateSnapshot, error) {
+	name = strings.TrimSuffix(name, ".zip")
+	if !strings.HasPrefix(name, "snapshot-") {
+		name = "snapshot-" + name
+	}
+	if strings.ContainsAny(name, `/\`) {
+		return nil, errSnapshotName
+	}
+
+	zipReader, err := zip.OpenReader(filepath.Join(SnapshotDir, name+".zip"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("no snapshot named " + name + " exists")
+		}
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	snapshot := make(StateSnapshot)
+	for _, bucket := range StateBuckets {
+		snapshot[bucket] = make(map[string][]byte)
+	}
+	for _, file := range zipReader.File {
+		bucket, key := path.Split(strings.TrimSuffix(file.Name, ".json"))
+		bucket = strings.TrimSuffix(bucket, "/")
+		if _, exists := snapshot[bucket]; !exists {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := ioutil.ReadAll(fileReader)
+		fileReader.Close()
+		if err != nil {
+			return nil, err
+		}
+		snapshot[bucket][key] = data
+	}
+	return snapshot, nil
+}
+
+// SchemaVersion returns the state schema version the snapshot was taken at
+func (snapshot StateSnapshot) SchemaVersion() int {
+	version := 0
+	json.Unmarshal(snapshot[StateBucketMeta][StateKeySchemaVersion], &version)
+	return version
+}
+
+// diffSnapshot returns a line for every entry that was added, removed or changed since the snapshot was taken, only looking at a guild's settings if guildID isn't empty
+func diffSnapshot(snapshot, live StateSnapshot, guildID string) []string {
+	buckets := StateBuckets
+	if guildID != "" {
+		buckets = SnapshotGuildBuckets
+	}
+
+	diff := make([]string, 0)
+	for _, bucket := range buckets {
+		if bucket == StateBucketMeta {
+			continue
+		}
+
+		keys := make([]string, 0)
+		for key := range snapshot[bucket] {
+			keys = append(keys, key)
+		}
+		for key := range live[bucket] {
+			if _, exists := snapshot[bucket][key]; !exists {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if guildID != "" && key != guildID {
+				continue
+			}
+			oldData, inSnapshot := snapshot[bucket][key]
+			newData, inLive := live[bucket][key]
+			switch {
+			case !inLive:
+				diff = append(diff, "- "+bucket+"/"+key+" (removed since)")
+			case !inSnapshot:
+				diff = append(diff, "+ "+bucket+"/"+key+" (added since)")
+			default:
+				if fields := diffStateEntry(oldData, newData); fields != nil {
+					line := "~ " + bucket + "/" + key
+					if len(fields) > 0 {
+						line += ": " + strings.Join(fields, ", ")
+					}
+					diff = append(diff, line)
+				}
+			}
+		}
+	}
+	return diff
+}
+
+// diffStateEntry returns the names of the fields that differ between two versions of an entry, an empty list if
+// the entries differ but aren't objects, or nil if they're the same
+func diffStateEntry(oldData, newData []byte) []string {
+	oldCompact, newCompact := &bytes.Buffer{}, &bytes.Buffer{}
+	if json.Compact(oldCompact, oldData) == nil && json.Compact(newCompact, newData) == nil && bytes.Equal(oldCompact.Bytes(), newCompact.Bytes()) {
+		return nil
+	}
+
+	oldFields, newFields := make(map[string]json.RawMessage), make(map[string]json.RawMessage)
+	if json.Unmarshal(oldData, &oldFields) != nil || json.Unmarshal(newData, &newFields) != nil {
+		return []string{}
+	}
+	fields := make([]string, 0)
+	for field, oldValue := range oldFields {
+		if newValue, exists := newFields[field]; !exists || !bytes.Equal(oldValue, newValue) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range newFields {
+		if _, exists := oldFields[field]; !exists {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// stateWriteSnapshot replaces the entries in a state store with those of a snapshot, only replacing a guild's settings if guildID isn't empty
+func stateWriteSnapshot(store StateStore, snapshot StateSnapshot, guildID string) error {
+	if guildID != "" {
+		if snapshot.SchemaVersion() != StateSchemaVersion {
+			return errSnapshotSchema
+		}
+		found := false
+		for _, bucket := range SnapshotGuildBuckets {
+			if data, exists := snapshot[bucket][guildID]; exists {
+				found = true
+				if err := store.Save(bucket, map[string][]byte{guildID: data}); err != nil {
+					return err
+				}
+			} else if err := store.Delete(bucket, guildID); err != nil {
+				return err
+			}
+		}
+		if !found {
+			return errSnapshotNoGuild
+		}
+		return nil
+	}
+
+	for _, bucket := range StateBuckets {
+		missing := make([]string, 0)
+		err := store.Load(bucket, func(key string, data []byte) error {
+			if _, exists := snapshot[bucket][key]; !exists {
+				missing = append(missing, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			if err := store.Delete(bucket, missing...); err != nil {
+				return err
+			}
+		}
+		if len(snapshot[bucket]) > 0 {
+			if err := store.Save(bucket, snapshot[bucket]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreGuildSnapshot replaces a guild's settings and starboard in memory with those of a snapshot and saves them
+//
+// It waits until no settings command is running in the guild, giving up once ctx ends.
+func restoreGuildSnapshot(ctx context.Context, snapshot StateSnapshot, guildID string) error {
+	if snapshot.SchemaVersion() != StateSchemaVersion {
+		return errSnapshotSchema
+	}
+	data, exists := snapshot[StateBucketGuildSettings][guildID]
+	if !exists {
+		return errSnapshotNoGuild
+	}
+	settings := &GuildSettings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return err
+	}
+	var starboard *Starboard
+	if data, exists := snapshot[StateBucketStarboards][guildID]; exists {
+		starboard = &Starboard{}
+		if err := json.Unmarshal(data, starboard); err != nil {
+			return err
+		}
+	}
+
+	unlock, err := lockGuildSettings(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	initializeGuildData(guildID)
+	guildData.Get(guildID).Lock()
+	defer guildData.Get(guildID).Unlock()
+
+	//Feeds check for updates on timers of their own, which need to be started again
+	oldFeeds := settings.Feeds
+	settings.Feeds = make([]*Feed, 0)
+	guildSettings.Store(guildID, settings)
+	for _, feed := range oldFeeds {
+		if err := addFeed(guildID, feed.ChannelID, feed.FeedURL, feed.Frequency); err != nil {
+			Error.Printf("Error adding feed [%s]: %v\n", feed.FeedLink, err)
+		}
+	}
+
+	if starboard != nil {
+		starboards.Store(guildID, starboard)
+	} else {
+		starboards.Delete(guildID)
+	}
+	stateSaveGuild(guildID, "")
+	if starboard == nil {
+		stateDeleteMissing(StateBucketStarboards, func(key string) bool { _, exists := starboards.Load(key); return exists })
+	}
+	scheduler.Rebuild()
+	return nil
+}
+
+// restoreSnapshot replaces all of the state with a snapshot and restarts the bot to load it
+func restoreSnapshot(snapshot StateSnapshot, channelID string) error {
+	store := stateStore
+	if store == nil {
+		return errors.New("the state store isn't open")
+	}
+	stateStore = nil //Nothing may save over the restored state before restarting
+
+	if err := stateWriteSnapshot(store, snapshot, ""); err != nil {
+		stateStore = store
+		return err
+	}
+	store.Close()
+
+	//Write the current channel ID to a restart file for the bot to read after the restart
+	ioutil.WriteFile(".restart", []byte(channelID), 0644)
+
+	//Close the bot process, as the MASTER process will open it again
+	os.Exit(0)
+	return nil
+}
+
+func commandSnapshot(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	switch args[0] {
+	case "create":
+		stateSaveAll()
+		info, err := createSnapshot()
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to create a snapshot: "+err.Error())
+		}
+		return NewGenericEmbed(env.Locale(), "Snapshot", "Successfully created snapshot ``"+info.Name+"`` ("+humanize.Bytes(uint64(info.Size))+").")
+	case "list":
+		parsed, usageEmbed := parseSubcommandArguments(snapshotListArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
+		}
+		pageNumber := 1
+		if parsed.Has("page") {
+			pageNumber = parsed.Int("page")
+		}
+		snapshots, err := listSnapshots()
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to list snapshots: "+err.Error())
+		}
+		if len(snapshots) == 0 {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "There are no snapshots yet.")
+		}
+
+		snapshotList := make([]*discordgo.MessageEmbedField, 0)
+		for _, info := range snapshots {
+			snapshotList = append(snapshotList, &discordgo.MessageEmbedField{Name: info.Name, Value: humanize.Time(info.Created) + " - " + humanize.Bytes(uint64(info.Size))})
+		}
+		snapshotEmbed, totalPages, err := page(snapshotList, pageNumber, getBotData().BotOptions.HelpMaxResults)
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", err.Error())
+		}
+		return snapshotEmbed.
+			SetTitle("Snapshots").
+			SetDescription("The snapshots of the state, newest first.").
+			SetFooter("Page " + strconv.Itoa(pageNumber) + " of " + strconv.Itoa(totalPages) + " | " + env.BotPrefix + env.Command + " list {page}").
+			SetColor(0x1C1C1C).MessageEmbed
+	case "diff":
+		if len(args) < 2 {
+			return getCommandUsage(env.Command, "Snapshot - Command Usage", env)
+		}
+		snapshot, err := loadSnapshot(args[1])
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to load the snapshot: "+err.Error())
+		}
+		guildID := ""
+		if len(args) > 2 {
+			guildID = args[2]
+		}
+
+		stateSaveAll()
+		live, err := stateDump()
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to read the state: "+err.Error())
+		}
+		diff := diffSnapshot(snapshot, live, guildID)
+		if len(diff) == 0 {
+			return NewGenericEmbedAdvanced("Snapshot", "Nothing has changed since ``"+args[1]+"``.", 0x1C1C1C)
+		}
+		if len(diff) > SnapshotDiffMaxLines {
+			diff = append(diff[:SnapshotDiffMaxLines], "... and "+strconv.Itoa(len(diff)-SnapshotDiffMaxLines)+" more")
+		}
+		return NewEmbed().
+			SetTitle("Snapshot - Diff").
+			SetDescription("```diff\n" + strings.Join(diff, "\n") + "```").
+			SetFooter("+ added, - removed, ~ changed since the snapshot").
+			SetColor(0x1C1C1C).MessageEmbed
+	case "restore":
+		if len(args) < 3 {
+			return getCommandUsage(env.Command, "Snapshot - Command Usage", env)
+		}
+		snapshot, err := loadSnapshot(args[1])
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to load the snapshot: "+err.Error())
+		}
+
+		if args[2] != "all" {
+			if err := restoreGuildSnapshot(env.Context, snapshot, args[2]); err != nil {
+				return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to restore the snapshot: "+err.Error())
+			}
+			return NewGenericEmbed(env.Locale(), "Snapshot", "Successfully restored the settings of guild ``"+args[2]+"`` from ``"+args[1]+"``.")
+		}
+
+		//Take a snapshot of what's being replaced in case the restore was a mistake
+		stateSaveAll()
+		info, err := createSnapshot()
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to snapshot the current state before restoring: "+err.Error())
+		}
+		getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewGenericEmbed(env.Locale(), "Snapshot", "Restoring ``"+args[1]+"`` and restarting "+getBotData().BotName+"... The state before restoring was saved as ``"+info.Name+"``."))
+		if err := restoreSnapshot(snapshot, env.Channel.ID); err != nil {
+			return NewErrorEmbed(env.Locale(), "Snapshot Error", "Unable to restore the snapshot: "+err.Error())
+		}
+		return nil
+	}
+	return getCommandUsage(env.Command, "Snapshot - Command Usage", env)
+}
+
+// runSnapshotCLI runs a snapshot command from the command line, returning the exit code
+//
+// The command is one of: list, create, diff NAME [guildID], restore NAME all|guildID
+func runSnapshotCLI(command string) int {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	store, err := openStateStore(getBotData().BotOptions.StateBackend)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to open the state store, is the bot still running? %v\n", err)
+		return 1
+	}
+	stateStore = store
+	defer stateClose()
+
+	switch args[0] {
+	case "list":
+		snapshots, err := listSnapshots()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, info := range snapshots {
+			fmt.Printf("%s\t%s\t%s\n", info.Name, info.Created.Format(time.RFC1123), humanize.Bytes(uint64(info.Size)))
+		}
+		return 0
+	case "create":
+		info, err := createSnapshot()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println("Created " + info.Path)
+		return 0
+	case "diff", "restore":
+		if len(args) < 2 || (args[0] == "restore" && len(args) < 3) {
+			break
+		}
+		snapshot, err := loadSnapshot(args[1])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		if args[0] == "diff" {
+			live, err := stateDump()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			guildID := ""
+			if len(args) > 2 {
+				guildID = args[2]
+			}
+			for _, line := range diffSnapshot(snapshot, live, guildID) {
+				fmt.Println(line)
+			}
+			return 0
+		}
+
+		guildID := args[2]
+		if guildID == "all" {
+			guildID = ""
+		}
+		if err := stateWriteSnapshot(store, snapshot, guildID); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println("Restored " + args[1])
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, "Usage: -snapshot \"list\", \"create\", \"diff NAME [guildID]\" or \"restore NAME all|guildID\"")
+	return 1
+}
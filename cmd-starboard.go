@@ -9,6 +9,13 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+var (
+	// starboardMinimumArguments contains the values taken by starboard minimum
+	starboardMinimumArguments = []CommandArgument{
+		{Name: "minimum", Description: "The minimum reactions a message needs to be added to the starboard", ArgType: ArgTypeNumber, Min: 1},
+	}
+)
+
 // Starboard holds data specific to a guild's starboard
 type Starboard struct {
 	Active            bool             //Whether or not the starboard is active
@@ -31,18 +38,19 @@ type StarboardEntry struct {
 	SourceMessageID    string //The source message ID
 	StarboardChannelID string //The channel ID the starboard entry message resides in (just in case the starboard channel changes and messages need to be moved to a new channel)
 	StarboardMessageID string //The starboard entry's message ID
+	AuthorID           string //The user ID of the source message's author, empty for entries made before authors were kept
 	Stars              int    //The amount of stars on this entry
 }
 
 func commandStarboard(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "debug":
-		if env.User.ID != botData.BotOwnerID {
-			NewErrorEmbed("Command Error - Not Authorized (NA)", "You are not authorized to use this command.")
+		if env.User.ID != getBotData().BotOwnerID {
+			return NewErrorEmbed(env.Locale(), "Command Error - Not Authorized (NA)", "You are not authorized to use this command.")
 		}
-		starboard := starboards[env.Guild.ID]
+		starboard, _ := starboards.Snapshot(env.Guild.ID)
 		json, _ := json.MarshalIndent(starboard, "", "")
-		return NewGenericEmbed("Starboard - Debug", string(json))
+		return NewGenericEmbedAdvanced("Starboard - Debug", redactSecrets(string(json)), 0x1C1C1C)
 	case "stats":
 		//Go through starboard for this guild and only pull entries from the caller
 		//Build list in embed
@@ -52,28 +60,35 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 	case "minimum":
 		if len(args) == 1 {
 			if env.Channel.NSFW {
-				return NewGenericEmbed("Starboard", "Minimum required "+starboards[env.Guild.ID].NSFWEmoji+" reactions: "+strconv.Itoa(starboards[env.Guild.ID].MinimumStars))
+				return NewGenericEmbed(env.Locale(), "Starboard", "Minimum required "+starboards.Get(env.Guild.ID).NSFWEmoji+" reactions: "+strconv.Itoa(starboards.Get(env.Guild.ID).MinimumStars))
 			}
-			return NewGenericEmbed("Starboard", "Minimum required "+starboards[env.Guild.ID].Emoji+" reactions: "+strconv.Itoa(starboards[env.Guild.ID].MinimumStars))
+			return NewGenericEmbed(env.Locale(), "Starboard", "Minimum required "+starboards.Get(env.Guild.ID).Emoji+" reactions: "+strconv.Itoa(starboards.Get(env.Guild.ID).MinimumStars))
 		}
 
-		minimum, err := strconv.Atoi(args[1])
-		if err != nil {
-			return NewErrorEmbed("Starboard Error", "``"+args[1]+"`` is not a valid number.")
+		parsed, usageEmbed := parseSubcommandArguments(starboardMinimumArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
 		}
+		minimum := parsed.Int("minimum")
 
-		starboards[env.Guild.ID].MinimumStars = minimum
-		return NewGenericEmbed("Starboard", "Successfully set the minimum required reactions to "+args[1]+".")
+		starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+			starboard.MinimumStars = minimum
+		})
+		return NewGenericEmbed(env.Locale(), "Starboard", "Successfully set the minimum required reactions to "+strconv.Itoa(minimum)+".")
 	case "leaderboard":
 		if len(args) == 1 {
-			//Go through starboard for this guild
-			starboardEntries := starboards[env.Guild.ID].StarboardEntries
+			//Go through a copy of the starboard for this guild, as the leaderboard drops entries from it while sorting
+			starboard, exists := starboards.Snapshot(env.Guild.ID)
+			if !exists {
+				starboard = &Starboard{}
+			}
+			starboardEntries := starboard.StarboardEntries
 
 			//Remove NSFW starboard entries from leaderboard if not NSFW channel
 			if env.Channel.NSFW == false {
 				for i, starboardEntry := range starboardEntries {
-					if starboardEntry.SourceChannelID == starboards[env.Guild.ID].NSFWChannelID {
-						starboardEntries = append(starboards[env.Guild.ID].StarboardEntries[:i], starboards[env.Guild.ID].StarboardEntries[i+1])
+					if starboardEntry.SourceChannelID == starboard.NSFWChannelID {
+						starboardEntries = append(starboard.StarboardEntries[:i], starboard.StarboardEntries[i+1])
 						i--
 					}
 				}
@@ -90,19 +105,19 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 				SetColor(0xFFE200)
 
 			for i, starboardEntry := range starboardEntries {
-				sourceMessage, err := botData.DiscordSession.ChannelMessage(starboardEntry.SourceChannelID, starboardEntry.SourceMessageID)
+				sourceMessage, err := getBotData().DiscordSession.ChannelMessage(starboardEntry.SourceChannelID, starboardEntry.SourceMessageID)
 				if err != nil {
-					starboardEntries = append(starboards[env.Guild.ID].StarboardEntries[:i], starboards[env.Guild.ID].StarboardEntries[i+1])
+					starboardEntries = append(starboard.StarboardEntries[:i], starboard.StarboardEntries[i+1])
 					i--
 					continue
 				}
-				sourceChannel, err := botData.DiscordSession.Channel(starboardEntry.SourceChannelID)
+				sourceChannel, err := getBotData().DiscordSession.Channel(starboardEntry.SourceChannelID)
 				if err != nil {
-					starboardEntries = append(starboards[env.Guild.ID].StarboardEntries[:i], starboards[env.Guild.ID].StarboardEntries[i+1])
+					starboardEntries = append(starboard.StarboardEntries[:i], starboard.StarboardEntries[i+1])
 					i--
 					continue
 				}
-				leaderboardEmbed.AddField(starboards[env.Guild.ID].Emoji+" "+strconv.Itoa(starboardEntry.Stars)+" - "+sourceMessage.Author.Username+"#"+sourceMessage.Author.Discriminator+" in #"+sourceChannel.Name, sourceMessage.Content)
+				leaderboardEmbed.AddField(starboard.Emoji+" "+strconv.Itoa(starboardEntry.Stars)+" - "+sourceMessage.Author.Username+"#"+sourceMessage.Author.Discriminator+" in #"+sourceChannel.Name, sourceMessage.Content)
 			}
 
 			//Return message to user
@@ -116,112 +131,137 @@ func commandStarboard(args []string, env *CommandEnvironment) *discordgo.Message
 
 		return nil
 	case "enable":
-		starboards[env.Guild.ID].Active = true
-		return NewGenericEmbed("Starboard", "Enabled the starboard successfully.")
+		starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+			starboard.Active = true
+		})
+		return NewGenericEmbed(env.Locale(), "Starboard", "Enabled the starboard successfully.")
 	case "disable":
-		starboards[env.Guild.ID].Active = false
-		return NewGenericEmbed("Starboard", "Disabled the starboard successfully.")
+		starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+			starboard.Active = false
+		})
+		return NewGenericEmbed(env.Locale(), "Starboard", "Disabled the starboard successfully.")
 	case "channel":
 		if len(args) == 1 {
-			if starboards[env.Guild.ID].ChannelID == "" {
-				return NewGenericEmbed("Starboard", "No starbard channel has been set.")
+			if starboards.Get(env.Guild.ID).ChannelID == "" {
+				return NewGenericEmbed(env.Locale(), "Starboard", "No starbard channel has been set.")
 			}
-			return NewGenericEmbed("Starboard", "Starboad channel: <#"+starboards[env.Guild.ID].ChannelID+">")
+			return NewGenericEmbed(env.Locale(), "Starboard", "Starboad channel: <#"+starboards.Get(env.Guild.ID).ChannelID+">")
 		}
 		if args[1] == "set" {
-			starboards[env.Guild.ID].ChannelID = env.Channel.ID
-			return NewGenericEmbed("Starboard", "Set the starboard channel to <#"+env.Channel.ID+">.")
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.ChannelID = env.Channel.ID
+			})
+			return NewGenericEmbed(env.Locale(), "Starboard", "Set the starboard channel to <#"+env.Channel.ID+">.")
 		}
 		if args[1] == "remove" {
-			starboards[env.Guild.ID].ChannelID = ""
-			return NewGenericEmbed("Starboard", "Unset the previous starboard channel.")
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.ChannelID = ""
+			})
+			return NewGenericEmbed(env.Locale(), "Starboard", "Unset the previous starboard channel.")
 		}
-		return NewErrorEmbed("Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the starboard channel.")
+		return NewErrorEmbed(env.Locale(), "Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the starboard channel.")
 	case "nsfwchannel":
 		if len(args) == 1 {
-			if starboards[env.Guild.ID].NSFWChannelID == "" {
-				return NewGenericEmbed("Starboard", "No NSFW starbard channel has been set.")
+			if starboards.Get(env.Guild.ID).NSFWChannelID == "" {
+				return NewGenericEmbed(env.Locale(), "Starboard", "No NSFW starbard channel has been set.")
 			}
-			return NewGenericEmbed("Starboard", "NSFW starboad channel: <#"+starboards[env.Guild.ID].NSFWChannelID+">")
+			return NewGenericEmbed(env.Locale(), "Starboard", "NSFW starboad channel: <#"+starboards.Get(env.Guild.ID).NSFWChannelID+">")
 		}
 		if args[1] == "set" {
 			if !env.Channel.NSFW {
-				return NewErrorEmbed("Starboard Error", "You must mark this channel as NSFW before you can use it as the NSFW starboard channel.")
+				return NewErrorEmbed(env.Locale(), "Starboard Error", "You must mark this channel as NSFW before you can use it as the NSFW starboard channel.")
 			}
-			starboards[env.Guild.ID].NSFWChannelID = env.Channel.ID
-			return NewGenericEmbed("Starboard", "Set the NSFW starboard channel to <#"+env.Channel.ID+">.")
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.NSFWChannelID = env.Channel.ID
+			})
+			return NewGenericEmbed(env.Locale(), "Starboard", "Set the NSFW starboard channel to <#"+env.Channel.ID+">.")
 		}
 		if args[1] == "remove" {
-			starboards[env.Guild.ID].NSFWChannelID = ""
-			return NewGenericEmbed("Starboard", "Unset the previous NSFW starboard channel.")
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.NSFWChannelID = ""
+			})
+			return NewGenericEmbed(env.Locale(), "Starboard", "Unset the previous NSFW starboard channel.")
 		}
-		return NewErrorEmbed("Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the NSFW starboard channel.")
+		return NewErrorEmbed(env.Locale(), "Starboard Error", "You must specify ``set`` instead of ``"+args[1]+"`` to set the current channel as the NSFW starboard channel.")
 	case "emoji":
 		if len(args) == 1 {
-			return NewGenericEmbed("Starboard", "Emoji: "+starboards[env.Guild.ID].Emoji)
+			return NewGenericEmbed(env.Locale(), "Starboard", "Emoji: "+starboards.Get(env.Guild.ID).Emoji)
 		}
 		if strings.Contains(args[1], ":") {
-			//starboards[env.Guild.ID].Emoji = GetStringInBetween(args[1], ":", ">")
-			return NewErrorEmbed("Starboard Error", "Custom emojis are not permitted at this time.")
+			//starboards.Get(env.Guild.ID).Emoji = GetStringInBetween(args[1], ":", ">")
+			return NewErrorEmbed(env.Locale(), "Starboard Error", "Custom emojis are not permitted at this time.")
 		}
-		starboards[env.Guild.ID].Emoji = args[1]
-		return NewGenericEmbed("Starboard", "Set the emoji to "+args[1]+".")
+		starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+			starboard.Emoji = args[1]
+		})
+		return NewGenericEmbed(env.Locale(), "Starboard", "Set the emoji to "+args[1]+".")
 	case "nsfwemoji":
 		if len(args) == 1 {
-			return NewGenericEmbed("Starboard", "NSFW Emoji: "+starboards[env.Guild.ID].NSFWEmoji)
+			return NewGenericEmbed(env.Locale(), "Starboard", "NSFW Emoji: "+starboards.Get(env.Guild.ID).NSFWEmoji)
 		}
 		if strings.Contains(args[1], ":") {
-			//starboards[env.Guild.ID].NSFWEmoji = GetStringInBetween(args[1], ":", ">")
-			return NewErrorEmbed("Starboard Error", "Custom emojis are not permitted at this time.")
+			//starboards.Get(env.Guild.ID).NSFWEmoji = GetStringInBetween(args[1], ":", ">")
+			return NewErrorEmbed(env.Locale(), "Starboard Error", "Custom emojis are not permitted at this time.")
 		}
-		starboards[env.Guild.ID].NSFWEmoji = args[1]
-		return NewGenericEmbed("Starboard", "Set the NSFW emoji to "+args[1]+".")
+		starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+			starboard.NSFWEmoji = args[1]
+		})
+		return NewGenericEmbed(env.Locale(), "Starboard", "Set the NSFW emoji to "+args[1]+".")
 	case "selfstar":
 		if len(args) == 1 {
-			return NewGenericEmbed("Starboard", "Allow selfstar: **"+strconv.FormatBool(starboards[env.Guild.ID].AllowSelfStar)+"**")
+			return NewGenericEmbed(env.Locale(), "Starboard", "Allow selfstar: **"+strconv.FormatBool(starboards.Get(env.Guild.ID).AllowSelfStar)+"**")
 		}
 		switch args[1] {
 		case "true", "yes", "enable":
-			starboards[env.Guild.ID].AllowSelfStar = true
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.AllowSelfStar = true
+			})
 
 			//Apparently Discord doesn't send enough info in the reactions object of a message
 			//I'll build up a list of who reacted with what later on in life, too much for now so selfstars won't get added for now
 
-			return NewGenericEmbed("Starboard", "Successfully enabled selfstar.")
+			return NewGenericEmbed(env.Locale(), "Starboard", "Successfully enabled selfstar.")
 		case "false", "no", "disable":
-			starboards[env.Guild.ID].AllowSelfStar = false
+			starboards.Update(env.Guild.ID, func(starboard *Starboard) {
+				starboard.AllowSelfStar = false
+			})
 
 			//Apparently Discord doesn't send enough info in the reactions object of a message
 			//I'll build up a list of who reacted with what later on in life, too much for now so selfstars won't get removed for now
 
-			return NewGenericEmbed("Starboard", "Successfully disabled selfstar.")
+			return NewGenericEmbed(env.Locale(), "Starboard", "Successfully disabled selfstar.")
 		default:
-			return NewErrorEmbed("Starboard Error", "Unknown value ``"+args[1]+"``. Please use either ``enable`` or ``disable``.")
+			return NewErrorEmbed(env.Locale(), "Starboard Error", "Unknown value ``"+args[1]+"``. Please use either ``enable`` or ``disable``.")
 		}
 	}
-	return NewErrorEmbed("Starboard Error", "Error finding the setting ``"+args[0]+"``.")
+	return NewErrorEmbed(env.Locale(), "Starboard Error", "Error finding the setting ``"+args[0]+"``.")
 }
 
 func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	if handleSuggestionReaction(session, reaction) {
+		return
+	}
+
 	channel, err := session.Channel(reaction.ChannelID)
 	if err != nil {
 		return
 	}
 
-	if _, exists := starboards[channel.GuildID]; exists == false {
+	starboard, exists := starboards.Snapshot(channel.GuildID) //A copy, as reactions are handled at the same time
+	if !exists {
 		return
 	}
 
-	if starboards[channel.GuildID].Active == false {
+	if starboard.Active == false {
 		return
 	}
-	if starboards[channel.GuildID].NSFWChannelID == "" && starboards[channel.GuildID].ChannelID == "" {
+	if starboard.NSFWChannelID == "" && starboard.ChannelID == "" {
 		return
 	}
-	if channel.NSFW && starboards[channel.GuildID].NSFWChannelID == "" {
+	if channel.NSFW && starboard.NSFWChannelID == "" {
 		return
 	}
-	if channel.NSFW == false && starboards[channel.GuildID].ChannelID == "" {
+	if channel.NSFW == false && starboard.ChannelID == "" {
 		return
 	}
 
@@ -235,37 +275,37 @@ func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.M
 
 	//A user can't self-star their message to add it to the starboard, however I give up on finding
 	//a method of subtracting their star for now so it'll still show the total star count
-	if message.Author.ID == reaction.UserID && starboards[channel.GuildID].AllowSelfStar == false {
+	if message.Author.ID == reaction.UserID && starboard.AllowSelfStar == false {
 		return
 	}
 
 	stars := 0
 	for _, msgReaction := range message.Reactions {
 		if channel.NSFW {
-			if msgReaction.Emoji.Name == starboards[channel.GuildID].NSFWEmoji || starboards[channel.GuildID].NSFWEmoji == msgReaction.Emoji.Name+":"+msgReaction.Emoji.ID {
+			if msgReaction.Emoji.Name == starboard.NSFWEmoji || starboard.NSFWEmoji == msgReaction.Emoji.Name+":"+msgReaction.Emoji.ID {
 				stars = msgReaction.Count
 				break
 			}
 		} else {
-			if msgReaction.Emoji.Name == starboards[channel.GuildID].Emoji || starboards[channel.GuildID].Emoji == msgReaction.Emoji.Name+":"+msgReaction.Emoji.ID {
+			if msgReaction.Emoji.Name == starboard.Emoji || starboard.Emoji == msgReaction.Emoji.Name+":"+msgReaction.Emoji.ID {
 				stars = msgReaction.Count
 				break
 			}
 		}
 	}
-	if stars == 0 || stars < starboards[channel.GuildID].MinimumStars {
+	if stars == 0 || stars < starboard.MinimumStars {
 		return
 	}
 
-	entry := createStarboardEntry(stars, message, channel)
+	entry := createStarboardEntry(starboard, stars, message, channel)
 
 	//Check to see if the entry already exists, and if so, update it instead of creating a new one
-	for _, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
+	for _, starboardEntry := range starboard.StarboardEntries {
 		if starboardEntry.SourceMessageID == message.ID {
 			if channel.NSFW {
-				session.ChannelMessageEditEmbed(starboards[channel.GuildID].NSFWChannelID, starboardEntry.StarboardMessageID, entry)
+				session.ChannelMessageEditEmbed(starboard.NSFWChannelID, starboardEntry.StarboardMessageID, entry)
 			} else {
-				session.ChannelMessageEditEmbed(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID, entry)
+				session.ChannelMessageEditEmbed(starboard.ChannelID, starboardEntry.StarboardMessageID, entry)
 			}
 			return
 		}
@@ -273,28 +313,30 @@ func discordMessageReactionAdd(session *discordgo.Session, reaction *discordgo.M
 
 	//Create a new entry
 	if channel.NSFW {
-		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].NSFWChannelID, entry)
+		starboardMessage, err := session.ChannelMessageSendEmbed(starboard.NSFWChannelID, entry)
 		if err != nil {
 			return
 		}
 
-		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
+		addStarboardEntry(channel.GuildID, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
-			StarboardChannelID: starboards[channel.GuildID].ChannelID,
+			AuthorID:           message.Author.ID,
+			StarboardChannelID: starboard.ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 			Stars:              stars,
 		})
 	} else {
-		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].ChannelID, entry)
+		starboardMessage, err := session.ChannelMessageSendEmbed(starboard.ChannelID, entry)
 		if err != nil {
 			return
 		}
 
-		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
+		addStarboardEntry(channel.GuildID, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
-			StarboardChannelID: starboards[channel.GuildID].ChannelID,
+			AuthorID:           message.Author.ID,
+			StarboardChannelID: starboard.ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 			Stars:              stars,
 		})
@@ -306,20 +348,21 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 		return
 	}
 
-	if _, exists := starboards[channel.GuildID]; !exists {
+	starboard, exists := starboards.Snapshot(channel.GuildID) //A copy, as reactions are handled at the same time
+	if !exists {
 		return
 	}
 
-	if starboards[channel.GuildID].Active == false {
+	if starboard.Active == false {
 		return
 	}
-	if starboards[channel.GuildID].NSFWChannelID == "" && starboards[channel.GuildID].ChannelID == "" {
+	if starboard.NSFWChannelID == "" && starboard.ChannelID == "" {
 		return
 	}
-	if channel.NSFW && starboards[channel.GuildID].NSFWChannelID == "" {
+	if channel.NSFW && starboard.NSFWChannelID == "" {
 		return
 	}
-	if !channel.NSFW && starboards[channel.GuildID].ChannelID == "" {
+	if !channel.NSFW && starboard.ChannelID == "" {
 		return
 	}
 
@@ -331,28 +374,27 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 	stars := 0
 	for _, msgReaction := range message.Reactions {
 		if channel.NSFW {
-			if msgReaction.Emoji.Name == starboards[channel.GuildID].NSFWEmoji {
+			if msgReaction.Emoji.Name == starboard.NSFWEmoji {
 				stars = msgReaction.Count
 				break
 			}
 		} else {
-			if msgReaction.Emoji.Name == starboards[channel.GuildID].Emoji {
+			if msgReaction.Emoji.Name == starboard.Emoji {
 				stars = msgReaction.Count
 				break
 			}
 		}
 	}
-	if stars == 0 || stars < starboards[channel.GuildID].MinimumStars {
-		for i, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
+	if stars == 0 || stars < starboard.MinimumStars {
+		for _, starboardEntry := range starboard.StarboardEntries {
 			if starboardEntry.SourceMessageID == message.ID {
 				if channel.NSFW {
-					session.ChannelMessageDelete(starboards[channel.GuildID].NSFWChannelID, starboardEntry.StarboardMessageID)
+					session.ChannelMessageDelete(starboard.NSFWChannelID, starboardEntry.StarboardMessageID)
 				} else {
-					session.ChannelMessageDelete(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID)
+					session.ChannelMessageDelete(starboard.ChannelID, starboardEntry.StarboardMessageID)
 				}
 
-				starboards[channel.GuildID].StarboardEntries[i] = starboards[channel.GuildID].StarboardEntries[len(starboards[channel.GuildID].StarboardEntries)-1]
-				starboards[channel.GuildID].StarboardEntries = starboards[channel.GuildID].StarboardEntries[:len(starboards[channel.GuildID].StarboardEntries)-1]
+				removeStarboardEntry(channel.GuildID, message.ID)
 
 				return
 			}
@@ -360,45 +402,46 @@ func discordMessageReactionRemove(session *discordgo.Session, reaction *discordg
 		return
 	}
 
-	entry := createStarboardEntry(stars, message, channel)
+	entry := createStarboardEntry(starboard, stars, message, channel)
 
 	//Check to see if the entry already exists, and if so, update it instead of create a new one
-	for i, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
+	for _, starboardEntry := range starboard.StarboardEntries {
 		if starboardEntry.SourceMessageID == message.ID {
 			if channel.NSFW {
-				session.ChannelMessageEditEmbed(starboards[channel.GuildID].NSFWChannelID, starboardEntry.StarboardMessageID, entry)
-				starboards[channel.GuildID].StarboardEntries[i].Stars = stars
+				session.ChannelMessageEditEmbed(starboard.NSFWChannelID, starboardEntry.StarboardMessageID, entry)
 			} else {
-				session.ChannelMessageEditEmbed(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID, entry)
-				starboards[channel.GuildID].StarboardEntries[i].Stars = stars
+				session.ChannelMessageEditEmbed(starboard.ChannelID, starboardEntry.StarboardMessageID, entry)
 			}
+			setStarboardEntryStars(channel.GuildID, message.ID, stars)
 			return
 		}
 	}
 
 	//Create a new entry
 	if channel.NSFW {
-		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].NSFWChannelID, entry)
+		starboardMessage, err := session.ChannelMessageSendEmbed(starboard.NSFWChannelID, entry)
 		if err != nil {
 			return
 		}
 
-		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
+		addStarboardEntry(channel.GuildID, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
-			StarboardChannelID: starboards[channel.GuildID].ChannelID,
+			AuthorID:           message.Author.ID,
+			StarboardChannelID: starboard.ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 		})
 	} else {
-		starboardMessage, err := session.ChannelMessageSendEmbed(starboards[channel.GuildID].ChannelID, entry)
+		starboardMessage, err := session.ChannelMessageSendEmbed(starboard.ChannelID, entry)
 		if err != nil {
 			return
 		}
 
-		starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries, StarboardEntry{
+		addStarboardEntry(channel.GuildID, StarboardEntry{
 			SourceChannelID:    channel.ID,
 			SourceMessageID:    message.ID,
-			StarboardChannelID: starboards[channel.GuildID].ChannelID,
+			AuthorID:           message.Author.ID,
+			StarboardChannelID: starboard.ChannelID,
 			StarboardMessageID: starboardMessage.ID,
 		})
 	}
@@ -409,20 +452,21 @@ func discordMessageReactionRemoveAll(session *discordgo.Session, reaction *disco
 		return
 	}
 
-	if _, exists := starboards[channel.GuildID]; !exists {
+	starboard, exists := starboards.Snapshot(channel.GuildID) //A copy, as reactions are handled at the same time
+	if !exists {
 		return
 	}
 
-	if starboards[channel.GuildID].Active == false {
+	if starboard.Active == false {
 		return
 	}
-	if starboards[channel.GuildID].NSFWChannelID == "" && starboards[channel.GuildID].ChannelID == "" {
+	if starboard.NSFWChannelID == "" && starboard.ChannelID == "" {
 		return
 	}
-	if channel.NSFW && starboards[channel.GuildID].NSFWChannelID == "" {
+	if channel.NSFW && starboard.NSFWChannelID == "" {
 		return
 	}
-	if !channel.NSFW && starboards[channel.GuildID].ChannelID == "" {
+	if !channel.NSFW && starboard.ChannelID == "" {
 		return
 	}
 
@@ -431,36 +475,36 @@ func discordMessageReactionRemoveAll(session *discordgo.Session, reaction *disco
 		return
 	}
 
-	for i, starboardEntry := range starboards[channel.GuildID].StarboardEntries {
+	for _, starboardEntry := range starboard.StarboardEntries {
 		if starboardEntry.SourceMessageID == message.ID {
 			if channel.NSFW {
-				session.ChannelMessageDelete(starboards[channel.GuildID].NSFWChannelID, starboardEntry.StarboardMessageID)
+				session.ChannelMessageDelete(starboard.NSFWChannelID, starboardEntry.StarboardMessageID)
 			} else {
-				session.ChannelMessageDelete(starboards[channel.GuildID].ChannelID, starboardEntry.StarboardMessageID)
+				session.ChannelMessageDelete(starboard.ChannelID, starboardEntry.StarboardMessageID)
 			}
-			starboards[channel.GuildID].StarboardEntries = append(starboards[channel.GuildID].StarboardEntries[:i], starboards[channel.GuildID].StarboardEntries[i+1])
+			removeStarboardEntry(channel.GuildID, message.ID)
 			return
 		}
 	}
 }
 
-func createStarboardEntry(stars int, message *discordgo.Message, channel *discordgo.Channel) *discordgo.MessageEmbed {
+func createStarboardEntry(starboard *Starboard, stars int, message *discordgo.Message, channel *discordgo.Channel) *discordgo.MessageEmbed {
 	entry := NewEmbed().
 		SetAuthor(message.Author.Username+"#"+message.Author.Discriminator+" in #"+channel.Name, message.Author.AvatarURL("2048"))
 
 	if channel.NSFW {
-		if strings.Contains(starboards[channel.GuildID].NSFWEmoji, ":") {
-			entry.SetFooter("<:" + starboards[channel.GuildID].NSFWEmoji + "> " + strconv.Itoa(stars))
+		if strings.Contains(starboard.NSFWEmoji, ":") {
+			entry.SetFooter("<:" + starboard.NSFWEmoji + "> " + strconv.Itoa(stars))
 		} else {
-			entry.SetFooter(starboards[channel.GuildID].NSFWEmoji + " " + strconv.Itoa(stars))
+			entry.SetFooter(starboard.NSFWEmoji + " " + strconv.Itoa(stars))
 		}
 		entry.SetColor(0xDEA7DF)
 	} else {
-		if strings.Contains(starboards[channel.GuildID].Emoji, ":") {
-			emoji := GetStringInBetween(":"+starboards[channel.GuildID].Emoji, ":", ":")
+		if strings.Contains(starboard.Emoji, ":") {
+			emoji := GetStringInBetween(":"+starboard.Emoji, ":", ":")
 			entry.SetFooter(":" + emoji + ": " + strconv.Itoa(stars))
 		} else {
-			entry.SetFooter(starboards[channel.GuildID].Emoji + " " + strconv.Itoa(stars))
+			entry.SetFooter(starboard.Emoji + " " + strconv.Itoa(stars))
 		}
 		entry.SetColor(0xFFE200)
 	}
@@ -480,3 +524,34 @@ func createStarboardEntry(stars int, message *discordgo.Message, channel *discor
 
 	return entry.MessageEmbed
 }
+
+// addStarboardEntry adds an entry to the starboard of a guild
+func addStarboardEntry(guildID string, entry StarboardEntry) {
+	starboards.Update(guildID, func(starboard *Starboard) {
+		starboard.StarboardEntries = append(starboard.StarboardEntries, entry)
+	})
+}
+
+// removeStarboardEntry removes the entry of a source message from the starboard of a guild
+func removeStarboardEntry(guildID, sourceMessageID string) {
+	starboards.Update(guildID, func(starboard *Starboard) {
+		for i, entry := range starboard.StarboardEntries {
+			if entry.SourceMessageID == sourceMessageID {
+				starboard.StarboardEntries = append(starboard.StarboardEntries[:i], starboard.StarboardEntries[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// setStarboardEntryStars updates the amount of stars on the entry of a source message in the starboard of a guild
+func setStarboardEntryStars(guildID, sourceMessageID string, stars int) {
+	starboards.Update(guildID, func(starboard *Starboard) {
+		for i, entry := range starboard.StarboardEntries {
+			if entry.SourceMessageID == sourceMessageID {
+				starboard.StarboardEntries[i].Stars = stars
+				return
+			}
+		}
+	})
+}
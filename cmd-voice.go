@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strconv"
@@ -10,38 +11,60 @@ import (
 	"github.com/rylio/ytdl"
 )
 
+var (
+	// voiceSelectArguments contains the values taken by youtube play and spotify play
+	voiceSelectArguments = []CommandArgument{
+		{Name: "result", Description: "The number of the search result to select", ArgType: ArgTypeNumber, Min: 1},
+	}
+
+	// voicePageArguments contains the values taken by spotify jump
+	voicePageArguments = []CommandArgument{
+		{Name: "page", Description: "The page of search results to jump to", ArgType: ArgTypeNumber, Min: 1},
+	}
+
+	// queueRemoveArguments contains the values taken by queue remove
+	queueRemoveArguments = []CommandArgument{
+		{Name: "entries", Description: "The numbers of the queue entries to remove", ArgType: ArgTypeNumber, Multiple: true, Min: 1},
+	}
+
+	// queuePageArguments contains the values taken by queue when listing its entries
+	queuePageArguments = []CommandArgument{
+		{Name: "page", Description: "The page of queue entries to view", ArgType: ArgTypeNumber, Optional: true, Min: 1},
+	}
+)
+
 func commandVoiceJoin(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID {
-			voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
-			return NewGenericEmbed("Voice", "Joined the voice channel.")
+			voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
+			return NewGenericEmbed(env.Locale(), "Voice", "Joined the voice channel.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel to use before using the join command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel to use before using the join command.")
 }
 
 func commandVoiceLeave(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	if voiceData[env.Guild.ID].VoiceConnection == nil {
-		return NewErrorEmbed("Voice Error", botData.BotName+"is not currently in a voice channel.")
+	if voiceData.Get(env.Guild.ID).VoiceConnection == nil {
+		return NewErrorEmbed(env.Locale(), "Voice Error", getBotData().BotName+"is not currently in a voice channel.")
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
-		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			voiceData[env.Guild.ID].Stop()
-			if err := voiceData[env.Guild.ID].Disconnect(); err != nil {
-				return NewErrorEmbed("Voice Error", "There was an error leaving the voice channel.")
+		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+			voiceData.Get(env.Guild.ID).Stop()
+			if err := voiceData.Get(env.Guild.ID).Disconnect(); err != nil {
+				return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error leaving the voice channel.")
 			}
-			return NewGenericEmbed("Voice", "Left the voice channel.")
+			return NewGenericEmbed(env.Locale(), "Voice", "Left the voice channel.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the leave command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" is in before using the leave command.")
 }
 
-func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+func commandPlay(args []string, env *CommandEnvironment) *CommandResponse {
 	VoiceInit(env.Guild.ID)
 
 	if env.UpdatedMessageEvent {
@@ -51,19 +74,19 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 	foundVoiceChannel := false
 	for _, voiceState := range env.Guild.VoiceStates {
 		if voiceState.UserID == env.Message.Author.ID {
-			if voiceData[env.Guild.ID].IsConnected() && voiceState.ChannelID != voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-				return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the play command.")
+			if voiceData.Get(env.Guild.ID).IsConnected() && voiceState.ChannelID != voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" is in before using the play command."))
 			}
 			foundVoiceChannel = true
-			voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
+			voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
 			break
 		}
 	}
 	if !foundVoiceChannel {
-		return NewErrorEmbed("Voice Error", "You must join the voice channel to use before using the play command.")
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel to use before using the play command."))
 	}
 
-	voiceData[env.Guild.ID].SetTextChannel(env.Channel.ID)
+	voiceData.Get(env.Guild.ID).SetTextChannel(env.Channel.ID)
 
 	mediaURL := ""
 
@@ -72,7 +95,7 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		if err != nil {
 			queryURL, err := YouTubeGetQuery(strings.Join(args, " "))
 			if err != nil {
-				return NewErrorEmbed("Voice Error", "There was an error getting a result for the specified query.")
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting a result for the specified query."))
 			}
 			mediaURL = queryURL
 		} else {
@@ -80,168 +103,188 @@ func commandPlay(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 		}
 	} else {
 		if len(env.Message.Attachments) > 0 {
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewEmbed().
-				SetTitle("Voice").
-				SetDescription("Please wait a moment as we add all "+strconv.Itoa(len(env.Message.Attachments))+" attachments to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.").
-				SetColor(0x1DB954).MessageEmbed)
-
-			for i, attachment := range env.Message.Attachments {
-				//Give a chance for other commands waiting in line to execute
-				guildData[env.Guild.ID].Unlock()
-				guildData[env.Guild.ID].Lock()
+			attachments := env.Message.Attachments
+			requester := env.Member.User
+			guildID := env.Guild.ID
+
+			return &CommandResponse{
+				Embeds: []*discordgo.MessageEmbed{
+					NewEmbed().
+						SetTitle("Voice").
+						SetDescription("Please wait a moment as we add all " + strconv.Itoa(len(attachments)) + " attachments to the queue...\n\nThe first result added will automatically begin playing.").
+						SetColor(0x1DB954).MessageEmbed,
+				},
+				FollowUp: func(ctx context.Context) *CommandResponse {
+					failed := make([]string, 0)
+					for i, attachment := range attachments {
+						if ctx.Err() != nil {
+							return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "Ran out of time adding attachments to the queue."))
+						}
+						queueEntry, err := createQueueEntry(attachment.URL)
+						if err != nil {
+							failed = append(failed, strconv.Itoa(i+1))
+							continue
+						}
+						queueEntry.Requester = requester
+						go voiceData.Get(guildID).Play(queueEntry, false)
+					}
 
-				queueEntry, err := createQueueEntry(attachment.URL)
-				if err != nil {
-					botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, NewErrorEmbed("Voice Error", "Error finding audio info for attachment "+strconv.Itoa(i+1)+"."))
-					continue
-				}
-				queueEntry.Requester = env.Member.User
-				go voiceData[env.Guild.ID].Play(queueEntry, false)
+					if len(failed) > 0 {
+						return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "Finished adding attachments to the queue, but there was an error finding audio info for attachment(s) "+strings.Join(failed, ", ")+"."))
+					}
+					return NewEmbedResponse(NewGenericEmbed(env.Locale(), "Voice", "Finished adding all "+strconv.Itoa(len(attachments))+" attachments to the queue."))
+				},
 			}
-
-			return NewGenericEmbed("Voice", "Finished adding all "+strconv.Itoa(len(env.Message.Attachments))+" attachments to the queue.")
 		}
 
-		if voiceData[env.Guild.ID].NowPlaying != nil {
-			if voiceData[env.Guild.ID].IsStreaming() {
-				return NewErrorEmbed("Voice Error", "There is already audio playing.")
+		if voiceData.Get(env.Guild.ID).NowPlaying != nil {
+			if voiceData.Get(env.Guild.ID).IsStreaming() {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "There is already audio playing."))
 			}
-			queueEntry := voiceData[env.Guild.ID].NowPlaying.Entry
-			go voiceData[env.Guild.ID].Play(queueEntry, true)
+			queueEntry := voiceData.Get(env.Guild.ID).NowPlaying.Entry
+			go voiceData.Get(env.Guild.ID).Play(queueEntry, true)
 			return nil
 		}
-		if len(voiceData[env.Guild.ID].Entries) > 0 {
-			if voiceData[env.Guild.ID].IsStreaming() {
-				return NewErrorEmbed("Voice Error", "There is already audio playing.")
+		if len(voiceData.Get(env.Guild.ID).Entries) > 0 {
+			if voiceData.Get(env.Guild.ID).IsStreaming() {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "There is already audio playing."))
+			}
+			var queueEntry *QueueEntry
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				if len(voice.Entries) > 0 {
+					queueEntry = voice.Entries[0]
+					voice.QueueRemove(0)
+				}
+			})
+			if queueEntry != nil {
+				go voiceData.Get(env.Guild.ID).Play(queueEntry, true)
 			}
-			queueEntry := voiceData[env.Guild.ID].Entries[0]
-			voiceData[env.Guild.ID].QueueRemove(0)
-			go voiceData[env.Guild.ID].Play(queueEntry, true)
 		}
 	}
 
 	if mediaURL != "" {
 		queueEntry, err := createQueueEntry(mediaURL)
 		if err != nil {
-			return NewErrorEmbed("Voice Error", "There was an error finding a service to handle the specified URL.")
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "There was an error finding a service to handle the specified URL."))
 		}
 		if env.Member == nil {
-			return NewErrorEmbed("Voice Error", "There was an error figuring out who requested the track.")
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "There was an error figuring out who requested the track."))
 		}
 		queueEntry.Requester = env.Member.User
-		go voiceData[env.Guild.ID].Play(queueEntry, true)
+		go voiceData.Get(env.Guild.ID).Play(queueEntry, true)
 		return nil
 	}
 
-	return NewErrorEmbed("Voice Error", "Could not find any audio to play.")
+	return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Voice Error", "Could not find any audio to play."))
 }
 
 func commandStop(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+	if !voiceData.Get(env.Guild.ID).IsConnected() {
+		return NewErrorEmbed(env.Locale(), "Voice Error", getBotData().BotName+" is not currently in a voice channel.")
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
-		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			if voiceData[env.Guild.ID].IsStreaming() {
-				if err := voiceData[env.Guild.ID].Stop(); err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error stopping the audio playback.")
+		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+			if voiceData.Get(env.Guild.ID).IsStreaming() {
+				if err := voiceData.Get(env.Guild.ID).Stop(); err != nil {
+					return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error stopping the audio playback.")
 				}
-				return NewGenericEmbed("Voice", "Stopped the audio playback.")
+				return NewGenericEmbed(env.Locale(), "Voice", "Stopped the audio playback.")
 			}
-			return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+			return NewErrorEmbed(env.Locale(), "Voice Error", "There is no audio currently playing.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" is in before using the "+env.Command+" command.")
 }
 
 func commandSkip(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+	if !voiceData.Get(env.Guild.ID).IsConnected() {
+		return NewErrorEmbed(env.Locale(), "Voice Error", getBotData().BotName+" is not currently in a voice channel.")
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
-		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			if voiceData[env.Guild.ID].IsStreaming() {
-				if err := voiceData[env.Guild.ID].Skip(); err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error skipping the audio playback.")
+		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+			if voiceData.Get(env.Guild.ID).IsStreaming() {
+				if err := voiceData.Get(env.Guild.ID).Skip(); err != nil {
+					return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error skipping the audio playback.")
 				}
 				return nil
 			}
-			return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+			return NewErrorEmbed(env.Locale(), "Voice Error", "There is no audio currently playing.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" is in before using the "+env.Command+" command.")
 }
 
 func commandPause(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+	if !voiceData.Get(env.Guild.ID).IsConnected() {
+		return NewErrorEmbed(env.Locale(), "Voice Error", getBotData().BotName+" is not currently in a voice channel.")
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
-		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			isPaused, err := voiceData[env.Guild.ID].Pause()
+		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+			isPaused, err := voiceData.Get(env.Guild.ID).Pause()
 			if err != nil {
 				if isPaused {
-					return NewErrorEmbed("Voice Error", "Already paused the audio.")
+					return NewErrorEmbed(env.Locale(), "Voice Error", "Already paused the audio.")
 				}
-				return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+				return NewErrorEmbed(env.Locale(), "Voice Error", "There is no audio currently playing.")
 			}
-			return NewGenericEmbed("Voice", "Paused the audio playback.")
+			return NewGenericEmbed(env.Locale(), "Voice", "Paused the audio playback.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" is in before using the "+env.Command+" command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" is in before using the "+env.Command+" command.")
 }
 
 func commandResume(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	if !voiceData[env.Guild.ID].IsConnected() {
-		return NewErrorEmbed("Voice Error", botData.BotName+" is not currently in a voice channel.")
+	if !voiceData.Get(env.Guild.ID).IsConnected() {
+		return NewErrorEmbed(env.Locale(), "Voice Error", getBotData().BotName+" is not currently in a voice channel.")
 	}
 
 	for _, voiceState := range env.Guild.VoiceStates {
-		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData[env.Guild.ID].VoiceConnection.ChannelID {
-			isPaused, err := voiceData[env.Guild.ID].Resume()
+		if voiceState.UserID == env.Message.Author.ID && voiceState.ChannelID == voiceData.Get(env.Guild.ID).VoiceConnection.ChannelID {
+			isPaused, err := voiceData.Get(env.Guild.ID).Resume()
 			if err != nil {
 				if isPaused {
-					return NewErrorEmbed("Voice Error", "Already playing audio.")
+					return NewErrorEmbed(env.Locale(), "Voice Error", "Already playing audio.")
 				}
-				return NewErrorEmbed("Voice Error", "There is no audio currently playing.")
+				return NewErrorEmbed(env.Locale(), "Voice Error", "There is no audio currently playing.")
 			}
-			return NewGenericEmbed("Voice", "Resumed the audio playback.")
+			return NewGenericEmbed(env.Locale(), "Voice", "Resumed the audio playback.")
 		}
 	}
-	return NewErrorEmbed("Voice Error", "You must join the voice channel "+botData.BotName+" to use before using the "+env.Command+" command.")
+	return NewErrorEmbed(env.Locale(), "Voice Error", "You must join the voice channel "+getBotData().BotName+" to use before using the "+env.Command+" command.")
 }
 
 func commandVolume(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	//Disabled until further notice, real-time volume control using hrabin/opus and manually adjusting samples results in static noise distortion with the correct volume
 	/*
-		volume, err := strconv.Atoi(args[0])
-		if err != nil {
-			return NewErrorEmbed("Volume Error", "``"+args[0]+"`` is not a valid number.")
-		}
-
-		if volume < 0 || volume > 100 {
-			return NewErrorEmbed("Volume Error", "You must specify a volume level from 0 to 100, with 100 being normal volume.")
+		parsed, usageEmbed := parseSubcommandArguments([]CommandArgument{
+			{Name: "volume", Description: "The volume level to use, with 100 being normal volume", ArgType: ArgTypeNumber, Max: 100},
+		}, args, env)
+		if usageEmbed != nil {
+			return usageEmbed
 		}
+		volume := parsed.Int("volume")
 
-		if voiceData[env.Guild.ID].EncodingOptions == nil {
-			voiceData[env.Guild.ID].EncodingOptions = encodeOptionsPresetHigh
-		}
-		voiceData[env.Guild.ID].EncodingOptions.Volume = float64(volume) * 0.01
-		return NewErrorEmbed("Volume", "Set the volume for audio playback to "+args[0]+".")
+		voiceData.Update(env.Guild.ID, func(voice *Voice) {
+			if voice.EncodingOptions == nil {
+				voice.EncodingOptions = encodeOptionsPresetHigh
+			}
+			voice.EncodingOptions.Volume = float64(volume) * 0.01
+		})
+		return NewErrorEmbed(env.Locale(), "Volume", "Set the volume for audio playback to "+args[0]+".")
 	*/
 
-	return NewGenericEmbed("Volume", "Volume adjustment in real time via this command is disabled at this time. While attempts proved to successfully change the volume, it was accompanied by static noise distortion and thus is not ready for production.\n"+
+	return NewGenericEmbed(env.Locale(), "Volume", "Volume adjustment in real time via this command is disabled at this time. While attempts proved to successfully change the volume, it was accompanied by static noise distortion and thus is not ready for production.\n"+
 		"If you wish to change your perceived volume of Clinet, consider using Discord's per-user volume control (right click Clinet on desktop/web or tap on Clinet in the user list on mobile to find it). Not only does it do what you want, but it doesn't have to ruin everyone else's high quality audio experience!\n"+
 		"If you would like to help with attempts to change the volume in real time, make sure to join the [Clinet Discord server](https://discord.gg/qkbKEWT).")
 }
@@ -249,29 +292,41 @@ func commandVolume(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 func commandRepeat(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
+	repeatLevel, levelGiven := RepeatNone, false
 	if len(args) > 0 {
 		switch strings.Join(args, " ") {
 		case "normal", "norm", "disable", "d", "0", "zero":
-			voiceData[env.Guild.ID].RepeatLevel = RepeatNone
-			return NewGenericEmbed("Voice", "The queue will now play through as normal.")
+			repeatLevel, levelGiven = RepeatNone, true
 		case "queue", "list", "queue list", "q", "l", "1", "one":
-			voiceData[env.Guild.ID].RepeatLevel = RepeatPlaylist
-			return NewGenericEmbed("Voice", "The queue will now be repeated on a loop.")
+			repeatLevel, levelGiven = RepeatPlaylist, true
 		case "nowplaying", "now playing", "now", "playing", "np", "n", "enable", "e", "2", "two":
-			voiceData[env.Guild.ID].RepeatLevel = RepeatNowPlaying
-			return NewGenericEmbed("Voice", "The now playing entry will now be repeated on a loop.")
+			repeatLevel, levelGiven = RepeatNowPlaying, true
 		}
 	}
-	switch voiceData[env.Guild.ID].RepeatLevel {
-	case 0: //No repeat
-		voiceData[env.Guild.ID].RepeatLevel = RepeatPlaylist
-		return NewGenericEmbed("Voice", "The queue will now be repeated on a loop.")
-	case 1: //Repeat the current queue
-		voiceData[env.Guild.ID].RepeatLevel = RepeatNowPlaying
-		return NewGenericEmbed("Voice", "The now playing entry will now be repeated on a loop.")
-	case 2: //Repeat what's in the now playing slot
-		voiceData[env.Guild.ID].RepeatLevel = RepeatNone
-		return NewGenericEmbed("Voice", "The queue will now play through as normal.")
+	voiceData.Update(env.Guild.ID, func(voice *Voice) {
+		if !levelGiven {
+			//Move on to the next repeat level
+			switch voice.RepeatLevel {
+			case RepeatNone:
+				repeatLevel = RepeatPlaylist
+			case RepeatPlaylist:
+				repeatLevel = RepeatNowPlaying
+			case RepeatNowPlaying:
+				repeatLevel = RepeatNone
+			default:
+				repeatLevel = voice.RepeatLevel
+			}
+		}
+		voice.RepeatLevel = repeatLevel
+	})
+
+	switch repeatLevel {
+	case RepeatNone:
+		return NewGenericEmbed(env.Locale(), "Voice", "The queue will now play through as normal.")
+	case RepeatPlaylist:
+		return NewGenericEmbed(env.Locale(), "Voice", "The queue will now be repeated on a loop.")
+	case RepeatNowPlaying:
+		return NewGenericEmbed(env.Locale(), "Voice", "The now playing entry will now be repeated on a loop.")
 	}
 	return nil
 }
@@ -279,11 +334,15 @@ func commandRepeat(args []string, env *CommandEnvironment) *discordgo.MessageEmb
 func commandShuffle(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	VoiceInit(env.Guild.ID)
 
-	voiceData[env.Guild.ID].Shuffle = !voiceData[env.Guild.ID].Shuffle
-	if voiceData[env.Guild.ID].Shuffle {
-		return NewGenericEmbed("Voice", "The queue will be shuffled around in a random order while playing.")
+	shuffle := false
+	voiceData.Update(env.Guild.ID, func(voice *Voice) {
+		voice.Shuffle = !voice.Shuffle
+		shuffle = voice.Shuffle
+	})
+	if shuffle {
+		return NewGenericEmbed(env.Locale(), "Voice", "The queue will be shuffled around in a random order while playing.")
 	}
-	return NewGenericEmbed("Voice", "The queue will play through as normal.")
+	return NewGenericEmbed(env.Locale(), "Voice", "The queue will play through as normal.")
 }
 
 func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
@@ -295,92 +354,91 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	case "search", "s":
 		query := strings.Join(args[1:], " ")
 		if query == "" {
-			return NewErrorEmbed("YouTube Error", "You must enter a search query to use before using the "+args[0]+" command.")
-		}
-
-		if guildData[env.Guild.ID].YouTubeResults == nil {
-			guildData[env.Guild.ID].YouTubeResults = make(map[string]*YouTubeResultNav)
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "You must enter a search query to use before using the "+args[0]+" command.")
 		}
 
-		guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] = &YouTubeResultNav{}
+		guildData.Get(env.Guild.ID).SetYouTubeResult(env.Message.Author.ID, &YouTubeResultNav{})
 
-		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID)
 		err := page.Search(query)
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error getting a result for the specified query.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "There was an error getting a result for the specified query.")
 		}
 	case "next", "n", "forward", "+":
-		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "No search session is in progress.")
 		}
 
-		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID)
 		err := page.Next()
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error finding the next page.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "There was an error finding the next page.")
 		}
 	case "prev", "previous", "p", "back", "-":
-		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "No search session is in progress.")
 		}
 
-		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID)
 		err := page.Prev()
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error finding the previous page.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "There was an error finding the previous page.")
 		}
 	case "cancel", "c":
-		if guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] != nil {
-			guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID] = nil
+		if guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID) != nil {
+			guildData.Get(env.Guild.ID).SetYouTubeResult(env.Message.Author.ID, nil)
 			return NewGenericEmbedAdvanced("YouTube", "Cancelled the search session.", 0xFF0000)
 		}
-		return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+		return NewErrorEmbed(env.Locale(), "YouTube Error", "No search session is in progress.")
 	case "select", "choose", "play":
-		if guildData[env.Guild.ID].YouTubeResults == nil {
-			return NewErrorEmbed("YouTube Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "No search session is in progress.")
 		}
 		if len(args) < 2 {
-			return NewErrorEmbed("YouTube Error", "You must specify which search result to select.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "You must specify which search result to select.")
 		}
 
-		page = guildData[env.Guild.ID].YouTubeResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetYouTubeResult(env.Message.Author.ID)
 		results, _ := page.GetResults()
 
-		selection, err := strconv.Atoi(args[1])
-		if err != nil {
-			return NewErrorEmbed("YouTube Error", "``"+args[1]+"`` is not a valid number.")
+		parsed, usageEmbed := parseSubcommandArguments(voiceSelectArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
 		}
-		if selection > len(results) || selection <= 0 {
-			return NewErrorEmbed("YouTube Error", "An invalid selection was specified.")
+		selection := parsed.Int("result")
+		if selection > len(results) {
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "An invalid selection was specified.")
 		}
 
 		foundVoiceChannel := false
 		for _, voiceState := range env.Guild.VoiceStates {
 			if voiceState.UserID == env.Message.Author.ID {
 				foundVoiceChannel = true
-				voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
+				voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
 				break
 			}
 		}
 		if !foundVoiceChannel {
-			return NewErrorEmbed("YouTube Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "You must join the voice channel to use before using the "+args[0]+" command.")
 		}
 
 		//Update channel ID to send voice messages to
-		voiceData[env.Guild.ID].TextChannelID = env.Channel.ID
+		voiceData.Update(env.Guild.ID, func(voice *Voice) {
+			voice.TextChannelID = env.Channel.ID
+		})
 
 		result := results[selection-1]
 		resultURL := "https://youtube.com/watch?v=" + result.Id.VideoId
 
 		queueEntry, err := createQueueEntry(resultURL)
 		if err != nil {
-			return NewErrorEmbed("YouTube Error", "There was an error getting info for the result.")
+			return NewErrorEmbed(env.Locale(), "YouTube Error", "There was an error getting info for the result.")
 		}
 		queueEntry.Requester = env.Member.User
-		go voiceData[env.Guild.ID].Play(queueEntry, true)
+		go voiceData.Get(env.Guild.ID).Play(queueEntry, true)
 		return nil
 	default:
-		return NewErrorEmbed("YouTube Error", "Unknown command ``"+args[0]+"``.")
+		return NewErrorEmbed(env.Locale(), "YouTube Error", "Unknown command ``"+args[0]+"``.")
 	}
 
 	commandList := env.BotPrefix + env.Command + " play N - Plays result N"
@@ -395,7 +453,7 @@ func commandYouTube(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 	results, err := page.GetResults()
 	if err != nil {
-		return NewErrorEmbed("YouTube Error", "No search results were found.")
+		return NewErrorEmbed(env.Locale(), "YouTube Error", "No search results were found.")
 	}
 	responseEmbed := NewEmbed().
 		SetTitle("YouTube Search Results - Page " + strconv.Itoa(page.PageNumber)).
@@ -429,83 +487,75 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	case "search", "s":
 		query := strings.Join(args[1:], " ")
 		if query == "" {
-			return NewErrorEmbed("Spotify Error", "You must enter a search query to use before using the "+args[0]+" command.")
-		}
-
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			guildData[env.Guild.ID].SpotifyResults = make(map[string]*SpotifyResultNav)
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "You must enter a search query to use before using the "+args[0]+" command.")
 		}
 
-		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &SpotifyResultNav{}
+		guildData.Get(env.Guild.ID).SetSpotifyResult(env.Message.Author.ID, &SpotifyResultNav{})
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		err := page.Search(query)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error getting a result for the specified query.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "There was an error getting a result for the specified query.")
 		}
 	case "playlist", "list":
 		playlistURL := strings.Join(args[1:], " ")
 		if playlistURL == "" {
-			return NewErrorEmbed("Spotify Error", "You must enter a playlist URL to use before using the "+args[0]+" command.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "You must enter a playlist URL to use before using the "+args[0]+" command.")
 		}
 
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			guildData[env.Guild.ID].SpotifyResults = make(map[string]*SpotifyResultNav)
-		}
-
-		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID] = &SpotifyResultNav{}
-		guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID].GuildID = env.Guild.ID
+		guildData.Get(env.Guild.ID).SetSpotifyResult(env.Message.Author.ID, &SpotifyResultNav{GuildID: env.Guild.ID})
 
 		waitEmbed := NewEmbed().
 			SetTitle("Spotify").
-			SetDescription("Please wait a while as we fetch the tracks from the specified playlist...\n\nDuring this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Once cancelled, the tracks gathered so far will still be displayed.").
+			SetDescription("Please wait a while as we fetch the tracks from the specified playlist...\n\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Once cancelled, the tracks gathered so far will still be displayed.").
 			SetColor(0x1DB954).MessageEmbed
-		botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+		getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		err := page.Playlist(playlistURL)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error getting a result for the specified playlist.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "There was an error getting a result for the specified playlist.")
 		}
 	case "next", "n", "forward", "+":
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "No search session is in progress.")
 		}
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		err := page.Next()
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding the next page.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "There was an error finding the next page.")
 		}
 	case "prev", "previous", "p", "back", "-":
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "No search session is in progress.")
 		}
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		err := page.Prev()
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding the previous page.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "There was an error finding the previous page.")
 		}
 	case "jump", "page":
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "No search session is in progress.")
 		}
 
-		pageNumber, err := strconv.Atoi(args[1])
-		if err != nil {
-			return NewErrorEmbed("Spotify Error", "Invalid page number ``"+args[1]+"``.")
+		parsed, usageEmbed := parseSubcommandArguments(voicePageArguments, args[1:], env)
+		if usageEmbed != nil {
+			return usageEmbed
 		}
+		pageNumber := parsed.Int("page")
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
-		err = page.Jump(pageNumber)
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
+		err := page.Jump(pageNumber)
 		if err != nil {
-			return NewErrorEmbed("Spotify Error", "There was an error finding page ``"+args[1]+"``.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "There was an error finding page ``"+strconv.Itoa(pageNumber)+"``.")
 		}
 	case "cancel", "c":
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		if page == nil {
-			return NewErrorEmbed("Spotify Error", "No Spotify session is in progress.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "No Spotify session is in progress.")
 		}
 
 		if page.AddingAll {
@@ -513,17 +563,17 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			return NewGenericEmbedAdvanced("Spotify", "Stopped adding results to the queue. A total of "+strconv.Itoa(page.AddedSoFar)+" tracks were added.", 0x1DB954)
 		}
 
-		page = nil
+		guildData.Get(env.Guild.ID).SetSpotifyResult(env.Message.Author.ID, nil)
 		return NewGenericEmbedAdvanced("Spotify", "Cancelled the Spotify session.", 0x1DB954)
 	case "select", "choose", "play":
-		if guildData[env.Guild.ID].SpotifyResults == nil {
-			return NewErrorEmbed("Spotify Error", "No search session is in progress.")
+		if guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID) == nil {
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "No search session is in progress.")
 		}
 		if len(args) < 2 {
-			return NewErrorEmbed("Spotify Error", "You must specify which result to select.")
+			return NewErrorEmbed(env.Locale(), "Spotify Error", "You must specify which result to select.")
 		}
 
-		page = guildData[env.Guild.ID].SpotifyResults[env.Message.Author.ID]
+		page = guildData.Get(env.Guild.ID).GetSpotifyResult(env.Message.Author.ID)
 		results, _ := page.GetResults()
 
 		switch args[1] {
@@ -532,31 +582,29 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			for _, voiceState := range env.Guild.VoiceStates {
 				if voiceState.UserID == env.Message.Author.ID {
 					foundVoiceChannel = true
-					voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
+					voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
 					break
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return NewErrorEmbed(env.Locale(), "Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
 			}
 
 			//Update channel ID to send voice messages to
-			voiceData[env.Guild.ID].TextChannelID = env.Channel.ID
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				voice.TextChannelID = env.Channel.ID
+			})
 
 			waitEmbed := NewEmbed().
 				SetTitle("Spotify").
-				SetDescription("Please wait a while as we add all " + strconv.Itoa(page.TotalResults) + " results to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
+				SetDescription("Please wait a while as we add all " + strconv.Itoa(page.TotalResults) + " results to the queue...\n\nThe first result added will automatically begin playing.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 				SetColor(0x1DB954).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+			getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 			page.AddingAll = true
 
 			for i, result := range page.AllResults {
-				//Give a chance for other commands waiting in line to execute
-				guildData[env.Guild.ID].Unlock()
-				guildData[env.Guild.ID].Lock()
-
-				if page.Cancelled {
+				if page.Cancelled || env.Context.Err() != nil {
 					page.AddingAll = false
 					return nil
 				}
@@ -569,11 +617,11 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
+					return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
 				}
 				queueEntry.Requester = env.Member.User
 
-				go voiceData[env.Guild.ID].Play(queueEntry, false)
+				go voiceData.Get(env.Guild.ID).Play(queueEntry, false)
 
 				page.AddedSoFar++
 			}
@@ -586,31 +634,29 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			for _, voiceState := range env.Guild.VoiceStates {
 				if voiceState.UserID == env.Message.Author.ID {
 					foundVoiceChannel = true
-					voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
+					voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
 					break
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return NewErrorEmbed(env.Locale(), "Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
 			}
 
 			//Update channel ID to send voice messages to
-			voiceData[env.Guild.ID].TextChannelID = env.Channel.ID
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				voice.TextChannelID = env.Channel.ID
+			})
 
 			waitEmbed := NewEmbed().
 				SetTitle("Spotify").
-				SetDescription("Please wait a moment as we add all " + strconv.Itoa(len(page.Results)) + " results to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
+				SetDescription("Please wait a moment as we add all " + strconv.Itoa(len(page.Results)) + " results to the queue...\n\nThe first result added will automatically begin playing.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 				SetColor(0x1DB954).MessageEmbed
-			botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+			getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 			page.AddingAll = true
 
 			for i, result := range page.Results {
-				//Give a chance for other commands waiting in line to execute
-				guildData[env.Guild.ID].Unlock()
-				guildData[env.Guild.ID].Lock()
-
-				if page.Cancelled {
+				if page.Cancelled || env.Context.Err() != nil {
 					page.AddingAll = false
 					return nil
 				}
@@ -623,11 +669,11 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
+					return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting info for result "+strconv.Itoa(i)+".")
 				}
 				queueEntry.Requester = env.Member.User
 
-				go voiceData[env.Guild.ID].Play(queueEntry, false)
+				go voiceData.Get(env.Guild.ID).Play(queueEntry, false)
 
 				page.AddedSoFar++
 			}
@@ -636,28 +682,31 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 			return NewGenericEmbedAdvanced("Spotify", "Finished adding all "+strconv.Itoa(page.AddedSoFar)+" tracks to the queue.", 0x1DB954)
 		default:
-			selection, err := strconv.Atoi(args[1])
-			if err != nil {
-				return NewErrorEmbed("Spotify Error", "``"+args[1]+"`` is not a valid number.")
+			parsed, usageEmbed := parseSubcommandArguments(voiceSelectArguments, args[1:], env)
+			if usageEmbed != nil {
+				return usageEmbed
 			}
-			if selection > len(results) || selection <= 0 {
-				return NewErrorEmbed("Spotify Error", "An invalid selection was specified.")
+			selection := parsed.Int("result")
+			if selection > len(results) {
+				return NewErrorEmbed(env.Locale(), "Spotify Error", "An invalid selection was specified.")
 			}
 
 			foundVoiceChannel := false
 			for _, voiceState := range env.Guild.VoiceStates {
 				if voiceState.UserID == env.Message.Author.ID {
 					foundVoiceChannel = true
-					voiceData[env.Guild.ID].Connect(env.Guild.ID, voiceState.ChannelID)
+					voiceData.Get(env.Guild.ID).Connect(env.Guild.ID, voiceState.ChannelID)
 					break
 				}
 			}
 			if !foundVoiceChannel {
-				return NewErrorEmbed("Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
+				return NewErrorEmbed(env.Locale(), "Spotify Error", "You must join the voice channel to use before using the "+args[0]+" command.")
 			}
 
 			//Update channel ID to send voice messages to
-			voiceData[env.Guild.ID].TextChannelID = env.Channel.ID
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				voice.TextChannelID = env.Channel.ID
+			})
 
 			result := results[selection-1]
 			switch result.GetType() {
@@ -666,31 +715,28 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				queueEntry, err := createQueueEntry(resultURL)
 				if err != nil {
-					return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+					return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting info for the result.")
 				}
 				queueEntry.Requester = env.Member.User
 
-				go voiceData[env.Guild.ID].Play(queueEntry, true)
+				go voiceData.Get(env.Guild.ID).Play(queueEntry, true)
 				return nil
 			case "artist":
-				artistInfo, err := botData.BotClients.Spotify.GetArtistInfo(result.URI)
+				artistInfo, err := getBotData().BotClients.Spotify.GetArtistInfo(result.URI)
 				if err != nil {
-					return NewErrorEmbed("Spotify Error", "Error fetching info for the specified result.")
+					return NewErrorEmbed(env.Locale(), "Spotify Error", "Error fetching info for the specified result.")
 				}
 
 				waitEmbed := NewEmbed().
 					SetTitle("Spotify").
-					SetDescription("Please wait a moment as we add the top " + strconv.Itoa(len(artistInfo.TopTracks)) + " tracks to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
+					SetDescription("Please wait a moment as we add the top " + strconv.Itoa(len(artistInfo.TopTracks)) + " tracks to the queue...\n\nThe first result added will automatically begin playing.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 					SetColor(0x1DB954).MessageEmbed
-				botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+				getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 				page.AddingAll = true
 
 				for _, topTrack := range artistInfo.TopTracks {
-					guildData[env.Guild.ID].Unlock()
-					guildData[env.Guild.ID].Lock()
-
-					if page.Cancelled {
+					if page.Cancelled || env.Context.Err() != nil {
 						page.AddingAll = false
 						return nil
 					}
@@ -699,11 +745,11 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 					queueEntry, err := createQueueEntry(resultURL)
 					if err != nil {
-						return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+						return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting info for the result.")
 					}
 					queueEntry.Requester = env.Member.User
 
-					go voiceData[env.Guild.ID].Play(queueEntry, false)
+					go voiceData.Get(env.Guild.ID).Play(queueEntry, false)
 
 					page.AddedSoFar++
 				}
@@ -712,9 +758,9 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				return NewGenericEmbedAdvanced("Spotify", "Finished adding all "+strconv.Itoa(page.AddedSoFar)+" tracks to the queue.", 0x1DB954)
 			case "album":
-				albumInfo, err := botData.BotClients.Spotify.GetAlbumInfo(result.URI)
+				albumInfo, err := getBotData().BotClients.Spotify.GetAlbumInfo(result.URI)
 				if err != nil {
-					return NewErrorEmbed("Spotify Error", "Error fetching info for the specified result.")
+					return NewErrorEmbed(env.Locale(), "Spotify Error", "Error fetching info for the specified result.")
 				}
 
 				totalTracks := 0
@@ -724,18 +770,15 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 				waitEmbed := NewEmbed().
 					SetTitle("Spotify").
-					SetDescription("Please wait a moment as we add all " + strconv.Itoa(totalTracks) + " tracks to the queue...\n\nThe first result added will automatically begin playing. During this process, it may feel as if other commands are slow or don't work; give them some time to process.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
+					SetDescription("Please wait a moment as we add all " + strconv.Itoa(totalTracks) + " tracks to the queue...\n\nThe first result added will automatically begin playing.\nYou may cancel at any moment with ``" + env.BotPrefix + env.Command + " cancel``. Cancelling will not remove any results added to the queue during this process.").
 					SetColor(0x1DB954).MessageEmbed
-				botData.DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
+				getBotData().DiscordSession.ChannelMessageSendEmbed(env.Channel.ID, waitEmbed)
 
 				page.AddingAll = true
 
 				for _, disc := range albumInfo.Discs {
 					for _, track := range disc.Tracks {
-						guildData[env.Guild.ID].Unlock()
-						guildData[env.Guild.ID].Lock()
-
-						if page.Cancelled {
+						if page.Cancelled || env.Context.Err() != nil {
 							page.AddingAll = false
 							return nil
 						}
@@ -744,11 +787,11 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 
 						queueEntry, err := createQueueEntry(resultURL)
 						if err != nil {
-							return NewErrorEmbed("Voice Error", "There was an error getting info for the result.")
+							return NewErrorEmbed(env.Locale(), "Voice Error", "There was an error getting info for the result.")
 						}
 						queueEntry.Requester = env.Member.User
 
-						go voiceData[env.Guild.ID].Play(queueEntry, false)
+						go voiceData.Get(env.Guild.ID).Play(queueEntry, false)
 
 						page.AddedSoFar++
 					}
@@ -763,12 +806,12 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			}
 		}
 	default:
-		return NewErrorEmbed("Spotify Error", "Unknown command ``"+args[0]+"``.")
+		return NewErrorEmbed(env.Locale(), "Spotify Error", "Unknown command ``"+args[0]+"``.")
 	}
 
 	results, err := page.GetResults()
 	if err != nil {
-		return NewErrorEmbed("Spotify Error", "No search results were found.")
+		return NewErrorEmbed(env.Locale(), "Spotify Error", "No search results were found.")
 	}
 
 	spotifyEmbed := NewEmbed().
@@ -803,7 +846,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 	for i := 0; i < len(results); i++ {
 		switch results[i].GetType() {
 		case "artist":
-			artistInfo, err := botData.BotClients.Spotify.GetArtistInfo(results[i].URI)
+			artistInfo, err := getBotData().BotClients.Spotify.GetArtistInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Artist", Value: "Error fetching info for [this artist](https://open.spotify.com/artist/" + results[i].ID + ")"})
 			} else {
@@ -812,7 +855,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Artist", Value: "[" + artist + "](https://open.spotify.com/artist/" + results[i].ID + ")"})
 			}
 		case "track":
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(results[i].URI)
+			trackInfo, err := getBotData().BotClients.Spotify.GetTrackInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Track", Value: "Error fetching info for [this track](https://open.spotify.com/track/" + results[i].ID + ")"})
 			} else {
@@ -839,7 +882,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 				}
 			}
 		case "album":
-			albumInfo, err := botData.BotClients.Spotify.GetAlbumInfo(results[i].URI)
+			albumInfo, err := getBotData().BotClients.Spotify.GetAlbumInfo(results[i].URI)
 			if err != nil {
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Album", Value: "Error fetching info for [this album](https://open.spotify.com/album/" + results[i].ID + ")"})
 			} else {
@@ -862,7 +905,7 @@ func commandSpotify(args []string, env *CommandEnvironment) *discordgo.MessageEm
 			}
 		case "user":
 			playlistURI := results[i].GetID()
-			playlistInfo, err := botData.BotClients.Spotify.GetPlaylist("spotify:user:" + url.QueryEscape(playlistURI[0]) + ":playlist:" + playlistURI[1])
+			playlistInfo, err := getBotData().BotClients.Spotify.GetPlaylist("spotify:user:" + url.QueryEscape(playlistURI[0]) + ":playlist:" + playlistURI[1])
 			if err != nil {
 				//fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Playlist", Value: "Error fetching info for [this playlist](https://open.spotify.com/user/" + playlistURI[0] + "/playlist/" + playlistURI[1] + ")"})
 				fields = append(fields, &discordgo.MessageEmbedField{Name: "Result #" + strconv.Itoa(i+1) + " - Playlist", Value: "Error fetching info for playlist debug: " + fmt.Sprintf("%v", err)})
@@ -885,38 +928,33 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 	if len(args) >= 1 {
 		switch args[0] {
 		case "clear":
-			if len(voiceData[env.Guild.ID].Entries) > 0 {
-				queueLength := len(voiceData[env.Guild.ID].Entries)
-
-				voiceData[env.Guild.ID].QueueClear()
-
-				return NewGenericEmbed("Queue", "Cleared all "+strconv.Itoa(queueLength)+" entries from the queue.")
+			queueLength := 0
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				queueLength = len(voice.Entries)
+				voice.QueueClear()
+			})
+			if queueLength > 0 {
+				return NewGenericEmbed(env.Locale(), "Queue", "Cleared all "+strconv.Itoa(queueLength)+" entries from the queue.")
 			}
-			return NewErrorEmbed("Queue Error", "There are no entries in the queue to clear.")
+			return NewErrorEmbed(env.Locale(), "Queue Error", "There are no entries in the queue to clear.")
 		case "remove":
-			if len(args) == 1 {
-				return NewErrorEmbed("Queue Error", "You must specify which queue entries to remove.")
+			parsed, usageEmbed := parseSubcommandArguments(queueRemoveArguments, args[1:], env)
+			if usageEmbed != nil {
+				return usageEmbed
 			}
+			removedNumbers := parsed.Ints("entries")
 
-			for _, queueEntry := range args[1:] {
-				queueEntryNumber, err := strconv.Atoi(queueEntry)
-				if err != nil {
-					return NewErrorEmbed("Queue Error", "``"+queueEntry+"`` is not a valid number.")
-				}
-				queueEntryNumber--
-
-				if queueEntryNumber >= len(voiceData[env.Guild.ID].Entries) || queueEntryNumber < 0 {
-					return NewErrorEmbed("Queue Error", "``"+queueEntry+"`` is not a valid queue entry.")
+			for _, queueEntryNumber := range removedNumbers {
+				if queueEntryNumber > len(voiceData.Get(env.Guild.ID).Entries) {
+					return NewErrorEmbed(env.Locale(), "Queue Error", "``"+strconv.Itoa(queueEntryNumber)+"`` is not a valid queue entry.")
 				}
 			}
 
 			var newAudioQueue []*QueueEntry
-			for queueEntryN, queueEntry := range voiceData[env.Guild.ID].Entries {
+			for queueEntryN, queueEntry := range voiceData.Get(env.Guild.ID).Entries {
 				keepQueueEntry := true
-				for _, removedQueueEntry := range args[1:] {
-					removedQueueEntryNumber, _ := strconv.Atoi(removedQueueEntry)
-					removedQueueEntryNumber--
-					if queueEntryN == removedQueueEntryNumber {
+				for _, removedQueueEntryNumber := range removedNumbers {
+					if queueEntryN == removedQueueEntryNumber-1 {
 						keepQueueEntry = false
 						break
 					}
@@ -926,56 +964,58 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 				}
 			}
 
-			voiceData[env.Guild.ID].Entries = newAudioQueue
+			voiceData.Update(env.Guild.ID, func(voice *Voice) {
+				voice.Entries = newAudioQueue
+			})
 
-			if len(args) > 2 {
-				return NewGenericEmbed("Queue", "Successfully removed the specified queue entries.")
+			if len(removedNumbers) > 1 {
+				return NewGenericEmbed(env.Locale(), "Queue", "Successfully removed the specified queue entries.")
 			}
-			return NewGenericEmbed("Queue", "Successfully removed the specified queue entry.")
+			return NewGenericEmbed(env.Locale(), "Queue", "Successfully removed the specified queue entry.")
 		case "copy":
 			if len(args) == 1 {
-				return NewErrorEmbed("Queue Error", "You must specify which guild queue(s) to copy.")
+				return NewErrorEmbed(env.Locale(), "Queue Error", "You must specify which guild queue(s) to copy.")
 			}
 
 			for _, guildID := range args[1:] {
-				if _, exists := guildData[guildID]; exists == false {
-					return NewErrorEmbed("Queue Error", "The guild ID ``"+guildID+"`` does not point to a known guild.")
+				if _, exists := guildData.Load(guildID); exists == false {
+					return NewErrorEmbed(env.Locale(), "Queue Error", "The guild ID ``"+guildID+"`` does not point to a known guild.")
 				}
 			}
 
 			copiedGuilds := make([]string, 0)
 			for _, guildID := range args[1:] {
-				if voice, exists := voiceData[guildID]; exists { //Just in case it doesn't exist anymore when we reach this point, we all know how edge cases go
+				if voice, exists := voiceData.Load(guildID); exists { //Just in case it doesn't exist anymore when we reach this point, we all know how edge cases go
 					if voice.NowPlaying.Entry.Metadata.StreamURL != "" || len(voice.Entries) > 0 {
+						copiedEntries := make([]*QueueEntry, 0)
 						if voice.NowPlaying.Entry.Metadata.StreamURL != "" {
-							voiceData[env.Guild.ID].Entries = append(voiceData[env.Guild.ID].Entries, voice.NowPlaying.Entry)
-						}
-						if len(voice.Entries) > 0 {
-							for i := 0; i < len(voice.Entries); i++ {
-								voiceData[env.Guild.ID].Entries = append(voiceData[env.Guild.ID].Entries, voice.Entries[i])
-							}
+							copiedEntries = append(copiedEntries, voice.NowPlaying.Entry)
 						}
+						copiedEntries = append(copiedEntries, voice.Entries...)
+						voiceData.Update(env.Guild.ID, func(voice *Voice) {
+							voice.Entries = append(voice.Entries, copiedEntries...)
+						})
 
-						guildState, _ := botData.DiscordSession.State.Guild(guildID)
+						guildState, _ := getBotData().DiscordSession.State.Guild(guildID)
 						copiedGuilds = append(copiedGuilds, guildState.Name)
 					}
 				}
 			}
 
 			if len(copiedGuilds) == 1 {
-				return NewGenericEmbed("Queue", "Successfully copied the queue from "+copiedGuilds[0]+".")
+				return NewGenericEmbed(env.Locale(), "Queue", "Successfully copied the queue from "+copiedGuilds[0]+".")
 			}
-			return NewGenericEmbed("Queue", "Successfully copied the queue from the following guilds:\n\n"+strings.Join(copiedGuilds, "\n"))
+			return NewGenericEmbed(env.Locale(), "Queue", "Successfully copied the queue from the following guilds:\n\n"+strings.Join(copiedGuilds, "\n"))
 		}
 	}
 
+	parsed, usageEmbed := parseSubcommandArguments(queuePageArguments, args, env)
+	if usageEmbed != nil {
+		return usageEmbed
+	}
 	pageNumber := 1
-	if len(args) >= 1 {
-		num, err := strconv.Atoi(args[0])
-		if err != nil {
-			return NewErrorEmbed("Queue Error", "Invalid page number ``"+args[0]+"``.")
-		}
-		pageNumber = num
+	if parsed.Has("page") {
+		pageNumber = parsed.Int("page")
 	}
 
 	nowPlaying := QueueEntry{}
@@ -984,8 +1024,8 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 		Value: "There is no audio currently playing.",
 	}
 
-	if voiceData[env.Guild.ID].IsStreaming() && voiceData[env.Guild.ID].NowPlaying != nil {
-		nowPlaying = *voiceData[env.Guild.ID].NowPlaying.Entry
+	if voiceData.Get(env.Guild.ID).IsStreaming() && voiceData.Get(env.Guild.ID).NowPlaying != nil {
+		nowPlaying = *voiceData.Get(env.Guild.ID).NowPlaying.Entry
 		track := "[" + nowPlaying.Metadata.Title + "](" + nowPlaying.Metadata.DisplayURL + ")"
 		if len(nowPlaying.Metadata.Artists) > 0 {
 			track += " by [" + nowPlaying.Metadata.Artists[0].Name + "](" + nowPlaying.Metadata.Artists[0].URL + ")"
@@ -1008,7 +1048,7 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 	}
 
 	queueList := make([]*discordgo.MessageEmbedField, 0)
-	for queueEntryNumber, queueEntry := range voiceData[env.Guild.ID].Entries {
+	for queueEntryNumber, queueEntry := range voiceData.Get(env.Guild.ID).Entries {
 		displayNumber := strconv.Itoa(queueEntryNumber + 1)
 
 		queueEntryFieldName := "Entry #" + displayNumber + " - " + queueEntry.ServiceName
@@ -1053,7 +1093,7 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 
 	pagedQueueList, totalPages, err := page(queueList, pageNumber, 10)
 	if err != nil {
-		return NewErrorEmbed("Queue Error", fmt.Sprintf("%v", err))
+		return NewErrorEmbed(env.Locale(), "Queue Error", fmt.Sprintf("%v", err))
 	}
 
 	queueColor := 0x1C1C1C
@@ -1077,24 +1117,24 @@ func commandQueue(args []string, env *CommandEnvironment) *discordgo.MessageEmbe
 }
 
 func commandNowPlaying(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if voiceData[env.Guild.ID].IsStreaming() {
-		return voiceData[env.Guild.ID].GetNowPlayingDurationEmbed(voiceData[env.Guild.ID].NowPlaying.Entry)
+	if voiceData.Get(env.Guild.ID).IsStreaming() {
+		return voiceData.Get(env.Guild.ID).GetNowPlayingDurationEmbed(voiceData.Get(env.Guild.ID).NowPlaying.Entry)
 	}
-	return NewErrorEmbed("Now Playing Error", "There is no audio currently playing.")
+	return NewErrorEmbed(env.Locale(), "Now Playing Error", "There is no audio currently playing.")
 }
 
 func commandLyrics(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if !voiceData[env.Guild.ID].IsStreaming() {
-		return NewErrorEmbed("Lyrics Error", "There is no audio currently playing.")
+	if !voiceData.Get(env.Guild.ID).IsStreaming() {
+		return NewErrorEmbed(env.Locale(), "Lyrics Error", "There is no audio currently playing.")
 	}
 
-	lyrics, err := botData.BotClients.Lyrics.Search(voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Title, voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.Artists[0].Name)
+	lyrics, err := getBotData().BotClients.Lyrics.Search(voiceData.Get(env.Guild.ID).NowPlaying.Entry.Metadata.Title, voiceData.Get(env.Guild.ID).NowPlaying.Entry.Metadata.Artists[0].Name)
 	if err != nil {
-		return NewErrorEmbed("Lyrics Error", "There was an error fetching the lyrics for the current track.")
+		return NewErrorEmbed(env.Locale(), "Lyrics Error", "There was an error fetching the lyrics for the current track.")
 	}
 
 	return NewEmbed().
 		AddField("Lyrics", lyrics).
-		SetThumbnail(voiceData[env.Guild.ID].NowPlaying.Entry.Metadata.ThumbnailURL).
-		SetColor(voiceData[env.Guild.ID].NowPlaying.Entry.ServiceColor).MessageEmbed
+		SetThumbnail(voiceData.Get(env.Guild.ID).NowPlaying.Entry.Metadata.ThumbnailURL).
+		SetColor(voiceData.Get(env.Guild.ID).NowPlaying.Entry.ServiceColor).MessageEmbed
 }
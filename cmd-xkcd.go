@@ -6,12 +6,19 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+var (
+	// xkcdComicArguments contains the comic number xkcd takes in place of latest or random
+	xkcdComicArguments = []CommandArgument{
+		{Name: "comic number", Description: "The number of the comic to view", ArgType: ArgTypeNumber, Min: 1},
+	}
+)
+
 func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	switch args[0] {
 	case "latest":
-		comic, err := botData.BotClients.XKCD.Latest()
+		comic, err := getBotData().BotClients.XKCD.Latest()
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching the latest xkcd comic.")
+			return NewErrorEmbed(env.Locale(), "xkcd Error", "There was an error fetching the latest xkcd comic.")
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + strconv.Itoa(comic.Number)).
@@ -19,9 +26,9 @@ func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 			SetImage(comic.ImageURL).
 			SetColor(0x96A8C8).MessageEmbed
 	case "random":
-		comic, err := botData.BotClients.XKCD.Random()
+		comic, err := getBotData().BotClients.XKCD.Random()
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching a random xkcd comic.")
+			return NewErrorEmbed(env.Locale(), "xkcd Error", "There was an error fetching a random xkcd comic.")
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + strconv.Itoa(comic.Number)).
@@ -29,14 +36,14 @@ func commandXKCD(args []string, env *CommandEnvironment) *discordgo.MessageEmbed
 			SetImage(comic.ImageURL).
 			SetColor(0x96A8C8).MessageEmbed
 	default:
-		comicNumber, err := strconv.Atoi(args[0])
-		if err != nil {
-			return NewErrorEmbed("xkcd Error", "``"+args[0]+"`` is not a valid number.")
+		parsed, usageEmbed := parseSubcommandArguments(xkcdComicArguments, args[:1], env)
+		if usageEmbed != nil {
+			return usageEmbed
 		}
 
-		comic, err := botData.BotClients.XKCD.Get(comicNumber)
+		comic, err := getBotData().BotClients.XKCD.Get(parsed.Int("comic number"))
 		if err != nil {
-			return NewErrorEmbed("xkcd Error", "There was an error fetching xkcd comic #"+args[0]+".")
+			return NewErrorEmbed(env.Locale(), "xkcd Error", "There was an error fetching xkcd comic #"+args[0]+".")
 		}
 		return NewEmbed().
 			SetTitle("xkcd - #" + args[0]).
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -20,6 +22,47 @@ type Command struct {
 
 	IsAdvancedCommand bool                                                                 //Whether or not this command uses advanced parameters
 	AdvancedFunction  func([]CommandArgument, *CommandEnvironment) *discordgo.MessageEmbed //The function value of what to execute when the command is ran
+
+	TypedArguments bool //Whether or not the arguments should be parsed and validated by their ArgType before the command is ran, see CommandEnvironment.Arguments
+
+	Cooldown CommandCooldown //How long users, channels and guilds must wait between uses of this command, can be overridden per guild
+
+	Category string //The category this command belongs to, used for toggling groups of commands
+
+	Examples []string //Example arguments shown in the command's detailed help, each after the command name
+
+	AllowDM bool //Whether or not this command can be used in direct messages, where the environment has no guild or member
+
+	Timeout time.Duration //How long the command may run before it's cancelled; default = DefaultCommandTimeout
+
+	RunsUnlocked func([]string) bool //For settings commands, whether or not the arguments run without the guild's settings locked, for subcommands that lock what they change themselves
+
+	ResponseFunction         func([]string, *CommandEnvironment) *CommandResponse          //Used instead of Function for commands that respond with more than a single embed
+	AdvancedResponseFunction func([]CommandArgument, *CommandEnvironment) *CommandResponse //Used instead of AdvancedFunction for advanced commands that respond with more than a single embed
+}
+
+// Command categories for grouping commands together
+const (
+	CommandCategoryInfo           = "info"
+	CommandCategoryFun            = "fun"
+	CommandCategoryUtility        = "utility"
+	CommandCategoryEconomy        = "economy"
+	CommandCategoryVoice          = "voice"
+	CommandCategoryModeration     = "moderation"
+	CommandCategorySettings       = "settings"
+	CommandCategoryAdministrative = "administrative"
+)
+
+// CommandCategories contains every command category in the order they should be listed
+var CommandCategories = []string{
+	CommandCategoryInfo,
+	CommandCategoryFun,
+	CommandCategoryUtility,
+	CommandCategoryEconomy,
+	CommandCategoryVoice,
+	CommandCategoryModeration,
+	CommandCategorySettings,
+	CommandCategoryAdministrative,
 }
 
 // CommandArgument holds data related to an argument available or required by a command
@@ -31,6 +74,13 @@ type CommandArgument struct {
 
 	//Used for command argument parsing
 	Value string //The value supplied with the argument
+
+	//Used for typed argument parsing
+	Optional bool     //Whether or not the argument may be left out
+	Multiple bool     //Whether or not the argument takes every following value of its type
+	Min      float64  //The minimum number, percentage or duration in seconds allowed
+	Max      float64  //The maximum number, percentage or duration in seconds allowed; if Min and Max are both 0, no range is enforced
+	Choices  []string //The only values allowed, if any
 }
 
 // CommandEnvironment holds data related to the environment a command can utilize for data or functionality
@@ -41,51 +91,65 @@ type CommandEnvironment struct {
 	User    *discordgo.User    //The user that executed the command
 	Member  *discordgo.Member  //The guild member that executed the command
 
-	Command   string //The command used to execute the command with this environment (in the event of a command alias)
-	BotPrefix string //The bot prefix used to execute this command (useful for command lists and example commands)
+	Context context.Context //Cancelled when the command runs out of time, pass it along to anything that may take a while
+
+	Command     string           //The command used to execute the command with this environment (in the event of a command alias)
+	CommandLine string           //The command and its arguments as they were written without the bot prefix, if they were written out
+	BotPrefix   string           //The bot prefix used to execute this command (useful for command lists and example commands)
+	Arguments   *ParsedArguments //The typed arguments, if the command uses TypedArguments
+	Input       *PipeInput       //The output of the previous command in a pipeline, if it was piped into this command
 
 	UpdatedMessageEvent bool
 }
 
-func initCommands() {
+func initCommands(config *BotData) {
 	//Initialize the commands map
-	botData.Commands = make(map[string]*Command)
+	config.Commands = make(map[string]*Command)
 
 	//All user-accessible commands with no parameters
-	botData.Commands["about"] = &Command{Function: commandAbout, HelpText: "Displays information about " + botData.BotName + " and how to use it."}
-	botData.Commands["invite"] = &Command{Function: commandInvite, HelpText: "Displays available invite links for " + botData.BotName + "."}
-	botData.Commands["donate"] = &Command{Function: commandDonate, HelpText: "Displays available donation links for " + botData.BotName + "."}
-	botData.Commands["source"] = &Command{Function: commandSource, HelpText: "Displays available source code links for " + botData.BotName + "."}
-	botData.Commands["version"] = &Command{Function: commandVersion, HelpText: "Displays the current version of " + botData.BotName + "."}
-	botData.Commands["credits"] = &Command{Function: commandCredits, HelpText: "Displays a list of credits for the creation and functionality of " + botData.BotName + "."}
-	botData.Commands["roll"] = &Command{Function: commandRoll, HelpText: "Rolls a dice."}
-	botData.Commands["doubleroll"] = &Command{Function: commandDoubleRoll, HelpText: "Rolls two die."}
-	botData.Commands["coinflip"] = &Command{Function: commandCoinFlip, HelpText: "Flips a coin."}
-	botData.Commands["join"] = &Command{Function: commandVoiceJoin, HelpText: "Joins the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
-	botData.Commands["leave"] = &Command{Function: commandVoiceLeave, HelpText: "Leaves the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect}
-	botData.Commands["ping"] = &Command{Function: commandPing, HelpText: "Returns the ping average to Discord."}
+	config.Commands["about"] = &Command{Function: commandAbout, HelpText: "Displays information about " + config.BotName + " and how to use it.", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["invite"] = &Command{Function: commandInvite, HelpText: "Displays available invite links for " + config.BotName + ".", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["donate"] = &Command{Function: commandDonate, HelpText: "Displays available donation links for " + config.BotName + ".", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["source"] = &Command{Function: commandSource, HelpText: "Displays available source code links for " + config.BotName + ".", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["version"] = &Command{Function: commandVersion, HelpText: "Displays the current version of " + config.BotName + ".", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["credits"] = &Command{Function: commandCredits, HelpText: "Displays a list of credits for the creation and functionality of " + config.BotName + ".", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["roll"] = &Command{Function: commandRoll, HelpText: "Rolls a dice.", Category: CommandCategoryFun, AllowDM: true}
+	config.Commands["doubleroll"] = &Command{Function: commandDoubleRoll, HelpText: "Rolls two die.", Category: CommandCategoryFun, AllowDM: true}
+	config.Commands["coinflip"] = &Command{Function: commandCoinFlip, HelpText: "Flips a coin.", Category: CommandCategoryFun, AllowDM: true}
+	config.Commands["join"] = &Command{Function: commandVoiceJoin, HelpText: "Joins the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect, Category: CommandCategoryVoice}
+	config.Commands["leave"] = &Command{Function: commandVoiceLeave, HelpText: "Leaves the current voice channel.", RequiredPermissions: discordgo.PermissionVoiceConnect, Category: CommandCategoryVoice}
+	config.Commands["ping"] = &Command{Function: commandPing, HelpText: "Returns the ping average to Discord.", Category: CommandCategoryInfo, AllowDM: true}
 
 	//All user-accessible info commands with or without parameters
-	botData.Commands["botinfo"] = &Command{Function: commandBotInfo, HelpText: "Displays info about the bot's current state."}
-	botData.Commands["serverinfo"] = &Command{Function: commandServerInfo, HelpText: "Displays info about the current server."}
-	botData.Commands["userinfo"] = &Command{
-		Function: commandUserInfo,
-		HelpText: "Displays info about the current or specified user.",
+	config.Commands["botinfo"] = &Command{Function: commandBotInfo, HelpText: "Displays info about the bot's current state.", Category: CommandCategoryInfo, AllowDM: true}
+	config.Commands["serverinfo"] = &Command{Function: commandServerInfo, HelpText: "Displays info about the current server.", Category: CommandCategoryInfo}
+	config.Commands["userinfo"] = &Command{
+		Category:       CommandCategoryInfo,
+		Function:       commandUserInfo,
+		HelpText:       "Displays info about the current or specified user.",
+		TypedArguments: true,
 		Arguments: []CommandArgument{
-			{Name: "user", Description: "The user to view info about", ArgType: "mention/user ID"},
+			{Name: "user", Description: "The user to view info about", ArgType: "mention/user ID", Optional: true},
 		},
 	}
 
 	//All user-accessible commands with parameters
-	botData.Commands["help"] = &Command{
+	config.Commands["help"] = &Command{
+		Category: CommandCategoryInfo,
+		AllowDM:  true,
 		Function: commandHelp,
-		HelpText: "Displays a list of commands you have permission to use.",
+		HelpText: "Displays the commands you have permission to use by category, or detailed help for a command.",
 		Arguments: []CommandArgument{
-			{Name: "page", Description: "The help page to view", ArgType: "number"},
-			{Name: "command", Description: "The command to view help for", ArgType: "string"},
+			{Name: "page", Description: "The page of every command to view", ArgType: "number"},
+			{Name: "category", Description: "The category of commands to view, optionally followed by a page", ArgType: "string"},
+			{Name: "command", Description: "The command to view detailed help for", ArgType: "string"},
+			{Name: "search", Description: "Searches the help text of every command for a keyword", ArgType: "keyword"},
 		},
+		Examples: []string{"voice", "play", "search queue"},
 	}
-	botData.Commands["nnid"] = &Command{
+	config.Commands["nnid"] = &Command{
+		Category: CommandCategoryUtility,
+		AllowDM:  true,
 		Function: commandNNID,
 		HelpText: "Checks whether the specified NNID exists or not.",
 		RequiredArguments: []string{
@@ -95,9 +159,12 @@ func initCommands() {
 			{Name: "username", Description: "The NNID to check for", ArgType: "string"},
 		},
 	}
-	botData.Commands["remind"] = &Command{
+	config.Commands["remind"] = &Command{
+		Category: CommandCategoryUtility,
+		AllowDM:  true,
 		Function: commandRemind,
 		HelpText: "Reminds you with the written message at the specified time.",
+		Examples: []string{"check the oven in 20 minutes", "list", "remove 1 2"},
 		RequiredArguments: []string{
 			"(message and time)/other",
 		},
@@ -107,7 +174,9 @@ func initCommands() {
 			{Name: "remove", Description: "Deletes the specified remind entry or entries", ArgType: "number(s)"},
 		},
 	}
-	botData.Commands["hewwo"] = &Command{
+	config.Commands["hewwo"] = &Command{
+		Category: CommandCategoryFun,
+		AllowDM:  true,
 		Function: commandHewwo,
 		HelpText: "Hewwo!!! (´・ω・｀)",
 		RequiredArguments: []string{
@@ -117,9 +186,12 @@ func initCommands() {
 			{Name: "message", Description: "The text to translate to Hewwo", ArgType: "string"},
 		},
 	}
-	botData.Commands["minecraft"] = &Command{
+	config.Commands["minecraft"] = &Command{
+		Category: CommandCategoryUtility,
+		AllowDM:  true,
 		Function: commandMinecraft,
 		HelpText: "Displays information about a specified user or server.",
+		Examples: []string{"user Notch", "server mc.hypixel.net"},
 		RequiredArguments: []string{
 			"user/server",
 			"name/host",
@@ -129,7 +201,9 @@ func initCommands() {
 			{Name: "server", Description: "Displays infromation about the specified server", ArgType: "ip(:port)"},
 		},
 	}
-	botData.Commands["zalgo"] = &Command{
+	config.Commands["zalgo"] = &Command{
+		Category: CommandCategoryFun,
+		AllowDM:  true,
 		Function: commandZalgo,
 		HelpText: "Mystifies your text.",
 		RequiredArguments: []string{
@@ -139,7 +213,9 @@ func initCommands() {
 			{Name: "message", Description: "The text to mystify", ArgType: "string"},
 		},
 	}
-	botData.Commands["nlp"] = &Command{
+	config.Commands["nlp"] = &Command{
+		Category: CommandCategoryFun,
+		AllowDM:  true,
 		Function: commandNLP,
 		HelpText: "Raw natural language processing in Discord. Powered by Prose:tm:.",
 		RequiredArguments: []string{
@@ -149,10 +225,15 @@ func initCommands() {
 			{Name: "message", Description: "The message to parse", ArgType: "string"},
 		},
 	}
-	botData.Commands["image"] = &Command{
-		IsAdvancedCommand: true,
-		AdvancedFunction:  commandImageAdv,
-		HelpText:          "Allows you to manipulate images with various effects.",
+	config.Commands["image"] = &Command{
+		Category:                 CommandCategoryFun,
+		AllowDM:                  true,
+		IsAdvancedCommand:        true,
+		AdvancedResponseFunction: commandImageAdv,
+		HelpText:                 "Allows you to manipulate images with various effects.",
+		Examples:                 []string{"-grayscale -rotate 90", "-width 512 -pixelate 8"},
+		Cooldown:                 CommandCooldown{User: 15 * time.Second},
+		Timeout:                  time.Minute,
 		RequiredArguments: []string{
 			"-effect (value)",
 		},
@@ -179,9 +260,14 @@ func initCommands() {
 			{Name: "width", Description: "Sets the width", ArgType: "number"},
 		},
 	}
-	botData.Commands["screenshot"] = &Command{
-		Function: commandScreenshot,
-		HelpText: "Takes a screenshot of a website.",
+	config.Commands["screenshot"] = &Command{
+		Category:         CommandCategoryUtility,
+		AllowDM:          true,
+		ResponseFunction: commandScreenshot,
+		HelpText:         "Takes a screenshot of a website.",
+		Examples:         []string{"https://github.com"},
+		TypedArguments:   true,
+		Cooldown:         CommandCooldown{User: 30 * time.Second, Guild: 5 * time.Second},
 		RequiredArguments: []string{
 			"url",
 		},
@@ -189,7 +275,9 @@ func initCommands() {
 			{Name: "url", Description: "The URL to take a screenshot of", ArgType: "url"},
 		},
 	}
-	botData.Commands["cve"] = &Command{
+	config.Commands["cve"] = &Command{
+		Category: CommandCategoryUtility,
+		AllowDM:  true,
 		Function: commandCVE,
 		HelpText: "Fetches information about a specified CVE.",
 		RequiredArguments: []string{
@@ -199,7 +287,9 @@ func initCommands() {
 			{Name: "cve", Description: "The CVE ID to fetch information about", ArgType: "string"},
 		},
 	}
-	botData.Commands["geoip"] = &Command{
+	config.Commands["geoip"] = &Command{
+		Category: CommandCategoryUtility,
+		AllowDM:  true,
 		Function: commandGeoIP,
 		HelpText: "Performs a GeoIP lookup on the specified IP/hostname.",
 		RequiredArguments: []string{
@@ -209,8 +299,10 @@ func initCommands() {
 			{Name: "IP/hostname", Description: "The IP or hostname to perform a GeoIP lookup on", ArgType: "IP address/hostname"},
 		},
 	}
-	if botData.BotOptions.UseXKCD {
-		botData.Commands["xkcd"] = &Command{
+	if config.BotOptions.UseXKCD {
+		config.Commands["xkcd"] = &Command{
+			Category: CommandCategoryFun,
+			AllowDM:  true,
 			Function: commandXKCD,
 			HelpText: "Displays an XKCD comic depending on the requested type or comic number.",
 			RequiredArguments: []string{
@@ -223,8 +315,10 @@ func initCommands() {
 			},
 		}
 	}
-	if botData.BotOptions.UseImgur {
-		botData.Commands["imgur"] = &Command{
+	if config.BotOptions.UseImgur {
+		config.Commands["imgur"] = &Command{
+			Category: CommandCategoryFun,
+			AllowDM:  true,
 			Function: commandImgur,
 			HelpText: "Displays info about the specified Imgur image or album URL.",
 			RequiredArguments: []string{
@@ -235,8 +329,10 @@ func initCommands() {
 			},
 		}
 	}
-	if botData.BotOptions.UseGitHub {
-		botData.Commands["github"] = &Command{
+	if config.BotOptions.UseGitHub {
+		config.Commands["github"] = &Command{
+			Category: CommandCategoryUtility,
+			AllowDM:  true,
 			Function: commandGitHub,
 			HelpText: "Displays info about the specified GitHub user or repo and fetches trending users and repositories.",
 			RequiredArguments: []string{
@@ -249,7 +345,9 @@ func initCommands() {
 			},
 		}
 	}
-	botData.Commands["urbandictionary"] = &Command{
+	config.Commands["urbandictionary"] = &Command{
+		Category: CommandCategoryFun,
+		AllowDM:  true,
 		Function: commandUrbanDictionary,
 		HelpText: "Displays the definition of a term according to the Urban Dictionary.",
 		RequiredArguments: []string{
@@ -259,55 +357,78 @@ func initCommands() {
 			{Name: "term", Description: "The term to fetch a definition for", ArgType: "string"},
 		},
 	}
-	botData.Commands["balance"] = &Command{
-		Function: commandBalance,
-		HelpText: "Displays the user's current balance.",
+	config.Commands["balance"] = &Command{
+		Category:       CommandCategoryEconomy,
+		AllowDM:        true,
+		Function:       commandBalance,
+		HelpText:       "Displays the user's current balance.",
+		Examples:       []string{"@user"},
+		TypedArguments: true,
+		Arguments: []CommandArgument{
+			{Name: "users", Description: "The users to display the balances of instead", ArgType: "mention", Optional: true, Multiple: true},
+		},
 	}
-	botData.Commands["daily"] = &Command{
+	config.Commands["daily"] = &Command{
+		Category: CommandCategoryEconomy,
+		AllowDM:  true,
 		Function: commandDaily,
 		HelpText: "Lets the user receive credits daily.",
 	}
-	botData.Commands["transfer"] = &Command{
-		Function: commandTransfer,
-		HelpText: "Transfers credits to another user.",
+	config.Commands["transfer"] = &Command{
+		Category:       CommandCategoryEconomy,
+		AllowDM:        true,
+		Function:       commandTransfer,
+		HelpText:       "Transfers credits to another user.",
+		Examples:       []string{"100 @user"},
+		TypedArguments: true,
 		RequiredArguments: []string{
 			"amount",
 			"target",
 		},
 		Arguments: []CommandArgument{
-			{Name: "amount", Description: "The amount of credits to transfer", ArgType: "number"},
+			{Name: "amount", Description: "The amount of credits to transfer", ArgType: "number", Min: 1},
 			{Name: "target", Description: "The target user to transfer credits to", ArgType: "mention"},
 		},
 	}
 
 	//Voice commands
-	botData.Commands["play"] = &Command{
-		Function: commandPlay,
-		HelpText: "Plays either the first result from a YouTube search query or the specified stream URL in the user's voice channel.",
+	config.Commands["play"] = &Command{
+		Category:         CommandCategoryVoice,
+		ResponseFunction: commandPlay,
+		HelpText:         "Plays either the first result from a YouTube search query or the specified stream URL in the user's voice channel.",
+		Examples:         []string{"never gonna give you up", "https://www.youtube.com/watch?v=dQw4w9WgXcQ"},
+		Cooldown:         CommandCooldown{User: 5 * time.Second},
+		Timeout:          time.Minute,
 		Arguments: []CommandArgument{
 			{Name: "search query", Description: "The YouTube search query to use when fetching a video to play", ArgType: "string"},
 			{Name: "url", Description: "The YouTube, Spotify, SoundCloud, Bandcamp or direct audio/video URL to play", ArgType: "string"},
 		},
 	}
-	botData.Commands["stop"] = &Command{
+	config.Commands["stop"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandStop,
 		HelpText: "Stops the audio playback in the user's voice channel.",
 	}
-	botData.Commands["skip"] = &Command{
+	config.Commands["skip"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandSkip,
 		HelpText: "Skips to the next queue entry in the user's voice channel.",
 	}
-	botData.Commands["pause"] = &Command{
+	config.Commands["pause"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandPause,
 		HelpText: "Pauses the audio playback in the user's voice channel.",
 	}
-	botData.Commands["resume"] = &Command{
+	config.Commands["resume"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandResume,
 		HelpText: "Resumes the audio playback in the user's voice channel.",
 	}
-	botData.Commands["volume"] = &Command{
+	config.Commands["volume"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandVolume,
 		HelpText: "Sets the volume level for the next audio playback.",
+		Examples: []string{"50"},
 		RequiredArguments: []string{
 			"volume",
 		},
@@ -315,7 +436,8 @@ func initCommands() {
 			{Name: "volume", Description: "The volume level to use", ArgType: "number [0 - 512]"},
 		},
 	}
-	botData.Commands["repeat"] = &Command{
+	config.Commands["repeat"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandRepeat,
 		HelpText: "Switches queue playback between three modes: no repeat, repeat queue, and repeat now playing.",
 		Arguments: []CommandArgument{
@@ -325,14 +447,18 @@ func initCommands() {
 		},
 	}
 	/* Disabled until a complete shuffle implementation is in place
-	botData.Commands["shuffle"] = &Command{
+	config.Commands["shuffle"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandShuffle,
 		HelpText: "Toggles queue shuffling during playback.",
 	}
 	*/
-	botData.Commands["youtube"] = &Command{
+	config.Commands["youtube"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandYouTube,
+		Timeout:  time.Minute,
 		HelpText: "Allows you to navigate YouTube search results to select what to add to the queue.",
+		Examples: []string{"search lofi hip hop", "play 2"},
 		RequiredArguments: []string{
 			"command (value)",
 		},
@@ -344,9 +470,12 @@ func initCommands() {
 			{Name: "play", Description: "Plays the chosen search result from the current page", ArgType: "number"},
 		},
 	}
-	botData.Commands["spotify"] = &Command{
+	config.Commands["spotify"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandSpotify,
+		Timeout:  10 * time.Minute, //Adding a whole playlist or album to the queue can take a while
 		HelpText: "Allows you to search Spotify search results and playlists to select to what to add to the queue.",
+		Examples: []string{"search daft punk", "play all"},
 		RequiredArguments: []string{
 			"command (value)",
 		},
@@ -362,62 +491,76 @@ func initCommands() {
 			{Name: "play view", Description: "Plays every track result on the current page", ArgType: "this"},
 		},
 	}
-	botData.Commands["queue"] = &Command{
+	config.Commands["queue"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandQueue,
 		HelpText: "Lists and manages entries in the queue.",
+		Examples: []string{"remove 3", "clear"},
 		Arguments: []CommandArgument{
 			{Name: "clear", Description: "Clears the queue", ArgType: "this"},
 			{Name: "remove", Description: "Removes the specified queue entry or entries", ArgType: "number"},
 		},
 	}
-	botData.Commands["nowplaying"] = &Command{
+	config.Commands["nowplaying"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandNowPlaying,
 		HelpText: "Displays the now playing entry.",
 	}
-	botData.Commands["lyrics"] = &Command{
+	config.Commands["lyrics"] = &Command{
+		Category: CommandCategoryVoice,
 		Function: commandLyrics,
 		HelpText: "Displays the lyrics for the currently playing track.",
 	}
 
 	//All moderation commands with parameters
-	botData.Commands["purge"] = &Command{
+	config.Commands["purge"] = &Command{
+		Category:            CommandCategoryModeration,
 		Function:            commandPurge,
 		HelpText:            "Purges the specified amount of messages from the channel, up to 100 messages at a time.",
+		Examples:            []string{"50", "20 @user"},
 		RequiredPermissions: discordgo.PermissionManageMessages,
+		TypedArguments:      true,
 		RequiredArguments: []string{
 			"amount (user1) (user2) (user3)",
 		},
 		Arguments: []CommandArgument{
-			{Name: "message count", Description: "The amount of messages to delete", ArgType: "number"},
-			{Name: "user(s)", Description: "The user(s) to delete the messages from within the specified amount of messages", ArgType: "mention"},
+			{Name: "message count", Description: "The amount of messages to delete", ArgType: "number", Min: 1, Max: 100},
+			{Name: "user(s)", Description: "The user(s) to delete the messages from within the specified amount of messages", ArgType: "mention", Optional: true, Multiple: true},
 		},
 	}
-	botData.Commands["kick"] = &Command{
+	config.Commands["kick"] = &Command{
+		Category:            CommandCategoryModeration,
 		Function:            commandKick,
 		HelpText:            "Kicks the specified user(s) from the server.",
+		Examples:            []string{"@user spamming"},
 		RequiredPermissions: discordgo.PermissionKickMembers,
+		TypedArguments:      true,
 		RequiredArguments: []string{
 			"user1 (user2) (user3) (reason for kick)",
 		},
 		Arguments: []CommandArgument{
-			{Name: "user(s)", Description: "The user(s) to kick", ArgType: "mention"},
-			{Name: "reason", Description: "The reason for the kick", ArgType: "string"},
+			{Name: "user(s)", Description: "The user(s) to kick", ArgType: "mention", Multiple: true},
+			{Name: "reason", Description: "The reason for the kick", ArgType: "string", Optional: true, Multiple: true},
 		},
 	}
-	botData.Commands["ban"] = &Command{
+	config.Commands["ban"] = &Command{
+		Category:            CommandCategoryModeration,
 		Function:            commandBan,
 		HelpText:            "Bans the specified user(s) from the server.",
+		Examples:            []string{"7 @user raiding"},
 		RequiredPermissions: discordgo.PermissionBanMembers,
+		TypedArguments:      true,
 		RequiredArguments: []string{
 			"(days) user1 (user2) (user3) (reason for ban)",
 		},
 		Arguments: []CommandArgument{
-			{Name: "days", Description: "How many days worth of messages to delete from the specified user(s)", ArgType: "number"},
-			{Name: "user(s)", Description: "The user(s) to ban", ArgType: "mention"},
-			{Name: "reason", Description: "The reason for the ban", ArgType: "string"},
+			{Name: "days", Description: "How many days worth of messages to delete from the specified user(s)", ArgType: "number", Optional: true, Max: 7},
+			{Name: "user(s)", Description: "The user(s) to ban", ArgType: "mention", Multiple: true},
+			{Name: "reason", Description: "The reason for the ban", ArgType: "string", Optional: true, Multiple: true},
 		},
 	}
-	botData.Commands["hackban"] = &Command{
+	config.Commands["hackban"] = &Command{
+		Category:            CommandCategoryModeration,
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandHackBan,
 		HelpText:            "Bans the specified user ID(s) from the server.",
@@ -432,8 +575,9 @@ func initCommands() {
 		},
 	}
 
-	botData.Commands["server"] = &Command{
-		Function:            commandSettingsServer,
+	config.Commands["server"] = &Command{
+		Category:            CommandCategorySettings,
+		ResponseFunction:    commandSettingsServerResponse,
 		HelpText:            "Changes the specified settings for the server.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
 		RequiredArguments: []string{
@@ -446,12 +590,38 @@ func initCommands() {
 			{Name: "log", Description: "Manages the logging events", ArgType: "this"},
 			{Name: "tips", Description: "Enables or disables logging events for this channel", ArgType: "enable/disable"},
 			{Name: "autosendnowplaying", Description: "Enables or disables automatically sending now playing embeds without user interaction", ArgType: "enable/disable"},
+			{Name: "suggestions", Description: "Enables or disables suggesting similar commands when an unknown command is used", ArgType: "enable/disable"},
+			{Name: "language", Description: "Sets the language to respond in for members who haven't chosen one", ArgType: "language code"},
 			{Name: "invitegen", Description: "Manages invite link generation via the API", ArgType: "this"},
+			{Name: "cooldown", Description: "Manages command cooldowns and roles exempt from them", ArgType: "this"},
+			{Name: "permissions", Description: "Manages bot admins and who can use each command", ArgType: "this"},
+			{Name: "commands", Description: "Enables, disables or restricts commands and categories to channels", ArgType: "this"},
+			{Name: "export", Description: "Uploads the server's custom responses, rolemes, feeds, starboard, log settings and swear filter as a bundle", ArgType: "this"},
+			{Name: "import", Description: "Applies an attached bundle, matching channels and roles by name unless mapped as old=new", ArgType: "(old=new...)"},
 			{Name: "reset", Description: "Resets the specified setting to the default/empty value", ArgType: "string"},
 		},
 	}
 
-	botData.Commands["roleme"] = &Command{
+	config.Commands["customcmd"] = &Command{
+		Category:            CommandCategorySettings,
+		Function:            commandCustomCommand,
+		HelpText:            "Manages the custom commands for the server.",
+		RequiredPermissions: discordgo.PermissionAdministrator,
+		RequiredArguments: []string{
+			"action (name) (response)",
+		},
+		Arguments: []CommandArgument{
+			{Name: "list", Description: "Lists the custom commands", ArgType: "this"},
+			{Name: "show", Description: "Shows the response template of a custom command", ArgType: "name"},
+			{Name: "add", Description: "Adds a custom command that replies with text", ArgType: "name response"},
+			{Name: "embed", Description: "Adds a custom command that replies with an embed", ArgType: "name response"},
+			{Name: "edit", Description: "Changes the response template of a custom command", ArgType: "name response"},
+			{Name: "remove", Description: "Removes a custom command", ArgType: "name"},
+			{Name: "response", Description: "Supports {user}, {channel}, {server}, {args}, {argN}, {mention(s)}, {choose:a|b} and {if:arg1==x}...{else}...{end}", ArgType: "template"},
+		},
+	}
+	config.Commands["roleme"] = &Command{
+		Category:            CommandCategorySettings,
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandRoleMe,
 		HelpText:            "Allows you to manage the roleme events list. No arguments will list the roleme events.",
@@ -465,7 +635,8 @@ func initCommands() {
 			{Name: "delete", Description: "Deletes the specified roleme entry", ArgType: "number"},
 		},
 	}
-	botData.Commands["bot"] = &Command{
+	config.Commands["bot"] = &Command{
+		Category:            CommandCategorySettings,
 		Function:            commandSettingsBot,
 		HelpText:            "Changes the specified settings for the bot within this server.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
@@ -473,23 +644,29 @@ func initCommands() {
 			"setting (value)",
 		},
 		Arguments: []CommandArgument{
-			{Name: "prefix", Description: "Sets the bot command prefix", ArgType: "string"},
+			{Name: "prefix", Description: "Displays or sets the bot command prefix, adds/removes additional prefixes, toggles case sensitivity or mention-as-prefix", ArgType: "this/prefix/(add prefix)/(remove prefix)/reset/casesensitive/caseinsensitive/mention"},
 		},
 	}
-	botData.Commands["user"] = &Command{
-		Function: commandSettingsUser,
-		HelpText: "Changes the specified settings for the user.",
+	config.Commands["user"] = &Command{
+		Category:         CommandCategorySettings,
+		AllowDM:          true,
+		ResponseFunction: commandSettingsUserResponse,
+		RunsUnlocked:     isUserDataCommand,
+		HelpText:         "Changes the specified settings for the user.",
 		RequiredArguments: []string{
 			"setting (value)",
 		},
 		Arguments: []CommandArgument{
 			{Name: "about/aboutme/description/desc/info", Description: "Sets your aboutme or views the aboutme of another user", ArgType: "string/mention"},
 			{Name: "timezone", Description: "Sets the timezone to use", ArgType: "timezone"},
+			{Name: "language", Description: "Sets the language to respond to you in, overriding the server's language", ArgType: "language code/reset"},
 			{Name: "social", Description: "Manages your socials", ArgType: "this"},
+			{Name: "data", Description: "Sends you everything stored about you, or deletes it once confirmed", ArgType: "export/delete (confirm code)"},
 		},
 	}
 
-	botData.Commands["starboard"] = &Command{
+	config.Commands["starboard"] = &Command{
+		Category:            CommandCategorySettings,
 		Function:            commandStarboard,
 		HelpText:            "Manages the guild's starboard.",
 		RequiredPermissions: discordgo.PermissionAdministrator,
@@ -508,7 +685,8 @@ func initCommands() {
 		},
 	}
 
-	botData.Commands["feed"] = &Command{
+	config.Commands["feed"] = &Command{
+		Category:            CommandCategorySettings,
 		IsAdvancedCommand:   true,
 		AdvancedFunction:    commandFeed,
 		HelpText:            "Manages the guild's various RSS and Atom feeds.",
@@ -526,39 +704,83 @@ func initCommands() {
 		},
 	}
 
+	config.Commands["schedule"] = &Command{
+		Category:            CommandCategorySettings,
+		Function:            commandSchedule,
+		HelpText:            "Schedules commands to run in the current channel on a cron expression or at a fixed time.",
+		RequiredPermissions: discordgo.PermissionAdministrator,
+		RequiredArguments: []string{
+			"add/at/list/remove (value(s))",
+		},
+		Arguments: []CommandArgument{
+			{Name: "add", Description: "Runs a command on a quoted cron expression such as \"0 9 * * MON\" or @daily, in your timezone", ArgType: "cron command"},
+			{Name: "at", Description: "Runs a command once at a quoted time such as \"2006-01-02 15:04\" or 20:00, in your timezone", ArgType: "time command"},
+			{Name: "list", Description: "Lists the scheduled commands on an optionally specified page", ArgType: "this/page"},
+			{Name: "remove", Description: "Removes the specified scheduled command(s)", ArgType: "ID(s)"},
+		},
+		Examples: []string{"add \"0 9 * * MON\" xkcd latest", "add @daily purge 100", "at 20:00 play https://example.com/radio.mp3", "list", "remove 2"},
+	}
+
 	//Alternate commands for pre-established commands
-	botData.Commands["?"] = &Command{IsAlternateOf: "help"}
-	botData.Commands["commands"] = &Command{IsAlternateOf: "help"}
-	botData.Commands["ver"] = &Command{IsAlternateOf: "version"}
-	botData.Commands["v"] = &Command{IsAlternateOf: "version"}
-	botData.Commands["rolldouble"] = &Command{IsAlternateOf: "doubleroll"}
-	botData.Commands["flipcoin"] = &Command{IsAlternateOf: "coinflip"}
-	botData.Commands["img"] = &Command{IsAlternateOf: "image"}
-	botData.Commands["gh"] = &Command{IsAlternateOf: "github"}
-	botData.Commands["yt"] = &Command{IsAlternateOf: "youtube"}
-	botData.Commands["sp"] = &Command{IsAlternateOf: "sp"}
-	botData.Commands["np"] = &Command{IsAlternateOf: "nowplaying"}
-	botData.Commands["q"] = &Command{IsAlternateOf: "queue"}
-	botData.Commands["loop"] = &Command{IsAlternateOf: "repeat"}
-	botData.Commands["next"] = &Command{IsAlternateOf: "skip"}
-	botData.Commands["ud"] = &Command{IsAlternateOf: "urbandictionary"}
-	botData.Commands["owo"] = &Command{IsAlternateOf: "hewwo"}
-	botData.Commands["uwu"] = &Command{IsAlternateOf: "hewwo"}
-	botData.Commands["mc"] = &Command{IsAlternateOf: "minecraft"}
-	botData.Commands["guildinfo"] = &Command{IsAlternateOf: "serverinfo"}
-	botData.Commands["ss"] = &Command{IsAlternateOf: "screenshot"}
-	botData.Commands["credits"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["cash"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["money"] = &Command{IsAlternateOf: "balance"}
-	botData.Commands["nightly"] = &Command{IsAlternateOf: "daily"}
-	botData.Commands["send"] = &Command{IsAlternateOf: "transfer"}
+	config.Commands["?"] = &Command{IsAlternateOf: "help"}
+	config.Commands["commands"] = &Command{IsAlternateOf: "help"}
+	config.Commands["ver"] = &Command{IsAlternateOf: "version"}
+	config.Commands["v"] = &Command{IsAlternateOf: "version"}
+	config.Commands["rolldouble"] = &Command{IsAlternateOf: "doubleroll"}
+	config.Commands["flipcoin"] = &Command{IsAlternateOf: "coinflip"}
+	config.Commands["img"] = &Command{IsAlternateOf: "image"}
+	config.Commands["gh"] = &Command{IsAlternateOf: "github"}
+	config.Commands["yt"] = &Command{IsAlternateOf: "youtube"}
+	config.Commands["sp"] = &Command{IsAlternateOf: "spotify"}
+	config.Commands["np"] = &Command{IsAlternateOf: "nowplaying"}
+	config.Commands["q"] = &Command{IsAlternateOf: "queue"}
+	config.Commands["loop"] = &Command{IsAlternateOf: "repeat"}
+	config.Commands["next"] = &Command{IsAlternateOf: "skip"}
+	config.Commands["ud"] = &Command{IsAlternateOf: "urbandictionary"}
+	config.Commands["owo"] = &Command{IsAlternateOf: "hewwo"}
+	config.Commands["uwu"] = &Command{IsAlternateOf: "hewwo"}
+	config.Commands["mc"] = &Command{IsAlternateOf: "minecraft"}
+	config.Commands["guildinfo"] = &Command{IsAlternateOf: "serverinfo"}
+	config.Commands["ss"] = &Command{IsAlternateOf: "screenshot"}
+	config.Commands["credits"] = &Command{IsAlternateOf: "balance"}
+	config.Commands["cash"] = &Command{IsAlternateOf: "balance"}
+	config.Commands["money"] = &Command{IsAlternateOf: "balance"}
+	config.Commands["nightly"] = &Command{IsAlternateOf: "daily"}
+	config.Commands["send"] = &Command{IsAlternateOf: "transfer"}
 
 	//Administrative commands for bot owners
-	botData.Commands["reload"] = &Command{Function: commandReload, HelpText: "Reloads the bot configuration.", IsAdministrative: true}
-	botData.Commands["restart"] = &Command{Function: commandRestart, HelpText: "Restarts the bot in case something goes awry.", IsAdministrative: true}
-	botData.Commands["update"] = &Command{Function: commandUpdate, HelpText: "Updates the bot to the latest git repo commit.", IsAdministrative: true}
-	botData.Commands["debug"] = &Command{Function: commandDebug, HelpText: "Toggles debug mode.", IsAdministrative: true}
-	botData.Commands["sudo"] = &Command{
+	config.Commands["reload"] = &Command{Function: commandReload, HelpText: "Reloads the bot configuration and lists what changed.", IsAdministrative: true, Category: CommandCategoryAdministrative}
+	config.Commands["restart"] = &Command{Function: commandRestart, HelpText: "Restarts the bot in case something goes awry.", IsAdministrative: true, Category: CommandCategoryAdministrative}
+	config.Commands["update"] = &Command{Function: commandUpdate, HelpText: "Updates the bot to the latest git repo commit.", IsAdministrative: true, Category: CommandCategoryAdministrative}
+	config.Commands["snapshot"] = &Command{
+		Category:         CommandCategoryAdministrative,
+		Function:         commandSnapshot,
+		HelpText:         "Lists, compares and restores snapshots of the state.",
+		IsAdministrative: true,
+		RequiredArguments: []string{
+			"create/list/diff/restore (value(s))",
+		},
+		Arguments: []CommandArgument{
+			{Name: "create", Description: "Takes a snapshot of the state now"},
+			{Name: "list", Description: "Lists the snapshots on an optionally specified page", ArgType: "this/page"},
+			{Name: "diff", Description: "Lists what changed since a snapshot was taken, optionally only for a guild", ArgType: "name (guild ID)"},
+			{Name: "restore", Description: "Restores the settings of a guild from a snapshot, or all of the state and restarts", ArgType: "name all/guild ID"},
+		},
+		Examples: []string{"list", "diff snapshot-20200102-150405", "restore snapshot-20200102-150405 123456789012345678", "restore 20200102-150405 all"},
+	}
+	config.Commands["audit"] = &Command{
+		Category:         CommandCategoryAdministrative,
+		Function:         commandAudit,
+		HelpText:         "Lists the user data export and deletion requests that have been made.",
+		IsAdministrative: true,
+		TypedArguments:   true,
+		Arguments: []CommandArgument{
+			{Name: "page", Description: "The page of requests to list", ArgType: "number", Optional: true, Min: 1},
+		},
+	}
+	config.Commands["debug"] = &Command{Function: commandDebug, HelpText: "Toggles debug mode.", IsAdministrative: true, Category: CommandCategoryAdministrative}
+	config.Commands["sudo"] = &Command{
+		Category:         CommandCategoryAdministrative,
 		Function:         commandSudo,
 		HelpText:         "Runs a command as the specified user.",
 		IsAdministrative: true,
@@ -573,22 +795,46 @@ func initCommands() {
 	}
 }
 
+// callCommand calls a command and returns its response embed, sending the response itself if it's more than a single embed
 func callCommand(commandName string, args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	if command, exists := botData.Commands[commandName]; exists {
+	response := callCommandResponse(commandName, args, env)
+	if response == nil {
+		return nil
+	}
+	if response == InternalResponseActionCompleted {
+		return InternalEmbedActionCompleted
+	}
+	if response.IsEmbedOnly() {
+		return response.Embed()
+	}
+	dataID := getEnvironmentDataID(env)
+	guildData.Get(dataID).Lock()
+	defer guildData.Get(dataID).Unlock()
+
+	sendCommandResponse(getBotData().DiscordSession, env.Message, env.Channel, env.Guild, dataID, response, env.UpdatedMessageEvent)
+	return InternalEmbedActionCompleted
+}
+
+// callCommandResponse calls a command or guild custom command and returns its response
+func callCommandResponse(commandName string, args []string, env *CommandEnvironment) *CommandResponse {
+	if command, exists := getBotData().Commands[commandName]; exists {
+		originalName := commandName
 		if command.IsAlternateOf != "" {
-			if commandAlternate, exists := botData.Commands[command.IsAlternateOf]; exists {
+			if commandAlternate, exists := getBotData().Commands[command.IsAlternateOf]; exists {
+				originalName = command.IsAlternateOf
 				command = commandAlternate
 			} else {
 				return nil
 			}
 		}
-		if command.IsAdministrative && env.User.ID != botData.BotOwnerID {
-			return NewErrorEmbed("Command Error - Not Authorized (NA)", "I'm sorry Dave, I'm afraid I can't do that.")
+		if env.Guild == nil && !command.AllowDM {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Command Error - Guild Only (GO)", "``%s`` can only be used in a server.", commandName))
 		}
-		if command.RequiredPermissions != 0 {
-			if permissionsAllowed, _ := MemberHasPermission(botData.DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
-				return NewErrorEmbed("Command Error - No Permissions (NP)", "Just what do you think you're doing, Dave?")
-			}
+		if disabledEmbed := checkCommandEnabled(originalName, command, env); disabledEmbed != nil {
+			return NewEmbedResponse(disabledEmbed)
+		}
+		if permissionsEmbed := checkCommandPermissions(originalName, command, env); permissionsEmbed != nil {
+			return NewEmbedResponse(permissionsEmbed)
 		}
 		if len(args) >= len(command.RequiredArguments) {
 			if command.IsAdvancedCommand {
@@ -610,66 +856,109 @@ func callCommand(commandName string, args []string, env *CommandEnvironment) *di
 						advancedArgs = append(advancedArgs, CommandArgument{Name: strings.TrimSpace(strings.TrimPrefix(args[i], "-")), Value: ""})
 						continue
 					} else {
-						return getCommandUsage(commandName, "Command Error - Loose Argument Value (LAV)", env)
+						return NewEmbedResponse(getCommandUsage(commandName, "Command Error - Loose Argument Value (LAV)", env))
 					}
 				}
 
-				return command.AdvancedFunction(advancedArgs, env)
+				if cooldownEmbed := checkCooldown(originalName, &command.Cooldown, env); cooldownEmbed != nil {
+					return NewEmbedResponse(cooldownEmbed)
+				}
+				return runCommand(command, args, env, func(env *CommandEnvironment) *CommandResponse {
+					if command.AdvancedResponseFunction != nil {
+						return command.AdvancedResponseFunction(advancedArgs, env)
+					}
+					return NewEmbedResponse(command.AdvancedFunction(advancedArgs, env))
+				})
+			}
+			if command.TypedArguments {
+				parsedArgs, err := parseArguments(command, args, env)
+				if err != nil {
+					return NewEmbedResponse(getCommandUsageError(commandName, "Command Error - Invalid Argument (IA)", err, env))
+				}
+				env.Arguments = parsedArgs
+			}
+			if cooldownEmbed := checkCooldown(originalName, &command.Cooldown, env); cooldownEmbed != nil {
+				return NewEmbedResponse(cooldownEmbed)
 			}
-			return command.Function(args, env)
+			return runCommand(command, args, env, func(env *CommandEnvironment) *CommandResponse {
+				if command.ResponseFunction != nil {
+					return command.ResponseFunction(args, env)
+				}
+				return NewEmbedResponse(command.Function(args, env))
+			})
 		}
-		return getCommandUsage(commandName, "Command Error - Not Enough Parameters (NEP)", env)
+		return NewEmbedResponse(getCommandUsage(commandName, "Command Error - Not Enough Parameters (NEP)", env))
 	}
-	return nil
+	if env.Guild != nil {
+		if customCommand, exists := getGuildCustomCommand(env.Guild.ID, strings.ToLower(commandName)); exists {
+			return callGuildCustomCommand(strings.ToLower(commandName), customCommand, args, env)
+		}
+	}
+	return getCommandSuggestionResponse(commandName, args, env)
+}
+
+// getOriginalCommandName returns the name of the command an alias points to, or the lowercase name if it isn't an alias
+func getOriginalCommandName(commandName string) string {
+	commandName = strings.ToLower(commandName)
+	if command, exists := getBotData().Commands[commandName]; exists && command.IsAlternateOf != "" {
+		return command.IsAlternateOf
+	}
+	return commandName
 }
 
 func getCommandUsage(commandName, title string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	command := botData.Commands[commandName]
+	command := getBotData().Commands[commandName]
 	if command.IsAlternateOf != "" {
-		command = botData.Commands[command.IsAlternateOf]
+		command = getBotData().Commands[command.IsAlternateOf]
 	}
 
 	parameterFields := []*discordgo.MessageEmbedField{}
-	parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: "Usage", Value: env.BotPrefix + commandName + " " + strings.Join(command.RequiredArguments, " ")})
+	parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Usage"), Value: env.BotPrefix + commandName + " " + strings.Join(command.RequiredArguments, " ")})
 	for i := 0; i < len(command.Arguments); i++ {
 		if command.IsAdvancedCommand {
 			name := "-" + command.Arguments[i].Name
 			if command.Arguments[i].ArgType != "" {
 				name += " (" + command.Arguments[i].ArgType + ")"
 			}
-			parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: name, Value: command.Arguments[i].Description, Inline: true})
+			parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: name, Value: env.Locale().T(command.Arguments[i].Description), Inline: true})
 			continue
 		}
-		parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: command.Arguments[i].Name + " (" + command.Arguments[i].ArgType + ")", Value: command.Arguments[i].Description, Inline: true})
+		parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: command.Arguments[i].Name + " (" + command.Arguments[i].ArgType + ")", Value: env.Locale().T(command.Arguments[i].Description), Inline: true})
 	}
 
 	usageEmbed := NewEmbed().
 		SetTitle(title).
-		SetDescription("**" + commandName + "**: " + command.HelpText).
+		SetDescription("**" + commandName + "**: " + env.Locale().T(command.HelpText)).
 		SetColor(0xFF0000).MessageEmbed
 	usageEmbed.Fields = parameterFields
 
 	return usageEmbed
 }
 
+func getCommandUsageError(commandName, title string, err error, env *CommandEnvironment) *discordgo.MessageEmbed {
+	usageEmbed := getCommandUsage(commandName, title, env)
+	usageEmbed.Fields = append([]*discordgo.MessageEmbedField{{Name: "Error", Value: err.Error()}}, usageEmbed.Fields...)
+	return usageEmbed
+}
+
 func getCustomCommandUsage(command *Command, commandName, title string, env *CommandEnvironment) *discordgo.MessageEmbed {
 	parameterFields := []*discordgo.MessageEmbedField{}
-	parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: "Usage", Value: env.BotPrefix + commandName + " " + strings.Join(command.RequiredArguments, " ")})
+	parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Usage"), Value: env.BotPrefix + commandName + " " + strings.Join(command.RequiredArguments, " ")})
 	for i := 0; i < len(command.Arguments); i++ {
 		if command.IsAdvancedCommand {
 			name := "-" + command.Arguments[i].Name
 			if command.Arguments[i].ArgType != "" {
 				name += " (" + command.Arguments[i].ArgType + ")"
 			}
-			parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: name, Value: command.Arguments[i].Description, Inline: true})
+			parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: name, Value: env.Locale().T(command.Arguments[i].Description), Inline: true})
 			continue
 		}
-		parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: command.Arguments[i].Name + " (" + command.Arguments[i].ArgType + ")", Value: command.Arguments[i].Description, Inline: true})
+		parameterFields = append(parameterFields, &discordgo.MessageEmbedField{Name: command.Arguments[i].Name + " (" + command.Arguments[i].ArgType + ")", Value: env.Locale().T(command.Arguments[i].Description), Inline: true})
 	}
 
 	usageEmbed := NewEmbed().
 		SetTitle(title).
-		SetDescription("**" + commandName + "**: " + command.HelpText).
+		SetDescription("**" + commandName + "**: " + env.Locale().T(command.HelpText)).
 		SetColor(0xFF0000).MessageEmbed
 	usageEmbed.Fields = parameterFields
 
@@ -1,9 +1,10 @@
 package main
 
 import (
-	"errors"
+	"net"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/mmcdole/gofeed"
 
@@ -23,7 +24,7 @@ import (
 
 // BotData stores all data for the bot
 type BotData struct {
-	BotClients           BotClients
+	BotClients           BotClients            `json:"-"`
 	BotKeys              BotKeys               `json:"botKeys"`
 	BotName              string                `json:"-"`
 	BotOwnerID           string                `json:"botOwnerID"`
@@ -40,14 +41,15 @@ type BotData struct {
 	DebugMode            bool                  `json:"debugMode"`
 	SendOwnerStackTraces bool                  `json:"sendOwnerStackTraces"`
 
-	DiscordSession *discordgo.Session
-	Commands       map[string]*Command
-	NLPCommands    []*CommandNLP
-	VoiceServices  []VoiceService
-	QueryServices  []QueryService
-	LastTipMessage int
+	DiscordSession *discordgo.Session  `json:"-"`
+	Commands       map[string]*Command `json:"-"`
+	NLPCommands    []*CommandNLP       `json:"-"`
+	VoiceServices  []VoiceService      `json:"-"`
+	QueryServices  []QueryService      `json:"-"`
+	LastTipMessage int                 `json:"-"`
+	Secrets        []string            `json:"-"` //The values of secrets within the configuration, which are redacted from logs
 
-	Updating bool
+	Updating bool `json:"-"`
 }
 
 // BotClients stores available clients for the bot
@@ -67,6 +69,7 @@ type BotClients struct {
 
 // BotKeys stores all bot keys for using external services
 type BotKeys struct {
+	DiscordPublicKey     string                   `json:"discordPublicKey"` //The application's hex public key, used to verify requests to the interactions endpoint
 	DuckDuckGoAppName    string                   `json:"ddgAppName"`
 	GeniusAccessToken    string                   `json:"geniusAccessToken"`
 	ImgurClientID        string                   `json:"imgurClientID"`
@@ -82,6 +85,7 @@ type BotKeys struct {
 type BotOptions struct {
 	MaxPingCount       int                `json:"maxPingCount"` //How many pings to test to determine the average ping
 	HelpMaxResults     int                `json:"helpMaxResults"`
+	MaxGuildWorkers    int                `json:"maxGuildWorkers"` //How many commands may run at once in each guild, default = DefaultGuildWorkers
 	SendTypingEvent    bool               `json:"sendTypingEvent"`
 	UseCustomResponses bool               `json:"useCustomResponses"`
 	UseDuckDuckGo      bool               `json:"useDuckDuckGo"`
@@ -90,6 +94,7 @@ type BotOptions struct {
 	UseImgur           bool               `json:"useImgur"`
 	UseLyrics          bool               `json:"useLyrics"`
 	UseNinty           bool               `json:"useNinty"`
+	UseSlashCommands   bool               `json:"useSlashCommands"` //Whether or not to register commands as Discord application commands
 	UseSoundCloud      bool               `json:"useSoundCloud"`
 	UseSpotify         bool               `json:"useSpotify"`
 	UseWolframAlpha    bool               `json:"useWolframAlpha"`
@@ -101,12 +106,23 @@ type BotOptions struct {
 	AudioEncoding      *dca.EncodeOptions `json:"audioEncoding"`
 	API                APIConfig          `json:"api"`
 	FeedFrequency      int                `json:"feedFrequency"` //Default interval in seconds for checking for new feed entries
+	StateBackend       string             `json:"stateBackend"`  //Where the state is stored, either "bolt" or "json"; default = "bolt"
+	Snapshots          SnapshotConfig     `json:"snapshots"`
+	WatchConfig        bool               `json:"watchConfig"` //Whether or not to reload the configuration when the file changes
 }
 
 // API stores configurations for the API
 type APIConfig struct {
 	Enabled bool   `json:"enabled"`
 	Host    string `json:"host"`
+	Key     string `json:"key"` //The key to send as a bearer token to endpoints that change or export guild data, which are disabled if empty
+}
+
+// SnapshotConfig stores configurations for periodic snapshots of the state
+type SnapshotConfig struct {
+	Enabled   bool `json:"enabled"`
+	Interval  int  `json:"interval"`  //How many minutes to wait between snapshots, default = DefaultSnapshotInterval
+	Retention int  `json:"retention"` //How many snapshots to keep before removing the oldest, default = DefaultSnapshotRetention
 }
 
 // CustomResponseQuery stores a custom response
@@ -143,51 +159,203 @@ type TipMessage struct {
 	Examples    []string `json:"examples"`
 }
 
-// PrepConfig checks the configuration for consistency and invalid errors
-func (configData *BotData) PrepConfig() error {
+// ConfigError describes a problem with a configuration value
+type ConfigError struct {
+	Path    string //The JSON path of the value, such as botOptions.api.host, or empty if it's the whole file
+	Problem string //What's wrong with the value
+	Fix     string //How to fix it, if there's a suggestion
+}
+
+func (err ConfigError) Error() string {
+	message := err.Problem
+	if err.Path != "" {
+		message = "config:{" + err.Path + "} " + message
+	}
+	if err.Fix != "" {
+		message += " (" + err.Fix + ")"
+	}
+	return message
+}
+
+// ConfigErrors holds every problem found with a configuration
+type ConfigErrors []ConfigError
+
+// Add adds a problem with the value at a JSON path
+func (errs *ConfigErrors) Add(path, problem, fix string) {
+	*errs = append(*errs, ConfigError{Path: path, Problem: problem, Fix: fix})
+}
+
+func (errs ConfigErrors) Error() string {
+	messages := make([]string, 0)
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// PrepConfig fills in the defaults of the configuration and checks it for consistency, returning every problem found
+func (configData *BotData) PrepConfig() ConfigErrors {
+	errs := make(ConfigErrors, 0)
+
 	//Bot config checks
 	if configData.BotToken == "" {
-		return errors.New("config:{botName: \"\"}")
+		errs.Add("botToken", "is empty", "set it to the token of your bot user, or set "+configEnvName("botToken"))
 	}
 	if configData.CommandPrefix == "" {
-		return errors.New("config:{cmdPrefix: \"\"}")
+		errs.Add("cmdPrefix", "is empty", "set it to what commands should start with, such as \"cli$\"")
 	}
 
 	//Value checks
 	if configData.BotOptions.MaxPingCount > 5 || configData.BotOptions.MaxPingCount <= 0 {
-		return errors.New("config:{botOptions:{maxPingCount}} must be 1, 2, 3, 4, or 5")
+		errs.Add("botOptions.maxPingCount", "must be between 1 and 5, not "+strconv.Itoa(configData.BotOptions.MaxPingCount), "set it to 4")
 	}
 	if configData.BotOptions.HelpMaxResults > EmbedLimitField || configData.BotOptions.HelpMaxResults <= 0 {
-		return errors.New("config:{botOptions:{helpMaxResults}} must be between 1 to " + strconv.Itoa(EmbedLimitField))
+		errs.Add("botOptions.helpMaxResults", "must be between 1 and "+strconv.Itoa(EmbedLimitField)+", not "+strconv.Itoa(configData.BotOptions.HelpMaxResults), "set it to 8")
 	}
 	if configData.BotOptions.YouTubeMaxResults > EmbedLimitField || configData.BotOptions.YouTubeMaxResults <= 0 {
-		return errors.New("config:{botOptions:{youtubeMaxResults}} must be between 1 to " + strconv.Itoa(EmbedLimitField))
+		errs.Add("botOptions.youtubeMaxResults", "must be between 1 and "+strconv.Itoa(EmbedLimitField)+", not "+strconv.Itoa(configData.BotOptions.YouTubeMaxResults), "set it to 8")
+	}
+	if configData.BotOptions.SpotifyMaxResults > EmbedLimitField || configData.BotOptions.SpotifyMaxResults <= 0 {
+		errs.Add("botOptions.spotifyMaxResults", "must be between 1 and "+strconv.Itoa(EmbedLimitField)+", not "+strconv.Itoa(configData.BotOptions.SpotifyMaxResults), "set it to 8")
+	}
+	if configData.BotOptions.MaxGuildWorkers < 0 {
+		errs.Add("botOptions.maxGuildWorkers", "must not be negative", "set it to 0 to use the default of "+strconv.Itoa(DefaultGuildWorkers))
+	}
+	if configData.BotOptions.FeedFrequency < 0 {
+		errs.Add("botOptions.feedFrequency", "must not be negative", "set it to how many seconds to wait between checking feeds, such as 3600")
+	}
+	switch configData.BotOptions.StateBackend {
+	case "":
+		configData.BotOptions.StateBackend = StateBackendBolt
+	case StateBackendBolt, StateBackendJSON:
+	default:
+		errs.Add("botOptions.stateBackend", "must be \""+StateBackendBolt+"\" or \""+StateBackendJSON+"\", not \""+configData.BotOptions.StateBackend+"\"", "set it to \""+StateBackendBolt+"\"")
 	}
+	if configData.BotOptions.Snapshots.Interval < 0 {
+		errs.Add("botOptions.snapshots.interval", "must not be negative", "set it to 0 to use the default of "+strconv.Itoa(DefaultSnapshotInterval)+" minutes")
+	} else if configData.BotOptions.Snapshots.Interval == 0 {
+		configData.BotOptions.Snapshots.Interval = DefaultSnapshotInterval
+	}
+	if configData.BotOptions.Snapshots.Retention < 0 {
+		errs.Add("botOptions.snapshots.retention", "must not be negative", "set it to 0 to use the default of "+strconv.Itoa(DefaultSnapshotRetention)+" snapshots")
+	} else if configData.BotOptions.Snapshots.Retention == 0 {
+		configData.BotOptions.Snapshots.Retention = DefaultSnapshotRetention
+	}
+	if configData.BotOptions.API.Enabled {
+		if configData.BotOptions.API.Host == "" {
+			errs.Add("botOptions.api.host", "is empty while the API is enabled", "set it to the address to listen on, such as \":8080\", or set botOptions.api.enabled to false")
+		} else if _, port, err := net.SplitHostPort(configData.BotOptions.API.Host); err != nil {
+			errs.Add("botOptions.api.host", "isn't a valid address: "+err.Error(), "set it to a host and port, such as \":8080\" or \"127.0.0.1:8080\"")
+		} else if _, err := net.LookupPort("tcp", port); err != nil {
+			errs.Add("botOptions.api.host", "doesn't have a valid port: "+err.Error(), "set the port to a number between 1 and 65535, such as \":8080\"")
+		}
+	}
+	configData.checkAudioEncoding(&errs)
 
 	//Bot key checks
 	if configData.BotOptions.UseDuckDuckGo && configData.BotKeys.DuckDuckGoAppName == "" {
-		return errors.New("config:{botOptions:{useDuckDuckGo: true}} not permitted, config:{botKeys:{ddgAppName: \"\"}}")
+		errs.Add("botKeys.ddgAppName", "is empty while botOptions.useDuckDuckGo is true", "set it, or set botOptions.useDuckDuckGo to false")
 	}
 	if configData.BotOptions.UseImgur && configData.BotKeys.ImgurClientID == "" {
-		return errors.New("config:{botOptions:{useImgur: true}} not permitted, config:{botKeys:{imgurClientID: \"\"}}")
+		errs.Add("botKeys.imgurClientID", "is empty while botOptions.useImgur is true", "set it, or set botOptions.useImgur to false")
 	}
 	if configData.BotOptions.UseSoundCloud && configData.BotKeys.SoundCloudClientID == "" {
-		return errors.New("config:{botOptions:{useSoundCloud: true}} not permitted, config:{botKeys:{soundcloudClientID: \"\"}}")
+		errs.Add("botKeys.soundcloudClientID", "is empty while botOptions.useSoundCloud is true", "set it, or set botOptions.useSoundCloud to false")
+	}
+	if configData.BotOptions.UseSpotify && configData.BotKeys.SpotifyHost == "" {
+		errs.Add("botKeys.spotifyHost", "is empty while botOptions.useSpotify is true", "set it, or set botOptions.useSpotify to false")
 	}
 	if configData.BotOptions.UseWolframAlpha && configData.BotKeys.WolframAppID == "" {
-		return errors.New("config:{botOptions:{useWolframAlpha: true}} not permitted, config:{botKeys:{wolframAppID: \"\"}}")
+		errs.Add("botKeys.wolframAppID", "is empty while botOptions.useWolframAlpha is true", "set it, or set botOptions.useWolframAlpha to false")
 	}
 	if configData.BotOptions.UseYouTube && configData.BotKeys.YouTubeAPIKey == "" {
-		return errors.New("config:{botOptions:{useYouTube: true}} not permitted, config:{botKeys:{youtubeAPIKey: \"\"}}")
+		errs.Add("botKeys.youtubeAPIKey", "is empty while botOptions.useYouTube is true", "set it, or set botOptions.useYouTube to false")
 	}
 
 	//Custom response checks
 	for i, customResponse := range configData.CustomResponses {
 		regexp, err := regexp.Compile(customResponse.Expression)
 		if err != nil {
-			return err
+			errs.Add("customResponses["+strconv.Itoa(i)+"].expression", "isn't a valid regular expression: "+err.Error(), "see https://golang.org/s/re2syntax for the syntax")
+			continue
 		}
 		configData.CustomResponses[i].Regexp = regexp
 	}
-	return nil
+
+	//Custom status checks, as one is picked at random every minute
+	if len(configData.CustomStatuses) == 0 {
+		errs.Add("customStatuses", "is empty", "add at least one status, such as {\"type\": 0, \"status\": \"cli$help\"}")
+	}
+	for i, customStatus := range configData.CustomStatuses {
+		path := "customStatuses[" + strconv.Itoa(i) + "]"
+		if customStatus.Type < 0 || customStatus.Type > 2 {
+			errs.Add(path+".type", "must be 0, 1 or 2, not "+strconv.Itoa(customStatus.Type), "set it to 0 for \"Playing\", 1 for \"Listening to\" or 2 for \"Streaming\"")
+		}
+		if customStatus.Status == "" {
+			errs.Add(path+".status", "is empty", "set it to the text of the status")
+		}
+		if customStatus.Type == 2 && customStatus.URL == "" {
+			errs.Add(path+".url", "is empty while the status is streaming", "set it to the URL of the stream, or change the type")
+		}
+	}
+
+	//Tip message checks, as one is picked at random every hour
+	if len(configData.TipMessages) == 0 {
+		errs.Add("tipMessages", "is empty", "add at least one tip message, such as those in config.example.json")
+	}
+	for i, tipMessage := range configData.TipMessages {
+		path := "tipMessages[" + strconv.Itoa(i) + "]"
+		if tipMessage.DidYouKnow == "" {
+			errs.Add(path+".didYouKnow", "is empty", "set it to what the tip is about")
+		}
+		if tipMessage.HowTo == "" {
+			errs.Add(path+".howTo", "is empty", "set it to how to use the feature")
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkAudioEncoding checks the audio encoding options against the ranges the encoder accepts, using the encoder's
+// defaults if there are none
+func (configData *BotData) checkAudioEncoding(errs *ConfigErrors) {
+	if configData.BotOptions.AudioEncoding == nil {
+		encoding := *dca.StdEncodeOptions
+		configData.BotOptions.AudioEncoding = &encoding
+		return
+	}
+
+	encoding := configData.BotOptions.AudioEncoding
+	if encoding.Volume < 0 || encoding.Volume > 512 {
+		errs.Add("botOptions.audioEncoding.Volume", "must be between 0 and 512, not "+strconv.Itoa(encoding.Volume), "set it to 256 to leave the volume as it is")
+	}
+	if encoding.Channels != 1 && encoding.Channels != 2 {
+		errs.Add("botOptions.audioEncoding.Channels", "must be 1 or 2, not "+strconv.Itoa(encoding.Channels), "set it to 2")
+	}
+	if encoding.FrameRate != 48000 {
+		errs.Add("botOptions.audioEncoding.FrameRate", "must be 48000 for Discord, not "+strconv.Itoa(encoding.FrameRate), "set it to 48000")
+	}
+	if encoding.FrameDuration != 20 && encoding.FrameDuration != 40 && encoding.FrameDuration != 60 {
+		errs.Add("botOptions.audioEncoding.FrameDuration", "must be 20, 40 or 60, not "+strconv.Itoa(encoding.FrameDuration), "set it to 20")
+	}
+	if encoding.Bitrate < 8 || encoding.Bitrate > 128 {
+		errs.Add("botOptions.audioEncoding.Bitrate", "must be between 8 and 128, not "+strconv.Itoa(encoding.Bitrate), "set it to 128")
+	}
+	if encoding.PacketLoss < 0 || encoding.PacketLoss > 100 {
+		errs.Add("botOptions.audioEncoding.PacketLoss", "must be between 0 and 100, not "+strconv.Itoa(encoding.PacketLoss), "set it to 0")
+	}
+	if encoding.CompressionLevel < 0 || encoding.CompressionLevel > 10 {
+		errs.Add("botOptions.audioEncoding.CompressionLevel", "must be between 0 and 10, not "+strconv.Itoa(encoding.CompressionLevel), "set it to 10")
+	}
+	if encoding.BufferedFrames < 0 {
+		errs.Add("botOptions.audioEncoding.BufferedFrames", "must not be negative", "set it to 100")
+	}
+	switch encoding.Application {
+	case dca.AudioApplicationAudio, dca.AudioApplicationVoip, dca.AudioApplicationLowDelay:
+	default:
+		errs.Add("botOptions.audioEncoding.Application", "must be \"audio\", \"voip\" or \"lowdelay\", not \""+string(encoding.Application)+"\"", "set it to \"audio\"")
+	}
 }
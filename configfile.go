@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+/*
+	The configuration file may be written in JSON, YAML or TOML, picked by its extension. YAML and TOML files are
+	converted to JSON before they're decoded, so every format uses the same field names as config.example.json.
+*/
+
+const (
+	ConfigFormatJSON = "json"
+	ConfigFormatYAML = "yaml"
+	ConfigFormatTOML = "toml"
+)
+
+// configFormat returns the format of a configuration file from its extension, defaulting to JSON
+func configFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return ConfigFormatYAML
+	case ".toml":
+		return ConfigFormatTOML
+	}
+	return ConfigFormatJSON
+}
+
+// parseConfig decodes and checks a configuration file into a new BotData, leaving the live configuration untouched
+//
+// Every problem found with the configuration is returned as ConfigErrors.
+func parseConfig(file string) (*BotData, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BotData{}
+	errs := decodeConfig(configFormat(file), data, config)
+	if len(errs) > 0 && errs[0].Path == "" {
+		return nil, errs //The file couldn't be decoded at all, rather than only some of its values
+	}
+
+	errs = append(errs, applyConfigOverrides(config)...)
+	errs = append(errs, config.PrepConfig()...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return config, nil
+}
+
+// decodeConfig decodes a configuration in the given format into config
+func decodeConfig(format string, data []byte, config *BotData) ConfigErrors {
+	errs := make(ConfigErrors, 0)
+
+	switch format {
+	case ConfigFormatYAML:
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			errs.Add("", "isn't valid YAML: "+err.Error(), "")
+			return errs
+		}
+		jsonData, err := json.Marshal(yamlToJSON(document))
+		if err != nil {
+			errs.Add("", "couldn't be converted from YAML: "+err.Error(), "")
+			return errs
+		}
+		data = jsonData
+	case ConfigFormatTOML:
+		document := make(map[string]interface{})
+		if _, err := toml.Decode(string(data), &document); err != nil {
+			errs.Add("", "isn't valid TOML: "+err.Error(), "")
+			return errs
+		}
+		jsonData, err := json.Marshal(document)
+		if err != nil {
+			errs.Add("", "couldn't be converted from TOML: "+err.Error(), "")
+			return errs
+		}
+		data = jsonData
+	}
+
+	var document json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		switch err := err.(type) {
+		case *json.SyntaxError:
+			line, column := jsonPosition(data, err.Offset)
+			errs.Add("", "isn't valid JSON at line "+strconv.Itoa(line)+", column "+strconv.Itoa(column)+": "+err.Error(), "check for a missing comma or quote, or an extra trailing comma")
+		default:
+			errs.Add("", err.Error(), "")
+		}
+		return errs
+	}
+
+	decodeConfigValue("", document, reflect.ValueOf(config).Elem(), &errs)
+	return errs
+}
+
+// decodeConfigValue decodes JSON into a configuration value, adding a problem for every value within it that has the
+// wrong type rather than stopping at the first one
+//
+// When the JSON doesn't fit, objects and lists are decoded again one field, entry or element at a time to find
+// exactly which values are at fault.
+func decodeConfigValue(path string, data json.RawMessage, value reflect.Value, errs *ConfigErrors) {
+	err := json.Unmarshal(data, value.Addr().Interface())
+	if err == nil {
+		return
+	}
+	typeErr, isTypeErr := err.(*json.UnmarshalTypeError)
+	if !isTypeErr {
+		errs.Add(path, err.Error(), "")
+		return
+	}
+
+	if _, isUnmarshaler := value.Addr().Interface().(json.Unmarshaler); !isUnmarshaler {
+		switch {
+		case value.Kind() == reflect.Struct && jsonKind(data) == '{':
+			decodeConfigStruct(path, data, value, errs)
+			return
+		case value.Kind() == reflect.Slice && jsonKind(data) == '[':
+			decodeConfigSlice(path, data, value, errs)
+			return
+		case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String && jsonKind(data) == '{':
+			decodeConfigMap(path, data, value, errs)
+			return
+		case value.Kind() == reflect.Ptr && jsonKind(data) != 'n':
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			decodeConfigValue(path, data, value.Elem(), errs)
+			return
+		}
+	}
+
+	if strings.HasPrefix(typeErr.Value, "number ") {
+		//A number that doesn't fit its field, such as a decimal where a whole number is expected
+		errs.Add(path, "must be a whole number within range, not "+strings.TrimPrefix(typeErr.Value, "number "), "change it to a whole number")
+		return
+	}
+	typeName := withArticle(jsonTypeName(typeErr.Type.String()))
+	errs.Add(path, "must be "+typeName+", not "+withArticle(jsonValueName(typeErr.Value)), "change it to "+typeName)
+}
+
+func decodeConfigStruct(path string, data json.RawMessage, value reflect.Value, errs *ConfigErrors) {
+	object := make(map[string]json.RawMessage)
+	json.Unmarshal(data, &object)
+
+	//Object keys are matched to fields the same way encoding/json does, preferring an exact match
+	for _, key := range sortedJSONKeys(object) {
+		element := object[key]
+		field, exists := configStructField(value.Type(), key)
+		if !exists {
+			continue //Unknown fields are ignored, as encoding/json ignores them
+		}
+		decodeConfigValue(joinConfigPath(path, key), element, value.FieldByIndex(field.Index), errs)
+	}
+}
+
+func decodeConfigSlice(path string, data json.RawMessage, value reflect.Value, errs *ConfigErrors) {
+	elements := make([]json.RawMessage, 0)
+	json.Unmarshal(data, &elements)
+
+	value.Set(reflect.MakeSlice(value.Type(), len(elements), len(elements)))
+	for i, element := range elements {
+		decodeConfigValue(path+"["+strconv.Itoa(i)+"]", element, value.Index(i), errs)
+	}
+}
+
+func decodeConfigMap(path string, data json.RawMessage, value reflect.Value, errs *ConfigErrors) {
+	object := make(map[string]json.RawMessage)
+	json.Unmarshal(data, &object)
+
+	value.Set(reflect.MakeMap(value.Type()))
+	for _, key := range sortedJSONKeys(object) {
+		element := object[key]
+		entry := reflect.New(value.Type().Elem()).Elem()
+		decodeConfigValue(joinConfigPath(path, key), element, entry, errs)
+		value.SetMapIndex(reflect.ValueOf(key).Convert(value.Type().Key()), entry)
+	}
+}
+
+// configStructField returns the field of a configuration struct that an object key decodes into
+func configStructField(structType reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || field.Anonymous {
+			continue
+		}
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		if name == key {
+			return field, true
+		}
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &field
+		}
+	}
+	if folded != nil {
+		return *folded, true
+	}
+	return reflect.StructField{}, false
+}
+
+// sortedJSONKeys returns the keys of a JSON object in order, so problems are listed the same way every time
+func sortedJSONKeys(object map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonKind returns the first character of a JSON value, which tells what kind of value it is
+func jsonKind(data json.RawMessage) byte {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return 0
+	}
+	return data[0]
+}
+
+// yamlToJSON converts the maps of a decoded YAML document to ones that can be encoded as JSON
+func yamlToJSON(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{})
+		for key, element := range value {
+			object[fmt.Sprint(key)] = yamlToJSON(element)
+		}
+		return object
+	case []interface{}:
+		for i := range value {
+			value[i] = yamlToJSON(value[i])
+		}
+	}
+	return value
+}
+
+// jsonPosition returns the line and column of an offset within JSON data
+func jsonPosition(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndex(before, []byte("\n"))
+	return line, column
+}
+
+// jsonTypeName returns how a Go type is written in JSON
+func jsonTypeName(goType string) string {
+	switch {
+	case goType == "string":
+		return "string"
+	case goType == "bool":
+		return "boolean"
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return "number"
+	case strings.HasPrefix(goType, "[]"):
+		return "list"
+	}
+	return "object"
+}
+
+// jsonValueName returns how the kind of a JSON value is written in problems, matching jsonTypeName
+func jsonValueName(value string) string {
+	switch value {
+	case "bool":
+		return "boolean"
+	case "array":
+		return "list"
+	}
+	return value
+}
+
+// withArticle puts "a" or "an" in front of a JSON type name
+func withArticle(name string) string {
+	if name == "object" {
+		return "an " + name
+	}
+	return "a " + name
+}
+
+// runValidateConfig checks a configuration file and prints every problem found with it, returning the exit code
+func runValidateConfig(file string) int {
+	if _, err := parseConfig(file); err != nil {
+		errs, isConfigErrs := err.(ConfigErrors)
+		if !isConfigErrs {
+			fmt.Fprintf(os.Stderr, "Unable to read %s: %v\n", file, err)
+			return 1
+		}
+
+		fmt.Fprintf(os.Stderr, "%s has %d problem(s):\n", file, len(errs))
+		for _, configErr := range errs {
+			path := configErr.Path
+			if path == "" {
+				path = "(file)"
+			}
+			fmt.Fprintf(os.Stderr, "\n  %s\n    %s\n", path, configErr.Problem)
+			if configErr.Fix != "" {
+				fmt.Fprintf(os.Stderr, "    Fix: %s\n", configErr.Fix)
+			}
+		}
+		return 1
+	}
+
+	fmt.Printf("%s is valid.\n", file)
+	return 0
+}
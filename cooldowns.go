@@ -0,0 +1,145 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	// QueryCooldown contains the default cooldown for natural language queries, which can be overridden per guild with the "query" command name
+	QueryCooldown = CommandCooldown{User: 5 * time.Second}
+
+	cooldowns = &CooldownTracker{expires: make(map[string]time.Time)}
+)
+
+// CommandCooldown holds how long a command must wait between uses in each scope (0 = no cooldown in that scope)
+type CommandCooldown struct {
+	User    time.Duration `json:"user,omitempty"`    //How long a user must wait before using the command again
+	Channel time.Duration `json:"channel,omitempty"` //How long a channel must wait before the command can be used in it again
+	Guild   time.Duration `json:"guild,omitempty"`   //How long a guild must wait before the command can be used in it again
+}
+
+// IsZero returns whether or not the cooldown has no scopes set
+func (cooldown *CommandCooldown) IsZero() bool {
+	return cooldown == nil || (cooldown.User <= 0 && cooldown.Channel <= 0 && cooldown.Guild <= 0)
+}
+
+// CooldownTracker keeps track of when each command cooldown scope expires
+type CooldownTracker struct {
+	sync.Mutex
+
+	expires   map[string]time.Time
+	nextPrune time.Time
+}
+
+// Use returns how long is left before the command can be used again, or records the use and returns 0 if it can be used now
+func (tracker *CooldownTracker) Use(commandName string, cooldown *CommandCooldown, guildID, channelID, userID string) time.Duration {
+	if cooldown.IsZero() {
+		return 0
+	}
+
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	now := time.Now()
+	if now.After(tracker.nextPrune) {
+		for key, expires := range tracker.expires {
+			if now.After(expires) {
+				delete(tracker.expires, key)
+			}
+		}
+		tracker.nextPrune = now.Add(time.Minute)
+	}
+
+	scopes := []struct {
+		key      string
+		duration time.Duration
+	}{
+		{"user:" + guildID + ":" + userID + ":" + commandName, cooldown.User},
+		{"channel:" + channelID + ":" + commandName, cooldown.Channel},
+		{"guild:" + guildID + ":" + commandName, cooldown.Guild},
+	}
+
+	remaining := time.Duration(0)
+	for _, scope := range scopes {
+		if scope.duration <= 0 {
+			continue
+		}
+		if expires, exists := tracker.expires[scope.key]; exists {
+			if left := expires.Sub(now); left > remaining {
+				remaining = left
+			}
+		}
+	}
+	if remaining > 0 {
+		return remaining
+	}
+
+	for _, scope := range scopes {
+		if scope.duration > 0 {
+			tracker.expires[scope.key] = now.Add(scope.duration)
+		}
+	}
+	return 0
+}
+
+// getCommandCooldown returns the guild's cooldown override for a command if one exists, otherwise the default cooldown
+func getCommandCooldown(guildID, commandName string, defaultCooldown *CommandCooldown) *CommandCooldown {
+	cooldown := defaultCooldown
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		if override, exists := settings.CommandCooldowns[commandName]; exists {
+			overrideCopy := *override
+			cooldown = &overrideCopy
+		}
+	})
+	return cooldown
+}
+
+// isCooldownExempt returns whether or not the user in the environment bypasses cooldowns
+func isCooldownExempt(env *CommandEnvironment) bool {
+	if env.User.ID == getBotData().BotOwnerID {
+		return true
+	}
+	if env.Member == nil || env.Guild == nil {
+		return false
+	}
+	exempt := false
+	guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+		for _, roleID := range env.Member.Roles {
+			if containsString(settings.CooldownExemptRoles, roleID) {
+				exempt = true
+				return
+			}
+		}
+	})
+	return exempt
+}
+
+// checkCooldown records a use of the command and returns a cooldown embed if it can't be used yet, otherwise nil
+func checkCooldown(commandName string, defaultCooldown *CommandCooldown, env *CommandEnvironment) *discordgo.MessageEmbed {
+	if isCooldownExempt(env) {
+		return nil
+	}
+
+	guildID := ""
+	if env.Guild != nil {
+		guildID = env.Guild.ID
+	}
+
+	cooldown := getCommandCooldown(guildID, commandName, defaultCooldown)
+	if remaining := cooldowns.Use(commandName, cooldown, guildID, env.Channel.ID, env.User.ID); remaining > 0 {
+		return NewErrorEmbed(env.Locale(), "Command Error - Cooldown (CD)", "Slow down! You can use ``"+commandName+"`` again in **"+formatCooldown(remaining)+"**.")
+	}
+	return nil
+}
+
+// formatCooldown rounds a remaining cooldown up to the nearest second for display
+func formatCooldown(remaining time.Duration) string {
+	rounded := roundTime(remaining, time.Second)
+	if rounded < remaining {
+		rounded += time.Second
+	}
+	return rounded.String()
+}
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"fmt"
-
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -22,6 +20,9 @@ const (
 	EmbedLimit            = 4000
 )
 
+// EmbedColorError contains the color of error embeds
+const EmbedColorError = 0xb40000
+
 //NewEmbed returns a new embed object
 func NewEmbed() *Embed {
 	return &Embed{&discordgo.MessageEmbed{}}
@@ -274,11 +275,14 @@ func (e *Embed) TruncateFooter() *Embed {
 	return e
 }
 
-// NewGenericEmbed creates a new generic embed
-func NewGenericEmbed(embedTitle, embedMsg string, replacements ...interface{}) *discordgo.MessageEmbed {
+// NewGenericEmbed creates a new generic embed in a locale
+//
+// The title and message are written in English and looked up in the locale's translations before the replacements
+// are formatted into the message, so the message format is also its key in the translation files. See Locale.T.
+func NewGenericEmbed(locale *Locale, embedTitle, embedMsg string, replacements ...interface{}) *discordgo.MessageEmbed {
 	genericEmbed := NewEmbed().
-		SetTitle(embedTitle).
-		SetDescription(fmt.Sprintf(embedMsg, replacements...)).
+		SetTitle(locale.T(embedTitle)).
+		SetDescription(locale.T(embedMsg, replacements...)).
 		SetColor(0x1c1c1c).MessageEmbed
 	return genericEmbed
 }
@@ -292,15 +296,23 @@ func NewGenericEmbedAdvanced(embedTitle, embedMsg string, embedColor int) *disco
 	return genericEmbed
 }
 
-// NewErrorEmbed creates a new error embed
-func NewErrorEmbed(errorTitle, errorMsg string, replacements ...interface{}) *discordgo.MessageEmbed {
+// NewErrorEmbed creates a new error embed in a locale
+//
+// The title and message are written in English and looked up in the locale's translations before the replacements
+// are formatted into the message, so the message format is also its key in the translation files. See Locale.T.
+func NewErrorEmbed(locale *Locale, errorTitle, errorMsg string, replacements ...interface{}) *discordgo.MessageEmbed {
 	errorEmbed := NewEmbed().
-		SetTitle(errorTitle).
-		SetDescription(fmt.Sprintf(errorMsg, replacements...)).
-		SetColor(0xb40000).MessageEmbed
+		SetTitle(locale.T(errorTitle)).
+		SetDescription(locale.T(errorMsg, replacements...)).
+		SetColor(EmbedColorError).MessageEmbed
 	return errorEmbed
 }
 
+// isErrorEmbed returns whether or not an embed was created by NewErrorEmbed, whose title may have been translated
+func isErrorEmbed(embed *discordgo.MessageEmbed) bool {
+	return embed != nil && embed.Color == EmbedColorError
+}
+
 // NewErrorEmbedAdvanced creates a new error embed with a custom color
 func NewErrorEmbedAdvanced(errorTitle, errorMsg string, errorColor int) *discordgo.MessageEmbed {
 	errorEmbed := NewEmbed().
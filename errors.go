@@ -27,6 +27,9 @@ var (
 	errVoicePlayingAlready       = errors.New("voice: already playing")
 	errVoiceSkippedManually      = errors.New("voice: skipped audio manually")
 	errVoiceStoppedManually      = errors.New("voice: stopped audio manually")
+
+	errScheduleTooOften = errors.New("schedule: runs more than once a minute")
+	errScheduleTime     = errors.New("schedule: invalid time")
 )
 
 func getErrorMessage(err error) (errHash, errMsg string) {
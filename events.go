@@ -39,20 +39,33 @@ func discordMessageDelete(session *discordgo.Session, event *discordgo.MessageDe
 	message := event //Make it easier to keep track of what's happening
 
 	guildChannel, err := session.Channel(message.ChannelID)
+	if err == nil && guildChannel.GuildID == "" {
+		//Queries in direct messages are tracked under the channel ID
+		if _, dataFound := guildData.Load(guildChannel.ID); dataFound {
+			guildData.Get(guildChannel.ID).Lock()
+			defer guildData.Get(guildChannel.ID).Unlock()
+
+			if query, messageFound := guildData.Get(guildChannel.ID).Queries[message.ID]; messageFound && query != nil {
+				deleteQueryResponses(session, query, message.ChannelID)
+				guildData.Get(guildChannel.ID).Queries[message.ID] = nil
+			}
+		}
+		return
+	}
 	if err == nil {
 		guildID := guildChannel.GuildID
 		guild, err := session.Guild(guildID)
 		if err == nil {
-			_, guildFound := guildData[guildID]
+			_, guildFound := guildData.Load(guildID)
 			if guildFound {
-				guildData[guildID].Lock()
-				defer guildData[guildID].Unlock()
+				guildData.Get(guildID).Lock()
+				defer guildData.Get(guildID).Unlock()
 
-				_, messageFound := guildData[guildID].Queries[message.ID]
-				if messageFound {
+				query, messageFound := guildData.Get(guildID).Queries[message.ID]
+				if messageFound && query != nil {
 					debugLog("[Deleted]["+guild.Name+" - #"+guildChannel.Name+"]: (Guild: "+guildID+", Channel: "+message.ChannelID+", Message: "+message.ID+")", false)
-					session.ChannelMessageDelete(message.ChannelID, guildData[guildID].Queries[message.ID].ResponseMessageID) //Delete the query response message
-					guildData[guildID].Queries[message.ID] = nil                                                              //Remove the message from the query list
+					deleteQueryResponses(session, query, message.ChannelID) //Delete the query response messages
+					guildData.Get(guildID).Queries[message.ID] = nil        //Remove the message from the query list
 				}
 			}
 		}
@@ -69,17 +82,17 @@ func discordMessageDeleteBulk(session *discordgo.Session, event *discordgo.Messa
 		guildID := guildChannel.GuildID
 		guild, err := session.Guild(guildID)
 		if err == nil {
-			_, guildFound := guildData[guildID]
+			_, guildFound := guildData.Load(guildID)
 			if guildFound {
-				guildData[guildID].Lock()
-				defer guildData[guildID].Unlock()
+				guildData.Get(guildID).Lock()
+				defer guildData.Get(guildID).Unlock()
 
-				for i := 0; i > len(messages); i++ {
-					_, messageFound := guildData[guildID].Queries[messages[i]]
-					if messageFound {
+				for i := 0; i < len(messages); i++ {
+					query, messageFound := guildData.Get(guildID).Queries[messages[i]]
+					if messageFound && query != nil {
 						debugLog("[Deleted]["+guild.Name+" - #"+guildChannel.Name+"]: (Guild: "+guildID+", Channel: "+channelID+", Message: "+messages[i]+")", false)
-						session.ChannelMessageDelete(channelID, guildData[guildID].Queries[messages[i]].ResponseMessageID) //Delete the query response message
-						guildData[guildID].Queries[messages[i]] = nil                                                      //Remove the message from the query list
+						deleteQueryResponses(session, query, channelID)   //Delete the query response messages
+						guildData.Get(guildID).Queries[messages[i]] = nil //Remove the message from the query list
 					}
 				}
 			}
@@ -88,7 +101,7 @@ func discordMessageDeleteBulk(session *discordgo.Session, event *discordgo.Messa
 }
 
 func discordChannelCreate(session *discordgo.Session, channel *discordgo.ChannelCreate) {
-	settings, guildFound := guildSettings[channel.GuildID]
+	settings, guildFound := guildSettings.Snapshot(channel.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelCreate {
 			switch channel.Type {
@@ -144,7 +157,7 @@ func discordChannelCreate(session *discordgo.Session, channel *discordgo.Channel
 	}
 }
 func discordChannelUpdate(session *discordgo.Session, channel *discordgo.ChannelUpdate) {
-	settings, guildFound := guildSettings[channel.GuildID]
+	settings, guildFound := guildSettings.Snapshot(channel.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelUpdate {
 			switch channel.Type {
@@ -204,7 +217,7 @@ func discordChannelUpdate(session *discordgo.Session, channel *discordgo.Channel
 	}
 }
 func discordChannelDelete(session *discordgo.Session, channel *discordgo.ChannelDelete) {
-	settings, guildFound := guildSettings[channel.GuildID]
+	settings, guildFound := guildSettings.Snapshot(channel.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.ChannelDelete {
 			switch channel.Type {
@@ -256,7 +269,7 @@ func discordChannelDelete(session *discordgo.Session, channel *discordgo.Channel
 	}
 }
 func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate) {
-	settings, guildFound := guildSettings[guild.ID]
+	settings, guildFound := guildSettings.Snapshot(guild.ID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildUpdate {
 			verificationLevel := "None"
@@ -270,7 +283,7 @@ func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate
 			}
 			afkChannel := "None"
 			if guild.AfkChannelID != "" {
-				channel, err := botData.DiscordSession.Channel(guild.AfkChannelID)
+				channel, err := getBotData().DiscordSession.Channel(guild.AfkChannelID)
 				if err == nil && channel.Type == discordgo.ChannelTypeGuildVoice {
 					afkChannel = ":speaker: " + channel.Name
 				}
@@ -294,7 +307,7 @@ func discordGuildUpdate(session *discordgo.Session, guild *discordgo.GuildUpdate
 	}
 }
 func discordGuildBanAdd(session *discordgo.Session, guild *discordgo.GuildBanAdd) {
-	settings, guildFound := guildSettings[guild.GuildID]
+	settings, guildFound := guildSettings.Snapshot(guild.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildBanAdd {
 			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
@@ -310,7 +323,7 @@ func discordGuildBanAdd(session *discordgo.Session, guild *discordgo.GuildBanAdd
 	}
 }
 func discordGuildBanRemove(session *discordgo.Session, guild *discordgo.GuildBanRemove) {
-	settings, guildFound := guildSettings[guild.GuildID]
+	settings, guildFound := guildSettings.Snapshot(guild.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildBanRemove {
 			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
@@ -326,19 +339,19 @@ func discordGuildBanRemove(session *discordgo.Session, guild *discordgo.GuildBan
 	}
 }
 func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMemberAdd) {
-	_, guildFound := guildSettings[member.GuildID]
+	settings, guildFound := guildSettings.Snapshot(member.GuildID)
 	if guildFound {
-		if guildSettings[member.GuildID].UserJoinMessage != "" && guildSettings[member.GuildID].UserJoinMessageChannel != "" {
-			message := guildSettings[member.GuildID].UserJoinMessage
+		if settings.UserJoinMessage != "" && settings.UserJoinMessageChannel != "" {
+			message := settings.UserJoinMessage
 			message = strings.Replace(message, "{user}", member.User.Username, -1)
 			message = strings.Replace(message, "{user-mention}", "<@"+member.User.ID+">", -1)
 			message = strings.Replace(message, "{user-id}", member.User.ID, -1)
 			message = strings.Replace(message, "{user-discriminator}", member.User.Discriminator, -1)
 
-			session.ChannelMessageSend(guildSettings[member.GuildID].UserJoinMessageChannel, message)
+			session.ChannelMessageSend(settings.UserJoinMessageChannel, message)
 		}
 
-		if guildSettings[member.GuildID].LogSettings.LoggingEnabled && guildSettings[member.GuildID].LogSettings.LoggingEvents.GuildMemberAdd {
+		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildMemberAdd {
 			joinedAt := member.JoinedAt
 			joinedAtTimeFormatted := ""
 			joinedAtTime, err := joinedAt.Parse()
@@ -354,7 +367,7 @@ func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMe
 				joinedAtTimeFormatted = joinedAtMonth + " " + strconv.Itoa(joinedAtDay) + ", " + strconv.Itoa(joinedAtYear) + " at " + strconv.Itoa(joinedAtHour) + ":" + strconv.Itoa(joinedAtMinute) + ":" + strconv.Itoa(joinedAtSecond)
 			}
 
-			session.ChannelMessageSendEmbed(guildSettings[member.GuildID].LogSettings.LoggingChannel, NewEmbed().
+			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
 				SetTitle("Logging Event - User Joined").
 				SetDescription("A new member joined the server.").
 				AddField("Joined At", joinedAtTimeFormatted).
@@ -371,18 +384,18 @@ func discordGuildMemberAdd(session *discordgo.Session, member *discordgo.GuildMe
 	}
 }
 func discordGuildMemberRemove(session *discordgo.Session, member *discordgo.GuildMemberRemove) {
-	_, guildFound := guildSettings[member.GuildID]
+	settings, guildFound := guildSettings.Snapshot(member.GuildID)
 	if guildFound {
-		if guildSettings[member.GuildID].UserLeaveMessage != "" && guildSettings[member.GuildID].UserLeaveMessageChannel != "" {
-			message := guildSettings[member.GuildID].UserLeaveMessage
+		if settings.UserLeaveMessage != "" && settings.UserLeaveMessageChannel != "" {
+			message := settings.UserLeaveMessage
 			message = strings.Replace(message, "{user}", member.User.Username, -1)
 			message = strings.Replace(message, "{user-id}", member.User.ID, -1)
 			message = strings.Replace(message, "{user-discriminator}", member.User.Discriminator, -1)
 
-			session.ChannelMessageSend(guildSettings[member.GuildID].UserLeaveMessageChannel, message)
+			session.ChannelMessageSend(settings.UserLeaveMessageChannel, message)
 		}
 
-		if guildSettings[member.GuildID].LogSettings.LoggingEnabled && guildSettings[member.GuildID].LogSettings.LoggingEvents.GuildMemberRemove {
+		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.GuildMemberRemove {
 			joinedAt := member.JoinedAt
 			joinedAtTimeFormatted := ""
 			joinedAtTime, err := joinedAt.Parse()
@@ -398,7 +411,7 @@ func discordGuildMemberRemove(session *discordgo.Session, member *discordgo.Guil
 				joinedAtTimeFormatted = joinedAtMonth + " " + strconv.Itoa(joinedAtDay) + ", " + strconv.Itoa(joinedAtYear) + " at " + strconv.Itoa(joinedAtHour) + ":" + strconv.Itoa(joinedAtMinute) + ":" + strconv.Itoa(joinedAtSecond)
 			}
 
-			session.ChannelMessageSendEmbed(guildSettings[member.GuildID].LogSettings.LoggingChannel, NewEmbed().
+			session.ChannelMessageSendEmbed(settings.LogSettings.LoggingChannel, NewEmbed().
 				SetTitle("Logging Event - User Left").
 				SetDescription("A member left the server.").
 				AddField("Joined At", joinedAtTimeFormatted).
@@ -430,7 +443,7 @@ func discordUserUpdate(session *discordgo.Session, user *discordgo.UserUpdate) {
 
 }
 func discordVoiceStateUpdate(session *discordgo.Session, voiceState *discordgo.VoiceStateUpdate) {
-	settings, guildFound := guildSettings[voiceState.GuildID]
+	settings, guildFound := guildSettings.Snapshot(voiceState.GuildID)
 	if guildFound {
 		if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.VoiceStateUpdate {
 			if voiceState.ChannelID == "" {
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"sync"
 
 	"github.com/JoshuaDoes/go-wolfram"
 )
 
 // GuildData holds data specific to a guild
+//
+// The embedded mutex guards Queries, which are only touched while sending or deleting responses.
+// Search results and conversations have their own locks so commands can run at the same time without holding up responses.
 type GuildData struct {
 	sync.Mutex //This struct gets accessed very repeatedly throughout various goroutines so we need a mutex to prevent race conditions
 
@@ -14,4 +18,89 @@ type GuildData struct {
 	YouTubeResults       map[string]*YouTubeResultNav     `json:"youtubeResults,omitempty"`
 	SpotifyResults       map[string]*SpotifyResultNav     `json:"spotifyResults,omitempty"`
 	WolframConversations map[string]*wolfram.Conversation `json:"wolframConversations,omitempty"`
+
+	resultsLock       sync.Mutex //Guards YouTubeResults and SpotifyResults
+	conversationsLock sync.Mutex //Guards WolframConversations
+}
+
+// GetYouTubeResult returns a user's YouTube search session, or nil if there is none
+func (data *GuildData) GetYouTubeResult(userID string) *YouTubeResultNav {
+	data.resultsLock.Lock()
+	defer data.resultsLock.Unlock()
+	return data.YouTubeResults[userID]
+}
+
+// SetYouTubeResult replaces a user's YouTube search session, removing it if page is nil
+func (data *GuildData) SetYouTubeResult(userID string, page *YouTubeResultNav) {
+	data.resultsLock.Lock()
+	defer data.resultsLock.Unlock()
+	if page == nil {
+		delete(data.YouTubeResults, userID)
+		return
+	}
+	if data.YouTubeResults == nil {
+		data.YouTubeResults = make(map[string]*YouTubeResultNav)
+	}
+	data.YouTubeResults[userID] = page
+}
+
+// GetSpotifyResult returns a user's Spotify session, or nil if there is none
+func (data *GuildData) GetSpotifyResult(userID string) *SpotifyResultNav {
+	data.resultsLock.Lock()
+	defer data.resultsLock.Unlock()
+	return data.SpotifyResults[userID]
+}
+
+// SetSpotifyResult replaces a user's Spotify session, removing it if page is nil
+func (data *GuildData) SetSpotifyResult(userID string, page *SpotifyResultNav) {
+	data.resultsLock.Lock()
+	defer data.resultsLock.Unlock()
+	if page == nil {
+		delete(data.SpotifyResults, userID)
+		return
+	}
+	if data.SpotifyResults == nil {
+		data.SpotifyResults = make(map[string]*SpotifyResultNav)
+	}
+	data.SpotifyResults[userID] = page
+}
+
+// GetWolframConversation returns a user's last Wolfram|Alpha conversation, or nil if there is none
+func (data *GuildData) GetWolframConversation(userID string) *wolfram.Conversation {
+	data.conversationsLock.Lock()
+	defer data.conversationsLock.Unlock()
+	return data.WolframConversations[userID]
+}
+
+// SetWolframConversation stores a user's last Wolfram|Alpha conversation
+func (data *GuildData) SetWolframConversation(userID string, conversation *wolfram.Conversation) {
+	data.conversationsLock.Lock()
+	defer data.conversationsLock.Unlock()
+	if data.WolframConversations == nil {
+		data.WolframConversations = make(map[string]*wolfram.Conversation)
+	}
+	data.WolframConversations[userID] = conversation
+}
+
+// MarshalJSON holds the results and conversations locks while encoding, as commands may change them while the state is saved
+//
+// The caller must hold the embedded mutex to keep Queries from changing.
+func (data *GuildData) MarshalJSON() ([]byte, error) {
+	type guildDataJSON struct {
+		Queries              map[string]*Query                `json:"queries,omitempty"`
+		YouTubeResults       map[string]*YouTubeResultNav     `json:"youtubeResults,omitempty"`
+		SpotifyResults       map[string]*SpotifyResultNav     `json:"spotifyResults,omitempty"`
+		WolframConversations map[string]*wolfram.Conversation `json:"wolframConversations,omitempty"`
+	}
+
+	data.resultsLock.Lock()
+	defer data.resultsLock.Unlock()
+	data.conversationsLock.Lock()
+	defer data.conversationsLock.Unlock()
+	return json.Marshal(&guildDataJSON{
+		Queries:              data.Queries,
+		YouTubeResults:       data.YouTubeResults,
+		SpotifyResults:       data.SpotifyResults,
+		WolframConversations: data.WolframConversations,
+	})
 }
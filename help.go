@@ -0,0 +1,196 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandCategoryNames contains the display name of each command category
+var CommandCategoryNames = map[string]string{
+	CommandCategoryInfo:           "Info",
+	CommandCategoryFun:            "Fun",
+	CommandCategoryUtility:        "Utility",
+	CommandCategoryEconomy:        "Economy",
+	CommandCategoryVoice:          "Voice",
+	CommandCategoryModeration:     "Moderation",
+	CommandCategorySettings:       "Settings",
+	CommandCategoryAdministrative: "Admin",
+}
+
+// PermissionNames contains the display name of each permission a command may require, in the order they should be listed
+var PermissionNames = []struct {
+	Permission int
+	Name       string
+}{
+	{discordgo.PermissionAdministrator, "Administrator"},
+	{discordgo.PermissionManageServer, "Manage Server"},
+	{discordgo.PermissionManageRoles, "Manage Roles"},
+	{discordgo.PermissionManageChannels, "Manage Channels"},
+	{discordgo.PermissionManageMessages, "Manage Messages"},
+	{discordgo.PermissionBanMembers, "Ban Members"},
+	{discordgo.PermissionKickMembers, "Kick Members"},
+	{discordgo.PermissionVoiceConnect, "Connect"},
+	{discordgo.PermissionVoiceSpeak, "Speak"},
+}
+
+// getCommandCategory returns the category matching the specified name or display name, or an empty string if there is none
+func getCommandCategory(name string) string {
+	name = strings.ToLower(name)
+	for _, category := range CommandCategories {
+		if name == category || name == strings.ToLower(CommandCategoryNames[category]) {
+			return category
+		}
+	}
+	return ""
+}
+
+// getCommandAliases returns every alias of a command in alphabetical order
+func getCommandAliases(commandName string) []string {
+	aliases := make([]string, 0)
+	for aliasName, alias := range getBotData().Commands {
+		if alias.IsAlternateOf == commandName {
+			aliases = append(aliases, aliasName)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// getPermissionNames returns the display names of the permissions in a permission bitmask
+func getPermissionNames(permissions int) []string {
+	names := make([]string, 0)
+	for _, permission := range PermissionNames {
+		if permissions&permission.Permission == permission.Permission {
+			names = append(names, permission.Name)
+		}
+	}
+	return names
+}
+
+// isCommandListed returns whether or not a command should be listed in help for the user in the environment
+func isCommandListed(commandName string, command *Command, env *CommandEnvironment) bool {
+	if command.IsAlternateOf != "" {
+		return false
+	}
+	if env.Guild == nil && !command.AllowDM {
+		return false
+	}
+	if env.Guild != nil && isCommandDisabled(env.Guild.ID, commandName, command) {
+		return false
+	}
+	return checkCommandPermissions(commandName, command, env) == nil
+}
+
+// getHelpFields returns a field for each command listed for the user that matches the filter, in alphabetical order
+func getHelpFields(env *CommandEnvironment, filter func(commandName string, command *Command) bool) []*discordgo.MessageEmbedField {
+	var commandNames []string
+	for commandName, command := range getBotData().Commands {
+		if isCommandListed(commandName, command, env) && (filter == nil || filter(commandName, command)) {
+			commandNames = append(commandNames, commandName)
+		}
+	}
+	sort.Strings(commandNames)
+
+	commandFields := []*discordgo.MessageEmbedField{}
+	for _, commandName := range commandNames {
+		commandFields = append(commandFields, &discordgo.MessageEmbedField{Name: env.BotPrefix + commandName, Value: env.Locale().T(getBotData().Commands[commandName].HelpText), Inline: true})
+	}
+	return commandFields
+}
+
+// matchesHelpKeyword returns whether or not a keyword appears in a command's name, aliases, help text, arguments or examples
+func matchesHelpKeyword(commandName string, command *Command, keyword string) bool {
+	helpText := []string{commandName, command.HelpText}
+	helpText = append(helpText, getCommandAliases(commandName)...)
+	helpText = append(helpText, command.Examples...)
+	for _, argument := range command.Arguments {
+		helpText = append(helpText, argument.Name, argument.Description)
+	}
+	return strings.Contains(strings.ToLower(strings.Join(helpText, "\n")), keyword)
+}
+
+// getHelpOverview returns an embed listing the commands the user may use in each category
+func getHelpOverview(env *CommandEnvironment) *discordgo.MessageEmbed {
+	helpEmbed := NewEmbed().
+		SetTitle(env.Locale().T("%s - Help", getBotData().BotName)).
+		SetDescription(env.Locale().T("A list of commands you have permission to use, by category.")).
+		SetFooter(env.BotPrefix + env.Command + " {category/command} | " + env.BotPrefix + env.Command + " search {keyword}").
+		SetColor(0xFAFAFA)
+
+	for _, category := range CommandCategories {
+		commandNames := make([]string, 0)
+		for _, field := range getHelpFields(env, func(commandName string, command *Command) bool { return command.Category == category }) {
+			commandNames = append(commandNames, "``"+strings.TrimPrefix(field.Name, env.BotPrefix)+"``")
+		}
+		if len(commandNames) == 0 {
+			continue
+		}
+		helpEmbed.AddField(CommandCategoryNames[category]+" ("+category+")", strings.Join(commandNames, " "))
+	}
+	return helpEmbed.MessageEmbed
+}
+
+// getHelpPage returns a page of command fields, or an error embed if the page doesn't exist
+//
+// The title, description and footer are expected to already be translated.
+func getHelpPage(env *CommandEnvironment, commandFields []*discordgo.MessageEmbedField, pageNumber int, title, description, footer string) *discordgo.MessageEmbed {
+	if len(commandFields) == 0 {
+		return NewErrorEmbed(env.Locale(), "Help Error", "No commands were found.")
+	}
+
+	helpEmbed, totalPages, err := page(commandFields, pageNumber, getBotData().BotOptions.HelpMaxResults)
+	if err != nil {
+		return NewErrorEmbed(env.Locale(), "Help Error", err.Error())
+	}
+
+	locale := env.Locale()
+	return helpEmbed.
+		SetTitle(title).
+		SetDescription(description).
+		SetFooter(locale.T("Page %s of %s | %s", locale.FormatNumber(pageNumber), locale.FormatNumber(totalPages), footer)).
+		SetColor(0xFAFAFA).MessageEmbed
+}
+
+// getCommandHelp returns the detailed help for a command, including its aliases, required permissions and examples
+func getCommandHelp(commandName string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	command := getBotData().Commands[commandName]
+	originalName := commandName
+	if command.IsAlternateOf != "" {
+		originalName = command.IsAlternateOf
+		command = getBotData().Commands[originalName]
+	}
+
+	helpEmbed := getCommandUsage(originalName, env.Locale().T("Help for **%s**", commandName), env)
+	helpEmbed.Color = 0xFAFAFA
+
+	detailFields := []*discordgo.MessageEmbedField{}
+	if category, exists := CommandCategoryNames[command.Category]; exists {
+		detailFields = append(detailFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Category"), Value: category, Inline: true})
+	}
+	aliases := getCommandAliases(originalName)
+	if originalName != commandName {
+		aliases = append([]string{originalName}, remove(aliases, commandName)...)
+	}
+	if len(aliases) > 0 {
+		detailFields = append(detailFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Aliases"), Value: "``" + strings.Join(aliases, "``, ``") + "``", Inline: true})
+	}
+	permissions := getPermissionNames(command.RequiredPermissions)
+	if command.IsAdministrative {
+		permissions = append([]string{"Bot Owner"}, permissions...)
+	}
+	if len(permissions) > 0 {
+		detailFields = append(detailFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Required Permissions"), Value: strings.Join(permissions, ", "), Inline: true})
+	}
+	if len(command.Examples) > 0 {
+		examples := make([]string, 0)
+		for _, example := range command.Examples {
+			examples = append(examples, "``"+strings.TrimSpace(env.BotPrefix+commandName+" "+example)+"``")
+		}
+		detailFields = append(detailFields, &discordgo.MessageEmbedField{Name: env.Locale().T("Examples"), Value: strings.Join(examples, "\n")})
+	}
+
+	helpEmbed.Fields = append(detailFields, helpEmbed.Fields...)
+	return helpEmbed
+}
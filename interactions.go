@@ -0,0 +1,576 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/go-chi/render"
+)
+
+// Interaction types
+const (
+	InteractionTypePing               = 1
+	InteractionTypeApplicationCommand = 2
+)
+
+// Interaction response types
+const (
+	InteractionResponsePong                             = 1
+	InteractionResponseChannelMessageWithSource         = 4
+	InteractionResponseDeferredChannelMessageWithSource = 5
+)
+
+// InteractionResponseFlagEphemeral makes an interaction response only visible to the user who ran the command
+const InteractionResponseFlagEphemeral = 64
+
+// Application command option types
+const (
+	ApplicationCommandOptionString  = 3
+	ApplicationCommandOptionInteger = 4
+	ApplicationCommandOptionBoolean = 5
+	ApplicationCommandOptionUser    = 6
+	ApplicationCommandOptionChannel = 7
+	ApplicationCommandOptionRole    = 8
+	ApplicationCommandOptionNumber  = 10
+)
+
+var (
+	// interactionsAPI contains the base URL used for application command and interaction requests, which can be pointed at a local fake Discord for testing
+	interactionsAPI = "https://discord.com/api/v8/"
+
+	// regexpApplicationCommandName matches characters that aren't allowed in application command and option names
+	regexpApplicationCommandName = regexp.MustCompile(`[^a-z0-9_-]+`)
+)
+
+// ApplicationCommand holds a command registered with Discord
+type ApplicationCommand struct {
+	ID            string                      `json:"id,omitempty"`
+	ApplicationID string                      `json:"application_id,omitempty"`
+	Name          string                      `json:"name"`
+	Description   string                      `json:"description"`
+	Options       []*ApplicationCommandOption `json:"options,omitempty"`
+}
+
+// ApplicationCommandOption holds an option of a command registered with Discord
+type ApplicationCommandOption struct {
+	Type        int                               `json:"type"`
+	Name        string                            `json:"name"`
+	Description string                            `json:"description"`
+	Required    bool                              `json:"required,omitempty"`
+	Choices     []*ApplicationCommandOptionChoice `json:"choices,omitempty"`
+
+	argument *CommandArgument //The argument this option was generated from, or nil for the raw arguments option
+}
+
+// ApplicationCommandOptionChoice holds a predefined value of an option
+type ApplicationCommandOptionChoice struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Interaction holds an interaction sent by Discord, either over the gateway or to the interactions endpoint
+type Interaction struct {
+	ID            string            `json:"id"`
+	ApplicationID string            `json:"application_id"`
+	Type          int               `json:"type"`
+	Data          *InteractionData  `json:"data,omitempty"`
+	GuildID       string            `json:"guild_id,omitempty"`
+	ChannelID     string            `json:"channel_id,omitempty"`
+	Member        *discordgo.Member `json:"member,omitempty"`
+	User          *discordgo.User   `json:"user,omitempty"`
+	Token         string            `json:"token"`
+	Version       int               `json:"version"`
+}
+
+// InteractionData holds the command and options of an application command interaction
+type InteractionData struct {
+	ID       string                   `json:"id"`
+	Name     string                   `json:"name"`
+	Options  []*InteractionDataOption `json:"options,omitempty"`
+	Resolved *InteractionResolved     `json:"resolved,omitempty"`
+}
+
+// InteractionDataOption holds the value of an option supplied with an application command
+type InteractionDataOption struct {
+	Name  string      `json:"name"`
+	Type  int         `json:"type"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// InteractionResolved holds the users mentioned in an application command's options, where key = user ID
+type InteractionResolved struct {
+	Users map[string]*discordgo.User `json:"users,omitempty"`
+}
+
+// InteractionResponse holds a response to an interaction
+type InteractionResponse struct {
+	Type int                      `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+// InteractionResponseData holds the message of a response to an interaction
+type InteractionResponseData struct {
+	Content         string                    `json:"content,omitempty"`
+	Embeds          []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+	Flags           int                       `json:"flags,omitempty"`
+	AllowedMentions *MessageAllowedMentions   `json:"allowed_mentions,omitempty"`
+}
+
+// getApplicationCommands returns the application commands to register for every command that isn't an alias or administrative
+//
+// Commands with TypedArguments get one option per argument. Every other command gets a single "arguments" option
+// that is split and passed along the same way as the text after a prefixed command.
+func getApplicationCommands() []*ApplicationCommand {
+	applicationCommands := make([]*ApplicationCommand, 0)
+	for commandName, command := range getBotData().Commands {
+		if command.IsAlternateOf != "" || command.IsAdministrative {
+			continue
+		}
+		applicationCommands = append(applicationCommands, &ApplicationCommand{
+			Name:        commandName,
+			Description: truncateApplicationText(command.HelpText, commandName),
+			Options:     getApplicationCommandOptions(command),
+		})
+	}
+	return applicationCommands
+}
+
+// getApplicationCommandOptions returns the application command options generated from a command's arguments
+func getApplicationCommandOptions(command *Command) []*ApplicationCommandOption {
+	if !command.TypedArguments {
+		if len(command.Arguments) == 0 && len(command.RequiredArguments) == 0 {
+			return nil
+		}
+		return []*ApplicationCommandOption{
+			{
+				Type:        ApplicationCommandOptionString,
+				Name:        "arguments",
+				Description: truncateApplicationText(strings.Join(command.RequiredArguments, " "), "The arguments to pass to the command"),
+				Required:    len(command.RequiredArguments) > 0,
+			},
+		}
+	}
+
+	options := make([]*ApplicationCommandOption, 0)
+	for i := range command.Arguments {
+		argument := &command.Arguments[i]
+		option := &ApplicationCommandOption{
+			Type:        ApplicationCommandOptionString,
+			Name:        getApplicationOptionName(argument.Name),
+			Description: truncateApplicationText(argument.Description, argument.Name),
+			Required:    !argument.Optional,
+			argument:    argument,
+		}
+		if !argument.Multiple {
+			switch argumentKind(argument.ArgType) {
+			case ArgTypeNumber:
+				option.Type = ApplicationCommandOptionInteger
+			case ArgTypeDecimal:
+				option.Type = ApplicationCommandOptionNumber
+			case ArgTypeBoolean:
+				option.Type = ApplicationCommandOptionBoolean
+			case ArgTypeUser:
+				option.Type = ApplicationCommandOptionUser
+			case ArgTypeRole:
+				option.Type = ApplicationCommandOptionRole
+			case ArgTypeChannel:
+				option.Type = ApplicationCommandOptionChannel
+			}
+		}
+		if len(argument.Choices) > 0 && len(argument.Choices) <= 25 {
+			for _, choice := range argument.Choices {
+				option.Choices = append(option.Choices, &ApplicationCommandOptionChoice{Name: choice, Value: choice})
+			}
+		}
+		options = append(options, option)
+	}
+
+	//Discord requires every required option to come before any optional ones
+	sortedOptions := make([]*ApplicationCommandOption, 0)
+	for _, option := range options {
+		if option.Required {
+			sortedOptions = append(sortedOptions, option)
+		}
+	}
+	for _, option := range options {
+		if !option.Required {
+			sortedOptions = append(sortedOptions, option)
+		}
+	}
+	return sortedOptions
+}
+
+// getApplicationOptionName converts an argument name into a valid application command option name
+func getApplicationOptionName(name string) string {
+	name = strings.ToLower(strings.Replace(name, " ", "-", -1))
+	name = regexpApplicationCommandName.ReplaceAllString(name, "")
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
+
+// truncateApplicationText returns text that fits in an application command description, or the fallback if the text is empty
+func truncateApplicationText(text, fallback string) string {
+	if text == "" {
+		text = fallback
+	}
+	if runes := []rune(text); len(runes) > 100 {
+		text = string(runes[:97]) + "..."
+	}
+	return text
+}
+
+// registerApplicationCommands replaces the bot's global application commands with the current command list
+func registerApplicationCommands(session *discordgo.Session) error {
+	_, err := session.RequestWithBucketID("PUT", interactionsAPI+"applications/"+session.State.User.ID+"/commands", getApplicationCommands(), "applications/commands")
+	return err
+}
+
+// discordInteractionCreate handles interactions sent over the gateway, which discordgo only delivers as raw events
+func discordInteractionCreate(session *discordgo.Session, event *discordgo.Event) {
+	defer recoverPanic()
+
+	if event.Type != "INTERACTION_CREATE" {
+		return
+	}
+
+	interaction := &Interaction{}
+	if err := json.Unmarshal(event.RawData, interaction); err != nil {
+		Error.Printf("Error parsing interaction: %v", err)
+		return
+	}
+
+	response := handleInteraction(interaction)
+	if response == nil {
+		return
+	}
+	if _, err := session.RequestWithBucketID("POST", interactionsAPI+"interactions/"+interaction.ID+"/"+interaction.Token+"/callback", response, "interactions/callback"); err != nil {
+		Error.Printf("Error responding to interaction: %v", err)
+	}
+}
+
+// interactionsEndpoint handles interactions sent to the HTTP interactions endpoint, verifying they were signed by Discord
+func interactionsEndpoint(w http.ResponseWriter, r *http.Request) {
+	publicKey, err := getInteractionsPublicKey()
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, errAPI("interactions endpoint is not configured"))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, errAPI("error reading body", err))
+		return
+	}
+	if !verifyInteraction(publicKey, r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		render.JSON(w, r, errAPI("invalid request signature"))
+		return
+	}
+
+	interaction := &Interaction{}
+	if err := json.Unmarshal(body, interaction); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, errAPI("error parsing interaction", err))
+		return
+	}
+
+	response := handleInteraction(interaction)
+	if response == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, errAPI("unsupported interaction type"))
+		return
+	}
+	render.JSON(w, r, response)
+}
+
+// getInteractionsPublicKey returns the application's public key used to verify requests to the interactions endpoint
+func getInteractionsPublicKey() (ed25519.PublicKey, error) {
+	if getBotData().BotKeys.DiscordPublicKey == "" {
+		return nil, errors.New("no public key configured")
+	}
+	publicKey, err := hex.DecodeString(getBotData().BotKeys.DiscordPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, errors.New("public key must be " + strconv.Itoa(ed25519.PublicKeySize) + " bytes")
+	}
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// verifyInteraction returns whether or not the hex signature of the timestamp and body was made by the public key
+func verifyInteraction(publicKey ed25519.PublicKey, signature, timestamp string, body []byte) bool {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil || len(signatureBytes) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+
+	var message bytes.Buffer
+	message.WriteString(timestamp)
+	message.Write(body)
+	return ed25519.Verify(publicKey, message.Bytes(), signatureBytes)
+}
+
+// handleInteraction returns the initial response to an interaction, running application commands in the background
+//
+// Commands may take longer than Discord allows for an initial response, so the response is always deferred and
+// the original response is edited once the command finishes.
+func handleInteraction(interaction *Interaction) *InteractionResponse {
+	switch interaction.Type {
+	case InteractionTypePing:
+		return &InteractionResponse{Type: InteractionResponsePong}
+	case InteractionTypeApplicationCommand:
+		if interaction.Data == nil {
+			return nil
+		}
+		go runInteractionCommand(interaction)
+		return &InteractionResponse{Type: InteractionResponseDeferredChannelMessageWithSource}
+	}
+	return nil
+}
+
+// runInteractionCommand runs the command of an application command interaction and edits the deferred response with the result
+func runInteractionCommand(interaction *Interaction) {
+	defer recoverPanic()
+
+	args := getInteractionArguments(interaction)
+	env, err := getInteractionEnvironment(interaction, args)
+	if err != nil {
+		editInteractionResponse(interaction, "@original", NewEmbedResponse(NewErrorEmbed(getGuildLocale(interaction.GuildID), "Interaction Error", err.Error())))
+		return
+	}
+
+	response := callCommandResponse(env.Command, args, env)
+
+	dataID := getEnvironmentDataID(env)
+	guildData.Get(dataID).Lock()
+	sendInteractionResponse(interaction, env, "@original", response)
+	stateSaveGuild(dataID, env.User.ID)
+	guildData.Get(dataID).Unlock()
+}
+
+// getInteractionEnvironment builds a command environment from an application command interaction
+func getInteractionEnvironment(interaction *Interaction, args []string) (*CommandEnvironment, error) {
+	session := getBotData().DiscordSession
+
+	user := interaction.User
+	if interaction.Member != nil {
+		user = interaction.Member.User
+	}
+	if user == nil {
+		return nil, errors.New("Unable to find the user who ran this command.")
+	}
+
+	channel, err := session.State.Channel(interaction.ChannelID)
+	if err != nil {
+		if channel, err = session.Channel(interaction.ChannelID); err != nil {
+			return nil, errors.New("Unable to find the channel this command was ran in.")
+		}
+	}
+
+	var guild *discordgo.Guild
+	dataID := channel.ID //Direct messages keep their data under the channel ID
+	if interaction.GuildID != "" {
+		if guild, err = session.State.Guild(interaction.GuildID); err != nil {
+			return nil, errors.New("Unable to find the server this command was ran in.")
+		}
+		dataID = guild.ID
+		initializeGuildSettings(guild.ID)
+		initializeStarboard(guild.ID)
+	}
+	initializeGuildData(dataID)
+	initializeUserSettings(user.ID)
+
+	commandName := interaction.Data.Name
+
+	//Commands expect to be ran from a message, so give them one that looks like the prefixed equivalent
+	message := &discordgo.Message{
+		ID:        interaction.ID,
+		ChannelID: channel.ID,
+		GuildID:   interaction.GuildID,
+		Author:    user,
+		Content:   "/" + strings.TrimSpace(commandName+" "+strings.Join(args, " ")),
+	}
+	if interaction.Data.Resolved != nil {
+		for _, mention := range interaction.Data.Resolved.Users {
+			message.Mentions = append(message.Mentions, mention)
+		}
+	}
+	if interaction.Member != nil {
+		interaction.Member.GuildID = interaction.GuildID
+	}
+
+	return &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: user, Member: interaction.Member, Command: commandName, CommandLine: getInteractionCommandLine(interaction, args), BotPrefix: "/"}, nil
+}
+
+// getInteractionCommandLine returns the command of an interaction and its arguments as they would be written after a prefix
+func getInteractionCommandLine(interaction *Interaction, args []string) string {
+	for _, option := range interaction.Data.Options {
+		if value, ok := option.Value.(string); ok && option.Name == "arguments" {
+			return strings.TrimSpace(interaction.Data.Name + " " + value)
+		}
+	}
+	return strings.TrimSpace(interaction.Data.Name + " " + strings.Join(args, " "))
+}
+
+// sendInteractionResponse replaces a deferred or previous interaction response with a command response
+//
+// Webhook messages can't carry the files a command attaches, be sent elsewhere or be split, so those responses are sent to the channel like a prefixed command's.
+func sendInteractionResponse(interaction *Interaction, env *CommandEnvironment, messageID string, response *CommandResponse) {
+	session := getBotData().DiscordSession
+	webhookURL := interactionsAPI + "webhooks/" + interaction.ApplicationID + "/" + interaction.Token
+
+	if response.IsEmpty() {
+		//The command already responded on its own, so the deferred response is no longer needed
+		session.RequestWithBucketID("DELETE", webhookURL+"/messages/"+messageID, nil, "webhooks/messages")
+		return
+	}
+	if len(response.Files) > 0 || response.DirectMessage || utf8.RuneCountInString(response.Content) > MessageMaxLength {
+		session.RequestWithBucketID("DELETE", webhookURL+"/messages/"+messageID, nil, "webhooks/messages")
+		sendCommandResponse(session, env.Message, env.Channel, env.Guild, getEnvironmentDataID(env), response, false)
+		return
+	}
+
+	if response.Ephemeral && messageID == "@original" {
+		//Only new messages can be ephemeral, so the deferred response is swapped out for one
+		session.RequestWithBucketID("DELETE", webhookURL+"/messages/@original", nil, "webhooks/messages")
+		messageID = ""
+	}
+	responseMessage := editInteractionResponse(interaction, messageID, response)
+	if responseMessage == nil {
+		return
+	}
+
+	if !response.Ephemeral {
+		addResponseReactions(session, responseMessage.ChannelID, responseMessage.ID, response.Reactions)
+	}
+	if response.FollowUp != nil {
+		go func() {
+			defer recoverPanic()
+
+			dataID := getEnvironmentDataID(env)
+			followUp := runFollowUp(dataID, response.FollowUp)
+			if followUp.IsEmpty() {
+				return
+			}
+
+			guildData.Get(dataID).Lock()
+			defer guildData.Get(dataID).Unlock()
+
+			sendInteractionResponse(interaction, env, responseMessage.ID, followUp)
+		}()
+	}
+}
+
+// editInteractionResponse edits an interaction response message with the content and embeds of a command response, or sends a follow-up message if messageID is empty
+func editInteractionResponse(interaction *Interaction, messageID string, response *CommandResponse) *discordgo.Message {
+	responseData := &InteractionResponseData{Content: response.Content, AllowedMentions: response.AllowedMentions}
+	for _, embed := range response.Embeds {
+		fixedEmbed := Embed{embed}
+		fixedEmbed.Truncate()
+		responseData.Embeds = append(responseData.Embeds, fixedEmbed.MessageEmbed)
+	}
+
+	method, webhookURL := "PATCH", interactionsAPI+"webhooks/"+interaction.ApplicationID+"/"+interaction.Token+"/messages/"+messageID
+	if messageID == "" {
+		method, webhookURL = "POST", interactionsAPI+"webhooks/"+interaction.ApplicationID+"/"+interaction.Token
+		if response.Ephemeral {
+			responseData.Flags = InteractionResponseFlagEphemeral
+		}
+	}
+
+	body, err := getBotData().DiscordSession.RequestWithBucketID(method, webhookURL, responseData, "webhooks/messages")
+	if err != nil {
+		Error.Printf("Error editing interaction response: %v", err)
+		return nil
+	}
+	responseMessage := &discordgo.Message{}
+	if err := json.Unmarshal(body, responseMessage); err != nil {
+		Error.Printf("Error parsing interaction response message: %v", err)
+		return nil
+	}
+	return responseMessage
+}
+
+// getInteractionArguments converts the options of an application command interaction back into command arguments
+func getInteractionArguments(interaction *Interaction) []string {
+	command, exists := getBotData().Commands[interaction.Data.Name]
+	if !exists {
+		return make([]string, 0)
+	}
+
+	values := make(map[string]*InteractionDataOption)
+	for _, option := range interaction.Data.Options {
+		values[option.Name] = option
+	}
+
+	if !command.TypedArguments {
+		if option, exists := values["arguments"]; exists {
+			if value, ok := option.Value.(string); ok && strings.TrimSpace(value) != "" {
+				return splitCommand(value)
+			}
+		}
+		return make([]string, 0)
+	}
+
+	//Typed arguments are positional, so they have to be passed in the order they were declared rather than the order Discord lists them
+	args := make([]string, 0)
+	for i := range command.Arguments {
+		args = append(args, getInteractionArgument(command, &command.Arguments[i], values)...)
+	}
+	return args
+}
+
+// getInteractionArgument returns the command argument values of the option generated from an argument, if it was supplied
+func getInteractionArgument(command *Command, argument *CommandArgument, values map[string]*InteractionDataOption) []string {
+	for _, option := range getApplicationCommandOptions(command) {
+		if option.argument != argument {
+			continue
+		}
+		optionValue, exists := values[option.Name]
+		if !exists {
+			return nil
+		}
+		value := interactionOptionString(optionValue)
+		switch option.Type {
+		case ApplicationCommandOptionUser:
+			value = "<@" + value + ">"
+		case ApplicationCommandOptionRole:
+			value = "<@&" + value + ">"
+		case ApplicationCommandOptionChannel:
+			value = "<#" + value + ">"
+		}
+		if argument.Multiple {
+			return strings.Fields(value)
+		}
+		return []string{value}
+	}
+	return nil
+}
+
+// interactionOptionString returns the value of an interaction option as it would be typed in a message
+func interactionOptionString(option *InteractionDataOption) string {
+	switch value := option.Value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+//These tests run the interactions endpoint against a local fake of Discord's interactions API
+
+// fakeInteractionsAPI records the webhook requests an interaction's response is sent with
+type fakeInteractionsAPI struct {
+	server   *httptest.Server
+	requests chan fakeInteractionsRequest
+}
+
+type fakeInteractionsRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// newFakeInteractionsAPI starts a fake interactions API and points interactionsAPI at it until the test finishes
+func newFakeInteractionsAPI(t *testing.T) *fakeInteractionsAPI {
+	fake := &fakeInteractionsAPI{requests: make(chan fakeInteractionsRequest, 10)}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		fake.requests <- fakeInteractionsRequest{Method: r.Method, Path: r.URL.Path, Body: body}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"response","channel_id":"channel"}`))
+	}))
+
+	oldAPI := interactionsAPI
+	interactionsAPI = fake.server.URL + "/"
+	t.Cleanup(func() {
+		interactionsAPI = oldAPI
+		fake.server.Close()
+	})
+	return fake
+}
+
+// setupInteractionsTest configures the bot with a new key pair for the interactions endpoint, returning the private key
+func setupInteractionsTest(t *testing.T) ed25519.PrivateKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.State.GuildAdd(&discordgo.Guild{ID: "guild"})
+	session.State.ChannelAdd(&discordgo.Channel{ID: "channel", GuildID: "guild"})
+
+	oldBotData := getBotData()
+	setBotData(&BotData{
+		BotKeys:        BotKeys{DiscordPublicKey: hex.EncodeToString(publicKey)},
+		DiscordSession: session,
+		Commands: map[string]*Command{
+			"echo": {
+				ResponseFunction: func(args []string, env *CommandEnvironment) *CommandResponse {
+					return &CommandResponse{Content: strings.Join(args, " ")}
+				},
+			},
+		},
+	})
+	t.Cleanup(func() {
+		setBotData(oldBotData)
+	})
+
+	discardLogs(t)
+	return privateKey
+}
+
+// postInteraction sends an interaction to the interactions endpoint, signed with the private key unless it's nil
+func postInteraction(t *testing.T, privateKey ed25519.PrivateKey, interaction *Interaction) *httptest.ResponseRecorder {
+	body, err := json.Marshal(interaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamp := "1600000000"
+
+	request := httptest.NewRequest("POST", "/interactions", bytes.NewReader(body))
+	if privateKey != nil {
+		signature := ed25519.Sign(privateKey, append([]byte(timestamp), body...))
+		request.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	}
+	request.Header.Set("X-Signature-Timestamp", timestamp)
+
+	recorder := httptest.NewRecorder()
+	interactionsEndpoint(recorder, request)
+	return recorder
+}
+
+func decodeInteractionResponse(t *testing.T, body io.Reader) *InteractionResponse {
+	response := &InteractionResponse{}
+	if err := json.NewDecoder(body).Decode(response); err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func TestInteractionsEndpointPing(t *testing.T) {
+	privateKey := setupInteractionsTest(t)
+
+	recorder := postInteraction(t, privateKey, &Interaction{ID: "ping", Type: InteractionTypePing})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d for a ping, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+	if response := decodeInteractionResponse(t, recorder.Body); response.Type != InteractionResponsePong {
+		t.Errorf("expected a pong, got response type %d", response.Type)
+	}
+}
+
+func TestInteractionsEndpointBadSignature(t *testing.T) {
+	setupInteractionsTest(t)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorder := postInteraction(t, otherKey, &Interaction{ID: "ping", Type: InteractionTypePing}); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for a request signed by another key, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+	if recorder := postInteraction(t, nil, &Interaction{ID: "ping", Type: InteractionTypePing}); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for an unsigned request, got %d", http.StatusUnauthorized, recorder.Code)
+	}
+}
+
+func TestInteractionsEndpointApplicationCommand(t *testing.T) {
+	privateKey := setupInteractionsTest(t)
+	fake := newFakeInteractionsAPI(t)
+
+	interaction := &Interaction{
+		ID:            "interaction",
+		ApplicationID: "application",
+		Type:          InteractionTypeApplicationCommand,
+		GuildID:       "guild",
+		ChannelID:     "channel",
+		Member:        &discordgo.Member{User: &discordgo.User{ID: "user", Username: "user"}},
+		Token:         "token",
+		Data: &InteractionData{
+			Name:    "echo",
+			Options: []*InteractionDataOption{{Name: "arguments", Type: ApplicationCommandOptionString, Value: "hello world"}},
+		},
+	}
+	recorder := postInteraction(t, privateKey, interaction)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d for an application command, got %d: %s", http.StatusOK, recorder.Code, recorder.Body)
+	}
+	if response := decodeInteractionResponse(t, recorder.Body); response.Type != InteractionResponseDeferredChannelMessageWithSource {
+		t.Fatalf("expected a deferred response, got response type %d", response.Type)
+	}
+
+	select {
+	case request := <-fake.requests:
+		if request.Method != "PATCH" || request.Path != "/webhooks/application/token/messages/@original" {
+			t.Fatalf("expected the deferred response to be edited, got %s %s", request.Method, request.Path)
+		}
+		responseData := &InteractionResponseData{}
+		if err := json.Unmarshal(request.Body, responseData); err != nil {
+			t.Fatal(err)
+		}
+		if responseData.Content != "hello world" {
+			t.Errorf("expected the command's response to be \"hello world\", got %q", responseData.Content)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the deferred response was never edited")
+	}
+}
+
+func TestTruncateApplicationText(t *testing.T) {
+	text := truncateApplicationText(strings.Repeat("é", 150), "")
+	if runes := []rune(text); len(runes) != 100 {
+		t.Errorf("expected 100 characters, got %d", len(runes))
+	}
+	if !strings.HasSuffix(text, "é...") {
+		t.Errorf("expected the text to be cut between characters, got %q", text)
+	}
+	if text := truncateApplicationText("", "fallback"); text != "fallback" {
+		t.Errorf("expected the fallback for empty text, got %q", text)
+	}
+}
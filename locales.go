@@ -0,0 +1,223 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// LocalesDir contains the directory translation files are loaded from, each named after its language code
+	LocalesDir = "locales"
+
+	// DefaultLanguage contains the language every message is written in, which needs no translation file
+	DefaultLanguage = "en"
+)
+
+var (
+	locales = map[string]*Locale{DefaultLanguage: {Code: DefaultLanguage, Name: "English", Messages: make(map[string]LocaleMessage)}}
+
+	regexpLocaleVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+)
+
+// Locale holds the translated messages for a language, where each message is keyed by its English text
+//
+// Messages with format verbs such as %s and %d are keyed by the format itself, which is translated before the
+// replacements are formatted into it, so "Successfully set the language for this server to **%s**." translates
+// every language that's set.
+type Locale struct {
+	Code               string                   `json:"-"`                            //The language code, taken from the file name
+	Name               string                   `json:"name"`                         //The name of the language in that language
+	PluralRule         string                   `json:"pluralRule,omitempty"`         //How to pick a plural form, see getPluralForm; default = "one"
+	ThousandsSeparator string                   `json:"thousandsSeparator,omitempty"` //The separator between groups of thousands in numbers; default = ","
+	Messages           map[string]LocaleMessage `json:"messages"`                     //The translated messages, where key = English message
+}
+
+// LocaleMessage holds the forms of a translated message, where there's one form for each plural form of the language
+type LocaleMessage []string
+
+// UnmarshalJSON allows a translated message to be either a single string or an array of plural forms
+func (message *LocaleMessage) UnmarshalJSON(data []byte) error {
+	var form string
+	if err := json.Unmarshal(data, &form); err == nil {
+		*message = LocaleMessage{form}
+		return nil
+	}
+	var forms []string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	*message = LocaleMessage(forms)
+	return nil
+}
+
+// loadLocales loads every translation file in the specified directory
+func loadLocales(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		localeJSON, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		locale := &Locale{}
+		if err := json.Unmarshal(localeJSON, locale); err != nil {
+			return fmt.Errorf("error parsing %s: %v", file, err)
+		}
+		locale.Code = strings.ToLower(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if locale.Messages == nil {
+			locale.Messages = make(map[string]LocaleMessage)
+		}
+		if locale.Name == "" {
+			locale.Name = locale.Code
+		}
+		locales[locale.Code] = locale
+		Debug.Printf("Loaded %d messages for language %s (%s)", len(locale.Messages), locale.Code, locale.Name)
+	}
+	return nil
+}
+
+// getLocale returns the locale for a language code, or the default locale if there is none
+func getLocale(code string) *Locale {
+	if locale, exists := locales[strings.ToLower(code)]; exists {
+		return locale
+	}
+	return locales[DefaultLanguage]
+}
+
+// getUserLocale returns the locale a user has chosen, then the locale of the guild, then the default locale
+func getUserLocale(userID, guildID string) *Locale {
+	language := ""
+	userSettings.View(userID, func(settings *UserSettings) {
+		language = settings.Language
+	})
+	if language != "" {
+		return getLocale(language)
+	}
+	return getGuildLocale(guildID)
+}
+
+// getGuildLocale returns the locale of a guild, or the default locale if it hasn't chosen one
+func getGuildLocale(guildID string) *Locale {
+	language := ""
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		language = settings.Language
+	})
+	if language != "" {
+		return getLocale(language)
+	}
+	return getLocale(DefaultLanguage)
+}
+
+// getChannelLocale returns the locale of the guild a channel belongs to, for messages that aren't sent to a user
+func getChannelLocale(channelID string) *Locale {
+	if channel, err := getBotData().DiscordSession.State.Channel(channelID); err == nil {
+		return getGuildLocale(channel.GuildID)
+	}
+	return getLocale(DefaultLanguage)
+}
+
+// Locale returns the locale responses in the environment should be translated to
+func (env *CommandEnvironment) Locale() *Locale {
+	guildID := ""
+	if env.Guild != nil {
+		guildID = env.Guild.ID
+	}
+	return getUserLocale(env.User.ID, guildID)
+}
+
+// getLanguageList returns the code and name of every language in alphabetical order
+func getLanguageList() []string {
+	languages := make([]string, 0)
+	for code, locale := range locales {
+		languages = append(languages, "``"+code+"`` - "+locale.Name)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// T returns the translation of a message, formatted with the replacements if there are any
+func (locale *Locale) T(message string, replacements ...interface{}) string {
+	if translated, exists := locale.Messages[message]; exists && len(translated) > 0 && translated[0] != "" {
+		message = translated[0]
+	}
+	if len(replacements) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, replacements...)
+}
+
+// N returns the translation of a message in the plural form for the count, formatted with the replacements if there are any
+//
+// The singular message is used as the key, and plural is used when there's no translation.
+func (locale *Locale) N(singular, plural string, count int, replacements ...interface{}) string {
+	message := plural
+	if count == 1 {
+		message = singular
+	}
+	if translated, exists := locale.Messages[singular]; exists && len(translated) > 0 {
+		form := locale.getPluralForm(count)
+		if form >= len(translated) {
+			form = len(translated) - 1
+		}
+		message = translated[form]
+	}
+	if len(replacements) == 0 || !regexpLocaleVerb.MatchString(message) {
+		return message //The singular form often leaves out the count
+	}
+	return fmt.Sprintf(message, replacements...)
+}
+
+// getPluralForm returns which plural form of a message to use for the count
+func (locale *Locale) getPluralForm(count int) int {
+	if count < 0 {
+		count = -count
+	}
+	switch locale.PluralRule {
+	case "none": //Japanese, Chinese, Korean, etc
+		return 0
+	case "zeroone": //French, Brazilian Portuguese, etc
+		if count <= 1 {
+			return 0
+		}
+		return 1
+	case "slavic": //Russian, Ukrainian, Serbian, etc
+		if count%10 == 1 && count%100 != 11 {
+			return 0
+		}
+		if count%10 >= 2 && count%10 <= 4 && (count%100 < 10 || count%100 >= 20) {
+			return 1
+		}
+		return 2
+	}
+	if count == 1 {
+		return 0
+	}
+	return 1
+}
+
+// FormatNumber returns a number with its groups of thousands separated
+func (locale *Locale) FormatNumber(number int) string {
+	separator := locale.ThousandsSeparator
+	if separator == "" {
+		separator = ","
+	}
+
+	digits := strconv.Itoa(number)
+	sign := ""
+	if number < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + separator + digits[i:]
+	}
+	return sign + digits
+}
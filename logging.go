@@ -31,19 +31,24 @@ var (
 )
 
 func initLogging(logFile *os.File, processType, debug string) {
+	//Everything is logged through a secretRedactor, so secrets from the configuration never reach the logs
+	stdout := &secretRedactor{io.MultiWriter(logFile, os.Stdout)}
+	stderr := &secretRedactor{io.MultiWriter(logFile, os.Stderr)}
+	log.SetOutput(&secretRedactor{os.Stderr}) //For libraries that log through the standard logger, such as discordgo
+
 	Debug = log.New(ioutil.Discard, "["+processType+"] DEBUG: ", logFlags)
 	if debug == "true" {
-		Debug = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] DEBUG: ", logFlags)
+		Debug = log.New(stdout, "["+processType+"] DEBUG: ", logFlags)
 	}
-	Info = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] INFO: ", logFlags)
-	Warning = log.New(io.MultiWriter(logFile, os.Stdout), "["+processType+"] WARNING: ", logFlags)
-	Error = log.New(io.MultiWriter(logFile, os.Stderr), "["+processType+"] ERROR: ", logFlags)
+	Info = log.New(stdout, "["+processType+"] INFO: ", logFlags)
+	Warning = log.New(stdout, "["+processType+"] WARNING: ", logFlags)
+	Error = log.New(stderr, "["+processType+"] ERROR: ", logFlags)
 
 	DebugAPI = log.New(ioutil.Discard, "[API] DEBUG: ", logFlags)
 	if debug == "true" {
-		DebugAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] DEBUG: ", logFlags)
+		DebugAPI = log.New(stdout, "[API] DEBUG: ", logFlags)
 	}
-	InfoAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] INFO: ", logFlags)
-	WarningAPI = log.New(io.MultiWriter(logFile, os.Stdout), "[API] WARNING: ", logFlags)
-	ErrorAPI = log.New(io.MultiWriter(logFile, os.Stderr), "[API] ERROR: ", logFlags)
+	InfoAPI = log.New(stdout, "[API] INFO: ", logFlags)
+	WarningAPI = log.New(stdout, "[API] WARNING: ", logFlags)
+	ErrorAPI = log.New(stderr, "[API] ERROR: ", logFlags)
 }
@@ -1,57 +1,43 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"io/ioutil"
 	"math/rand"
-	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/mmcdole/gofeed"
-
-	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
-	soundcloud "github.com/JoshuaDoes/go-soundcloud"
-	wolfram "github.com/JoshuaDoes/go-wolfram"
-	"github.com/JoshuaDoes/spotigo"
 	"github.com/bwmarrin/discordgo"
-	"github.com/google/go-github/github"
-	klogger "github.com/koffeinsource/go-klogger"
-	xkcd "github.com/nishanths/go-xkcd"
-	lyrics "github.com/rhnvrm/lyric-api-go"
 	"github.com/robfig/cron"
-	"github.com/superwhiskers/fennel"
-	"google.golang.org/api/googleapi/transport"
-	"google.golang.org/api/youtube/v3"
 )
 
 var (
-	//Contains all bot configurations
-	botData *BotData = &BotData{}
+	//Contains all bot configurations, read with getBotData as a reload may swap them out at any time
+	liveBotData atomic.Value
 
-	//Contains guild-specific data in a string map, where key = guild ID
-	guildData = make(map[string]*GuildData)
+	//Contains guild-specific data, where key = guild ID
+	guildData = NewGuildDataStore()
 
-	//Contains guild-specific settings in a string map, where key = guild ID
-	guildSettings = make(map[string]*GuildSettings)
+	//Contains guild-specific settings, where key = guild ID
+	guildSettings = NewGuildSettingsStore()
 
-	//Contains user-specific settings in a string map, where key = user ID
-	userSettings = make(map[string]*UserSettings)
+	//Contains user-specific settings, where key = user ID
+	userSettings = NewUserSettingsStore()
 
-	//Contains guild-specific starboard data in a string map, where key = guild ID
-	starboards = make(map[string]*Starboard)
+	//Contains guild-specific starboard data, where key = guild ID
+	starboards = NewStarboardStore()
 
 	//Contains all remind entries
-	remindEntries = make([]RemindEntry, 0)
+	remindEntries = NewRemindStore()
 
-	//Contains guild-specific voice data in a string map, where key = guild ID
-	voiceData = make(map[string]*Voice)
+	//Contains guild-specific voice data, where key = guild ID
+	voiceData = NewVoiceStore()
 
 	//Contains a pointer to the current log file
 	logFile *os.File
@@ -61,21 +47,31 @@ var (
 )
 
 var (
-	configFile  string
-	configIsBot string
-	masterPID   int
-	killOldBot  string
-	debug       string
+	configFile     string
+	configIsBot    string
+	masterPID      int
+	killOldBot     string
+	debug          string
+	snapshotCmd    string
+	validateConfig bool
+	showStatus     bool
 )
 
 func init() {
-	flag.StringVar(&configFile, "config", "config.json", "The location of the JSON-structured configuration file")
+	setBotData(&BotData{})
+
+	flag.StringVar(&configFile, "config", "config.json", "The location of the configuration file, written in JSON, YAML (.yaml, .yml) or TOML (.toml)")
 	flag.StringVar(&configIsBot, "bot", "false", "Whether or not to act as a bot")
 	flag.IntVar(&masterPID, "masterpid", -1, "The bot master's PID")
 	flag.StringVar(&killOldBot, "killold", "false", "Whether or not to kill an old bot process")
 	flag.StringVar(&debug, "debug", "false", "Whether or not to output debugging and trace messages")
-	flag.Parse()
+	flag.StringVar(&snapshotCmd, "snapshot", "", "Runs a state snapshot command and exits: \"list\", \"create\", \"diff NAME [guildID]\" or \"restore NAME all|guildID\"")
+	flag.BoolVar(&validateConfig, "validate-config", false, "Checks the configuration file, prints every problem found with it and exits")
+	flag.BoolVar(&showStatus, "status", false, "Prints the state of the bot process and its crash history and exits")
+}
 
+// openLog starts logging to the log file of this process, which depends on whether or not it's the bot process
+func openLog() {
 	if configIsBot == "true" {
 		logFile, err := os.OpenFile("clinet.bot.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 		if err != nil {
@@ -92,15 +88,34 @@ func init() {
 }
 
 func main() {
+	//Flags are parsed here rather than in init, so the package can be tested without its flags getting in the way
+	flag.Parse()
+	openLog()
+
 	defer recoverPanic()
 	defer logFile.Close()
 
+	if validateConfig {
+		os.Exit(runValidateConfig(configFile))
+	}
+	if showStatus {
+		os.Exit(runStatusCLI(SupervisorStatusFile))
+	}
+
 	Info.Println("Clinet © JoshuaDoes: 2017-2018.")
 	Info.Println("Build ID: " + BuildID)
 	Info.Println("Current PID: " + strconv.Itoa(os.Getpid()))
 
 	uptime = time.Now()
 
+	if snapshotCmd != "" {
+		if err := loadConfig(configFile); err != nil {
+			Error.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(runSnapshotCLI(snapshotCmd))
+	}
+
 	if configIsBot == "true" {
 		numCPU := runtime.NumCPU()
 		runtime.GOMAXPROCS(numCPU * 2)
@@ -109,77 +124,26 @@ func main() {
 		Debug.Printf("Max Process Count: %d\n", numCPU*2)
 
 		Info.Println("Loading settings...")
-		configFileHandle, err := os.Open(configFile)
-		defer configFileHandle.Close()
-		if err != nil {
+		if err := loadConfig(configFile); err != nil {
 			Error.Println(err)
 			os.Exit(1)
-		} else {
-			configParser := json.NewDecoder(configFileHandle)
-			if err = configParser.Decode(&botData); err != nil {
-				Error.Println(err)
-				os.Exit(1)
-			} else {
-				configErr := botData.PrepConfig() //Check the configuration for any errors or inconsistencies, then prepare it for usage
-				if configErr != nil {
-					Error.Println(configErr)
-					os.Exit(1)
-				}
-			}
 		}
 
-		Info.Println("Initializing clients for external services...")
-		if botData.BotOptions.UseDuckDuckGo {
-			botData.BotClients.DuckDuckGo = &duckduckgo.Client{AppName: botData.BotKeys.DuckDuckGoAppName}
-		}
-		if botData.BotOptions.UseImgur {
-			botData.BotClients.Imgur.HTTPClient = &http.Client{}
-			botData.BotClients.Imgur.Log = &klogger.CLILogger{}
-			botData.BotClients.Imgur.ImgurClientID = botData.BotKeys.ImgurClientID
-		}
-		if botData.BotOptions.UseSoundCloud {
-			botData.BotClients.SoundCloud = &soundcloud.Client{ClientID: botData.BotKeys.SoundCloudClientID}
-		}
-		if botData.BotOptions.UseSpotify {
-			botData.BotClients.Spotify = &spotigo.Client{Host: botData.BotKeys.SpotifyHost, Pass: botData.BotKeys.SpotifyPass}
-		}
-		if botData.BotOptions.UseWolframAlpha {
-			botData.BotClients.Wolfram = &wolfram.Client{AppID: botData.BotKeys.WolframAppID}
-		}
-		if botData.BotOptions.UseXKCD {
-			botData.BotClients.XKCD = xkcd.NewClient()
-		}
-		if botData.BotOptions.UseYouTube {
-			httpClient := &http.Client{
-				Transport: &transport.APIKey{Key: botData.BotKeys.YouTubeAPIKey},
-			}
-			youtubeClient, err := youtube.New(httpClient)
-			if err != nil {
-				Error.Printf("Error initializing YouTube: %v", err)
-			} else {
-				botData.BotClients.YouTube = youtubeClient
-			}
-		}
-		if botData.BotOptions.UseGitHub {
-			botData.BotClients.GitHub = github.NewClient(nil)
-		}
-		if botData.BotOptions.UseLyrics {
-			botData.BotClients.Lyrics = lyrics.New(lyrics.WithoutProviders(), lyrics.WithLyricsWikia(), lyrics.WithMusixMatch(), lyrics.WithSongLyrics(), lyrics.WithGeniusLyrics(botData.BotKeys.GeniusAccessToken))
-		}
-		if botData.BotOptions.UseNinty {
-			nintyClient, err := fennel.NewAccountServerClient("https://account.nintendo.net/v1/api", ctrCommonCert, ctrCommonKey, botData.BotKeys.Ninty)
-			if err != nil {
-				Error.Printf("Error initializing Ninty: %v", err)
-			} else {
-				botData.BotClients.Ninty = nintyClient
-			}
+		Info.Println("Loading translations...")
+		if err := loadLocales(LocalesDir); err != nil {
+			Error.Println(err)
+			os.Exit(1)
 		}
-		if botData.BotOptions.UseFeed {
-			botData.BotClients.FeedParser = gofeed.NewParser()
+
+		Info.Println("Initializing clients for external services...")
+		var clientErrs []error
+		getBotData().BotClients, clientErrs = newBotClients(getBotData())
+		for _, err := range clientErrs {
+			Error.Println(err)
 		}
 
 		Info.Println("Creating a Discord session...")
-		discord, err := discordgo.New("Bot " + botData.BotToken)
+		discord, err := discordgo.New("Bot " + getBotData().BotToken)
 		if err != nil {
 			panic(err)
 		}
@@ -210,10 +174,14 @@ func main() {
 		discord.AddHandler(discordMessageReactionRemove)
 		discord.AddHandler(discordMessageReactionRemoveAll)
 		discord.AddHandler(discordReady)
+		discord.AddHandler(discordInteractionCreate)
 
 		//If a state exists, load it
 		Info.Println("Loading state...")
-		stateRestoreAll()
+		if err := stateRestoreAll(); err != nil {
+			Error.Println(err)
+			os.Exit(1)
+		}
 
 		Info.Println("Connecting to Discord...")
 		err = discord.Open()
@@ -221,9 +189,9 @@ func main() {
 			panic(err)
 		}
 		Info.Println("Connected successfully!")
-		botData.DiscordSession = discord
+		getBotData().DiscordSession = discord
 
-		if botData.SendOwnerStackTraces {
+		if getBotData().SendOwnerStackTraces {
 			checkPanicRecovery()
 		}
 
@@ -238,11 +206,14 @@ func main() {
 		Debug.Println("Checking if bot was updated...")
 		checkUpdate()
 
-		if botData.BotOptions.API.Enabled {
-			Info.Printf("Starting API on [%s]...\n", botData.BotOptions.API.Host)
-			go StartAPI(botData.BotOptions.API.Host)
+		if getBotData().BotOptions.API.Enabled {
+			Info.Printf("Starting API on [%s]...\n", getBotData().BotOptions.API.Host)
+			go StartAPI(getBotData().BotOptions.API.Host)
 		}
 
+		Debug.Println("Watching the configuration file for changes...")
+		go watchConfig(configFile)
+
 		Debug.Println("Waiting for SIGINT syscall signal...")
 		sc := make(chan os.Signal, 1)
 		signal.Notify(sc, syscall.SIGINT)
@@ -254,11 +225,11 @@ func main() {
 		stateSaveAll()
 
 		//Leave all voice channels
-		for _, voiceIDRow := range voiceData {
+		for _, voiceIDRow := range voiceData.All() {
 			if voiceIDRow.IsConnected() {
 				if voiceIDRow.IsStreaming() {
 					//Notify users that an update is occuring
-					botData.DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+botData.BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
+					getBotData().DiscordSession.ChannelMessageSendEmbed(voiceIDRow.TextChannelID, NewEmbed().SetTitle("Update").SetDescription("Your audio playback has been interrupted for a "+getBotData().BotName+" update event. You may resume playback in a few seconds.").SetColor(0x1C1C1C).MessageEmbed)
 
 					debugLog("> Stopping stream in voice channel "+voiceIDRow.VoiceConnection.ChannelID+"...", false)
 					voiceIDRow.Stop()
@@ -270,27 +241,15 @@ func main() {
 
 		Info.Println("Disconnecting from Discord...")
 		discord.Close()
+
+		Info.Println("Closing state...")
+		stateClose()
 	} else {
-		botPid := spawnBot()
-		sc := make(chan os.Signal, 1)
-		signal.Notify(sc, syscall.SIGINT)
-		watchdogTicker := time.Tick(1 * time.Second)
-
-		for {
-			select {
-			case _, ok := <-sc:
-				if ok {
-					botProcess, _ := os.FindProcess(botPid)
-					_ = botProcess.Signal(syscall.SIGINT)
-					waitProcess(botPid)
-					os.Exit(0)
-				}
-			case <-watchdogTicker:
-				if !isProcessRunning(botPid) {
-					botPid = spawnBot()
-				}
-			}
+		//The configuration is only needed to alert the bot owner of crash loops, which the bot can run without
+		if err := loadConfig(configFile); err != nil {
+			Warning.Printf("Unable to load the configuration, the bot owner won't be alerted of crash loops: %v\n", err)
 		}
+		os.Exit(NewSupervisor(SupervisorStatusFile).Run())
 	}
 }
 
@@ -298,19 +257,26 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 	defer recoverPanic()
 
 	Debug.Println("Setting bot username from Discord state...")
-	botData.BotName = session.State.User.Username
+	getBotData().BotName = session.State.User.Username
 
 	Debug.Println("Initializing commands...")
-	initCommands()
+	initCommands(getBotData())
+
+	if getBotData().BotOptions.UseSlashCommands {
+		Debug.Println("Registering application commands...")
+		if err := registerApplicationCommands(session); err != nil {
+			Error.Printf("Error registering application commands: %v", err)
+		}
+	}
 
 	Debug.Println("Initializing natural language commands...")
-	initNLPCommands()
+	initNLPCommands(getBotData())
 
 	Debug.Println("Initializing query service handlers...")
-	initQueryServices()
+	initQueryServices(getBotData())
 
 	Debug.Println("Initializing voice service handlers...")
-	initVoiceServices()
+	initVoiceServices(getBotData())
 
 	Debug.Println("Setting random presence...")
 	updateRandomStatus(session, 0)
@@ -324,20 +290,38 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 	Debug.Println("Creating random tip message cronjob...")
 	cronjob.AddFunc("@every 1h", func() { sendTipMessages() })
 
+	Debug.Println("Creating state save cronjob...")
+	cronjob.AddFunc("@every 5m", func() { stateSaveAll() })
+
+	if getBotData().BotOptions.Snapshots.Enabled {
+		Debug.Println("Creating state snapshot cronjob...")
+		cronjob.AddFunc("@every "+strconv.Itoa(getBotData().BotOptions.Snapshots.Interval)+"m", func() {
+			stateSaveAll()
+			if info, err := createSnapshot(); err != nil {
+				Error.Printf("Error creating state snapshot: %s\n", err)
+			} else {
+				Debug.Printf("Created state snapshot %s\n", info.Name)
+			}
+		})
+	}
+
 	Debug.Println("Starting cronjobs...")
 	cronjob.Start()
 
 	Debug.Println("Loading active reminders...")
-	oldRemindEntries := remindEntries
-	remindEntries = make([]RemindEntry, 0)
+	oldRemindEntries := remindEntries.All()
+	remindEntries.Replace(nil) //Each reminder is added back as it's scheduled
 	for i := range oldRemindEntries {
 		remindWhen(oldRemindEntries[i].UserID, oldRemindEntries[i].GuildID, oldRemindEntries[i].ChannelID, oldRemindEntries[i].Message, oldRemindEntries[i].Added, oldRemindEntries[i].When, time.Now())
 	}
 
 	Debug.Println("Loading feeds...")
-	for guildID, guild := range guildSettings {
-		oldFeeds := guild.Feeds
-		guild.Feeds = make([]*Feed, 0)
+	for guildID := range guildSettings.All() {
+		var oldFeeds []*Feed
+		guildSettings.Update(guildID, func(settings *GuildSettings) {
+			oldFeeds = settings.Feeds
+			settings.Feeds = make([]*Feed, 0)
+		})
 		for _, feed := range oldFeeds {
 			addErr := addFeed(guildID, feed.ChannelID, feed.FeedURL, feed.Frequency)
 			if addErr != nil {
@@ -346,37 +330,40 @@ func discordReady(session *discordgo.Session, event *discordgo.Ready) {
 		}
 	}
 
+	Debug.Println("Loading scheduled commands...")
+	scheduler.Rebuild()
+
 	Info.Println("Discord is ready!")
 }
 
 func updateRandomStatus(session *discordgo.Session, status int) {
 	if status == 0 {
-		status = rand.Intn(len(botData.CustomStatuses)) + 1
+		status = rand.Intn(len(getBotData().CustomStatuses)) + 1
 	}
 	status--
 
-	switch botData.CustomStatuses[status].Type {
+	switch getBotData().CustomStatuses[status].Type {
 	case 0:
-		Debug.Printf("Presence: Playing %s\n", botData.CustomStatuses[status].Status)
-		session.UpdateStatus(0, botData.CustomStatuses[status].Status)
+		Debug.Printf("Presence: Playing %s\n", getBotData().CustomStatuses[status].Status)
+		session.UpdateStatus(0, getBotData().CustomStatuses[status].Status)
 	case 1:
-		Debug.Printf("Presence: Listening to %s\n", botData.CustomStatuses[status].Status)
-		session.UpdateListeningStatus(botData.CustomStatuses[status].Status)
+		Debug.Printf("Presence: Listening to %s\n", getBotData().CustomStatuses[status].Status)
+		session.UpdateListeningStatus(getBotData().CustomStatuses[status].Status)
 	case 2:
-		Debug.Printf("Presence: Streaming %s at %s\n", botData.CustomStatuses[status].Status, botData.CustomStatuses[status].URL)
-		session.UpdateStreamingStatus(0, botData.CustomStatuses[status].Status, botData.CustomStatuses[status].URL)
+		Debug.Printf("Presence: Streaming %s at %s\n", getBotData().CustomStatuses[status].Status, getBotData().CustomStatuses[status].URL)
+		session.UpdateStreamingStatus(0, getBotData().CustomStatuses[status].Status, getBotData().CustomStatuses[status].URL)
 	}
 }
 
 func sendTipMessages() {
 	tipMessageN := -1
 	for {
-		tipMessageN = rand.Intn(len(botData.TipMessages))
-		if tipMessageN != botData.LastTipMessage {
+		tipMessageN = rand.Intn(len(getBotData().TipMessages))
+		if tipMessageN != getBotData().LastTipMessage || len(getBotData().TipMessages) == 1 {
 			break
 		}
 	}
-	tipMessage := botData.TipMessages[tipMessageN]
+	tipMessage := getBotData().TipMessages[tipMessageN]
 	tipMessageEmbed := NewEmbed().
 		AddField("Did You Know?", tipMessage.DidYouKnow).
 		AddField("How To Use", tipMessage.HowTo).
@@ -387,118 +374,52 @@ func sendTipMessages() {
 		tipMessageEmbed.AddField("Examples", strings.Join(tipMessage.Examples, "\n"))
 	}
 
-	for _, guild := range guildSettings {
+	for _, guild := range guildSettings.All() {
 		if guild.TipsChannel != "" {
-			botData.DiscordSession.ChannelMessageSendEmbed(guild.TipsChannel, tipMessageEmbed.MessageEmbed)
+			getBotData().DiscordSession.ChannelMessageSendEmbed(guild.TipsChannel, tipMessageEmbed.MessageEmbed)
 		}
 	}
 
-	botData.LastTipMessage = tipMessageN
+	getBotData().LastTipMessage = tipMessageN
 }
 
 func typingEvent(session *discordgo.Session, channelID string, updatedMessageEvent bool) {
-	if botData.BotOptions.SendTypingEvent && updatedMessageEvent == false {
+	if getBotData().BotOptions.SendTypingEvent && updatedMessageEvent == false {
 		Debug.Printf("Typing in channel %s...\n", channelID)
 		session.ChannelTyping(channelID)
 	}
 }
 
-func debugLog(msg string, overrideConfig bool) {
-	if botData.DebugMode || overrideConfig {
-		Debug.Println(msg)
-	}
-}
-
-func stateSaveAll() {
-	if _, err := os.Stat("state"); os.IsNotExist(err) {
-		os.Mkdir("state", 0744)
-	}
-
-	err := stateSaveRaw(guildData, "state/guildData.json")
-	if err != nil {
-		Error.Printf("Error saving guildData state: %s\n", err)
-	}
-
-	err = stateSaveRaw(guildSettings, "state/guildSettings.json")
-	if err != nil {
-		Error.Printf("Error saving guildSettings state: %s\n", err)
-	}
-
-	err = stateSaveRaw(userSettings, "state/userSettings.json")
-	if err != nil {
-		Error.Printf("Error saving userSettings state: %s\n", err)
-	}
-
-	err = stateSaveRaw(starboards, "state/starboards.json")
-	if err != nil {
-		Error.Printf("Error saving starboards: %s\n", err)
-	}
-
-	err = stateSaveRaw(remindEntries, "state/reminds.json")
-	if err != nil {
-		Error.Printf("Error saving reminders: %s\n", err)
-	}
-
-	err = stateSaveRaw(voiceData, "state/voiceData.json")
-	if err != nil {
-		Error.Printf("Error saving voiceData state: %s\n", err)
-	}
-}
-
-func stateSaveRaw(data interface{}, file string) error {
-	dataJSON, err := json.MarshalIndent(data, "", "\t")
+// loadConfig loads the configuration file as the live configuration, then checks it for any errors or inconsistencies and prepares it for usage
+func loadConfig(file string) error {
+	config, err := parseConfig(file)
 	if err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(file, dataJSON, 0744)
-	return err
+	setBotData(config)
+	return nil
 }
 
-func stateRestoreAll() {
-	err := stateRestoreRaw("state/guildData.json", &guildData)
-	if err != nil {
-		Error.Printf("Error loading guildData state: %s\n", err)
-	}
-
-	err = stateRestoreRaw("state/guildSettings.json", &guildSettings)
-	if err != nil {
-		Error.Printf("Error loading guildSettings state: %s\n", err)
-	}
-
-	err = stateRestoreRaw("state/userSettings.json", &userSettings)
-	if err != nil {
-		Error.Printf("Error loading userSettings state: %s\n", err)
-	}
-
-	err = stateRestoreRaw("state/starboards.json", &starboards)
-	if err != nil {
-		Error.Printf("Error loading starboards: %s\n", err)
-	}
-
-	err = stateRestoreRaw("state/reminds.json", &remindEntries)
-	if err != nil {
-		Error.Printf("Error loading reminders: %s\n", err)
-	}
+// getBotData returns the live configuration
+func getBotData() *BotData {
+	return liveBotData.Load().(*BotData)
+}
 
-	err = stateRestoreRaw("state/voiceData.json", &voiceData)
-	if err != nil {
-		Error.Printf("Error loading voiceData state: %s\n", err)
-	}
+// setBotData replaces the live configuration, which every later call to getBotData returns
+func setBotData(config *BotData) {
+	liveBotData.Store(config)
 }
 
-func stateRestoreRaw(file string, data interface{}) error {
-	dataJSON, err := ioutil.ReadFile(file)
-	if err != nil {
-		return err
+func debugLog(msg string, overrideConfig bool) {
+	if getBotData().DebugMode || overrideConfig {
+		Debug.Println(msg)
 	}
-	err = json.Unmarshal(dataJSON, data)
-	return err
 }
 
 func firstRun() bool {
 	_, err := ioutil.ReadFile(".firstrun")
 	if err == nil {
-		DowntimeReason = "Restarted by host system or <@" + botData.BotOwnerID + ">"
+		DowntimeReason = "Restarted by host system or <@" + getBotData().BotOwnerID + ">"
 		return false
 	}
 
@@ -509,11 +430,11 @@ func firstRun() bool {
 func checkRestart() {
 	restartChannelID, err := ioutil.ReadFile(".restart")
 	if err == nil && len(restartChannelID) > 0 {
-		DowntimeReason = "Restarted by <@" + botData.BotOwnerID + ">"
+		DowntimeReason = "Restarted by <@" + getBotData().BotOwnerID + ">"
 
 		Info.Println("Restart succeeded!")
-		restartEmbed := NewGenericEmbed("Restart", "Successfully restarted "+botData.BotName+"!")
-		botData.DiscordSession.ChannelMessageSendEmbed(string(restartChannelID), restartEmbed)
+		restartEmbed := NewGenericEmbed(getChannelLocale(string(restartChannelID)), "Restart", "Successfully restarted "+getBotData().BotName+"!")
+		getBotData().DiscordSession.ChannelMessageSendEmbed(string(restartChannelID), restartEmbed)
 
 		os.Remove(".restart")
 	}
@@ -525,8 +446,8 @@ func checkUpdate() {
 		DowntimeReason = "Updated to " + BuildID
 
 		Info.Println("Update succeeded!")
-		updateEmbed := NewGenericEmbed("Update", "Successfully updated "+botData.BotName+"!")
-		botData.DiscordSession.ChannelMessageSendEmbed(string(updateChannelID), updateEmbed)
+		updateEmbed := NewGenericEmbed(getChannelLocale(string(updateChannelID)), "Update", "Successfully updated "+getBotData().BotName+"!")
+		getBotData().DiscordSession.ChannelMessageSendEmbed(string(updateChannelID), updateEmbed)
 
 		os.Remove(".update")
 	}
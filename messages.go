@@ -7,13 +7,39 @@ import (
 	"strings"
 	"time"
 
-	"github.com/JoshuaDoes/go-wolfram"
 	"github.com/bwmarrin/discordgo"
 )
 
-// Query holds data about a query's response message
+// Query holds data about a query's response messages
 type Query struct {
-	ResponseMessageID string `json:"responseMessageID,omitempty"`
+	ResponseMessageID  string   `json:"responseMessageID,omitempty"`  //The first response message
+	ResponseMessageIDs []string `json:"responseMessageIDs,omitempty"` //Every response message, including the first
+	ResponseChannelID  string   `json:"responseChannelID,omitempty"`  //The channel the responses were sent in if it isn't the query's channel, such as a direct message
+	AuthorID           string   `json:"authorID,omitempty"`           //The user who sent the query
+}
+
+// MessageIDs returns the IDs of every response message
+func (query *Query) MessageIDs() []string {
+	if len(query.ResponseMessageIDs) == 0 && query.ResponseMessageID != "" {
+		return []string{query.ResponseMessageID} //Queries saved before multiple responses were tracked
+	}
+	return query.ResponseMessageIDs
+}
+
+// AddMessageID tracks another response message
+func (query *Query) AddMessageID(messageID string) {
+	if query.ResponseMessageID == "" {
+		query.ResponseMessageID = messageID
+	}
+	query.ResponseMessageIDs = append(query.ResponseMessageIDs, messageID)
+}
+
+// GetChannelID returns the channel the responses were sent in, where channelID is the channel of the query
+func (query *Query) GetChannelID(channelID string) string {
+	if query.ResponseChannelID != "" {
+		return query.ResponseChannelID
+	}
+	return channelID
 }
 
 func debugMessage(session *discordgo.Session, message *discordgo.Message, channel *discordgo.Channel, guild *discordgo.Guild, updatedMessageEvent bool) {
@@ -40,9 +66,9 @@ func debugMessage(session *discordgo.Session, message *discordgo.Message, channe
 	}
 
 	if strings.Contains(content, "\n") {
-		Debug.Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
+		Debug.Printf("[%s][%s] %s%s#%s:\n%s", eventType, debugLocation(channel, guild), userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
 	} else {
-		Debug.Printf("[%s][%s - #%s] %s%s#%s: %s", eventType, guild.Name, channel.Name, userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
+		Debug.Printf("[%s][%s] %s%s#%s: %s", eventType, debugLocation(channel, guild), userType, message.Author.Username, message.Author.Discriminator, contentReplaced)
 	}
 }
 
@@ -60,7 +86,15 @@ func debugEmbed(embed *discordgo.MessageEmbed, author *discordgo.User, channel *
 		userType = "*"
 	}
 
-	Debug.Printf("[%s][%s - #%s] %s%s#%s:\n%s", eventType, guild.Name, channel.Name, userType, author.Username, author.Discriminator, string(embedJSON))
+	Debug.Printf("[%s][%s] %s%s#%s:\n%s", eventType, debugLocation(channel, guild), userType, author.Username, author.Discriminator, string(embedJSON))
+}
+
+// debugLocation returns where a message was sent for debug logs
+func debugLocation(channel *discordgo.Channel, guild *discordgo.Guild) string {
+	if guild == nil {
+		return "Direct Message"
+	}
+	return guild.Name + " - #" + channel.Name
 }
 
 func handleMessage(session *discordgo.Session, message *discordgo.Message, updatedMessageEvent bool) {
@@ -72,7 +106,13 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 
 	channel, err := session.State.Channel(message.ChannelID)
 	if err != nil {
-		return //Error finding the channel
+		if channel, err = session.Channel(message.ChannelID); err != nil {
+			return //Error finding the channel
+		}
+	}
+	if channel.GuildID == "" {
+		handleDirectMessage(session, message, channel, updatedMessageEvent)
+		return
 	}
 	guild, err := session.State.Guild(channel.GuildID)
 	if err != nil {
@@ -82,7 +122,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 	if content == "" {
 		return //The message was empty
 	}
-	member, err := botData.DiscordSession.GuildMember(guild.ID, message.Author.ID)
+	member, err := getBotData().DiscordSession.GuildMember(guild.ID, message.Author.ID)
 	if err != nil {
 		return //Error finding the guild member
 	}
@@ -93,13 +133,16 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 	initializeUserSettings(message.Author.ID)
 	initializeStarboard(guild.ID)
 
-	guildData[guild.ID].Lock()
-	defer guildData[guild.ID].Unlock()
+	//The response that will be sent off to Discord
+	var response *CommandResponse
 
-	//The embed that will be sent off to Discord
-	var responseEmbed *discordgo.MessageEmbed
+	//A copy of the guild's settings, so they can be read without blocking changes to them
+	settings, _ := guildSettings.Snapshot(guild.ID)
+	if settings == nil {
+		settings = &GuildSettings{}
+	}
 
-	for _, roleMe := range guildSettings[guild.ID].RoleMeList {
+	for _, roleMe := range settings.RoleMeList {
 		for _, trigger := range roleMe.Triggers {
 			if roleMe.CaseSensitive {
 				if trigger == content {
@@ -116,109 +159,38 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 	}
 
 	regexpBotName, _ := regexp.MatchString("^<(@|@\\!)"+session.State.User.ID+">(.*?)$", content) //Ensure prefix is bot tag
-	prefix := ""
-	if guildSettings[guild.ID].BotPrefix != "" {
-		if strings.HasPrefix(content, guildSettings[guild.ID].BotPrefix) {
-			prefix = guildSettings[guild.ID].BotPrefix
-		}
-	} else {
-		if strings.HasPrefix(content, botData.CommandPrefix) {
-			prefix = botData.CommandPrefix
-		}
+	prefix := matchGuildPrefix(content, guild.ID)
+	if prefix == "" && regexpBotName {
+		prefix = matchMentionPrefix(content, guild.ID, session.State.User.ID)
 	}
 
-	if regexpBotName {
-		if botData.BotOptions.UseWolframAlpha || botData.BotOptions.UseDuckDuckGo || botData.BotOptions.UseCustomResponses {
+	if regexpBotName && prefix == "" {
+		if getBotData().BotOptions.UseWolframAlpha || getBotData().BotOptions.UseDuckDuckGo || getBotData().BotOptions.UseCustomResponses {
 			debugMessage(session, message, channel, guild, updatedMessageEvent)
 			typingEvent(session, message.ChannelID, updatedMessageEvent)
 
-			query := content
-			query = strings.Replace(query, "<@!"+session.State.User.ID+">", "", -1)
-			query = strings.Replace(query, "<@"+session.State.User.ID+">", "", -1)
-			for {
-				if strings.HasPrefix(query, " ") {
-					query = strings.Replace(query, " ", "", 1)
-				} else if strings.HasPrefix(query, ",") {
-					query = strings.Replace(query, ",", "", 1)
-				} else if strings.HasPrefix(query, ":") {
-					query = strings.Replace(query, ":", "", 1)
-				} else {
-					break
-				}
-			}
-
-			commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, UpdatedMessageEvent: updatedMessageEvent}
-			responseEmbed = callNLP(query, commandEnvironment)
+			query := trimMentionQuery(content, session.State.User.ID)
 
-			if responseEmbed == nil {
-				typingEvent(session, message.ChannelID, updatedMessageEvent)
-
-				var previousConversation *wolfram.Conversation
-
-				if guildData[guild.ID].WolframConversations != nil {
-					if guildData[guild.ID].WolframConversations[message.Author.ID] != nil {
-						previousConversation = guildData[guild.ID].WolframConversations[message.Author.ID]
-					} else {
-						guildData[guild.ID].WolframConversations[message.Author.ID] = &wolfram.Conversation{}
-					}
-				} else {
-					guildData[guild.ID].WolframConversations = make(map[string]*wolfram.Conversation)
-					guildData[guild.ID].WolframConversations[message.Author.ID] = &wolfram.Conversation{}
-				}
-
-				queryEnvironment := &QueryEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, WolframConversation: previousConversation}
-				responseEmbed, err = getQueryResult(query, queryEnvironment)
-				if err != nil {
-					responseEmbed = NewErrorEmbed("Query Error", "We couldn't find a service to handle your query.\nMake sure you're using proper grammar and query structure where applicable.")
-				}
-			}
+			commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, BotPrefix: getGuildPrefix(guild.ID), UpdatedMessageEvent: updatedMessageEvent}
+			response = runQuery(session, query, commandEnvironment, guild.ID)
 		}
 	} else if prefix != "" {
 		debugMessage(session, message, channel, guild, updatedMessageEvent)
 
 		cmdMsg := strings.TrimPrefix(content, prefix)
 
-		cmd := strings.Split(cmdMsg, " ")
-
-		//0>>>>>>-1>>>>>-2>>>>>>>>>>>>>>>>>>-3>>>>>>>>>>
-		//spotify search "dance gavin dance" bloodsucker
-		//0: spotify
-		//1: search
-		//2: dance gavin dance
-		//3: bloodsucker
-		newCmd := make([]string, 0)
-		for i := 0; i < len(cmd); i++ {
-			if strings.HasPrefix(cmd[i], "\"") && !strings.HasPrefix(cmd[i], "\"\"") {
-				for j := i; j < len(cmd); j++ {
-					if strings.HasSuffix(cmd[j], "\"") && !strings.HasSuffix(cmd[j], "\"\"") {
-						newArg := strings.Join(cmd[i:j+1], " ")
-						newArg = strings.TrimPrefix(newArg, "\"")
-						newArg = strings.TrimSuffix(newArg, "\"")
-						newCmd = append(newCmd, newArg)
-						i = j
-						break
-					}
-				}
-			} else {
-				newCmd = append(newCmd, cmd[i])
-			}
-		}
-		if len(newCmd) > 0 {
-			cmd = newCmd
-		}
+		member, _ := getBotData().DiscordSession.GuildMember(guild.ID, message.Author.ID)
 
-		member, _ := botData.DiscordSession.GuildMember(guild.ID, message.Author.ID)
-
-		commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, Command: cmd[0], BotPrefix: botData.CommandPrefix, UpdatedMessageEvent: updatedMessageEvent}
-		responseEmbed = callCommand(cmd[0], cmd[1:], commandEnvironment)
+		commandEnvironment := &CommandEnvironment{Channel: channel, Guild: guild, Message: message, User: message.Author, Member: member, BotPrefix: prefix, UpdatedMessageEvent: updatedMessageEvent}
+		response = callCommandLine(cmdMsg, commandEnvironment, isPrefixCaseInsensitive(guild.ID))
 	}
 
 	//Swear filter check
-	if guildSettings[guild.ID].SwearFilter.Enabled && responseEmbed == nil {
-		swearFound, swears, err := guildSettings[guild.ID].SwearFilter.Check(content)
+	if settings.SwearFilter.Enabled && response == nil {
+		swearFound, swears, err := settings.SwearFilter.Check(content)
 		if err != nil {
 			//Report error to developer
-			ownerPrivChannel, chanErr := session.UserChannelCreate(botData.BotOwnerID)
+			ownerPrivChannel, chanErr := session.UserChannelCreate(getBotData().BotOwnerID)
 			if chanErr != nil {
 				debugLog("An error occurred creating a private channel with the bot owner.", false)
 			} else {
@@ -227,8 +199,7 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 		}
 		if swearFound {
 			//Log swear event to log channel with list of swears found
-			settings, guildFound := guildSettings[guild.ID]
-			if guildFound && settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.SwearDetect {
+			if settings.LogSettings.LoggingEnabled && settings.LogSettings.LoggingEvents.SwearDetect {
 				swearDetectEmbed := NewEmbed().
 					SetTitle("Logging Event - Swear Detect").
 					SetDescription("One or more swears were detected in a message.").
@@ -249,8 +220,8 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 			msgWarning, _ := session.ChannelMessageSend(message.ChannelID, ":warning: <@"+message.Author.ID+">, please watch your language!")
 
 			//Delete warning after x seconds if x > 0
-			if guildSettings[guild.ID].SwearFilter.WarningDeleteTimeout > 0 {
-				timer := time.NewTimer(guildSettings[guild.ID].SwearFilter.WarningDeleteTimeout * time.Second)
+			if settings.SwearFilter.WarningDeleteTimeout > 0 {
+				timer := time.NewTimer(settings.SwearFilter.WarningDeleteTimeout * time.Second)
 				<-timer.C
 				session.ChannelMessageDelete(msgWarning.ChannelID, msgWarning.ID)
 			}
@@ -259,43 +230,158 @@ func handleMessage(session *discordgo.Session, message *discordgo.Message, updat
 		}
 	}
 
-	if responseEmbed == InternalEmbedActionCompleted {
-		return
+	if !response.IsEmpty() {
+		guildData.Get(guild.ID).Lock()
+		sendCommandResponse(session, message, channel, guild, guild.ID, response, updatedMessageEvent)
+		stateSaveGuild(guild.ID, message.Author.ID) //Save what the interaction changed
+		guildData.Get(guild.ID).Unlock()
+	}
+}
+
+// handleDirectMessage handles a message sent to the bot in a direct message, where there is no guild or member
+//
+// Data that would normally be stored per guild, such as queries and conversations, is stored under the channel ID instead.
+func handleDirectMessage(session *discordgo.Session, message *discordgo.Message, channel *discordgo.Channel, updatedMessageEvent bool) {
+	content := message.Content
+	if content == "" {
+		return //The message was empty
 	}
 
-	if responseEmbed != nil {
-		fixedEmbed := Embed{responseEmbed}
-		fixedEmbed.Truncate()
-		responseEmbed = fixedEmbed.MessageEmbed
+	//Initialize various datapoints
+	initializeGuildData(channel.ID)
+	initializeUserSettings(message.Author.ID)
 
-		canUpdateMessage := false
-		responseID := ""
+	//The response that will be sent off to Discord
+	var response *CommandResponse
 
-		if guildData[guild.ID].Queries != nil {
-			if guildData[guild.ID].Queries[message.ID] != nil {
-				canUpdateMessage = true
-				responseID = guildData[guild.ID].Queries[message.ID].ResponseMessageID
-			} else {
-				guildData[guild.ID].Queries[message.ID] = &Query{}
-			}
-		} else {
-			guildData[guild.ID].Queries = make(map[string]*Query)
-			guildData[guild.ID].Queries[message.ID] = &Query{}
+	debugMessage(session, message, channel, nil, updatedMessageEvent)
+
+	prefix := ""
+	if strings.HasPrefix(content, getBotData().CommandPrefix) {
+		prefix = getBotData().CommandPrefix
+	}
+
+	if prefix != "" {
+		commandEnvironment := &CommandEnvironment{Channel: channel, Message: message, User: message.Author, BotPrefix: prefix, UpdatedMessageEvent: updatedMessageEvent}
+		response = callCommandLine(strings.TrimPrefix(content, prefix), commandEnvironment, false)
+	} else if getBotData().BotOptions.UseWolframAlpha || getBotData().BotOptions.UseDuckDuckGo || getBotData().BotOptions.UseCustomResponses {
+		//Everything sent in a direct message is meant for the bot, so treat anything that isn't a command as a query
+		typingEvent(session, message.ChannelID, updatedMessageEvent)
+
+		query := trimMentionQuery(content, session.State.User.ID)
+
+		commandEnvironment := &CommandEnvironment{Channel: channel, Message: message, User: message.Author, BotPrefix: getBotData().CommandPrefix, UpdatedMessageEvent: updatedMessageEvent}
+		response = runQuery(session, query, commandEnvironment, channel.ID)
+	}
+
+	if !response.IsEmpty() {
+		guildData.Get(channel.ID).Lock()
+		sendCommandResponse(session, message, channel, nil, channel.ID, response, updatedMessageEvent)
+		stateSaveGuild(channel.ID, message.Author.ID) //Save what the interaction changed
+		guildData.Get(channel.ID).Unlock()
+	}
+}
+
+// runQuery runs a natural language query on one of the guild's workers, where dataID is the key of the guild data to keep conversations in
+func runQuery(session *discordgo.Session, query string, env *CommandEnvironment, dataID string) *CommandResponse {
+	return runCommandWorker(env, QueryTimeout, false, func(env *CommandEnvironment) *CommandResponse {
+		return NewEmbedResponse(handleQuery(session, query, env, dataID))
+	})
+}
+
+// handleQuery returns the response to a natural language query, where dataID is the key of the guild data to keep conversations in
+func handleQuery(session *discordgo.Session, query string, env *CommandEnvironment, dataID string) *discordgo.MessageEmbed {
+	responseEmbed := callNLP(query, env)
+
+	if responseEmbed == nil {
+		responseEmbed = checkCooldown("query", &QueryCooldown, env)
+	}
+	if responseEmbed == nil {
+		typingEvent(session, env.Channel.ID, env.UpdatedMessageEvent)
+
+		previousConversation := guildData.Get(dataID).GetWolframConversation(env.User.ID)
+
+		queryEnvironment := &QueryEnvironment{Channel: env.Channel, Guild: env.Guild, Message: env.Message, User: env.User, Member: env.Member, BotPrefix: env.BotPrefix, WolframConversation: previousConversation}
+		queryEmbed, err := getQueryResult(query, queryEnvironment)
+		if err != nil {
+			return NewErrorEmbed(env.Locale(), "Query Error", "We couldn't find a service to handle your query.\nMake sure you're using proper grammar and query structure where applicable.")
 		}
+		responseEmbed = queryEmbed
+	}
+	return responseEmbed
+}
 
-		if canUpdateMessage {
-			session.ChannelMessageEditEmbed(message.ChannelID, responseID, responseEmbed)
-			debugEmbed(responseEmbed, botData.DiscordSession.State.User, channel, guild, updatedMessageEvent)
+// trimMentionQuery removes mentions of the bot and any leading separators from a query
+func trimMentionQuery(query, botID string) string {
+	query = strings.Replace(query, "<@!"+botID+">", "", -1)
+	query = strings.Replace(query, "<@"+botID+">", "", -1)
+	for {
+		if strings.HasPrefix(query, " ") {
+			query = strings.Replace(query, " ", "", 1)
+		} else if strings.HasPrefix(query, ",") {
+			query = strings.Replace(query, ",", "", 1)
+		} else if strings.HasPrefix(query, ":") {
+			query = strings.Replace(query, ":", "", 1)
 		} else {
-			typingEvent(session, message.ChannelID, updatedMessageEvent)
+			break
+		}
+	}
+	return query
+}
 
-			responseMessage, err := session.ChannelMessageSendEmbed(message.ChannelID, responseEmbed)
-			if err == nil {
-				debugEmbed(responseEmbed, botData.DiscordSession.State.User, channel, guild, updatedMessageEvent)
-				guildData[guild.ID].Queries[message.ID].ResponseMessageID = responseMessage.ID
+// splitCommand splits a command message into the command name and its arguments, keeping quoted arguments together
+func splitCommand(cmdMsg string) []string {
+	cmd := strings.Split(cmdMsg, " ")
+
+	//0>>>>>>-1>>>>>-2>>>>>>>>>>>>>>>>>>-3>>>>>>>>>>
+	//spotify search "dance gavin dance" bloodsucker
+	//0: spotify
+	//1: search
+	//2: dance gavin dance
+	//3: bloodsucker
+	newCmd := make([]string, 0)
+	for i := 0; i < len(cmd); i++ {
+		if strings.HasPrefix(cmd[i], "\"") && !strings.HasPrefix(cmd[i], "\"\"") {
+			for j := i; j < len(cmd); j++ {
+				if strings.HasSuffix(cmd[j], "\"") && !strings.HasSuffix(cmd[j], "\"\"") {
+					newArg := strings.Join(cmd[i:j+1], " ")
+					newArg = strings.TrimPrefix(newArg, "\"")
+					newArg = strings.TrimSuffix(newArg, "\"")
+					newCmd = append(newCmd, newArg)
+					i = j
+					break
+				}
 			}
+		} else {
+			newCmd = append(newCmd, cmd[i])
 		}
+	}
+	if len(newCmd) > 0 {
+		cmd = newCmd
+	}
+	return cmd
+}
 
-		stateSaveAll() //Save the state after every interaction
+// commandLineArguments returns the text of a command message after its first count arguments, as it was written
+//
+// Arguments are counted the same way splitCommand splits them, but the quotes around them are kept.
+func commandLineArguments(cmdMsg string, count int) string {
+	cmd := strings.Split(cmdMsg, " ")
+	for i := 0; i < len(cmd); i++ {
+		if count == 0 {
+			return strings.Join(cmd[i:], " ")
+		}
+		if strings.HasPrefix(cmd[i], "\"") && !strings.HasPrefix(cmd[i], "\"\"") {
+			for j := i; j < len(cmd); j++ {
+				if strings.HasSuffix(cmd[j], "\"") && !strings.HasSuffix(cmd[j], "\"\"") {
+					i = j
+					count--
+					break
+				}
+			}
+		} else {
+			count--
+		}
 	}
+	return ""
 }
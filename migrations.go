@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// StateMigration upgrades the state from the previous schema version to Version
+type StateMigration struct {
+	Version     int                                      //The schema version the state is at once this migration has ran
+	Description string                                   //What the migration changes, for logs and errors
+	Buckets     []string                                 //The buckets holding the entries to migrate
+	Migrate     func(entry map[string]interface{}) error //Changes a decoded entry in place, where each reminder is its own entry
+}
+
+// StateMigrations contains every migration in the order they must run, with each one's version following the last
+//
+// A migration must never be changed or removed once released, as the state of every bot that ran it was changed by it.
+// Fields that are renamed or restructured need a new migration that moves the old data to where the new code looks for it.
+var StateMigrations = []*StateMigration{
+	{
+		Version:     1,
+		Description: "store whether to send Now Playing embeds under autoSendNowPlaying instead of disableNowPlaying",
+		Buckets:     []string{StateBucketGuildSettings},
+		Migrate: func(entry map[string]interface{}) error {
+			return renameStateField(entry, "disableNowPlaying", "autoSendNowPlaying")
+		},
+	},
+	{
+		Version:     2,
+		Description: "move the single botPrefix into botPrefixes",
+		Buckets:     []string{StateBucketGuildSettings},
+		Migrate: func(entry map[string]interface{}) error {
+			prefix, exists := entry["botPrefix"]
+			if !exists {
+				return nil
+			}
+			delete(entry, "botPrefix")
+			if prefixes, exists := entry["botPrefixes"].([]interface{}); exists && len(prefixes) > 0 {
+				return nil //The prefixes already overrode the single prefix
+			}
+			if prefix, ok := prefix.(string); ok && prefix != "" {
+				entry["botPrefixes"] = []interface{}{prefix}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     3,
+		Description: "convert starboard entries from a map of message IDs into a list",
+		Buckets:     []string{StateBucketStarboards},
+		Migrate: func(entry map[string]interface{}) error {
+			oldEntries, isMap := entry["StarboardEntries"].(map[string]interface{})
+			if !isMap {
+				return nil
+			}
+
+			//Only the message IDs were kept, so the entries are assumed to be in the current starboard channel
+			channelID, _ := entry["ChannelID"].(string)
+			newEntries := make([]interface{}, 0)
+			for sourceMessageID, starboardMessageID := range oldEntries {
+				starboardMessageID, ok := starboardMessageID.(string)
+				if !ok {
+					return fmt.Errorf("starboard entry for message %s is not a message ID", sourceMessageID)
+				}
+				newEntries = append(newEntries, map[string]interface{}{
+					"SourceMessageID":    sourceMessageID,
+					"StarboardChannelID": channelID,
+					"StarboardMessageID": starboardMessageID,
+				})
+			}
+			entry["StarboardEntries"] = newEntries
+			return nil
+		},
+	},
+}
+
+// StateSchemaVersion contains the schema version of the state written by this build
+var StateSchemaVersion = StateMigrations[len(StateMigrations)-1].Version
+
+// renameStateField moves the value of a field to a new name, unless a value was already stored under the new name
+func renameStateField(entry map[string]interface{}, oldName, newName string) error {
+	value, exists := entry[oldName]
+	if !exists {
+		return nil
+	}
+	delete(entry, oldName)
+	if _, exists := entry[newName]; !exists {
+		entry[newName] = value
+	}
+	return nil
+}
+
+// stateMigrate runs every migration newer than the schema version of the state, backing the state up first
+func stateMigrate() error {
+	version := 0
+	if data, exists := stateGetMeta(StateKeySchemaVersion); exists {
+		if err := json.Unmarshal(data, &version); err != nil {
+			return fmt.Errorf("state: invalid schema version %s: %v", data, err)
+		}
+	}
+	if version > StateSchemaVersion {
+		return fmt.Errorf("state: schema version %d is newer than the %d this build supports, refusing to load it so it isn't overwritten", version, StateSchemaVersion)
+	}
+	if version == StateSchemaVersion {
+		return nil
+	}
+
+	if stateIsEmpty() {
+		return stateSetMeta(StateKeySchemaVersion, StateSchemaVersion) //Nothing to migrate
+	}
+
+	backupName := "schema-v" + strconv.Itoa(version) + "-" + time.Now().Format("20060102-150405")
+	backupPath, err := stateStore.Backup(StateBackupDir, backupName)
+	if err != nil {
+		return fmt.Errorf("state: unable to back up the state to %s before migrating, leaving it at schema version %d: %v", backupPath, version, err)
+	}
+	Info.Printf("Backed up the state to %s, migrating from schema version %d to %d...\n", backupPath, version, StateSchemaVersion)
+
+	for _, migration := range StateMigrations {
+		if migration.Version <= version {
+			continue
+		}
+		Info.Printf("> Migrating the state to schema version %d: %s\n", migration.Version, migration.Description)
+		for _, bucket := range migration.Buckets {
+			if err := stateMigrateBucket(bucket, migration); err != nil {
+				return fmt.Errorf("state: migration to schema version %d (%s) failed, the state before migrating was backed up to %s: %v", migration.Version, migration.Description, backupPath, err)
+			}
+		}
+		if err := stateSetMeta(StateKeySchemaVersion, migration.Version); err != nil {
+			return fmt.Errorf("state: unable to save schema version %d, the state before migrating was backed up to %s: %v", migration.Version, backupPath, err)
+		}
+	}
+	return nil
+}
+
+// stateMigrateBucket runs a migration on every entry of a bucket, saving the entries in a single write once they've all been migrated
+func stateMigrateBucket(bucket string, migration *StateMigration) error {
+	migrated := make(map[string][]byte)
+	err := stateStore.Load(bucket, func(key string, data []byte) error {
+		var entry interface{}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return fmt.Errorf("%s/%s: %v", bucket, key, err)
+		}
+
+		//Buckets holding a list, such as reminders, have each item migrated on its own
+		entries := []interface{}{entry}
+		if list, isList := entry.([]interface{}); isList {
+			entries = list
+		}
+		for _, item := range entries {
+			fields, isObject := item.(map[string]interface{})
+			if !isObject {
+				continue
+			}
+			if err := migration.Migrate(fields); err != nil {
+				return fmt.Errorf("%s/%s: %v", bucket, key, err)
+			}
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %v", bucket, key, err)
+		}
+		migrated[key] = data
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(migrated) == 0 {
+		return nil
+	}
+	return stateStore.Save(bucket, migrated)
+}
+
+// stateIsEmpty returns whether or not every bucket other than the meta bucket is empty
+func stateIsEmpty() bool {
+	empty := true
+	for _, bucket := range StateBuckets {
+		if bucket == StateBucketMeta {
+			continue
+		}
+		stateStore.Load(bucket, func(key string, data []byte) error {
+			empty = false
+			return errStateNotEmpty //Stop at the first entry
+		})
+		if !empty {
+			return false
+		}
+	}
+	return true
+}
@@ -40,64 +40,66 @@ type NLP struct {
 	RegexReplace   string         //The replacement scheme to follow for pulling arguments
 }
 
-func initNLPCommands() {
+func initNLPCommands(config *BotData) {
+	config.NLPCommands = make([]*CommandNLP, 0)
+
 	//@Clinet Play Dance Gavin Dance on Spotify
-	addNLPCommand(nlpNew("spotify", "search", "", regexp.MustCompile("(?i)(?:.*)(?:play|listen to)(?:\\s)(.*)(?:\\s)(?:from Spotify|on Spotify)(?:.*)"), nil, nil, "${1}"),
+	addNLPCommand(config, nlpNew("spotify", "search", "", regexp.MustCompile("(?i)(?:.*)(?:play|listen to)(?:\\s)(.*)(?:\\s)(?:from Spotify|on Spotify)(?:.*)"), nil, nil, "${1}"),
 		nlpNew("spotify", "play", "", regexp.MustCompile("(?i)(?:.*)(?:play|listen to)(?:\\s)(.*)(?:\\s)(?:from Spotify|on Spotify)(?:.*)"), nil, nil, "1"))
 
 	//@Clinet Play Dance Gavin Dance
-	addNLPCommand(nlpNew("play", "", "", regexp.MustCompile("(?i)(?:.*)(?:play|listen to)(?:\\s)(.*)"), nil, nil, ""))
+	addNLPCommand(config, nlpNew("play", "", "", regexp.MustCompile("(?i)(?:.*)(?:play|listen to)(?:\\s)(.*)"), nil, nil, ""))
 
 	//@Clinet Remove the 1st entry from the queue
-	addNLPCommand(nlpNew("queue", "remove", "", regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)(\\b\\d+)(?:.*)(?:queue)(?:.*)"), nil, regexp.MustCompile("(\\b\\d+)"), ""))
+	addNLPCommand(config, nlpNew("queue", "remove", "", regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)(\\b\\d+)(?:.*)(?:queue)(?:.*)"), nil, regexp.MustCompile("(\\b\\d+)"), ""))
 
 	//@Clinet Can the queue be cleared?
-	addNLPCommand(nlpNew("queue", "clear", "", regexp.MustCompile("(?i)(?:.*)(?:queue)(?:.*)(?:clear)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("queue", "clear", "", regexp.MustCompile("(?i)(?:.*)(?:queue)(?:.*)(?:clear)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Clear the queue
-	addNLPCommand(nlpNew("queue", "clear", "", regexp.MustCompile("(?i)(?:.*)(?:clear)(?:.*)(?:queue)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("queue", "clear", "", regexp.MustCompile("(?i)(?:.*)(?:clear)(?:.*)(?:queue)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet List the 1st page of the queue
-	addNLPCommand(nlpNew("queue", "", "", regexp.MustCompile("(?i)(?:.*)(\\d+)(?:.*)(?:queue)(?:.*)"), regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)"), regexp.MustCompile("(\\d+)"), ""))
+	addNLPCommand(config, nlpNew("queue", "", "", regexp.MustCompile("(?i)(?:.*)(\\d+)(?:.*)(?:queue)(?:.*)"), regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)"), regexp.MustCompile("(\\d+)"), ""))
 
 	//@Clinet List the queue entries
-	addNLPCommand(nlpNew("queue", "", "", regexp.MustCompile("(?i)(?:.*)(?:queue)(?:.*)"), regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)"), nil, "${1}"))
+	addNLPCommand(config, nlpNew("queue", "", "", regexp.MustCompile("(?i)(?:.*)(?:queue)(?:.*)"), regexp.MustCompile("(?i)(?:.*)(?:remove|delete)(?:.*)"), nil, "${1}"))
 
 	//@Clinet Skip this song
-	addNLPCommand(nlpNew("skip", "", "", regexp.MustCompile("(?i)(?:.*)(?:skip|next)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("skip", "", "", regexp.MustCompile("(?i)(?:.*)(?:skip|next)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Stop the playback
-	addNLPCommand(nlpNew("stop", "", "", regexp.MustCompile("(?i)(?:.*)(?:stop)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("stop", "", "", regexp.MustCompile("(?i)(?:.*)(?:stop)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Pause the song
-	addNLPCommand(nlpNew("pause", "", "", regexp.MustCompile("(?i)(?:.*)(?:pause)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("pause", "", "", regexp.MustCompile("(?i)(?:.*)(?:pause)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Resume the playback
-	addNLPCommand(nlpNew("resume", "", "", regexp.MustCompile("(?i)(?:.*)(?:resume)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("resume", "", "", regexp.MustCompile("(?i)(?:.*)(?:resume)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet What are the lyrics for this song?
-	addNLPCommand(nlpNew("lyrics", "", "", regexp.MustCompile("(?i)(?:.*)(?:lyrics)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("lyrics", "", "", regexp.MustCompile("(?i)(?:.*)(?:lyrics)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Set a reminder to add some cool stuff in 1 hour
-	addNLPCommand(nlpNew("remind", "", "", regexp.MustCompile("(?i)(.*)(?:.*)(remind me|set a reminder)(.*)"), nil, nil, ""))
+	addNLPCommand(config, nlpNew("remind", "", "", regexp.MustCompile("(?i)(.*)(?:.*)(remind me|set a reminder)(.*)"), nil, nil, ""))
 
 	//@Clinet What are my reminders?
-	addNLPCommand(nlpNew("remind", "list", "", regexp.MustCompile("(?i)(?:.*)(?:reminders)(?:.*)"), nil, nil, "${1}"))
+	addNLPCommand(config, nlpNew("remind", "list", "", regexp.MustCompile("(?i)(?:.*)(?:reminders)(?:.*)"), nil, nil, "${1}"))
 
 	//@Clinet Take a screensot of https://google.com/ please
-	addNLPCommand(nlpNew("screenshot", "", "", regexp.MustCompile("(?i)(?:.*)(?:screenshot)(?:.*)"), nil, regexp.MustCompile("((http|https)://([\\w_-]+(?:(?:\\.[\\w_-]+)+)[\\w.,@?^=%&:/~+#-]*[\\w@?^=%&/~+#-])?)"), ""))
+	addNLPCommand(config, nlpNew("screenshot", "", "", regexp.MustCompile("(?i)(?:.*)(?:screenshot)(?:.*)"), nil, regexp.MustCompile("((http|https)://([\\w_-]+(?:(?:\\.[\\w_-]+)+)[\\w.,@?^=%&:/~+#-]*[\\w@?^=%&/~+#-])?)"), ""))
 }
 
 func nlpNew(command, argPrefix, argSuffix string, regex, regexBadMatch, regexArguments *regexp.Regexp, regexReplace string) *NLP {
 	return &NLP{Command: command, ArgPrefix: argPrefix, ArgSuffix: argSuffix, Regex: regex, RegexBadMatch: regexBadMatch, RegexArguments: regexArguments, RegexReplace: regexReplace}
 }
 
-func addNLPCommand(nlp ...*NLP) {
-	botData.NLPCommands = append(botData.NLPCommands, &CommandNLP{Commands: nlp})
+func addNLPCommand(config *BotData, nlp ...*NLP) {
+	config.NLPCommands = append(config.NLPCommands, &CommandNLP{Commands: nlp})
 }
 
 func callNLP(message string, env *CommandEnvironment) *discordgo.MessageEmbed {
-	for i, command := range botData.NLPCommands {
+	for i, command := range getBotData().NLPCommands {
 		for j := 0; j < len(command.Commands); j++ {
 			Debug.Printf("Testing NLP %d, command %d...", i, j)
 
@@ -117,7 +119,7 @@ func callNLP(message string, env *CommandEnvironment) *discordgo.MessageEmbed {
 				}
 			}
 
-			if _, exists := botData.Commands[nlp.Command]; !exists {
+			if _, exists := getBotData().Commands[nlp.Command]; !exists {
 				break
 			}
 
@@ -171,7 +173,7 @@ func callNLP(message string, env *CommandEnvironment) *discordgo.MessageEmbed {
 				}
 				return embed
 			} else {
-				if strings.Contains(embed.Title, "Error") {
+				if isErrorEmbed(embed) {
 					return embed
 				}
 			}
@@ -156,6 +156,15 @@ func remove(s []string, r string) []string {
 	return s
 }
 
+func containsString(s []string, r string) bool {
+	for _, v := range s {
+		if v == r {
+			return true
+		}
+	}
+	return false
+}
+
 // GetStringInBetween returns empty string if no start string found
 func GetStringInBetween(str string, start string, end string) (result string) {
 	s := strings.Index(str, start)
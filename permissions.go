@@ -0,0 +1,253 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CommandPermissions holds the roles, channels and users a command is allowed or denied for in a guild
+type CommandPermissions struct {
+	AllowedRoles    []string `json:"allowedRoles,omitempty"`    //An array of role IDs that may use the command regardless of its required permissions
+	DeniedRoles     []string `json:"deniedRoles,omitempty"`     //An array of role IDs that may not use the command unless another role allows it
+	AllowedChannels []string `json:"allowedChannels,omitempty"` //An array of channel IDs the command is restricted to, if any
+	DeniedChannels  []string `json:"deniedChannels,omitempty"`  //An array of channel IDs the command may not be used in
+	AllowedUsers    []string `json:"allowedUsers,omitempty"`    //An array of user IDs that may always use the command
+	DeniedUsers     []string `json:"deniedUsers,omitempty"`     //An array of user IDs that may never use the command
+}
+
+// IsEmpty returns whether or not the command permissions have no rules
+func (permissions *CommandPermissions) IsEmpty() bool {
+	return len(permissions.AllowedRoles) == 0 && len(permissions.DeniedRoles) == 0 &&
+		len(permissions.AllowedChannels) == 0 && len(permissions.DeniedChannels) == 0 &&
+		len(permissions.AllowedUsers) == 0 && len(permissions.DeniedUsers) == 0
+}
+
+// Copy returns a copy of the command permissions that doesn't share any lists with the original
+func (permissions *CommandPermissions) Copy() *CommandPermissions {
+	return &CommandPermissions{
+		AllowedRoles:    append([]string{}, permissions.AllowedRoles...),
+		DeniedRoles:     append([]string{}, permissions.DeniedRoles...),
+		AllowedChannels: append([]string{}, permissions.AllowedChannels...),
+		DeniedChannels:  append([]string{}, permissions.DeniedChannels...),
+		AllowedUsers:    append([]string{}, permissions.AllowedUsers...),
+		DeniedUsers:     append([]string{}, permissions.DeniedUsers...),
+	}
+}
+
+// Allow allows the specified ID in the specified list, removing it from the matching denied list
+func (permissions *CommandPermissions) Allow(targetType, targetID string) {
+	permissions.Clear(targetType, targetID)
+	switch targetType {
+	case "role":
+		permissions.AllowedRoles = append(permissions.AllowedRoles, targetID)
+	case "channel":
+		permissions.AllowedChannels = append(permissions.AllowedChannels, targetID)
+	case "user":
+		permissions.AllowedUsers = append(permissions.AllowedUsers, targetID)
+	}
+}
+
+// Deny denies the specified ID in the specified list, removing it from the matching allowed list
+func (permissions *CommandPermissions) Deny(targetType, targetID string) {
+	permissions.Clear(targetType, targetID)
+	switch targetType {
+	case "role":
+		permissions.DeniedRoles = append(permissions.DeniedRoles, targetID)
+	case "channel":
+		permissions.DeniedChannels = append(permissions.DeniedChannels, targetID)
+	case "user":
+		permissions.DeniedUsers = append(permissions.DeniedUsers, targetID)
+	}
+}
+
+// Clear removes the specified ID from both the allowed and denied lists
+func (permissions *CommandPermissions) Clear(targetType, targetID string) {
+	switch targetType {
+	case "role":
+		permissions.AllowedRoles = remove(permissions.AllowedRoles, targetID)
+		permissions.DeniedRoles = remove(permissions.DeniedRoles, targetID)
+	case "channel":
+		permissions.AllowedChannels = remove(permissions.AllowedChannels, targetID)
+		permissions.DeniedChannels = remove(permissions.DeniedChannels, targetID)
+	case "user":
+		permissions.AllowedUsers = remove(permissions.AllowedUsers, targetID)
+		permissions.DeniedUsers = remove(permissions.DeniedUsers, targetID)
+	}
+}
+
+// isBotAdmin returns whether or not the user has been granted bot admin rights in the guild, either directly or through a role
+func isBotAdmin(guildID, userID string, member *discordgo.Member) bool {
+	if userID == getBotData().BotOwnerID {
+		return true
+	}
+	isAdmin := false
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		if containsString(settings.BotAdminUsers, userID) {
+			isAdmin = true
+			return
+		}
+		if member != nil {
+			for _, roleID := range member.Roles {
+				if containsString(settings.BotAdminRoles, roleID) {
+					isAdmin = true
+					return
+				}
+			}
+		}
+	})
+	return isAdmin
+}
+
+// isGuildAdmin returns whether or not the user has the administrator permission in the guild
+func isGuildAdmin(env *CommandEnvironment) bool {
+	if env.User.ID == getBotData().BotOwnerID {
+		return true
+	}
+	isAdmin, _ := MemberHasPermission(getBotData().DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, discordgo.PermissionAdministrator)
+	return isAdmin
+}
+
+// isDenyExempt returns whether or not the user in the environment ignores the user and role deny rules of a command
+//
+// Guild administrators ignore them for settings commands, so bot admins can't lock them out of managing their own server.
+func isDenyExempt(command *Command, env *CommandEnvironment) bool {
+	return command.Category == CommandCategorySettings && isGuildAdmin(env)
+}
+
+// checkCommandPermissions returns an error embed if the user in the environment may not use the command, otherwise nil
+//
+// Rules are checked from most to least specific: denied/allowed users, then channels, then denied/allowed roles,
+// and finally the command's required permissions, which bot admins always satisfy.
+func checkCommandPermissions(commandName string, command *Command, env *CommandEnvironment) *discordgo.MessageEmbed {
+	if command.IsAdministrative && env.User.ID != getBotData().BotOwnerID {
+		return NewErrorEmbed(env.Locale(), "Command Error - Not Authorized (NA)", "I'm sorry Dave, I'm afraid I can't do that.")
+	}
+	if env.User.ID == getBotData().BotOwnerID || env.Guild == nil {
+		return nil
+	}
+
+	var permissions *CommandPermissions
+	guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+		if rules, exists := settings.CommandPermissions[commandName]; exists {
+			permissions = rules.Copy()
+		}
+	})
+	if permissions != nil {
+		if containsString(permissions.DeniedUsers, env.User.ID) && !isDenyExempt(command, env) {
+			return NewErrorEmbed(env.Locale(), "Command Error - Restricted (RS)", "You aren't allowed to use ``"+commandName+"`` in this server.")
+		}
+		if containsString(permissions.AllowedUsers, env.User.ID) {
+			return nil
+		}
+
+		if containsString(permissions.DeniedChannels, env.Channel.ID) || (len(permissions.AllowedChannels) > 0 && !containsString(permissions.AllowedChannels, env.Channel.ID)) {
+			return NewErrorEmbed(env.Locale(), "Command Error - Restricted (RS)", "``%s`` can't be used in this channel.", commandName)
+		}
+
+		if env.Member != nil {
+			roleAllowed, roleDenied := false, false
+			for _, roleID := range env.Member.Roles {
+				if containsString(permissions.AllowedRoles, roleID) {
+					roleAllowed = true
+				}
+				if containsString(permissions.DeniedRoles, roleID) {
+					roleDenied = true
+				}
+			}
+			if roleAllowed {
+				return nil
+			}
+			if roleDenied && !isDenyExempt(command, env) {
+				return NewErrorEmbed(env.Locale(), "Command Error - Restricted (RS)", "None of your roles are allowed to use ``"+commandName+"``.")
+			}
+		}
+	}
+
+	if command.RequiredPermissions != 0 && !isBotAdmin(env.Guild.ID, env.User.ID, env.Member) {
+		if permissionsAllowed, _ := MemberHasPermission(getBotData().DiscordSession, env.Guild.ID, env.User.ID, env.Channel.ID, command.RequiredPermissions); permissionsAllowed == false {
+			return NewErrorEmbed(env.Locale(), "Command Error - No Permissions (NP)", "Just what do you think you're doing, Dave?")
+		}
+	}
+	return nil
+}
+
+// resolvePermissionTarget resolves a value to a channel, role or user ID, returning the type of target found
+func resolvePermissionTarget(value string, env *CommandEnvironment) (targetType, targetID string) {
+	if regexpArgChannel.MatchString(value) {
+		if channel, err := resolveArgumentChannel(value, env); err == nil {
+			return "channel", channel.ID
+		}
+	}
+	if role, err := resolveArgumentRole(value, env); err == nil {
+		return "role", role.ID
+	}
+	if channel, err := resolveArgumentChannel(value, env); err == nil {
+		return "channel", channel.ID
+	}
+	if user, err := resolveArgumentUser(value, env); err == nil {
+		return "user", user.ID
+	}
+	return "", ""
+}
+
+// formatPermissionTarget returns a mention for the specified target
+func formatPermissionTarget(targetType, targetID string) string {
+	switch targetType {
+	case "role":
+		return "<@&" + targetID + ">"
+	case "channel":
+		return "<#" + targetID + ">"
+	}
+	return "<@" + targetID + ">"
+}
+
+// isCategoryToggleable returns whether or not a command category may be disabled or restricted to channels by a guild
+func isCategoryToggleable(category string) bool {
+	return category != CommandCategorySettings && category != CommandCategoryAdministrative
+}
+
+// isCategoryDisabled returns whether or not a command category has been disabled in the guild
+func isCategoryDisabled(settings *GuildSettings, category string) bool {
+	if category == CommandCategoryVoice {
+		return settings.AllowVoice != nil && !*settings.AllowVoice
+	}
+	return containsString(settings.DisabledCategories, category)
+}
+
+// isCommandDisabled returns whether or not a command has been disabled in the guild, either by name or by category
+func isCommandDisabled(guildID, commandName string, command *Command) bool {
+	if !isCategoryToggleable(command.Category) {
+		return false
+	}
+	disabled := false
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		disabled = containsString(settings.DisabledCommands, commandName) || isCategoryDisabled(settings, command.Category)
+	})
+	return disabled
+}
+
+// checkCommandEnabled returns an error embed if the command is disabled in the guild or its category is restricted to other channels, otherwise nil
+func checkCommandEnabled(commandName string, command *Command, env *CommandEnvironment) *discordgo.MessageEmbed {
+	if env.Guild == nil {
+		return nil
+	}
+	if isCommandDisabled(env.Guild.ID, commandName, command) {
+		return NewErrorEmbed(env.Locale(), "Command Error - Disabled (DC)", "``%s`` has been disabled in this server.", commandName)
+	}
+	if !isCategoryToggleable(command.Category) {
+		return nil
+	}
+	var channels []string
+	guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+		channels = append(channels, settings.CategoryChannels[command.Category]...)
+	})
+	if len(channels) > 0 && !containsString(channels, env.Channel.ID) {
+		allowedChannels := make([]string, 0)
+		for _, channelID := range channels {
+			allowedChannels = append(allowedChannels, formatPermissionTarget("channel", channelID))
+		}
+		return NewErrorEmbed(env.Locale(), "Command Error - Restricted (RS)", "``%s`` can only be used in %s.", commandName, strings.Join(allowedChannels, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// PipelineMaxSteps contains how many commands may be chained together in one message
+	PipelineMaxSteps = 5
+)
+
+// PipelineStep holds a single command of a pipeline
+type PipelineStep struct {
+	Command []string //The command name followed by its arguments
+	Line    string   //The command and its arguments as they were written
+	Piped   bool     //Whether or not the output of the previous step is piped into this step, rather than ran after it
+}
+
+// PipeInput holds the output of a command that was piped into the next command
+type PipeInput struct {
+	Text      string            //The text content of the output, or the description of its first embed if there was no content
+	ImageURLs []string          //The URLs of images displayed by the output's embeds
+	Files     []*discordgo.File //The files attached to the output
+}
+
+// NewPipeInput returns the output of a response in a form the next command in a pipeline can use, or nil if there is none
+func NewPipeInput(response *CommandResponse) *PipeInput {
+	if response.IsEmpty() {
+		return nil
+	}
+
+	input := &PipeInput{Text: response.Content, Files: response.Files}
+	for _, embed := range response.Embeds {
+		if input.Text == "" {
+			input.Text = embed.Description
+		}
+		if embed.Image != nil && embed.Image.URL != "" && !strings.HasPrefix(embed.Image.URL, "attachment://") {
+			input.ImageURLs = append(input.ImageURLs, embed.Image.URL)
+		}
+		if embed.Thumbnail != nil && embed.Thumbnail.URL != "" && !strings.HasPrefix(embed.Thumbnail.URL, "attachment://") {
+			input.ImageURLs = append(input.ImageURLs, embed.Thumbnail.URL)
+		}
+	}
+	return input
+}
+
+// splitPipeline splits a command message on each ; and | that isn't in quotes
+//
+// Returns nil if the message isn't a pipeline, which is also the case when any part after the first
+// doesn't start with a known command, so arguments that happen to contain a ; or | still work.
+func splitPipeline(cmdMsg string, env *CommandEnvironment, caseInsensitive bool) []*PipelineStep {
+	if !strings.ContainsAny(cmdMsg, ";|") {
+		return nil
+	}
+
+	parts := make([]string, 0)
+	piped := []bool{false}
+	inQuotes := false
+	last := 0
+	for i, char := range cmdMsg {
+		switch char {
+		case '"':
+			inQuotes = !inQuotes
+		case ';', '|':
+			if inQuotes {
+				continue
+			}
+			parts = append(parts, cmdMsg[last:i])
+			piped = append(piped, char == '|')
+			last = i + 1
+		}
+	}
+	parts = append(parts, cmdMsg[last:])
+	if len(parts) < 2 {
+		return nil
+	}
+
+	steps := make([]*PipelineStep, 0)
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if piped[i] {
+				return nil //Nothing to pipe into
+			}
+			continue //Allow a trailing ;
+		}
+		if piped[i] && len(steps) == 0 {
+			return nil //Nothing to pipe from
+		}
+
+		cmd := splitCommand(part)
+		if caseInsensitive {
+			cmd[0] = strings.ToLower(cmd[0])
+		}
+		if i > 0 && !isKnownCommand(cmd[0], env) {
+			return nil
+		}
+		steps = append(steps, &PipelineStep{Command: cmd, Line: part, Piped: piped[i]})
+	}
+	if len(steps) < 2 {
+		return nil
+	}
+	return steps
+}
+
+// isKnownCommand returns whether or not a command, alias or custom command exists with the specified name
+func isKnownCommand(commandName string, env *CommandEnvironment) bool {
+	if _, exists := getBotData().Commands[commandName]; exists {
+		return true
+	}
+	if env.Guild != nil {
+		if _, exists := getGuildCustomCommand(env.Guild.ID, strings.ToLower(commandName)); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// callCommandLine runs a command message without its prefix, which may be a pipeline of several commands
+func callCommandLine(cmdMsg string, env *CommandEnvironment, caseInsensitive bool) *CommandResponse {
+	if steps := splitPipeline(cmdMsg, env, caseInsensitive); steps != nil {
+		return runPipeline(steps, env)
+	}
+
+	cmd := splitCommand(cmdMsg)
+	if caseInsensitive {
+		cmd[0] = strings.ToLower(cmd[0])
+	}
+	env.Command = cmd[0]
+	env.CommandLine = cmdMsg
+	return callCommandResponse(cmd[0], cmd[1:], env)
+}
+
+// runPipeline runs each step of a pipeline in order, returning the combined output of every step that wasn't piped into another
+//
+// Every step is ran as its own command with its own permission checks, cooldowns and timeout.
+func runPipeline(steps []*PipelineStep, env *CommandEnvironment) *CommandResponse {
+	if len(steps) > PipelineMaxSteps {
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Pipeline Error", "You can only chain up to %d commands at once.", PipelineMaxSteps))
+	}
+
+	dataID := getEnvironmentDataID(env)
+	outputs := make([]*CommandResponse, 0)
+	var previous *CommandResponse
+	for i, step := range steps {
+		stepEnv := *env
+		stepEnv.Command = step.Command[0]
+		stepEnv.CommandLine = step.Line
+		stepEnv.Context = nil
+		stepEnv.Arguments = nil
+		stepEnv.Input = nil
+		args := step.Command[1:]
+
+		if step.Piped {
+			input := NewPipeInput(previous)
+			if input == nil {
+				return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Pipeline Error", "``"+steps[i-1].Command[0]+"`` had no output to pipe into ``"+step.Command[0]+"``."))
+			}
+			stepEnv.Input = input
+			if len(args) == 0 && input.Text != "" {
+				args = strings.Fields(input.Text) //Commands that weren't given arguments take the piped text as their arguments
+			}
+		} else if previous != nil && previous != InternalResponseActionCompleted {
+			outputs = append(outputs, previous)
+		}
+
+		previous = callCommandResponse(step.Command[0], args, &stepEnv)
+		if previous != nil && previous.FollowUp != nil {
+			//The final output is needed before moving on, so follow-ups can't be left to run in the background
+			if followUp := runFollowUp(dataID, previous.FollowUp); !followUp.IsEmpty() {
+				previous = followUp
+			} else {
+				previous.FollowUp = nil
+			}
+		}
+	}
+	if previous != nil && previous != InternalResponseActionCompleted {
+		outputs = append(outputs, previous)
+	}
+
+	switch len(outputs) {
+	case 0:
+		return nil
+	case 1:
+		return outputs[0]
+	}
+
+	response := &CommandResponse{}
+	contents := make([]string, 0)
+	for _, output := range outputs {
+		if output.Content != "" {
+			contents = append(contents, output.Content)
+		}
+		response.Embeds = append(response.Embeds, output.Embeds...)
+		response.Files = append(response.Files, output.Files...)
+		response.Reactions = append(response.Reactions, output.Reactions...)
+		response.Ephemeral = response.Ephemeral || output.Ephemeral
+		response.DirectMessage = response.DirectMessage || output.DirectMessage
+		if output.AllowedMentions != nil {
+			response.AllowedMentions = output.AllowedMentions
+		}
+	}
+	response.Content = strings.Join(contents, "\n")
+	return response
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// getGuildPrefixes returns the command prefixes to use in a guild, with the primary prefix first
+func getGuildPrefixes(guildID string) []string {
+	prefixes := []string{getBotData().CommandPrefix}
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		prefixes = guildPrefixesOf(settings)
+	})
+	return prefixes
+}
+
+// guildPrefixesOf returns a copy of the command prefixes set in a guild's settings, or the bot's prefix if none are set
+func guildPrefixesOf(settings *GuildSettings) []string {
+	if len(settings.BotPrefixes) > 0 {
+		return append([]string{}, settings.BotPrefixes...)
+	}
+	if settings.BotPrefix != "" {
+		return []string{settings.BotPrefix}
+	}
+	return []string{getBotData().CommandPrefix}
+}
+
+// getGuildPrefix returns the primary command prefix of a guild, for use when a command wasn't ran with a prefix
+func getGuildPrefix(guildID string) string {
+	return getGuildPrefixes(guildID)[0]
+}
+
+// isPrefixCaseInsensitive returns whether or not a guild's prefixes and command names match regardless of case
+func isPrefixCaseInsensitive(guildID string) bool {
+	caseInsensitive := false
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		caseInsensitive = settings.PrefixCaseInsensitive
+	})
+	return caseInsensitive
+}
+
+// matchGuildPrefix returns the prefix the message content starts with as it was typed, or an empty string if none match
+func matchGuildPrefix(content, guildID string) string {
+	prefixes := append([]string{}, getGuildPrefixes(guildID)...)
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j]) //Check longer prefixes first so "!!" isn't mistaken for "!"
+	})
+
+	caseInsensitive := isPrefixCaseInsensitive(guildID)
+	for _, prefix := range prefixes {
+		if prefix == "" || len(content) < len(prefix) {
+			continue
+		}
+		if content[:len(prefix)] == prefix || (caseInsensitive && strings.EqualFold(content[:len(prefix)], prefix)) {
+			return content[:len(prefix)]
+		}
+	}
+	return ""
+}
+
+// matchMentionPrefix returns the bot mention the message content starts with if it's followed by a command, or an empty string otherwise
+func matchMentionPrefix(content, guildID, botID string) string {
+	mentionPrefix := false
+	guildSettings.View(guildID, func(settings *GuildSettings) {
+		mentionPrefix = settings.MentionPrefix
+	})
+	if !mentionPrefix {
+		return ""
+	}
+
+	mention := regexp.MustCompile("^<@!?" + botID + ">[\\s,:]*").FindString(content)
+	if mention == "" {
+		return ""
+	}
+
+	commandName := strings.ToLower(strings.SplitN(strings.TrimPrefix(content, mention), " ", 2)[0])
+	if _, exists := getBotData().Commands[commandName]; exists {
+		return mention
+	}
+	if _, exists := getGuildCustomCommand(guildID, commandName); exists {
+		return mention
+	}
+	return ""
+}
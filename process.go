@@ -1,17 +1,18 @@
 package main
 
 import (
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"syscall"
 
 	"github.com/mitchellh/go-ps"
 )
 
-func spawnBot() int {
+// spawnBot starts a new bot process, writing its stderr to both the MASTER process's stderr and stderr
+func spawnBot(stderr io.Writer) (*exec.Cmd, error) {
 	if killOldBot == "true" {
 		processList, err := ps.Processes()
 		if err == nil {
@@ -27,41 +28,11 @@ func spawnBot() int {
 	}
 	os.Remove(os.Args[0] + ".old")
 
-	botProcess := exec.Command(os.Args[0], "-bot", "true", "-masterpid", strconv.Itoa(os.Getpid()), "-debug", debug)
+	botProcess := exec.Command(os.Args[0], "-bot", "true", "-masterpid", strconv.Itoa(os.Getpid()), "-debug", debug, "-config", configFile)
 	botProcess.Stdout = os.Stdout
-	botProcess.Stderr = os.Stderr
-	err := botProcess.Start()
-	if err != nil {
-		panic(err)
+	botProcess.Stderr = io.MultiWriter(os.Stderr, stderr)
+	if err := botProcess.Start(); err != nil {
+		return nil, err
 	}
-	return botProcess.Process.Pid
-}
-
-func isProcessRunning(pid int) bool {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	if runtime.GOOS != "windows" {
-		return process.Signal(syscall.Signal(0)) == nil
-	}
-
-	processState, err := process.Wait()
-	if err != nil {
-		return false
-	}
-	if processState.Exited() {
-		return false
-	}
-
-	return true
-}
-
-func waitProcess(pid int) {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return
-	}
-	_, _ = process.Wait()
+	return botProcess, nil
 }
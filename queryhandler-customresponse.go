@@ -28,12 +28,14 @@ func (*CustomResponse) Query(query string, env *QueryEnvironment) (*discordgo.Me
 	customResponses := make([]CustomResponseQuery, 0)
 
 	//Add guild-specific custom responses
-	if len(guildSettings[env.Guild.ID].CustomResponses) > 0 {
-		customResponses = append(customResponses, guildSettings[env.Guild.ID].CustomResponses...)
+	if env.Guild != nil {
+		guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+			customResponses = append(customResponses, settings.CustomResponses...)
+		})
 	}
 	//Add global custom responses
-	if len(botData.CustomResponses) > 0 {
-		customResponses = append(customResponses, botData.CustomResponses...)
+	if len(getBotData().CustomResponses) > 0 {
+		customResponses = append(customResponses, getBotData().CustomResponses...)
 	}
 
 	for _, response := range customResponses {
@@ -42,7 +44,7 @@ func (*CustomResponse) Query(query string, env *QueryEnvironment) (*discordgo.Me
 			if len(response.CmdResponses) > 0 {
 				randomCmd := rand.Intn(len(response.CmdResponses))
 
-				commandEnvironment := &CommandEnvironment{Channel: env.Channel, Guild: env.Guild, Message: env.Message, User: env.User, Member: env.Member, Command: response.CmdResponses[randomCmd].CommandName, UpdatedMessageEvent: env.UpdatedMessageEvent}
+				commandEnvironment := &CommandEnvironment{Channel: env.Channel, Guild: env.Guild, Message: env.Message, User: env.User, Member: env.Member, Command: response.CmdResponses[randomCmd].CommandName, BotPrefix: env.BotPrefix, UpdatedMessageEvent: env.UpdatedMessageEvent}
 				return callCommand(response.CmdResponses[randomCmd].CommandName, response.CmdResponses[randomCmd].Arguments, commandEnvironment), nil
 			}
 			if len(response.Responses) > 0 {
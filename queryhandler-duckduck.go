@@ -23,7 +23,7 @@ func (*DuckDuckGo) GetIconURL() string {
 
 func (*DuckDuckGo) Query(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
 	Debug.Printf("[DuckDuckGo] Getting result for [%s]...", query)
-	queryResult, err := botData.BotClients.DuckDuckGo.GetQueryResult(query)
+	queryResult, err := getBotData().BotClients.DuckDuckGo.GetQueryResult(query)
 	if err != nil {
 		Debug.Printf("[DuckDuckGo] Error getting query result: %v", err)
 		return nil, errors.New("error getting response")
@@ -25,7 +25,7 @@ func (*WolframAlpha) GetIconURL() string {
 
 func (service *WolframAlpha) Query(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
 	Debug.Printf("[Wolfram|Alpha] Getting result for query [%s]...", query)
-	conversationResult, err := botData.BotClients.Wolfram.GetConversationalQuery(query, wolfram.Metric, env.WolframConversation)
+	conversationResult, err := getBotData().BotClients.Wolfram.GetConversationalQuery(query, wolfram.Metric, env.WolframConversation)
 	if err != nil {
 		Debug.Printf("[Wolfram|Alpha] Error getting query result: %v", err)
 		wolframStoreConversation(nil, env)
@@ -56,5 +56,9 @@ func (service *WolframAlpha) Query(query string, env *QueryEnvironment) (*discor
 
 func wolframStoreConversation(conversation *wolfram.Conversation, env *QueryEnvironment) {
 	Debug.Printf("[Wolfram|Alpha] Storing conversation...")
-	guildData[env.Guild.ID].WolframConversations[env.User.ID] = conversation
+	dataID := env.Channel.ID //Direct messages keep their data under the channel ID
+	if env.Guild != nil {
+		dataID = env.Guild.ID
+	}
+	guildData.Get(dataID).SetWolframConversation(env.User.ID, conversation)
 }
@@ -29,22 +29,22 @@ type QueryEnvironment struct {
 	UpdatedMessageEvent bool
 }
 
-func initQueryServices() {
-	botData.QueryServices = make([]QueryService, 0)
+func initQueryServices(config *BotData) {
+	config.QueryServices = make([]QueryService, 0)
 
-	if botData.BotOptions.UseCustomResponses {
-		botData.QueryServices = append(botData.QueryServices, &CustomResponse{})
+	if config.BotOptions.UseCustomResponses {
+		config.QueryServices = append(config.QueryServices, &CustomResponse{})
 	}
-	if botData.BotOptions.UseDuckDuckGo {
-		botData.QueryServices = append(botData.QueryServices, &DuckDuckGo{})
+	if config.BotOptions.UseDuckDuckGo {
+		config.QueryServices = append(config.QueryServices, &DuckDuckGo{})
 	}
-	if botData.BotOptions.UseWolframAlpha {
-		botData.QueryServices = append(botData.QueryServices, &WolframAlpha{})
+	if config.BotOptions.UseWolframAlpha {
+		config.QueryServices = append(config.QueryServices, &WolframAlpha{})
 	}
 }
 
 func getQueryResult(query string, env *QueryEnvironment) (*discordgo.MessageEmbed, error) {
-	for _, service := range botData.QueryServices {
+	for _, service := range getBotData().QueryServices {
 		queryResult, err := service.Query(query, env)
 		if err != nil {
 			continue
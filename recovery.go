@@ -11,7 +11,7 @@ func recoverPanic() {
 	if panicReason := recover(); panicReason != nil {
 		fmt.Println("Clinet has encountered an unrecoverable error and has crashed.")
 		fmt.Println("Some information describing this crash: " + panicReason.(error).Error())
-		if botData.SendOwnerStackTraces || configIsBot == "false" {
+		if getBotData().SendOwnerStackTraces || configIsBot == "false" {
 			stack := make([]byte, 65536)
 			l := runtime.Stack(stack, true)
 			fmt.Println("Stack trace:\n" + string(stack[:l]))
@@ -29,7 +29,7 @@ func recoverPanic() {
 }
 
 func checkPanicRecovery() {
-	ownerPrivChannel, err := botData.DiscordSession.UserChannelCreate(botData.BotOwnerID)
+	ownerPrivChannel, err := getBotData().DiscordSession.UserChannelCreate(getBotData().BotOwnerID)
 	if err != nil {
 		debugLog("An error occurred creating a private channel with the bot owner.", false)
 	} else {
@@ -41,9 +41,9 @@ func checkPanicRecovery() {
 		if crashErr == nil && stackErr == nil {
 			DowntimeReason = "Crash: " + string(crash)
 
-			botData.DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Clinet has just recovered from an error that caused a crash.")
-			botData.DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Crash:\n```"+string(crash)+"```")
-			botData.DiscordSession.ChannelFileSendWithMessage(ownerPrivChannelID, "Stack trace:", "stacktrace.txt", stack)
+			getBotData().DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Clinet has just recovered from an error that caused a crash.")
+			getBotData().DiscordSession.ChannelMessageSend(ownerPrivChannelID, "Crash:\n```"+string(crash)+"```")
+			getBotData().DiscordSession.ChannelFileSendWithMessage(ownerPrivChannelID, "Stack trace:", "stacktrace.txt", stack)
 		}
 
 		stack.Close()
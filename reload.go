@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	duckduckgo "github.com/JoshuaDoes/duckduckgolang"
+	soundcloud "github.com/JoshuaDoes/go-soundcloud"
+	wolfram "github.com/JoshuaDoes/go-wolfram"
+	"github.com/JoshuaDoes/spotigo"
+	"github.com/google/go-github/github"
+	klogger "github.com/koffeinsource/go-klogger"
+	xkcd "github.com/nishanths/go-xkcd"
+	lyrics "github.com/rhnvrm/lyric-api-go"
+	"github.com/superwhiskers/fennel"
+	"google.golang.org/api/googleapi/transport"
+	"google.golang.org/api/youtube/v3"
+)
+
+const (
+	ConfigWatchInterval  = 10 * time.Second //How often the configuration file is checked for changes when botOptions.watchConfig is enabled
+	ConfigDiffMaxValue   = 64               //How long a changed value may be before it's left out of the list of changes
+	ConfigReloadMaxLines = 15               //How many changes to list after a reload before summarizing the rest
+)
+
+var (
+	//Only one reload may run at a time, as both the reload command and the file watcher may start one
+	configReloadLock sync.Mutex
+)
+
+// ConfigReload stores the outcome of a configuration reload
+type ConfigReload struct {
+	Changes         []string //A line for every field that was added, removed or changed
+	RestartRequired []string //The changed fields that are only read on startup
+	Errors          []error  //Clients that failed to initialize and were left disabled
+}
+
+// newBotClients creates a client for every external service enabled in a configuration, leaving the rest unset
+//
+// A client that fails to initialize is also left unset, with its error returned alongside the others.
+func newBotClients(config *BotData) (BotClients, []error) {
+	clients := BotClients{}
+	errs := make([]error, 0)
+
+	if config.BotOptions.UseDuckDuckGo {
+		clients.DuckDuckGo = &duckduckgo.Client{AppName: config.BotKeys.DuckDuckGoAppName}
+	}
+	if config.BotOptions.UseImgur {
+		clients.Imgur.HTTPClient = &http.Client{}
+		clients.Imgur.Log = &klogger.CLILogger{}
+		clients.Imgur.ImgurClientID = config.BotKeys.ImgurClientID
+	}
+	if config.BotOptions.UseSoundCloud {
+		clients.SoundCloud = &soundcloud.Client{ClientID: config.BotKeys.SoundCloudClientID}
+	}
+	if config.BotOptions.UseSpotify {
+		clients.Spotify = &spotigo.Client{Host: config.BotKeys.SpotifyHost, Pass: config.BotKeys.SpotifyPass}
+	}
+	if config.BotOptions.UseWolframAlpha {
+		clients.Wolfram = &wolfram.Client{AppID: config.BotKeys.WolframAppID}
+	}
+	if config.BotOptions.UseXKCD {
+		clients.XKCD = xkcd.NewClient()
+	}
+	if config.BotOptions.UseYouTube {
+		httpClient := &http.Client{
+			Transport: &transport.APIKey{Key: config.BotKeys.YouTubeAPIKey},
+		}
+		youtubeClient, err := youtube.New(httpClient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error initializing YouTube: %v", err))
+		} else {
+			clients.YouTube = youtubeClient
+		}
+	}
+	if config.BotOptions.UseGitHub {
+		clients.GitHub = github.NewClient(nil)
+	}
+	if config.BotOptions.UseLyrics {
+		clients.Lyrics = lyrics.New(lyrics.WithoutProviders(), lyrics.WithLyricsWikia(), lyrics.WithMusixMatch(), lyrics.WithSongLyrics(), lyrics.WithGeniusLyrics(config.BotKeys.GeniusAccessToken))
+	}
+	if config.BotOptions.UseNinty {
+		nintyClient, err := fennel.NewAccountServerClient("https://account.nintendo.net/v1/api", ctrCommonCert, ctrCommonKey, config.BotKeys.Ninty)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error initializing Ninty: %v", err))
+		} else {
+			clients.Ninty = nintyClient
+		}
+	}
+	if config.BotOptions.UseFeed {
+		clients.FeedParser = gofeed.NewParser()
+	}
+	return clients, errs
+}
+
+// initRegistries fills a configuration's commands and service handlers, which depend on the options it enables
+func initRegistries(config *BotData) {
+	initCommands(config)
+	initNLPCommands(config)
+	initQueryServices(config)
+	initVoiceServices(config)
+}
+
+// reloadConfig replaces the live configuration with the one in a configuration file
+//
+// The new configuration is parsed, checked and given its own clients and registries before anything is swapped, so
+// a configuration that fails to load leaves the bot as it was. Clients of services that were disabled are dropped.
+func reloadConfig(file string) (*ConfigReload, error) {
+	configReloadLock.Lock()
+	defer configReloadLock.Unlock()
+
+	config, err := parseConfig(file)
+	if err != nil {
+		return nil, err
+	}
+
+	oldConfig := getBotData()
+	reload := &ConfigReload{
+		Changes:         diffConfig(oldConfig, config),
+		RestartRequired: configRestartRequired(oldConfig, config),
+	}
+	config.BotClients, reload.Errors = newBotClients(config)
+
+	//Carry over what was set while running, as none of it comes from the configuration file
+	config.DiscordSession = oldConfig.DiscordSession
+	config.BotName = oldConfig.BotName
+	config.LastTipMessage = oldConfig.LastTipMessage
+	config.Updating = oldConfig.Updating
+
+	initRegistries(config)
+
+	//Everything reads the configuration through getBotData, so swapping it switches every client and registry at once
+	setBotData(config)
+
+	if config.BotOptions.UseSlashCommands && config.DiscordSession != nil && config.DiscordSession.State.User != nil {
+		if err := registerApplicationCommands(config.DiscordSession); err != nil {
+			reload.Errors = append(reload.Errors, fmt.Errorf("error registering application commands: %v", err))
+		}
+	}
+
+	Info.Printf("Reloaded the configuration with %d change(s)\n", len(reload.Changes))
+	for _, change := range reload.Changes {
+		Info.Println(change)
+	}
+	for _, err := range reload.Errors {
+		Error.Println(err)
+	}
+	return reload, nil
+}
+
+// configRestartRequired returns the fields that changed between two configurations but are only read on startup
+func configRestartRequired(oldConfig, newConfig *BotData) []string {
+	fields := make([]string, 0)
+	if oldConfig.BotToken != newConfig.BotToken {
+		fields = append(fields, "botToken")
+	}
+	if oldConfig.BotOptions.API.Enabled != newConfig.BotOptions.API.Enabled || oldConfig.BotOptions.API.Host != newConfig.BotOptions.API.Host {
+		fields = append(fields, "botOptions.api")
+	}
+	if oldConfig.BotOptions.StateBackend != newConfig.BotOptions.StateBackend {
+		fields = append(fields, "botOptions.stateBackend")
+	}
+	if oldConfig.BotOptions.Snapshots.Enabled != newConfig.BotOptions.Snapshots.Enabled || oldConfig.BotOptions.Snapshots.Interval != newConfig.BotOptions.Snapshots.Interval {
+		fields = append(fields, "botOptions.snapshots")
+	}
+	if oldConfig.BotOptions.MaxGuildWorkers != newConfig.BotOptions.MaxGuildWorkers {
+		fields = append(fields, "botOptions.maxGuildWorkers")
+	}
+	return fields
+}
+
+// diffConfig returns a line for every field that was added, removed or changed between two configurations, leaving
+// out the values of secrets
+func diffConfig(oldConfig, newConfig *BotData) []string {
+	oldFields, newFields := flattenConfig(oldConfig), flattenConfig(newConfig)
+
+	paths := make([]string, 0)
+	for path := range oldFields {
+		paths = append(paths, path)
+	}
+	for path := range newFields {
+		if _, exists := oldFields[path]; !exists {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	secrets := append(append([]string{}, oldConfig.Secrets...), newConfig.Secrets...)
+	diff := make([]string, 0)
+	for _, path := range paths {
+		oldValue, inOld := oldFields[path]
+		newValue, inNew := newFields[path]
+		switch {
+		case !inNew:
+			diff = append(diff, "- "+path)
+		case !inOld:
+			line := "+ " + path
+			if !isSecretConfigField(path) && len(newValue) <= ConfigDiffMaxValue {
+				line += ": " + newValue
+			}
+			diff = append(diff, redactSecretsIn(line, secrets))
+		case oldValue != newValue:
+			line := "~ " + path
+			if !isSecretConfigField(path) && len(oldValue) <= ConfigDiffMaxValue && len(newValue) <= ConfigDiffMaxValue {
+				line += ": " + oldValue + " -> " + newValue
+			}
+			diff = append(diff, redactSecretsIn(line, secrets))
+		}
+	}
+	return diff
+}
+
+// flattenConfig returns the compact JSON value of every field in a configuration that isn't an object, keyed by its
+// dotted path
+func flattenConfig(config *BotData) map[string]string {
+	fields := make(map[string]string)
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fields
+	}
+	flattenConfigValue(fields, "", data)
+	return fields
+}
+
+func flattenConfigValue(fields map[string]string, path string, data json.RawMessage) {
+	object := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &object); err == nil && len(data) > 0 && data[0] == '{' {
+		for key, value := range object {
+			if path == "" {
+				flattenConfigValue(fields, key, value)
+			} else {
+				flattenConfigValue(fields, path+"."+key, value)
+			}
+		}
+		return
+	}
+
+	compact := &bytes.Buffer{}
+	if err := json.Compact(compact, data); err != nil {
+		fields[path] = string(data)
+		return
+	}
+	fields[path] = compact.String()
+}
+
+// watchConfig reloads the configuration whenever its file is modified while botOptions.watchConfig is enabled
+func watchConfig(file string) {
+	defer recoverPanic()
+
+	lastModified := time.Time{}
+	if info, err := os.Stat(file); err == nil {
+		lastModified = info.ModTime()
+	}
+
+	for range time.Tick(ConfigWatchInterval) {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().After(lastModified) {
+			continue
+		}
+		lastModified = info.ModTime()
+
+		if !getBotData().BotOptions.WatchConfig {
+			continue
+		}
+		Info.Println("The configuration file was modified, reloading...")
+		if _, err := reloadConfig(file); err != nil {
+			Error.Printf("Error reloading the configuration, keeping the current one: %v\n", err)
+		}
+	}
+}
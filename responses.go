@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// MessageMaxLength contains how many characters Discord allows in the content of a message
+	MessageMaxLength = 2000
+)
+
+var (
+	// InternalResponseActionCompleted is returned when a command has already handled its own response
+	InternalResponseActionCompleted = &CommandResponse{}
+)
+
+// CommandResponse holds everything a command wants sent back to the user
+//
+// Each embed is sent as its own message, with the content and any files not referenced by an embed
+// attached to the first message. Files referenced by an embed with an attachment:// URL are sent with that embed.
+// Content longer than MessageMaxLength is split, with each piece but the last sent as a message of its own before the rest.
+type CommandResponse struct {
+	Content       string                                     //The text content of the response
+	Embeds        []*discordgo.MessageEmbed                  //The embeds to send, in order
+	Files         []*discordgo.File                          //The files to attach to the response
+	Ephemeral     bool                                       //Whether or not only the user should see the response, sent as a direct message if the command wasn't ran as an application command
+	DirectMessage bool                                       //Whether or not the response should be sent to the user in a direct message
+	Reactions     []string                                   //The emojis to react to the first response message with
+	FollowUp      func(ctx context.Context) *CommandResponse //Ran on a worker after the response is sent, replacing the response with the result if not nil
+
+	AllowedMentions *MessageAllowedMentions //Which mentions in the content may notify, or nil to let every mention notify
+}
+
+// MessageAllowedMentions holds which mentions in a message's content may notify, as discordgo can't send allowed mentions yet
+type MessageAllowedMentions struct {
+	Parse []string `json:"parse"`           //The types of mentions that may notify: "everyone", "roles" and/or "users"
+	Roles []string `json:"roles,omitempty"` //The IDs of roles that may be notified when roles aren't parsed
+	Users []string `json:"users,omitempty"` //The IDs of users that may be notified when users aren't parsed
+}
+
+// messageSendAllowedMentions holds a message to send along with the mentions it may notify
+type messageSendAllowedMentions struct {
+	*discordgo.MessageSend
+	AllowedMentions *MessageAllowedMentions `json:"allowed_mentions"`
+}
+
+// messageEditAllowedMentions holds a message edit along with the mentions it may notify
+type messageEditAllowedMentions struct {
+	Content         *string                 `json:"content,omitempty"`
+	Embed           *discordgo.MessageEmbed `json:"embed,omitempty"`
+	AllowedMentions *MessageAllowedMentions `json:"allowed_mentions"`
+}
+
+// NewEmbedResponse returns a response containing a single embed, or nil if there is no embed
+func NewEmbedResponse(embed *discordgo.MessageEmbed) *CommandResponse {
+	if embed == nil {
+		return nil
+	}
+	if embed == InternalEmbedActionCompleted {
+		return InternalResponseActionCompleted
+	}
+	return &CommandResponse{Embeds: []*discordgo.MessageEmbed{embed}}
+}
+
+// Embed returns the first embed of the response, or nil if there are none
+func (response *CommandResponse) Embed() *discordgo.MessageEmbed {
+	if response == nil || len(response.Embeds) == 0 {
+		return nil
+	}
+	return response.Embeds[0]
+}
+
+// IsEmbedOnly returns whether or not the response is nothing more than a single embed
+func (response *CommandResponse) IsEmbedOnly() bool {
+	return response.Content == "" && len(response.Embeds) == 1 && len(response.Files) == 0 &&
+		!response.Ephemeral && !response.DirectMessage && len(response.Reactions) == 0 && response.FollowUp == nil
+}
+
+// IsEmpty returns whether or not the response has nothing to send
+func (response *CommandResponse) IsEmpty() bool {
+	return response == nil || response == InternalResponseActionCompleted || (response.Content == "" && len(response.Embeds) == 0 && len(response.Files) == 0)
+}
+
+// Messages splits the response into the messages needed to send it
+func (response *CommandResponse) Messages() []*discordgo.MessageSend {
+	messages := make([]*discordgo.MessageSend, 0)
+	usedFiles := make(map[*discordgo.File]bool)
+
+	for _, embed := range response.Embeds {
+		fixedEmbed := Embed{embed}
+		fixedEmbed.Truncate()
+
+		messageSend := &discordgo.MessageSend{Embed: fixedEmbed.MessageEmbed}
+		for _, file := range response.Files {
+			if embedReferencesFile(embed, file.Name) {
+				messageSend.Files = append(messageSend.Files, file)
+				usedFiles[file] = true
+			}
+		}
+		messages = append(messages, messageSend)
+	}
+	if len(messages) == 0 {
+		messages = append(messages, &discordgo.MessageSend{})
+	}
+
+	contents := splitMessageContent(response.Content, MessageMaxLength)
+	messages[0].Content = contents[len(contents)-1]
+	for _, file := range response.Files {
+		if !usedFiles[file] {
+			messages[0].Files = append(messages[0].Files, file)
+		}
+	}
+
+	contentMessages := make([]*discordgo.MessageSend, 0)
+	for _, content := range contents[:len(contents)-1] {
+		contentMessages = append(contentMessages, &discordgo.MessageSend{Content: content})
+	}
+	return append(contentMessages, messages...)
+}
+
+// splitMessageContent splits content into pieces of at most max characters, breaking at the last line break or space that fits if there is one
+func splitMessageContent(content string, max int) []string {
+	pieces := make([]string, 0)
+	runes := []rune(content)
+	for len(runes) > max {
+		piece := string(runes[:max])
+		breakIndex := strings.LastIndex(piece, "\n")
+		if breakIndex <= 0 {
+			breakIndex = strings.LastIndex(piece, " ")
+		}
+		if breakIndex > 0 {
+			piece = piece[:breakIndex]
+		}
+		pieces = append(pieces, piece)
+
+		runes = runes[len([]rune(piece)):]
+		if breakIndex > 0 {
+			runes = runes[1:] //The line break or space is replaced by the split
+		}
+	}
+	return append(pieces, string(runes))
+}
+
+// embedReferencesFile returns whether or not an embed displays an attached file
+func embedReferencesFile(embed *discordgo.MessageEmbed, fileName string) bool {
+	attachmentURL := "attachment://" + fileName
+	if embed.Image != nil && embed.Image.URL == attachmentURL {
+		return true
+	}
+	if embed.Thumbnail != nil && embed.Thumbnail.URL == attachmentURL {
+		return true
+	}
+	return false
+}
+
+// getEnvironmentDataID returns the key of the guild data for the environment, which is the channel ID in direct messages
+func getEnvironmentDataID(env *CommandEnvironment) string {
+	if env.Guild != nil {
+		return env.Guild.ID
+	}
+	return env.Channel.ID
+}
+
+// getQuery returns the query tracking the responses to a message, creating it if it doesn't exist
+func getQuery(dataID, messageID string) (query *Query, existed bool) {
+	if guildData.Get(dataID).Queries == nil {
+		guildData.Get(dataID).Queries = make(map[string]*Query)
+	}
+	if query = guildData.Get(dataID).Queries[messageID]; query != nil {
+		return query, true
+	}
+	query = &Query{}
+	guildData.Get(dataID).Queries[messageID] = query
+	return query, false
+}
+
+// sendCommandResponse sends a response to a message, or replaces the previous response if the message was edited
+//
+// dataID is the key of the guild data the query is tracked in, which is the channel ID in direct messages.
+func sendCommandResponse(session *discordgo.Session, message *discordgo.Message, channel *discordgo.Channel, guild *discordgo.Guild, dataID string, response *CommandResponse, updatedMessageEvent bool) {
+	if response.IsEmpty() {
+		return
+	}
+
+	query, existed := getQuery(dataID, message.ID)
+	query.AuthorID = message.Author.ID
+
+	channelID := message.ChannelID
+	if response.Ephemeral || response.DirectMessage {
+		userChannel, err := session.UserChannelCreate(message.Author.ID)
+		if err != nil {
+			Error.Printf("Error creating a direct message channel with %s: %v", message.Author.ID, err)
+			return
+		}
+		channelID = userChannel.ID
+	}
+
+	messages := response.Messages()
+	if existed {
+		//A single message with no files can be edited in place, anything else has to be sent again
+		responseIDs := query.MessageIDs()
+		if len(responseIDs) == 1 && len(messages) == 1 && len(messages[0].Files) == 0 && query.GetChannelID(message.ChannelID) == channelID {
+			messageEdit := discordgo.NewMessageEdit(channelID, responseIDs[0]).SetContent(messages[0].Content)
+			if messages[0].Embed != nil {
+				messageEdit.SetEmbed(messages[0].Embed)
+			}
+			if responseMessage, err := channelMessageEditAllowedMentions(session, messageEdit, response.AllowedMentions); err == nil {
+				debugMessage(session, responseMessage, channel, guild, updatedMessageEvent)
+			}
+			addResponseReactions(session, channelID, responseIDs[0], response.Reactions)
+			runResponseFollowUp(session, message, channel, guild, dataID, response)
+			return
+		}
+		deleteQueryResponses(session, query, message.ChannelID)
+	}
+
+	typingEvent(session, channelID, updatedMessageEvent)
+
+	query.ResponseChannelID = ""
+	if channelID != message.ChannelID {
+		query.ResponseChannelID = channelID
+	}
+	query.ResponseMessageID = ""
+	query.ResponseMessageIDs = nil
+	for _, messageSend := range messages {
+		responseMessage, err := channelMessageSendAllowedMentions(session, channelID, messageSend, response.AllowedMentions)
+		if err != nil {
+			Error.Printf("Error sending command response: %v", err)
+			continue
+		}
+		debugMessage(session, responseMessage, channel, guild, updatedMessageEvent)
+		query.AddMessageID(responseMessage.ID)
+	}
+
+	if responseIDs := query.MessageIDs(); len(responseIDs) > 0 {
+		addResponseReactions(session, channelID, responseIDs[0], response.Reactions)
+	}
+	runResponseFollowUp(session, message, channel, guild, dataID, response)
+}
+
+// channelMessageSendAllowedMentions sends a message, only letting the mentions allowed by allowedMentions notify unless it's nil
+func channelMessageSendAllowedMentions(session *discordgo.Session, channelID string, messageSend *discordgo.MessageSend, allowedMentions *MessageAllowedMentions) (*discordgo.Message, error) {
+	if allowedMentions == nil {
+		return session.ChannelMessageSendComplex(channelID, messageSend)
+	}
+	if messageSend.Embed != nil && messageSend.Embed.Type == "" {
+		messageSend.Embed.Type = "rich"
+	}
+
+	endpoint := discordgo.EndpointChannelMessages(channelID)
+	payload := &messageSendAllowedMentions{MessageSend: messageSend, AllowedMentions: allowedMentions}
+
+	var body []byte
+	var err error
+	if len(messageSend.Files) == 0 {
+		body, err = session.RequestWithBucketID("POST", endpoint, payload, endpoint)
+	} else {
+		//Messages with files are sent as multipart forms, with the message itself in the payload_json field
+		contentType, form, formErr := newMessageForm(payload, messageSend.Files)
+		if formErr != nil {
+			return nil, formErr
+		}
+		body, err = session.RequestWithLockedBucket("POST", endpoint, contentType, form, session.Ratelimiter.LockBucket(endpoint), 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message := &discordgo.Message{}
+	if err := json.Unmarshal(body, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// channelMessageEditAllowedMentions edits a message, only letting the mentions allowed by allowedMentions notify unless it's nil
+func channelMessageEditAllowedMentions(session *discordgo.Session, messageEdit *discordgo.MessageEdit, allowedMentions *MessageAllowedMentions) (*discordgo.Message, error) {
+	if allowedMentions == nil {
+		return session.ChannelMessageEditComplex(messageEdit)
+	}
+	if messageEdit.Embed != nil && messageEdit.Embed.Type == "" {
+		messageEdit.Embed.Type = "rich"
+	}
+
+	payload := &messageEditAllowedMentions{Content: messageEdit.Content, Embed: messageEdit.Embed, AllowedMentions: allowedMentions}
+	body, err := session.RequestWithBucketID("PATCH", discordgo.EndpointChannelMessage(messageEdit.Channel, messageEdit.ID), payload, discordgo.EndpointChannelMessage(messageEdit.Channel, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	message := &discordgo.Message{}
+	if err := json.Unmarshal(body, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// newMessageForm returns the content type and body of a multipart form containing a message and its files
+func newMessageForm(payload interface{}, files []*discordgo.File) (string, []byte, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	form := &bytes.Buffer{}
+	formWriter := multipart.NewWriter(form)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := formWriter.CreatePart(header)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := part.Write(payloadJSON); err != nil {
+		return "", nil, err
+	}
+
+	for i, file := range files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file%d"; filename="%s"`, i, strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(file.Name)))
+		header.Set("Content-Type", contentType)
+		part, err := formWriter.CreatePart(header)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := formWriter.Close(); err != nil {
+		return "", nil, err
+	}
+	return formWriter.FormDataContentType(), form.Bytes(), nil
+}
+
+// addResponseReactions reacts to a response message with each emoji
+func addResponseReactions(session *discordgo.Session, channelID, messageID string, reactions []string) {
+	for _, reaction := range reactions {
+		if err := session.MessageReactionAdd(channelID, messageID, strings.Trim(reaction, "<>")); err != nil {
+			Error.Printf("Error adding reaction %s to response: %v", reaction, err)
+		}
+	}
+}
+
+// runResponseFollowUp runs the follow-up of a response in the background and replaces the response with its result
+func runResponseFollowUp(session *discordgo.Session, message *discordgo.Message, channel *discordgo.Channel, guild *discordgo.Guild, dataID string, response *CommandResponse) {
+	if response.FollowUp == nil {
+		return
+	}
+	go func() {
+		defer recoverPanic()
+
+		followUp := runFollowUp(dataID, response.FollowUp)
+		if followUp.IsEmpty() {
+			return
+		}
+
+		guildData.Get(dataID).Lock()
+		defer guildData.Get(dataID).Unlock()
+
+		sendCommandResponse(session, message, channel, guild, dataID, followUp, true)
+		stateSaveGuild(dataID, message.Author.ID)
+	}()
+}
+
+// deleteQueryResponses deletes every response message of a query, where channelID is the channel the query was sent in
+func deleteQueryResponses(session *discordgo.Session, query *Query, channelID string) {
+	channelID = query.GetChannelID(channelID)
+	for _, responseID := range query.MessageIDs() {
+		session.ChannelMessageDelete(channelID, responseID)
+	}
+	query.ResponseMessageID = ""
+	query.ResponseMessageIDs = nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+/*
+	Any configuration value can be overridden by an environment variable, named after its path in the configuration
+	file with ConfigEnvPrefix in front, such as CLINET_BOTTOKEN or CLINET_BOTKEYS_YOUTUBEAPIKEY. An environment variable
+	always takes precedence over the configuration file, and a value that isn't a string is read as JSON.
+
+	Either may instead refer to a file with ConfigFilePrefix, such as "@file:/run/secrets/token", in which case the
+	contents of the file are used as the value, without any trailing newlines.
+
+	The values of secret fields and of anything read from a file are redacted from logs and from the few responses
+	that could otherwise reveal them.
+*/
+
+const (
+	ConfigEnvPrefix  = "CLINET_"    //The prefix of environment variables that override configuration values
+	ConfigFilePrefix = "@file:"     //The prefix of configuration values that are read from a file
+	SecretRedacted   = "[redacted]" //What a secret is replaced with when it's redacted
+	SecretMinLength  = 8            //How long a value has to be to be redacted, as shorter ones would redact common text
+)
+
+var (
+	//Fields whose values are secrets, including everything within them
+	configSecretFields = []string{
+		"botToken",
+		"botKeys.geniusAccessToken",
+		"botKeys.imgurClientID",
+		"botKeys.soundcloudClientID",
+		"botKeys.spotifyPass",
+		"botKeys.wolframAppID",
+		"botKeys.youtubeAPIKey",
+		"botKeys.ninty",
+		"botOptions.api.key",
+	}
+)
+
+// applyConfigOverrides replaces the values of a configuration with those of environment variables and files,
+// keeping track of every secret it holds and returning every value that couldn't be read
+func applyConfigOverrides(config *BotData) ConfigErrors {
+	secrets := make([]string, 0)
+	errs := make(ConfigErrors, 0)
+	overrideConfigStruct(reflect.ValueOf(config).Elem(), "", &secrets, &errs)
+
+	//Longer secrets are redacted first, in case a shorter one is a part of it
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	config.Secrets = secrets
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func overrideConfigStruct(value reflect.Value, path string, secrets *[]string, errs *ConfigErrors) {
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" {
+			continue //Unexported
+		}
+
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue //Not a part of the configuration file
+		} else if tag != "" {
+			name = tag
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		overrideConfigField(value.Field(i), name, secrets, errs)
+	}
+}
+
+func overrideConfigField(field reflect.Value, path string, secrets *[]string, errs *ConfigErrors) {
+	envName := configEnvName(path)
+	if envValue, exists := os.LookupEnv(envName); exists {
+		value, fromFile, err := readConfigFile(envValue)
+		if err != nil {
+			errs.Add(path, "couldn't be read from the file "+envName+" refers to: "+err.Error(), "make sure the file exists and can be read by the bot")
+			return
+		}
+		if field.Kind() == reflect.String {
+			field.SetString(value)
+		} else if err := json.Unmarshal([]byte(value), field.Addr().Interface()); err != nil {
+			errs.Add(path, "couldn't be read from "+envName+": "+err.Error(), "set "+envName+" to a JSON "+field.Type().String())
+			return
+		}
+		if fromFile || isSecretConfigField(path) {
+			addSecret(secrets, field)
+		}
+		return
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		value, fromFile, err := readConfigFile(field.String())
+		if err != nil {
+			errs.Add(path, "couldn't be read from the file it refers to: "+err.Error(), "make sure the file exists and can be read by the bot")
+			return
+		}
+		field.SetString(value)
+		if fromFile || isSecretConfigField(path) {
+			addSecret(secrets, field)
+		}
+	case reflect.Struct:
+		overrideConfigStruct(field, path, secrets, errs)
+	case reflect.Ptr:
+		if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+			overrideConfigStruct(field.Elem(), path, secrets, errs)
+		}
+	case reflect.Slice:
+		for i := 0; i < field.Len(); i++ {
+			overrideConfigField(field.Index(i), path+"["+strconv.Itoa(i)+"]", secrets, errs)
+		}
+	}
+}
+
+// configEnvName returns the name of the environment variable that overrides a configuration field
+func configEnvName(path string) string {
+	path = strings.NewReplacer(".", "_", "[", "_", "]", "").Replace(path)
+	return ConfigEnvPrefix + strings.ToUpper(path)
+}
+
+// readConfigFile returns the contents of the file a configuration value refers to, or the value itself if it doesn't
+// refer to one
+func readConfigFile(value string) (string, bool, error) {
+	if !strings.HasPrefix(value, ConfigFilePrefix) {
+		return value, false, nil
+	}
+	contents, err := ioutil.ReadFile(strings.TrimPrefix(value, ConfigFilePrefix))
+	if err != nil {
+		return "", true, err
+	}
+	return strings.TrimRight(string(contents), "\r\n"), true, nil
+}
+
+// addSecret keeps track of the strings within a configuration field as secrets
+func addSecret(secrets *[]string, field reflect.Value) {
+	switch field.Kind() {
+	case reflect.String:
+		if len(field.String()) >= SecretMinLength {
+			*secrets = append(*secrets, field.String())
+		}
+	case reflect.Struct:
+		for i := 0; i < field.NumField(); i++ {
+			if field.Type().Field(i).PkgPath == "" {
+				addSecret(secrets, field.Field(i))
+			}
+		}
+	case reflect.Ptr:
+		if !field.IsNil() {
+			addSecret(secrets, field.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < field.Len(); i++ {
+			addSecret(secrets, field.Index(i))
+		}
+	}
+}
+
+func isSecretConfigField(path string) bool {
+	for _, field := range configSecretFields {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// redactSecrets replaces every secret of the live configuration within text
+func redactSecrets(text string) string {
+	return redactSecretsIn(text, getBotData().Secrets)
+}
+
+func redactSecretsIn(text string, secrets []string) string {
+	for _, secret := range secrets {
+		text = strings.Replace(text, secret, SecretRedacted, -1)
+	}
+	return text
+}
+
+// redactEmbed replaces every secret of the live configuration within an embed's text
+func redactEmbed(embed *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	embed.Title = redactSecrets(embed.Title)
+	embed.Description = redactSecrets(embed.Description)
+	for _, field := range embed.Fields {
+		field.Name = redactSecrets(field.Name)
+		field.Value = redactSecrets(field.Value)
+	}
+	if embed.Author != nil {
+		embed.Author.Name = redactSecrets(embed.Author.Name)
+	}
+	if embed.Footer != nil {
+		embed.Footer.Text = redactSecrets(embed.Footer.Text)
+	}
+	return embed
+}
+
+// secretRedactor redacts the secrets of the live configuration from everything written through it
+type secretRedactor struct {
+	writer io.Writer
+}
+
+func (redactor *secretRedactor) Write(p []byte) (int, error) {
+	if _, err := redactor.writer.Write([]byte(redactSecrets(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
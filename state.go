@@ -0,0 +1,750 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// State backends that can be set in the configuration
+const (
+	StateBackendBolt = "bolt" //An embedded BoltDB database, written in transactions
+	StateBackendJSON = "json" //A JSON file per entry, written atomically
+)
+
+// StateJournalFile contains the name of the file in a JSON state bucket that lists the entries of an unfinished save
+const StateJournalFile = ".journal"
+
+// State buckets, each holding the entries of one of the global maps where key = guild, channel or user ID
+const (
+	StateBucketGuildData     = "guildData"
+	StateBucketGuildSettings = "guildSettings"
+	StateBucketUserSettings  = "userSettings"
+	StateBucketStarboards    = "starboards"
+	StateBucketReminds       = "reminds"
+	StateBucketVoiceData     = "voiceData"
+	StateBucketAudit         = "audit" //Where key = time and user ID of a user data request
+	StateBucketMeta          = "meta"
+)
+
+// StateBuckets contains every state bucket
+var StateBuckets = []string{
+	StateBucketGuildData,
+	StateBucketGuildSettings,
+	StateBucketUserSettings,
+	StateBucketStarboards,
+	StateBucketReminds,
+	StateBucketVoiceData,
+	StateBucketAudit,
+	StateBucketMeta,
+}
+
+const (
+	// StateDir contains the directory the state is stored in
+	StateDir = "state"
+
+	// StateKeyAll is the key of buckets that hold a single entry, such as reminders
+	StateKeyAll = "all"
+
+	// StateBackupDir contains the directory backups of the state are written to
+	StateBackupDir = "state/backups"
+
+	// StateKeyImported is set in the meta bucket once the legacy state files have been imported
+	StateKeyImported = "imported"
+
+	// StateKeySchemaVersion is set in the meta bucket to the schema version the state was last migrated to
+	StateKeySchemaVersion = "schemaVersion"
+)
+
+var (
+	stateStore StateStore
+
+	//Hashes of the last saved JSON of every entry, so only entries that changed are written, where key = bucket/key
+	stateHashes     = make(map[string]uint64)
+	stateHashesLock sync.Mutex
+
+	errStateKey      = errors.New("state: invalid key")
+	errStateNotEmpty = errors.New("state: not empty")
+)
+
+// StateStore stores the state in buckets of keyed JSON entries
+type StateStore interface {
+	Save(bucket string, entries map[string][]byte) error                //Writes every entry at once, so either all or none of them are saved
+	Delete(bucket string, keys ...string) error                         //Removes the entries with the specified keys
+	Load(bucket string, load func(key string, data []byte) error) error //Calls load for every entry in the bucket
+	Backup(dir, name string) (string, error)                            //Copies every bucket to a new backup in dir, returning its path
+	Close() error
+}
+
+// BoltStateStore stores the state in an embedded BoltDB database
+type BoltStateStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStateStore opens or creates the database at the specified path
+func NewBoltStateStore(path string) (*BoltStateStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStateStore{db: db}, nil
+}
+
+// Save writes every entry in a single transaction
+func (store *BoltStateStore) Save(bucket string, entries map[string][]byte) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		for key, data := range entries {
+			if err := b.Put([]byte(key), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete removes the entries with the specified keys in a single transaction
+func (store *BoltStateStore) Delete(bucket string, keys ...string) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		for _, key := range keys {
+			if err := b.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Load calls load for every entry in the bucket
+func (store *BoltStateStore) Load(bucket string, load func(key string, data []byte) error) error {
+	return store.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(key, data []byte) error {
+			return load(string(key), data)
+		})
+	})
+}
+
+// Backup copies the database to a new file in dir, named name.db
+func (store *BoltStateStore) Backup(dir, name string) (string, error) {
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name+".db")
+	return path, store.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(path, 0600)
+	})
+}
+
+// Close closes the database
+func (store *BoltStateStore) Close() error {
+	return store.db.Close()
+}
+
+// JSONStateStore stores the state as a JSON file per entry, in a directory per bucket
+//
+// A save writes every entry to a temporary file and lists them in the bucket's journal before renaming any of them
+// into place, so a save that's interrupted partway is finished from the journal the next time the store is opened.
+type JSONStateStore struct {
+	dir  string
+	lock sync.Mutex //Only one save or delete may use a bucket's journal at a time
+}
+
+// NewJSONStateStore uses the specified directory, creating it if it doesn't exist and finishing any interrupted saves
+func NewJSONStateStore(dir string) (*JSONStateStore, error) {
+	if err := os.MkdirAll(dir, 0744); err != nil {
+		return nil, err
+	}
+	store := &JSONStateStore{dir: dir}
+	for _, bucket := range StateBuckets {
+		if err := store.replayJournal(bucket); err != nil {
+			return nil, fmt.Errorf("error finishing an interrupted save of %s: %v", bucket, err)
+		}
+	}
+	return store, nil
+}
+
+// entryPath returns the path to the file of an entry, refusing keys that would escape the bucket's directory
+func (store *JSONStateStore) entryPath(bucket, key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || strings.HasPrefix(key, ".") {
+		return "", errStateKey
+	}
+	return filepath.Join(store.dir, bucket, key+".json"), nil
+}
+
+// Save writes every entry to a temporary file, then journals and renames them over the old ones, so either all or none
+// of the entries are saved
+func (store *JSONStateStore) Save(bucket string, entries map[string][]byte) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	bucketDir := filepath.Join(store.dir, bucket)
+	if err := os.MkdirAll(bucketDir, 0744); err != nil {
+		return err
+	}
+	if err := store.replayJournal(bucket); err != nil {
+		return err //A previous save has to be finished before its journal can be replaced
+	}
+
+	journal := make(map[string]string) //Where key = temporary file and value = entry file, both within the bucket
+	removeTempFiles := func() {
+		for tempFile := range journal {
+			os.Remove(filepath.Join(bucketDir, tempFile))
+		}
+	}
+	for key, data := range entries {
+		path, err := store.entryPath(bucket, key)
+		if err != nil {
+			removeTempFiles()
+			return err
+		}
+		tempPath, err := writeTempFile(path, data, 0644)
+		if err != nil {
+			removeTempFiles()
+			return err
+		}
+		journal[filepath.Base(tempPath)] = filepath.Base(path)
+	}
+
+	journalJSON, err := json.Marshal(journal)
+	if err != nil {
+		removeTempFiles()
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(bucketDir, StateJournalFile), journalJSON, 0644); err != nil {
+		removeTempFiles()
+		return err
+	}
+	return store.replayJournal(bucket)
+}
+
+// replayJournal renames every temporary file listed in a bucket's journal over its entry and removes the journal
+func (store *JSONStateStore) replayJournal(bucket string) error {
+	bucketDir := filepath.Join(store.dir, bucket)
+	journalPath := filepath.Join(bucketDir, StateJournalFile)
+	journalJSON, err := ioutil.ReadFile(journalPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	journal := make(map[string]string)
+	if err := json.Unmarshal(journalJSON, &journal); err != nil {
+		return err
+	}
+	for tempFile, entryFile := range journal {
+		if strings.ContainsAny(tempFile+entryFile, `/\`) {
+			return errStateKey
+		}
+		//A temporary file that no longer exists was already renamed before the save was interrupted
+		if err := os.Rename(filepath.Join(bucketDir, tempFile), filepath.Join(bucketDir, entryFile)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Remove(journalPath)
+}
+
+// Delete removes the files of the entries with the specified keys
+func (store *JSONStateStore) Delete(bucket string, keys ...string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, key := range keys {
+		path, err := store.entryPath(bucket, key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load calls load for every entry in the bucket
+func (store *JSONStateStore) Load(bucket string, load func(key string, data []byte) error) error {
+	files, err := filepath.Glob(filepath.Join(store.dir, bucket, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := load(strings.TrimSuffix(filepath.Base(file), ".json"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backup copies the files of every bucket to a new directory in dir, named name
+func (store *JSONStateStore) Backup(dir, name string) (string, error) {
+	path := filepath.Join(dir, name)
+	for _, bucket := range StateBuckets {
+		if err := os.MkdirAll(filepath.Join(path, bucket), 0744); err != nil {
+			return path, err
+		}
+		err := store.Load(bucket, func(key string, data []byte) error {
+			return ioutil.WriteFile(filepath.Join(path, bucket, key+".json"), data, 0644)
+		})
+		if err != nil {
+			return path, err
+		}
+	}
+	return path, nil
+}
+
+// Close does nothing, as every file is closed once it's written
+func (store *JSONStateStore) Close() error {
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory, syncs it and renames it to the path
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tempPath, err := writeTempFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// writeTempFile writes data to a new temporary file next to the path and syncs it, returning the temporary file's path
+func writeTempFile(path string, data []byte, perm os.FileMode) (string, error) {
+	tempFile, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return "", err
+	}
+	tempPath := tempFile.Name()
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return "", err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		os.Remove(tempPath)
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempPath)
+		return "", err
+	}
+	if err := os.Chmod(tempPath, perm); err != nil {
+		os.Remove(tempPath)
+		return "", err
+	}
+	return tempPath, nil
+}
+
+// openStateStore opens the state store for the configured backend
+func openStateStore(backend string) (StateStore, error) {
+	if err := os.MkdirAll(StateDir, 0744); err != nil {
+		return nil, err
+	}
+	switch backend {
+	case StateBackendJSON:
+		return NewJSONStateStore(StateDir)
+	}
+	return NewBoltStateStore(filepath.Join(StateDir, "clinet.db"))
+}
+
+// stateHash returns a hash of an entry's JSON
+func stateHash(data []byte) uint64 {
+	hash := fnv.New64a()
+	hash.Write(data)
+	return hash.Sum64()
+}
+
+// stateSaveEntries writes the entries of a bucket that changed since they were last saved
+func stateSaveEntries(bucket string, entries map[string]interface{}) error {
+	encoded := make(map[string][]byte)
+	for key, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		encoded[key] = data
+	}
+	return stateSaveEncoded(bucket, encoded)
+}
+
+// stateSaveEncoded writes the already encoded entries of a bucket that changed since they were last saved
+func stateSaveEncoded(bucket string, entries map[string][]byte) error {
+	changed := make(map[string][]byte)
+	hashes := make(map[string]uint64)
+	for key, data := range entries {
+		hash := stateHash(data)
+
+		stateHashesLock.Lock()
+		lastHash, saved := stateHashes[bucket+"/"+key]
+		stateHashesLock.Unlock()
+		if saved && lastHash == hash {
+			continue
+		}
+		changed[key] = data
+		hashes[key] = hash
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := stateStore.Save(bucket, changed); err != nil {
+		return err
+	}
+	stateHashesLock.Lock()
+	for key, hash := range hashes {
+		stateHashes[bucket+"/"+key] = hash
+	}
+	stateHashesLock.Unlock()
+	return nil
+}
+
+// stateDeleteMissing removes the entries of a bucket that were saved before but no longer exist
+func stateDeleteMissing(bucket string, exists func(key string) bool) error {
+	missing := make([]string, 0)
+	stateHashesLock.Lock()
+	for hashKey := range stateHashes {
+		if strings.HasPrefix(hashKey, bucket+"/") {
+			if key := strings.TrimPrefix(hashKey, bucket+"/"); !exists(key) {
+				missing = append(missing, key)
+			}
+		}
+	}
+	stateHashesLock.Unlock()
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if err := stateStore.Delete(bucket, missing...); err != nil {
+		return err
+	}
+	stateHashesLock.Lock()
+	for _, key := range missing {
+		delete(stateHashes, bucket+"/"+key)
+	}
+	stateHashesLock.Unlock()
+	return nil
+}
+
+// stateSaveGuild saves the entries of a guild (or direct message channel) and a user that changed
+//
+// The caller must hold the guild data lock, which keeps the guild's entries from changing while they're saved.
+func stateSaveGuild(guildID, userID string) {
+	if stateStore == nil {
+		return
+	}
+
+	if data, exists := guildData.Load(guildID); exists {
+		if err := stateSaveEntries(StateBucketGuildData, map[string]interface{}{guildID: data}); err != nil {
+			Error.Printf("Error saving guildData state for %s: %s\n", guildID, err)
+		}
+	}
+	if data, exists, err := guildSettings.Encode(guildID); exists {
+		if err == nil {
+			err = stateSaveEncoded(StateBucketGuildSettings, map[string][]byte{guildID: data})
+		}
+		if err != nil {
+			Error.Printf("Error saving guildSettings state for %s: %s\n", guildID, err)
+		}
+	}
+	if data, exists, err := starboards.Encode(guildID); exists {
+		if err == nil {
+			err = stateSaveEncoded(StateBucketStarboards, map[string][]byte{guildID: data})
+		}
+		if err != nil {
+			Error.Printf("Error saving starboard for %s: %s\n", guildID, err)
+		}
+	}
+	if data, exists, err := voiceData.Encode(guildID); exists {
+		if err == nil {
+			err = stateSaveEncoded(StateBucketVoiceData, map[string][]byte{guildID: data})
+		}
+		if err != nil {
+			Error.Printf("Error saving voiceData state for %s: %s\n", guildID, err)
+		}
+	}
+	if data, exists, err := userSettings.Encode(userID); exists {
+		if err == nil {
+			err = stateSaveEncoded(StateBucketUserSettings, map[string][]byte{userID: data})
+		}
+		if err != nil {
+			Error.Printf("Error saving userSettings state for %s: %s\n", userID, err)
+		}
+	}
+	stateSaveReminds()
+}
+
+// stateSaveReminds saves the reminders if they changed
+func stateSaveReminds() {
+	data, err := remindEntries.Encode()
+	if err == nil {
+		err = stateSaveEncoded(StateBucketReminds, map[string][]byte{StateKeyAll: data})
+	}
+	if err != nil {
+		Error.Printf("Error saving reminders: %s\n", err)
+	}
+}
+
+// stateSaveAll saves every entry that changed and removes the entries that no longer exist
+//
+// Each guild's data is locked while it's saved, so this must not be called while holding any guild data lock.
+func stateSaveAll() {
+	if stateStore == nil {
+		return
+	}
+
+	for guildID, data := range guildData.All() {
+		data.Lock()
+		err := stateSaveEntries(StateBucketGuildData, map[string]interface{}{guildID: data})
+		data.Unlock()
+		if err != nil {
+			Error.Printf("Error saving guildData state for %s: %s\n", guildID, err)
+		}
+	}
+
+	encoded, err := guildSettings.EncodeAll()
+	if err == nil {
+		err = stateSaveEncoded(StateBucketGuildSettings, encoded)
+	}
+	if err != nil {
+		Error.Printf("Error saving guildSettings state: %s\n", err)
+	}
+
+	encoded, err = userSettings.EncodeAll()
+	if err == nil {
+		err = stateSaveEncoded(StateBucketUserSettings, encoded)
+	}
+	if err != nil {
+		Error.Printf("Error saving userSettings state: %s\n", err)
+	}
+
+	encoded, err = starboards.EncodeAll()
+	if err == nil {
+		err = stateSaveEncoded(StateBucketStarboards, encoded)
+	}
+	if err != nil {
+		Error.Printf("Error saving starboards: %s\n", err)
+	}
+
+	stateSaveReminds()
+
+	encoded, err = voiceData.EncodeAll()
+	if err == nil {
+		err = stateSaveEncoded(StateBucketVoiceData, encoded)
+	}
+	if err != nil {
+		Error.Printf("Error saving voiceData state: %s\n", err)
+	}
+
+	stateDeleteMissing(StateBucketGuildData, func(key string) bool { _, exists := guildData.Load(key); return exists })
+	stateDeleteMissing(StateBucketGuildSettings, func(key string) bool { _, exists := guildSettings.Load(key); return exists })
+	stateDeleteMissing(StateBucketUserSettings, func(key string) bool { _, exists := userSettings.Load(key); return exists })
+	stateDeleteMissing(StateBucketStarboards, func(key string) bool { _, exists := starboards.Load(key); return exists })
+	stateDeleteMissing(StateBucketVoiceData, func(key string) bool { _, exists := voiceData.Load(key); return exists })
+}
+
+// stateClose closes the state store, which must not be used afterwards
+func stateClose() {
+	if stateStore == nil {
+		return
+	}
+	if err := stateStore.Close(); err != nil {
+		Error.Printf("Error closing the state store: %s\n", err)
+	}
+	stateStore = nil
+}
+
+// stateRestoreAll opens the state store and loads every entry from it
+//
+// The legacy state files are imported first if they haven't been, and the state is migrated to the current schema version.
+// Returns an error if the state couldn't be migrated, in which case nothing was loaded.
+func stateRestoreAll() error {
+	store, err := openStateStore(getBotData().BotOptions.StateBackend)
+	if err != nil {
+		Error.Printf("Error opening the state store, the state will not be saved: %s\n", err)
+		return nil
+	}
+	stateStore = store
+
+	if _, imported := stateGetMeta(StateKeyImported); !imported {
+		if err := stateImportLegacy(); err != nil {
+			Error.Printf("Error importing legacy state files: %s\n", err)
+		}
+	}
+
+	if err := stateMigrate(); err != nil {
+		stateClose() //Nothing may be saved over the state until it's migrated
+		return err
+	}
+
+	stateRestoreBucket(StateBucketGuildData, func(key string, data []byte) error {
+		entry := &GuildData{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		guildData.Store(key, entry)
+		return nil
+	})
+	stateRestoreBucket(StateBucketGuildSettings, func(key string, data []byte) error {
+		entry := &GuildSettings{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		guildSettings.Store(key, entry)
+		return nil
+	})
+	stateRestoreBucket(StateBucketUserSettings, func(key string, data []byte) error {
+		entry := &UserSettings{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		userSettings.Store(key, entry)
+		return nil
+	})
+	stateRestoreBucket(StateBucketStarboards, func(key string, data []byte) error {
+		entry := &Starboard{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		starboards.Store(key, entry)
+		return nil
+	})
+	stateRestoreBucket(StateBucketReminds, func(key string, data []byte) error {
+		entries := make([]RemindEntry, 0)
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return err
+		}
+		remindEntries.Replace(entries)
+		return nil
+	})
+	stateRestoreBucket(StateBucketVoiceData, func(key string, data []byte) error {
+		entry := &Voice{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		voiceData.Store(key, entry)
+		return nil
+	})
+	return nil
+}
+
+// stateGetMeta returns the value of a key in the meta bucket and whether or not it exists
+func stateGetMeta(key string) ([]byte, bool) {
+	var value []byte
+	exists := false
+	stateStore.Load(StateBucketMeta, func(metaKey string, data []byte) error {
+		if metaKey == key {
+			value = append([]byte{}, data...) //BoltDB reuses the memory once the transaction ends
+			exists = true
+		}
+		return nil
+	})
+	return value, exists
+}
+
+// stateSetMeta sets the value of a key in the meta bucket
+func stateSetMeta(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return stateStore.Save(StateBucketMeta, map[string][]byte{key: data})
+}
+
+// stateRestoreBucket loads every entry of a bucket, remembering what was loaded so unchanged entries aren't saved again
+func stateRestoreBucket(bucket string, load func(key string, data []byte) error) {
+	err := stateStore.Load(bucket, func(key string, data []byte) error {
+		if err := load(key, data); err != nil {
+			Error.Printf("Error loading %s state for %s: %s\n", bucket, key, err)
+			return nil //One broken entry shouldn't lose the rest
+		}
+		stateHashesLock.Lock()
+		stateHashes[bucket+"/"+key] = stateHash(data)
+		stateHashesLock.Unlock()
+		return nil
+	})
+	if err != nil {
+		Error.Printf("Error loading %s state: %s\n", bucket, err)
+	}
+}
+
+// stateImportLegacy copies the entries of the state files written before the state store existed into the store and renames the files out of the way
+//
+// The entries are copied as they are, so they're migrated along with the rest of the state.
+func stateImportLegacy() error {
+	legacyFiles := map[string]string{
+		"guildData.json":     StateBucketGuildData,
+		"guildSettings.json": StateBucketGuildSettings,
+		"userSettings.json":  StateBucketUserSettings,
+		"starboards.json":    StateBucketStarboards,
+		"reminds.json":       StateBucketReminds,
+		"voiceData.json":     StateBucketVoiceData,
+	}
+	found := make([]string, 0)
+	for file, bucket := range legacyFiles {
+		path := filepath.Join(StateDir, file)
+		dataJSON, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err //Leave the files alone until they can be imported
+		}
+
+		entries := make(map[string][]byte)
+		if bucket == StateBucketReminds {
+			entries[StateKeyAll] = dataJSON
+		} else {
+			rawEntries := make(map[string]json.RawMessage)
+			if err := json.Unmarshal(dataJSON, &rawEntries); err != nil {
+				return fmt.Errorf("error parsing %s: %v", path, err)
+			}
+			for key, data := range rawEntries {
+				entries[key] = data
+			}
+		}
+		if err := stateStore.Save(bucket, entries); err != nil {
+			return err
+		}
+		found = append(found, path)
+	}
+
+	if len(found) > 0 {
+		Info.Printf("Imported %d legacy state file(s)\n", len(found))
+	}
+	if err := stateSetMeta(StateKeyImported, time.Now()); err != nil {
+		return err
+	}
+	for _, path := range found {
+		if err := os.Rename(path, path+".imported"); err != nil {
+			Error.Printf("Error renaming imported state file %s: %s\n", path, err)
+		}
+	}
+	return nil
+}
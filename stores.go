@@ -0,0 +1,610 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// keyedStore holds entries by key behind a read/write lock, and is wrapped by a typed store for each kind of entry
+//
+// The lock guards the map itself, as well as the entries while they're changed with update or read with encode and copy.
+// Entries handed out by load and items are shared, so changes to them must go through update to be seen whole by
+// the API and the state saver.
+type keyedStore struct {
+	sync.RWMutex
+	entries map[string]interface{}
+}
+
+func newKeyedStore() keyedStore {
+	return keyedStore{entries: make(map[string]interface{})}
+}
+
+func (store *keyedStore) load(key string) (interface{}, bool) {
+	store.RLock()
+	defer store.RUnlock()
+	entry, exists := store.entries[key]
+	return entry, exists
+}
+
+func (store *keyedStore) loadOrStore(key string, entry interface{}) (interface{}, bool) {
+	store.Lock()
+	defer store.Unlock()
+	if existing, exists := store.entries[key]; exists {
+		return existing, true
+	}
+	store.entries[key] = entry
+	return entry, false
+}
+
+func (store *keyedStore) set(key string, entry interface{}) {
+	store.Lock()
+	defer store.Unlock()
+	store.entries[key] = entry
+}
+
+func (store *keyedStore) remove(key string) {
+	store.Lock()
+	defer store.Unlock()
+	delete(store.entries, key)
+}
+
+func (store *keyedStore) len() int {
+	store.RLock()
+	defer store.RUnlock()
+	return len(store.entries)
+}
+
+// items returns a copy of the map, so it can be ranged over while entries are added and removed
+func (store *keyedStore) items() map[string]interface{} {
+	store.RLock()
+	defer store.RUnlock()
+	items := make(map[string]interface{}, len(store.entries))
+	for key, entry := range store.entries {
+		items[key] = entry
+	}
+	return items
+}
+
+// update runs fn on an entry while holding the write lock, returning false if the entry doesn't exist
+func (store *keyedStore) update(key string, fn func(entry interface{})) bool {
+	store.Lock()
+	defer store.Unlock()
+	entry, exists := store.entries[key]
+	if !exists {
+		return false
+	}
+	fn(entry)
+	return true
+}
+
+// view runs fn on an entry while holding the read lock, returning false if the entry doesn't exist
+func (store *keyedStore) view(key string, fn func(entry interface{})) bool {
+	store.RLock()
+	defer store.RUnlock()
+	entry, exists := store.entries[key]
+	if !exists {
+		return false
+	}
+	fn(entry)
+	return true
+}
+
+// encode returns the JSON of an entry, encoded while holding the read lock
+func (store *keyedStore) encode(key string) ([]byte, bool, error) {
+	store.RLock()
+	defer store.RUnlock()
+	entry, exists := store.entries[key]
+	if !exists {
+		return nil, false, nil
+	}
+	data, err := json.Marshal(entry)
+	return data, true, err
+}
+
+// encodeAll returns the JSON of every entry, encoded while holding the read lock
+func (store *keyedStore) encodeAll() (map[string][]byte, error) {
+	store.RLock()
+	defer store.RUnlock()
+	encoded := make(map[string][]byte, len(store.entries))
+	for key, entry := range store.entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		encoded[key] = data
+	}
+	return encoded, nil
+}
+
+// copy decodes a deep copy of an entry into copied, which must be a pointer to a new entry
+func (store *keyedStore) copy(key string, copied interface{}) bool {
+	data, exists, err := store.encode(key)
+	if !exists || err != nil {
+		return false
+	}
+	return json.Unmarshal(data, copied) == nil
+}
+
+// GuildDataStore holds the GuildData of each guild and direct message channel, where key = guild or channel ID
+//
+// GuildData guards its own fields, so the store only guards which entries exist.
+type GuildDataStore struct {
+	store keyedStore
+}
+
+// NewGuildDataStore returns an empty GuildDataStore
+func NewGuildDataStore() *GuildDataStore {
+	return &GuildDataStore{store: newKeyedStore()}
+}
+
+// Get returns the data of a guild, or nil if there is none
+func (store *GuildDataStore) Get(guildID string) *GuildData {
+	data, _ := store.Load(guildID)
+	return data
+}
+
+// Load returns the data of a guild and whether or not it exists
+func (store *GuildDataStore) Load(guildID string) (*GuildData, bool) {
+	entry, exists := store.store.load(guildID)
+	if !exists {
+		return nil, false
+	}
+	return entry.(*GuildData), true
+}
+
+// LoadOrStore returns the data of a guild, storing data first if there is none
+func (store *GuildDataStore) LoadOrStore(guildID string, data *GuildData) *GuildData {
+	entry, _ := store.store.loadOrStore(guildID, data)
+	return entry.(*GuildData)
+}
+
+// Store sets the data of a guild
+func (store *GuildDataStore) Store(guildID string, data *GuildData) {
+	store.store.set(guildID, data)
+}
+
+// Delete removes the data of a guild
+func (store *GuildDataStore) Delete(guildID string) {
+	store.store.remove(guildID)
+}
+
+// All returns a copy of the map of guild data
+func (store *GuildDataStore) All() map[string]*GuildData {
+	all := make(map[string]*GuildData)
+	for guildID, entry := range store.store.items() {
+		all[guildID] = entry.(*GuildData)
+	}
+	return all
+}
+
+// Len returns the amount of guilds with data
+func (store *GuildDataStore) Len() int {
+	return store.store.len()
+}
+
+// GuildSettingsStore holds the settings of each guild, where key = guild ID
+type GuildSettingsStore struct {
+	store keyedStore
+}
+
+// NewGuildSettingsStore returns an empty GuildSettingsStore
+func NewGuildSettingsStore() *GuildSettingsStore {
+	return &GuildSettingsStore{store: newKeyedStore()}
+}
+
+// Get returns the settings of a guild, or nil if there are none
+func (store *GuildSettingsStore) Get(guildID string) *GuildSettings {
+	settings, _ := store.Load(guildID)
+	return settings
+}
+
+// Load returns the settings of a guild and whether or not they exist
+func (store *GuildSettingsStore) Load(guildID string) (*GuildSettings, bool) {
+	entry, exists := store.store.load(guildID)
+	if !exists {
+		return nil, false
+	}
+	return entry.(*GuildSettings), true
+}
+
+// LoadOrStore returns the settings of a guild, storing settings first if there are none
+func (store *GuildSettingsStore) LoadOrStore(guildID string, settings *GuildSettings) *GuildSettings {
+	entry, _ := store.store.loadOrStore(guildID, settings)
+	return entry.(*GuildSettings)
+}
+
+// Store sets the settings of a guild
+func (store *GuildSettingsStore) Store(guildID string, settings *GuildSettings) {
+	store.store.set(guildID, settings)
+}
+
+// Delete removes the settings of a guild
+func (store *GuildSettingsStore) Delete(guildID string) {
+	store.store.remove(guildID)
+}
+
+// All returns a copy of the map of guild settings
+func (store *GuildSettingsStore) All() map[string]*GuildSettings {
+	all := make(map[string]*GuildSettings)
+	for guildID, entry := range store.store.items() {
+		all[guildID] = entry.(*GuildSettings)
+	}
+	return all
+}
+
+// Len returns the amount of guilds with settings
+func (store *GuildSettingsStore) Len() int {
+	return store.store.len()
+}
+
+// Update changes the settings of a guild while no one else can, returning false if the guild has no settings
+func (store *GuildSettingsStore) Update(guildID string, fn func(settings *GuildSettings)) bool {
+	return store.store.update(guildID, func(entry interface{}) {
+		fn(entry.(*GuildSettings))
+	})
+}
+
+// View reads the settings of a guild while no one can change them, returning false if the guild has no settings
+//
+// fn must not change anything or call into the store, and shouldn't keep anything it reads past returning.
+func (store *GuildSettingsStore) View(guildID string, fn func(settings *GuildSettings)) bool {
+	return store.store.view(guildID, func(entry interface{}) {
+		fn(entry.(*GuildSettings))
+	})
+}
+
+// Snapshot returns a deep copy of the settings of a guild, which is safe to read or encode while the settings change
+func (store *GuildSettingsStore) Snapshot(guildID string) (*GuildSettings, bool) {
+	settings := &GuildSettings{}
+	if !store.store.copy(guildID, settings) {
+		return nil, false
+	}
+	return settings, true
+}
+
+// Encode returns the JSON of the settings of a guild
+func (store *GuildSettingsStore) Encode(guildID string) ([]byte, bool, error) {
+	return store.store.encode(guildID)
+}
+
+// EncodeAll returns the JSON of the settings of every guild
+func (store *GuildSettingsStore) EncodeAll() (map[string][]byte, error) {
+	return store.store.encodeAll()
+}
+
+// UserSettingsStore holds the settings of each user, where key = user ID
+type UserSettingsStore struct {
+	store keyedStore
+}
+
+// NewUserSettingsStore returns an empty UserSettingsStore
+func NewUserSettingsStore() *UserSettingsStore {
+	return &UserSettingsStore{store: newKeyedStore()}
+}
+
+// Get returns the settings of a user, or nil if there are none
+func (store *UserSettingsStore) Get(userID string) *UserSettings {
+	settings, _ := store.Load(userID)
+	return settings
+}
+
+// Load returns the settings of a user and whether or not they exist
+func (store *UserSettingsStore) Load(userID string) (*UserSettings, bool) {
+	entry, exists := store.store.load(userID)
+	if !exists {
+		return nil, false
+	}
+	return entry.(*UserSettings), true
+}
+
+// LoadOrStore returns the settings of a user, storing settings first if there are none
+func (store *UserSettingsStore) LoadOrStore(userID string, settings *UserSettings) *UserSettings {
+	entry, _ := store.store.loadOrStore(userID, settings)
+	return entry.(*UserSettings)
+}
+
+// Store sets the settings of a user
+func (store *UserSettingsStore) Store(userID string, settings *UserSettings) {
+	store.store.set(userID, settings)
+}
+
+// Delete removes the settings of a user
+func (store *UserSettingsStore) Delete(userID string) {
+	store.store.remove(userID)
+}
+
+// All returns a copy of the map of user settings
+func (store *UserSettingsStore) All() map[string]*UserSettings {
+	all := make(map[string]*UserSettings)
+	for userID, entry := range store.store.items() {
+		all[userID] = entry.(*UserSettings)
+	}
+	return all
+}
+
+// Len returns the amount of users with settings
+func (store *UserSettingsStore) Len() int {
+	return store.store.len()
+}
+
+// Update changes the settings of a user while no one else can, returning false if the user has no settings
+func (store *UserSettingsStore) Update(userID string, fn func(settings *UserSettings)) bool {
+	return store.store.update(userID, func(entry interface{}) {
+		fn(entry.(*UserSettings))
+	})
+}
+
+// View reads the settings of a user while no one can change them, returning false if the user has no settings
+//
+// fn must not change anything or call into the store, and shouldn't keep anything it reads past returning.
+func (store *UserSettingsStore) View(userID string, fn func(settings *UserSettings)) bool {
+	return store.store.view(userID, func(entry interface{}) {
+		fn(entry.(*UserSettings))
+	})
+}
+
+// Snapshot returns a deep copy of the settings of a user, which is safe to read or encode while the settings change
+func (store *UserSettingsStore) Snapshot(userID string) (*UserSettings, bool) {
+	settings := &UserSettings{}
+	if !store.store.copy(userID, settings) {
+		return nil, false
+	}
+	return settings, true
+}
+
+// Encode returns the JSON of the settings of a user
+func (store *UserSettingsStore) Encode(userID string) ([]byte, bool, error) {
+	return store.store.encode(userID)
+}
+
+// EncodeAll returns the JSON of the settings of every user
+func (store *UserSettingsStore) EncodeAll() (map[string][]byte, error) {
+	return store.store.encodeAll()
+}
+
+// StarboardStore holds the starboard of each guild, where key = guild ID
+type StarboardStore struct {
+	store keyedStore
+}
+
+// NewStarboardStore returns an empty StarboardStore
+func NewStarboardStore() *StarboardStore {
+	return &StarboardStore{store: newKeyedStore()}
+}
+
+// Get returns the starboard of a guild, or nil if there is none
+func (store *StarboardStore) Get(guildID string) *Starboard {
+	starboard, _ := store.Load(guildID)
+	return starboard
+}
+
+// Load returns the starboard of a guild and whether or not it exists
+func (store *StarboardStore) Load(guildID string) (*Starboard, bool) {
+	entry, exists := store.store.load(guildID)
+	if !exists {
+		return nil, false
+	}
+	return entry.(*Starboard), true
+}
+
+// LoadOrStore returns the starboard of a guild, storing starboard first if there is none
+func (store *StarboardStore) LoadOrStore(guildID string, starboard *Starboard) *Starboard {
+	entry, _ := store.store.loadOrStore(guildID, starboard)
+	return entry.(*Starboard)
+}
+
+// Store sets the starboard of a guild
+func (store *StarboardStore) Store(guildID string, starboard *Starboard) {
+	store.store.set(guildID, starboard)
+}
+
+// Delete removes the starboard of a guild
+func (store *StarboardStore) Delete(guildID string) {
+	store.store.remove(guildID)
+}
+
+// All returns a copy of the map of starboards
+func (store *StarboardStore) All() map[string]*Starboard {
+	all := make(map[string]*Starboard)
+	for guildID, entry := range store.store.items() {
+		all[guildID] = entry.(*Starboard)
+	}
+	return all
+}
+
+// Len returns the amount of guilds with a starboard
+func (store *StarboardStore) Len() int {
+	return store.store.len()
+}
+
+// Update changes the starboard of a guild while no one else can, returning false if the guild has no starboard
+func (store *StarboardStore) Update(guildID string, fn func(starboard *Starboard)) bool {
+	return store.store.update(guildID, func(entry interface{}) {
+		fn(entry.(*Starboard))
+	})
+}
+
+// View reads the starboard of a guild while no one can change it, returning false if the guild has no starboard
+//
+// fn must not change anything or call into the store, and shouldn't keep anything it reads past returning.
+func (store *StarboardStore) View(guildID string, fn func(starboard *Starboard)) bool {
+	return store.store.view(guildID, func(entry interface{}) {
+		fn(entry.(*Starboard))
+	})
+}
+
+// Snapshot returns a deep copy of the starboard of a guild, which is safe to read or encode while the starboard changes
+func (store *StarboardStore) Snapshot(guildID string) (*Starboard, bool) {
+	starboard := &Starboard{}
+	if !store.store.copy(guildID, starboard) {
+		return nil, false
+	}
+	return starboard, true
+}
+
+// Encode returns the JSON of the starboard of a guild
+func (store *StarboardStore) Encode(guildID string) ([]byte, bool, error) {
+	return store.store.encode(guildID)
+}
+
+// EncodeAll returns the JSON of the starboard of every guild
+func (store *StarboardStore) EncodeAll() (map[string][]byte, error) {
+	return store.store.encodeAll()
+}
+
+// VoiceStore holds the voice session of each guild, where key = guild ID
+//
+// Voice guards its own fields, so the store only guards which entries exist.
+type VoiceStore struct {
+	store keyedStore
+}
+
+// NewVoiceStore returns an empty VoiceStore
+func NewVoiceStore() *VoiceStore {
+	return &VoiceStore{store: newKeyedStore()}
+}
+
+// Get returns the voice session of a guild, or nil if there is none
+func (store *VoiceStore) Get(guildID string) *Voice {
+	voice, _ := store.Load(guildID)
+	return voice
+}
+
+// Load returns the voice session of a guild and whether or not it exists
+func (store *VoiceStore) Load(guildID string) (*Voice, bool) {
+	entry, exists := store.store.load(guildID)
+	if !exists {
+		return nil, false
+	}
+	return entry.(*Voice), true
+}
+
+// LoadOrStore returns the voice session of a guild, storing voice first if there is none
+func (store *VoiceStore) LoadOrStore(guildID string, voice *Voice) *Voice {
+	entry, _ := store.store.loadOrStore(guildID, voice)
+	return entry.(*Voice)
+}
+
+// Store sets the voice session of a guild
+func (store *VoiceStore) Store(guildID string, voice *Voice) {
+	store.store.set(guildID, voice)
+}
+
+// Delete removes the voice session of a guild
+func (store *VoiceStore) Delete(guildID string) {
+	store.store.remove(guildID)
+}
+
+// All returns a copy of the map of voice sessions
+func (store *VoiceStore) All() map[string]*Voice {
+	all := make(map[string]*Voice)
+	for guildID, entry := range store.store.items() {
+		all[guildID] = entry.(*Voice)
+	}
+	return all
+}
+
+// Len returns the amount of guilds with a voice session
+func (store *VoiceStore) Len() int {
+	return store.store.len()
+}
+
+// Update changes the voice session of a guild while holding its lock, returning false if there is none
+func (store *VoiceStore) Update(guildID string, fn func(voice *Voice)) bool {
+	voice, exists := store.Load(guildID)
+	if !exists {
+		return false
+	}
+	voice.Lock()
+	defer voice.Unlock()
+	fn(voice)
+	return true
+}
+
+// Encode returns the JSON of a guild's voice session, encoded while holding its lock, and whether or not it exists
+func (store *VoiceStore) Encode(guildID string) ([]byte, bool, error) {
+	voice, exists := store.Load(guildID)
+	if !exists {
+		return nil, false, nil
+	}
+	voice.Lock()
+	defer voice.Unlock()
+	data, err := json.Marshal(voice)
+	return data, true, err
+}
+
+// EncodeAll returns the JSON of every voice session, each encoded while holding its lock
+func (store *VoiceStore) EncodeAll() (map[string][]byte, error) {
+	encoded := make(map[string][]byte)
+	for guildID := range store.All() {
+		data, exists, err := store.Encode(guildID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			encoded[guildID] = data
+		}
+	}
+	return encoded, nil
+}
+
+// RemindStore holds every pending reminder
+type RemindStore struct {
+	sync.RWMutex
+	entries []RemindEntry
+}
+
+// NewRemindStore returns an empty RemindStore
+func NewRemindStore() *RemindStore {
+	return &RemindStore{entries: make([]RemindEntry, 0)}
+}
+
+// Add adds a reminder
+func (store *RemindStore) Add(entry RemindEntry) {
+	store.Lock()
+	defer store.Unlock()
+	store.entries = append(store.entries, entry)
+}
+
+// All returns a copy of every reminder
+func (store *RemindStore) All() []RemindEntry {
+	store.RLock()
+	defer store.RUnlock()
+	return append(make([]RemindEntry, 0, len(store.entries)), store.entries...)
+}
+
+// Remove removes every reminder that match returns true for, returning how many were removed
+func (store *RemindStore) Remove(match func(entry RemindEntry) bool) int {
+	store.Lock()
+	defer store.Unlock()
+	kept := make([]RemindEntry, 0, len(store.entries))
+	for _, entry := range store.entries {
+		if !match(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(store.entries) - len(kept)
+	store.entries = kept
+	return removed
+}
+
+// Replace replaces every reminder
+func (store *RemindStore) Replace(entries []RemindEntry) {
+	store.Lock()
+	defer store.Unlock()
+	store.entries = append(make([]RemindEntry, 0, len(entries)), entries...)
+}
+
+// Len returns the amount of reminders
+func (store *RemindStore) Len() int {
+	store.RLock()
+	defer store.RUnlock()
+	return len(store.entries)
+}
+
+// Encode returns the JSON of every reminder
+func (store *RemindStore) Encode() ([]byte, error) {
+	store.RLock()
+	defer store.RUnlock()
+	return json.Marshal(store.entries)
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+//These tests are meant to be ran with the race detector: go test -race -run 'Transfer|GuildSettings'
+
+const (
+	transferTestUsers     = 8   //How many users send credits to each other at once
+	transferTestTransfers = 50  //How many transfers each user sends
+	transferTestBalance   = 100 //How many credits each user starts with, low enough for some transfers to fail
+	transferTestCredits   = 7   //How many credits each transfer sends
+)
+
+// setupTransferTest configures the bot with its commands and gives every test user the starting balance
+func setupTransferTest(t *testing.T) {
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &BotData{DiscordSession: session}
+	initCommands(config)
+
+	oldBotData, oldUserSettings := getBotData(), userSettings
+	setBotData(config)
+	userSettings = NewUserSettingsStore()
+	t.Cleanup(func() {
+		setBotData(oldBotData)
+		userSettings = oldUserSettings
+	})
+
+	for i := 0; i < transferTestUsers; i++ {
+		userSettings.Store(transferTestUserID(i), &UserSettings{Balance: transferTestBalance})
+	}
+
+	discardLogs(t)
+}
+
+// discardLogs silences the loggers until the test finishes
+func discardLogs(t *testing.T) {
+	oldDebug, oldInfo, oldWarning, oldError := Debug, Info, Warning, Error
+	logger := log.New(ioutil.Discard, "", 0)
+	Debug, Info, Warning, Error = logger, logger, logger, logger
+	t.Cleanup(func() {
+		Debug, Info, Warning, Error = oldDebug, oldInfo, oldWarning, oldError
+	})
+}
+
+func transferTestUserID(user int) string {
+	return strconv.Itoa(1000 + user)
+}
+
+// runTransfer runs the transfer command as a user would from their direct messages
+func runTransfer(sender, target int, credits int) *CommandResponse {
+	senderUser := &discordgo.User{ID: transferTestUserID(sender), Username: "sender"}
+	targetUser := &discordgo.User{ID: transferTestUserID(target), Username: "target"}
+	env := &CommandEnvironment{
+		Channel: &discordgo.Channel{ID: "dm" + senderUser.ID, Type: discordgo.ChannelTypeDM},
+		Message: &discordgo.Message{Author: senderUser, Mentions: []*discordgo.User{targetUser}},
+		User:    senderUser,
+		Command: "transfer",
+	}
+	return callCommandResponse("transfer", []string{strconv.Itoa(credits), "<@!" + targetUser.ID + ">"}, env)
+}
+
+func TestTransferConcurrent(t *testing.T) {
+	setupTransferTest(t)
+
+	var wait sync.WaitGroup
+	start := make(chan struct{})
+	for sender := 0; sender < transferTestUsers; sender++ {
+		//Every user sends to two others, so each balance is spent and refilled at the same time
+		for _, target := range []int{(sender + 1) % transferTestUsers, (sender + 3) % transferTestUsers} {
+			wait.Add(1)
+			go func(sender, target int) {
+				defer wait.Done()
+				<-start
+				for i := 0; i < transferTestTransfers; i++ {
+					if response := runTransfer(sender, target, transferTestCredits); response == nil || response.Embed() == nil {
+						t.Errorf("expected the transfer from %d to %d to respond with an embed", sender, target)
+						return
+					}
+				}
+			}(sender, target)
+		}
+	}
+	close(start)
+	wait.Wait()
+
+	total := 0
+	for userID, settings := range userSettings.All() {
+		if settings.Balance < 0 {
+			t.Errorf("expected the balance of %s to never go below zero, got %d", userID, settings.Balance)
+		}
+		total += settings.Balance
+	}
+	if expected := transferTestUsers * transferTestBalance; total != expected {
+		t.Errorf("expected the transfers to keep the total balance at %d, got %d", expected, total)
+	}
+}
+
+func TestTransferInsufficientCredits(t *testing.T) {
+	setupTransferTest(t)
+
+	response := runTransfer(0, 1, transferTestBalance+1)
+	if embed := response.Embed(); embed == nil || !isErrorEmbed(embed) {
+		t.Fatal("expected an error for a transfer of more credits than the sender has")
+	}
+	if settings, _ := userSettings.Snapshot(transferTestUserID(0)); settings.Balance != transferTestBalance {
+		t.Errorf("expected the failed transfer to leave the sender's balance at %d, got %d", transferTestBalance, settings.Balance)
+	}
+	if settings, _ := userSettings.Snapshot(transferTestUserID(1)); settings.Balance != transferTestBalance {
+		t.Errorf("expected the failed transfer to leave the target's balance at %d, got %d", transferTestBalance, settings.Balance)
+	}
+}
+
+const (
+	guildSettingsTestWriters = 4  //How many bot admins change the guild's settings at once
+	guildSettingsTestChanges = 20 //How many times each of them adds and removes their settings
+	guildSettingsTestOwnerID = "owner"
+)
+
+// setupGuildSettingsTest configures the bot with its commands and a guild with a role for each writer, returning an environment in that guild
+func setupGuildSettingsTest(t *testing.T) *CommandEnvironment {
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &BotData{DiscordSession: session, BotOwnerID: guildSettingsTestOwnerID, CommandPrefix: "cc!"}
+	initCommands(config)
+
+	oldBotData, oldGuildData, oldGuildSettings := getBotData(), guildData, guildSettings
+	setBotData(config)
+	guildData = NewGuildDataStore()
+	guildSettings = NewGuildSettingsStore()
+	t.Cleanup(func() {
+		setBotData(oldBotData)
+		guildData = oldGuildData
+		guildSettings = oldGuildSettings
+	})
+	discardLogs(t)
+
+	guild := &discordgo.Guild{ID: "guild", Channels: []*discordgo.Channel{{ID: "channel", GuildID: "guild"}}}
+	member := &discordgo.Member{GuildID: guild.ID, User: &discordgo.User{ID: "member"}}
+	for i := 0; i < guildSettingsTestWriters; i++ {
+		roleID := guildSettingsTestRoleID(i)
+		guild.Roles = append(guild.Roles, &discordgo.Role{ID: roleID, Name: "role" + roleID})
+		member.Roles = append(member.Roles, roleID)
+	}
+	initializeGuildData(guild.ID)
+	initializeGuildSettings(guild.ID)
+
+	return &CommandEnvironment{Channel: guild.Channels[0], Guild: guild, User: member.User, Member: member}
+}
+
+func guildSettingsTestRoleID(writer int) string {
+	return strconv.Itoa(2000 + writer)
+}
+
+// runGuildSettingsCommand runs a command as the bot owner would in the test guild, failing the test if it responds with an error
+func runGuildSettingsCommand(t *testing.T, env *CommandEnvironment, commandName string, args ...string) {
+	owner := &discordgo.User{ID: guildSettingsTestOwnerID, Username: "owner"}
+	ownerEnv := &CommandEnvironment{
+		Channel: env.Channel,
+		Guild:   env.Guild,
+		Message: &discordgo.Message{Author: owner, ChannelID: env.Channel.ID, GuildID: env.Guild.ID},
+		User:    owner,
+		Member:  &discordgo.Member{GuildID: env.Guild.ID, User: owner},
+		Command: commandName,
+	}
+	response := callCommandResponse(commandName, args, ownerEnv)
+	if response == nil || response.Embed() == nil {
+		t.Errorf("expected %s %v to respond with an embed", commandName, args)
+		return
+	}
+	if embed := response.Embed(); isErrorEmbed(embed) {
+		t.Errorf("expected %s %v to succeed, got %s: %s", commandName, args, embed.Title, embed.Description)
+	}
+}
+
+func TestGuildSettingsConcurrent(t *testing.T) {
+	env := setupGuildSettingsTest(t)
+	ping := getBotData().Commands["ping"]
+
+	var wait, readers sync.WaitGroup
+	start, done := make(chan struct{}), make(chan struct{})
+	for writer := 0; writer < guildSettingsTestWriters; writer++ {
+		wait.Add(1)
+		go func(writer int) {
+			defer wait.Done()
+			<-start
+			role := "<@&" + guildSettingsTestRoleID(writer) + ">"
+			name := "custom" + strconv.Itoa(writer)
+			prefix := strconv.Itoa(writer) + "!"
+			for i := 0; i < guildSettingsTestChanges; i++ {
+				runGuildSettingsCommand(t, env, "server", "permissions", "deny", "ping", role)
+				runGuildSettingsCommand(t, env, "customcmd", "add", name, "hello {user}")
+				runGuildSettingsCommand(t, env, "bot", "prefix", "add", prefix)
+				runGuildSettingsCommand(t, env, "server", "permissions", "clear", "ping", role)
+				runGuildSettingsCommand(t, env, "customcmd", "remove", name)
+				runGuildSettingsCommand(t, env, "bot", "prefix", "remove", prefix)
+			}
+		}(writer)
+	}
+
+	//Read the settings the way every message does while they change
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		<-start
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			checkCommandPermissions("ping", ping, env)
+			checkCommandEnabled("ping", ping, env)
+			getGuildPrefixes(env.Guild.ID)
+			matchGuildPrefix("0!ping", env.Guild.ID)
+			isKnownCommand("custom0", env)
+			if _, err := guildSettings.EncodeAll(); err != nil {
+				t.Errorf("expected the guild settings to encode, got %v", err)
+				return
+			}
+		}
+	}()
+
+	close(start)
+	wait.Wait()
+	close(done)
+	readers.Wait()
+
+	settings, _ := guildSettings.Snapshot(env.Guild.ID)
+	if len(settings.CommandPermissions) != 0 {
+		t.Errorf("expected every permission rule to be cleared, got %v", settings.CommandPermissions)
+	}
+	if len(settings.CustomCommands) != 0 {
+		t.Errorf("expected every custom command to be removed, got %v", settings.CustomCommands)
+	}
+	if prefixes := getGuildPrefixes(env.Guild.ID); len(prefixes) != 1 || prefixes[0] != getBotData().CommandPrefix {
+		t.Errorf("expected only the bot's prefix to be left, got %v", prefixes)
+	}
+}
+
+func TestGuildSettingsDenyRule(t *testing.T) {
+	env := setupGuildSettingsTest(t)
+	ping := getBotData().Commands["ping"]
+
+	runGuildSettingsCommand(t, env, "server", "permissions", "deny", "ping", "<@&"+guildSettingsTestRoleID(0)+">")
+	if embed := checkCommandPermissions("ping", ping, env); embed == nil || !isErrorEmbed(embed) {
+		t.Fatal("expected a member with a denied role to be refused")
+	}
+
+	runGuildSettingsCommand(t, env, "server", "permissions", "allow", "ping", "<@&"+guildSettingsTestRoleID(1)+">")
+	if embed := checkCommandPermissions("ping", ping, env); embed != nil {
+		t.Errorf("expected a member with an allowed role to be let through, got %s", embed.Title)
+	}
+}
+
+func TestGuildSettingsDenyRuleGuildAdmin(t *testing.T) {
+	env := setupGuildSettingsTest(t)
+	server := getBotData().Commands["server"]
+
+	adminRole := &discordgo.Role{ID: "admins", Name: "admins", Permissions: discordgo.PermissionAdministrator}
+	admin := &discordgo.Member{GuildID: env.Guild.ID, User: &discordgo.User{ID: "3000"}, Roles: []string{adminRole.ID}}
+	state := getBotData().DiscordSession.State
+	state.GuildAdd(&discordgo.Guild{ID: env.Guild.ID, Roles: []*discordgo.Role{adminRole}})
+	state.ChannelAdd(env.Channel)
+	state.MemberAdd(admin)
+	adminEnv := &CommandEnvironment{Channel: env.Channel, Guild: env.Guild, User: admin.User, Member: admin}
+
+	runGuildSettingsCommand(t, env, "server", "permissions", "deny", "server", "<@3000>")
+	if embed := checkCommandPermissions("server", server, adminEnv); embed != nil {
+		t.Errorf("expected a guild administrator to ignore being denied a settings command, got %s", embed.Title)
+	}
+}
+
+func TestGuildSettingsRemoveUnlistedAdmin(t *testing.T) {
+	env := setupGuildSettingsTest(t)
+	owner := &discordgo.User{ID: guildSettingsTestOwnerID}
+	ownerEnv := &CommandEnvironment{Channel: env.Channel, Guild: env.Guild, Message: &discordgo.Message{Author: owner}, User: owner, Command: "server"}
+
+	response := callCommandResponse("server", []string{"permissions", "admin", "remove", "<@&" + guildSettingsTestRoleID(0) + ">"}, ownerEnv)
+	if embed := response.Embed(); embed == nil || !isErrorEmbed(embed) {
+		t.Fatal("expected an error for revoking bot admin rights from a role that doesn't have them")
+	}
+
+	runGuildSettingsCommand(t, env, "server", "permissions", "admin", "add", "<@&"+guildSettingsTestRoleID(0)+">")
+	runGuildSettingsCommand(t, env, "server", "permissions", "admin", "remove", "<@&"+guildSettingsTestRoleID(0)+">")
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// SuggestionMaxResults contains how many commands may be suggested for an unknown command
+	SuggestionMaxResults = 3
+
+	// SuggestionExpiry contains how long the reactions on a suggestion can be used to run a suggested command
+	SuggestionExpiry = 2 * time.Minute
+)
+
+var (
+	// SuggestionReactions contains the reactions offered for each suggestion when there's more than one
+	SuggestionReactions = []string{"1\ufe0f\u20e3", "2\ufe0f\u20e3", "3\ufe0f\u20e3"}
+
+	// SuggestionConfirmReaction contains the reaction offered when there's only one suggestion
+	SuggestionConfirmReaction = "✅"
+
+	commandSuggestions = &SuggestionTracker{pending: make(map[string]*CommandSuggestion)}
+)
+
+// CommandSuggestion holds the commands suggested for an unknown command and what's needed to run one of them
+type CommandSuggestion struct {
+	Commands []string            //The suggested command names, in order of the reactions offered
+	Args     []string            //The arguments the unknown command was ran with
+	Env      *CommandEnvironment //The environment the unknown command was ran in
+	Expires  time.Time           //When the suggestion can no longer be used
+}
+
+// SuggestionTracker keeps track of the suggestions awaiting a reaction, where key = the message that ran the unknown command
+type SuggestionTracker struct {
+	sync.Mutex
+
+	pending map[string]*CommandSuggestion
+}
+
+// Add tracks a suggestion for the message that ran an unknown command, pruning expired suggestions
+func (tracker *SuggestionTracker) Add(messageID string, suggestion *CommandSuggestion) {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	now := time.Now()
+	for pendingID, pending := range tracker.pending {
+		if now.After(pending.Expires) {
+			delete(tracker.pending, pendingID)
+		}
+	}
+	tracker.pending[messageID] = suggestion
+}
+
+// Take returns and stops tracking the suggestion whose response was reacted to by the user who ran it, or nil if there is none
+func (tracker *SuggestionTracker) Take(responseID, userID string) (messageID string, suggestion *CommandSuggestion) {
+	tracker.Lock()
+	defer tracker.Unlock()
+
+	for pendingID, pending := range tracker.pending {
+		if pending.Env.User.ID != userID || time.Now().After(pending.Expires) {
+			continue
+		}
+
+		dataID := getEnvironmentDataID(pending.Env)
+		guildData.Get(dataID).Lock()
+		query := guildData.Get(dataID).Queries[pendingID]
+		guildData.Get(dataID).Unlock()
+
+		if query != nil && containsString(query.MessageIDs(), responseID) {
+			delete(tracker.pending, pendingID)
+			return pendingID, pending
+		}
+	}
+	return "", nil
+}
+
+// getCommandSuggestionResponse returns a response suggesting the closest commands to an unknown command, or nil if none are close or suggestions are disabled
+func getCommandSuggestionResponse(commandName string, args []string, env *CommandEnvironment) *CommandResponse {
+	if env.Guild != nil {
+		disabled := false
+		guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+			disabled = settings.DisableSuggestions
+		})
+		if disabled {
+			return nil
+		}
+	}
+
+	suggestions := getCommandSuggestions(commandName, env)
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	commandList := make([]string, 0)
+	reactions := make([]string, 0)
+	for i, suggestion := range suggestions {
+		reaction := SuggestionReactions[i]
+		if len(suggestions) == 1 {
+			reaction = SuggestionConfirmReaction
+		}
+		commandList = append(commandList, reaction+" ``"+env.BotPrefix+strings.TrimSpace(suggestion+" "+strings.Join(args, " "))+"``")
+		reactions = append(reactions, reaction)
+	}
+
+	commandSuggestions.Add(env.Message.ID, &CommandSuggestion{Commands: suggestions, Args: args, Env: env, Expires: time.Now().Add(SuggestionExpiry)})
+
+	return &CommandResponse{
+		Embeds: []*discordgo.MessageEmbed{
+			NewGenericEmbed(env.Locale(), "Command Error - Unknown Command (UC)", "Did you mean:\n%s\n\nReact to run the command.", strings.Join(commandList, "\n")),
+		},
+		Reactions: reactions,
+	}
+}
+
+// getCommandSuggestions returns the names of the commands, aliases and custom commands closest to an unknown command that the user may run
+func getCommandSuggestions(commandName string, env *CommandEnvironment) []string {
+	commandName = strings.ToLower(commandName)
+	maxDistance := 2
+	if len(commandName) <= 3 {
+		maxDistance = 1
+	}
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	best := make(map[string]*candidate) //Only suggest the closest name for each command, where key = original command name
+
+	consider := func(name, originalName string) {
+		distance := levenshteinDistance(commandName, name)
+		if distance > maxDistance || distance >= len(commandName) {
+			return
+		}
+		if current, exists := best[originalName]; !exists || distance < current.distance || (distance == current.distance && name < current.name) {
+			best[originalName] = &candidate{name: name, distance: distance}
+		}
+	}
+
+	for name, command := range getBotData().Commands {
+		originalName := name
+		if command.IsAlternateOf != "" {
+			originalName = command.IsAlternateOf
+			if command = getBotData().Commands[originalName]; command == nil {
+				continue
+			}
+		}
+		if command.IsAdministrative && env.User.ID != getBotData().BotOwnerID {
+			continue
+		}
+		if env.Guild == nil && !command.AllowDM {
+			continue
+		}
+		if env.Guild != nil && isCommandDisabled(env.Guild.ID, originalName, command) {
+			continue
+		}
+		consider(name, originalName)
+	}
+	if env.Guild != nil {
+		customCommandNames := make([]string, 0)
+		guildSettings.View(env.Guild.ID, func(settings *GuildSettings) {
+			for name := range settings.CustomCommands {
+				customCommandNames = append(customCommandNames, name)
+			}
+		})
+		for _, name := range customCommandNames {
+			consider(name, "custom:"+name)
+		}
+	}
+
+	candidates := make([]*candidate, 0)
+	for _, c := range best {
+		candidates = append(candidates, c)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	suggestions := make([]string, 0)
+	for i := 0; i < len(candidates) && i < SuggestionMaxResults; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// levenshteinDistance returns the number of single character insertions, deletions and substitutions needed to turn a into b
+func levenshteinDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			cost := 1
+			if runesA[i-1] == runesB[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
+
+// minInt returns the smaller of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// handleSuggestionReaction runs the suggested command picked by a reaction, returning whether or not the reaction was for a suggestion
+func handleSuggestionReaction(session *discordgo.Session, reaction *discordgo.MessageReactionAdd) bool {
+	if reaction.UserID == session.State.User.ID {
+		return false
+	}
+
+	choice := -1
+	if reaction.Emoji.Name == SuggestionConfirmReaction {
+		choice = 0
+	}
+	for i, emoji := range SuggestionReactions {
+		if reaction.Emoji.Name == emoji {
+			choice = i
+			break
+		}
+	}
+	if choice == -1 {
+		return false
+	}
+
+	messageID, suggestion := commandSuggestions.Take(reaction.MessageID, reaction.UserID)
+	if suggestion == nil {
+		return false
+	}
+	if choice >= len(suggestion.Commands) {
+		return true
+	}
+
+	go func() {
+		defer recoverPanic()
+
+		env := *suggestion.Env
+		env.Command = suggestion.Commands[choice]
+		env.Context = nil
+		env.Arguments = nil
+		response := callCommandResponse(env.Command, suggestion.Args, &env)
+		if response.IsEmpty() {
+			return
+		}
+
+		dataID := getEnvironmentDataID(&env)
+		guildData.Get(dataID).Lock()
+		defer guildData.Get(dataID).Unlock()
+
+		if query := guildData.Get(dataID).Queries[messageID]; query != nil {
+			session.MessageReactionsRemoveAll(query.GetChannelID(env.Channel.ID), reaction.MessageID)
+		}
+		sendCommandResponse(session, env.Message, env.Channel, env.Guild, dataID, response, true) //Replaces the suggestion with the command's response
+		stateSaveGuild(dataID, env.User.ID)
+	}()
+	return true
+}
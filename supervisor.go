@@ -0,0 +1,364 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/dustin/go-humanize"
+)
+
+/*
+	The MASTER process supervises the bot process, restarting it whenever it exits.
+
+	A bot that exits with a code of 0 was restarted or updated on purpose and is started again right away. Any other
+	exit is a crash, which is kept in the crash history along with the end of what the bot wrote to stderr, and the bot
+	is started again after a delay that doubles with every crash in a row. Too many crashes in a short time are a crash
+	loop, which the bot owner is alerted about, and after too many crashes in a row the supervisor stops restarting the
+	bot until it's sent a SIGHUP.
+
+	What the supervisor is doing is written to SupervisorStatusFile, which can be printed with the -status flag.
+*/
+
+const (
+	SupervisorStatusFile      = "clinet.status.json" //Where the state of the supervisor and the crash history are kept
+	SupervisorMinBackoff      = 1 * time.Second      //How long to wait before restarting the bot after its first crash in a row
+	SupervisorMaxBackoff      = 5 * time.Minute      //The longest to wait before restarting the bot
+	SupervisorStableUptime    = 10 * time.Minute     //How long the bot has to run before its crashes no longer count as in a row
+	SupervisorCrashLoopCount  = 5                    //How many crashes within SupervisorCrashLoopWindow are a crash loop
+	SupervisorCrashLoopWindow = 10 * time.Minute     //How recently crashes have to happen to count towards a crash loop
+	SupervisorMaxCrashes      = 10                   //How many crashes in a row before the supervisor stops restarting the bot
+	SupervisorCrashHistory    = 20                   //How many crashes to keep in the crash history
+	SupervisorStderrTail      = 16 * 1024            //How many bytes from the end of the bot's stderr to keep for each crash
+	SupervisorAlertStderrTail = 1500                 //How many bytes from the end of the bot's stderr to send the bot owner with an alert
+)
+
+const (
+	SupervisorStateStarting  = "starting"   //The bot is being started
+	SupervisorStateRunning   = "running"    //The bot is running
+	SupervisorStateBackoff   = "backoff"    //The bot crashed and will be restarted at NextRestart
+	SupervisorStateCrashLoop = "crash-loop" //The bot is crash looping and will be restarted at NextRestart
+	SupervisorStateGaveUp    = "gave-up"    //The bot crashed too many times in a row and won't be restarted until a SIGHUP
+	SupervisorStateStopped   = "stopped"    //The supervisor was stopped
+)
+
+// SupervisorStatus stores the state of the supervisor and the bot process it supervises
+type SupervisorStatus struct {
+	State              string       `json:"state"`
+	MasterPID          int          `json:"masterPID"`
+	BotPID             int          `json:"botPID,omitempty"`
+	BotStarted         time.Time    `json:"botStarted,omitempty"`
+	NextRestart        time.Time    `json:"nextRestart,omitempty"`
+	Restarts           int          `json:"restarts"`           //How many times the bot was started since the supervisor started
+	ConsecutiveCrashes int          `json:"consecutiveCrashes"` //How many times the bot crashed in a row without running for SupervisorStableUptime
+	Crashes            []CrashEntry `json:"crashes"`            //The crash history, oldest first
+	Updated            time.Time    `json:"updated"`
+}
+
+// CrashEntry stores why the bot process exited when it crashed
+type CrashEntry struct {
+	Time     time.Time     `json:"time"`
+	PID      int           `json:"pid,omitempty"`
+	ExitCode int           `json:"exitCode"` //-1 if the bot was killed by a signal or couldn't be started
+	Reason   string        `json:"reason"`
+	Uptime   time.Duration `json:"uptime"`
+	Stderr   string        `json:"stderr,omitempty"` //The end of what the bot wrote to stderr
+}
+
+// Supervisor restarts the bot process whenever it exits, backing off while it keeps crashing
+type Supervisor struct {
+	status     SupervisorStatus
+	statusFile string
+	alerted    bool //Whether or not the bot owner was alerted of the current crash loop
+}
+
+// NewSupervisor returns a supervisor that keeps its status in statusFile, along with the crash history kept there
+func NewSupervisor(statusFile string) *Supervisor {
+	supervisor := &Supervisor{statusFile: statusFile}
+	if data, err := ioutil.ReadFile(statusFile); err == nil {
+		var lastStatus SupervisorStatus
+		if err := json.Unmarshal(data, &lastStatus); err == nil {
+			supervisor.status.Crashes = lastStatus.Crashes
+		}
+	}
+	supervisor.status.MasterPID = os.Getpid()
+	return supervisor
+}
+
+// Run starts the bot and restarts it whenever it exits until the supervisor receives a SIGINT, returning the exit code
+func (supervisor *Supervisor) Run() int {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGHUP)
+
+	for {
+		supervisor.setState(SupervisorStateStarting)
+		crash, stopped := supervisor.runBot(signals)
+		if stopped {
+			supervisor.setState(SupervisorStateStopped)
+			return 0
+		}
+		if crash == nil {
+			continue
+		}
+
+		delay := supervisor.crashed(*crash)
+		if delay < 0 {
+			Error.Printf("The bot crashed %d times in a row, send a SIGHUP to PID %d to start it again\n", supervisor.status.ConsecutiveCrashes, os.Getpid())
+		} else {
+			Warning.Printf("The bot crashed (%s), restarting it in %s\n", crash.Reason, delay)
+		}
+
+		if !supervisor.waitRestart(signals, delay) {
+			supervisor.setState(SupervisorStateStopped)
+			return 0
+		}
+	}
+}
+
+// runBot starts the bot and waits for it to exit, returning the crash if it crashed or whether the supervisor was stopped
+func (supervisor *Supervisor) runBot(signals chan os.Signal) (*CrashEntry, bool) {
+	stderr := &tailBuffer{size: SupervisorStderrTail}
+	botProcess, err := spawnBot(stderr)
+	if err != nil {
+		Error.Printf("Error starting the bot: %v\n", err)
+		return &CrashEntry{Time: time.Now(), ExitCode: -1, Reason: "unable to start: " + err.Error()}, false
+	}
+
+	started := time.Now()
+	supervisor.status.Restarts++
+	supervisor.status.BotPID = botProcess.Process.Pid
+	supervisor.status.BotStarted = started
+	supervisor.setState(SupervisorStateRunning)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- botProcess.Wait()
+	}()
+
+	for {
+		select {
+		case sig := <-signals:
+			if sig != syscall.SIGINT {
+				continue //The bot is already running
+			}
+			botProcess.Process.Signal(syscall.SIGINT)
+			<-exited
+			return nil, true
+		case err := <-exited:
+			supervisor.status.BotPID = 0
+			if err == nil {
+				Info.Println("The bot exited on purpose, starting it again...")
+				supervisor.status.ConsecutiveCrashes = 0
+				if time.Since(started) < SupervisorMinBackoff {
+					time.Sleep(SupervisorMinBackoff) //Don't spin if the bot keeps exiting right away
+				}
+				return nil, false
+			}
+
+			crash := &CrashEntry{
+				Time:     time.Now(),
+				PID:      botProcess.Process.Pid,
+				ExitCode: -1,
+				Reason:   err.Error(),
+				Uptime:   time.Since(started),
+				Stderr:   redactSecrets(stderr.String()),
+			}
+			if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
+				crash.ExitCode = exitErr.ExitCode()
+			}
+			return crash, false
+		}
+	}
+}
+
+// crashed adds a crash to the crash history and returns how long to wait before restarting the bot, or -1 if it
+// shouldn't be restarted
+func (supervisor *Supervisor) crashed(crash CrashEntry) time.Duration {
+	supervisor.status.Crashes = append(supervisor.status.Crashes, crash)
+	if len(supervisor.status.Crashes) > SupervisorCrashHistory {
+		supervisor.status.Crashes = supervisor.status.Crashes[len(supervisor.status.Crashes)-SupervisorCrashHistory:]
+	}
+
+	if crash.Uptime >= SupervisorStableUptime {
+		supervisor.status.ConsecutiveCrashes = 0
+		supervisor.alerted = false
+	}
+	supervisor.status.ConsecutiveCrashes++
+
+	if supervisor.status.ConsecutiveCrashes >= SupervisorMaxCrashes {
+		supervisor.status.NextRestart = time.Time{}
+		supervisor.setState(SupervisorStateGaveUp)
+		supervisor.alertOwner("Clinet crashed " + strconv.Itoa(supervisor.status.ConsecutiveCrashes) + " times in a row and won't be restarted until its MASTER process is sent a SIGHUP.")
+		return -1
+	}
+
+	delay := SupervisorMinBackoff
+	for i := 1; i < supervisor.status.ConsecutiveCrashes && delay < SupervisorMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > SupervisorMaxBackoff {
+		delay = SupervisorMaxBackoff
+	}
+	supervisor.status.NextRestart = time.Now().Add(delay)
+
+	if supervisor.recentCrashes() >= SupervisorCrashLoopCount {
+		supervisor.setState(SupervisorStateCrashLoop)
+		if !supervisor.alerted {
+			supervisor.alerted = true
+			supervisor.alertOwner("Clinet is crash looping, it crashed " + strconv.Itoa(supervisor.recentCrashes()) + " times in the last " + strconv.Itoa(int(SupervisorCrashLoopWindow.Minutes())) + " minutes and will be restarted in " + delay.String() + ".")
+		}
+	} else {
+		supervisor.setState(SupervisorStateBackoff)
+	}
+	return delay
+}
+
+// recentCrashes returns how many crashes happened within SupervisorCrashLoopWindow
+func (supervisor *Supervisor) recentCrashes() int {
+	recent := 0
+	for _, crash := range supervisor.status.Crashes {
+		if time.Since(crash.Time) <= SupervisorCrashLoopWindow {
+			recent++
+		}
+	}
+	return recent
+}
+
+// waitRestart waits before restarting the bot, forever if delay is negative, returning false if the supervisor was stopped
+//
+// A SIGHUP restarts the bot right away.
+func (supervisor *Supervisor) waitRestart(signals chan os.Signal, delay time.Duration) bool {
+	var restart <-chan time.Time
+	if delay >= 0 {
+		restart = time.After(delay)
+	}
+
+	select {
+	case sig := <-signals:
+		if sig == syscall.SIGINT {
+			return false
+		}
+		Info.Println("Received a SIGHUP, starting the bot again...")
+		supervisor.status.ConsecutiveCrashes = 0
+		supervisor.alerted = false
+	case <-restart:
+	}
+	return true
+}
+
+// alertOwner sends a direct message to the bot owner, along with the last crash, if the configuration could be loaded
+func (supervisor *Supervisor) alertOwner(message string) {
+	Error.Println(message)
+	if getBotData().BotToken == "" || getBotData().BotOwnerID == "" {
+		return
+	}
+
+	if len(supervisor.status.Crashes) > 0 {
+		crash := supervisor.status.Crashes[len(supervisor.status.Crashes)-1]
+		message += "\n\nLast crash: " + crash.Reason + " after running for " + crash.Uptime.String()
+		if crash.Stderr != "" {
+			stderr := crash.Stderr
+			if len(stderr) > SupervisorAlertStderrTail {
+				stderr = stderr[len(stderr)-SupervisorAlertStderrTail:]
+			}
+			message += "\n```" + strings.Replace(stderr, "```", "'''", -1) + "```"
+		}
+	}
+
+	//Only the REST API is used, so there's no need to connect to the gateway
+	session, err := discordgo.New("Bot " + getBotData().BotToken)
+	if err != nil {
+		Error.Printf("Error alerting the bot owner: %v\n", err)
+		return
+	}
+	ownerChannel, err := session.UserChannelCreate(getBotData().BotOwnerID)
+	if err != nil {
+		Error.Printf("Error alerting the bot owner: %v\n", err)
+		return
+	}
+	if _, err := session.ChannelMessageSend(ownerChannel.ID, message); err != nil {
+		Error.Printf("Error alerting the bot owner: %v\n", err)
+	}
+}
+
+// setState changes the state of the supervisor and writes its status to the status file
+func (supervisor *Supervisor) setState(state string) {
+	supervisor.status.State = state
+	supervisor.status.Updated = time.Now()
+
+	data, err := json.MarshalIndent(supervisor.status, "", "\t")
+	if err != nil {
+		Error.Printf("Error encoding the supervisor status: %v\n", err)
+		return
+	}
+	if err := writeFileAtomic(supervisor.statusFile, data, 0644); err != nil {
+		Error.Printf("Error writing the supervisor status: %v\n", err)
+	}
+}
+
+// runStatusCLI prints the status of the supervisor and its crash history, returning the exit code
+func runStatusCLI(statusFile string) int {
+	data, err := ioutil.ReadFile(statusFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read %s, has Clinet been started? %v\n", statusFile, err)
+		return 1
+	}
+	var status SupervisorStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read %s: %v\n", statusFile, err)
+		return 1
+	}
+
+	fmt.Printf("State:\t\t%s (as of %s)\n", status.State, humanize.Time(status.Updated))
+	fmt.Printf("Master PID:\t%d\n", status.MasterPID)
+	if status.BotPID != 0 {
+		fmt.Printf("Bot PID:\t%d, started %s\n", status.BotPID, humanize.Time(status.BotStarted))
+	}
+	if !status.NextRestart.IsZero() && (status.State == SupervisorStateBackoff || status.State == SupervisorStateCrashLoop) {
+		fmt.Printf("Next restart:\t%s\n", humanize.Time(status.NextRestart))
+	}
+	fmt.Printf("Restarts:\t%d\n", status.Restarts)
+	fmt.Printf("Crashes in a row:\t%d\n", status.ConsecutiveCrashes)
+
+	if len(status.Crashes) > 0 {
+		fmt.Println("\nCrash history, newest first:")
+		for i := len(status.Crashes) - 1; i >= 0; i-- {
+			crash := status.Crashes[i]
+			fmt.Printf("  %s\t%s, exit code %d, after running for %s\n", crash.Time.Format(time.RFC1123), crash.Reason, crash.ExitCode, crash.Uptime)
+		}
+	}
+	return 0
+}
+
+// tailBuffer keeps the last bytes written to it, up to its size
+type tailBuffer struct {
+	sync.Mutex
+	size int
+	data []byte
+}
+
+func (tail *tailBuffer) Write(p []byte) (int, error) {
+	tail.Lock()
+	defer tail.Unlock()
+
+	tail.data = append(tail.data, p...)
+	if len(tail.data) > tail.size {
+		tail.data = append([]byte{}, tail.data[len(tail.data)-tail.size:]...)
+	}
+	return len(p), nil
+}
+
+func (tail *tailBuffer) String() string {
+	tail.Lock()
+	defer tail.Unlock()
+	return string(tail.data)
+}
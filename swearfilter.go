@@ -22,7 +22,7 @@ type SwearFilter struct {
 	DisableSpacedBypass             bool          //Disables testing for spaced bypasses (if hell is in filter, look for occurrences of h and detect only alphabetic characters that follow; ex: h[space]e[space]l[space]l[space] -> hell)
 	WarningDeleteTimeout            time.Duration //How many seconds to wait before deleting the warning message (0 = no timeout)
 	AllowAdminBypass                bool          //Allows members with the administrative permission to bypass the filter
-	AllowBotOwnerBypass             bool          //Allows the user set in botData.BotOwnerID to bypass the filter
+	AllowBotOwnerBypass             bool          //Allows the user set in getBotData().BotOwnerID to bypass the filter
 
 	BlacklistedWords []string //A list of words to blacklist
 }
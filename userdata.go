@@ -0,0 +1,448 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JoshuaDoes/go-wolfram"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// UserDataConfirmTimeout contains how long a user has to confirm deleting their data
+	UserDataConfirmTimeout = 5 * time.Minute
+
+	// Actions recorded in the audit trail
+	AuditActionExport = "export"
+	AuditActionDelete = "delete"
+)
+
+var (
+	//Pending data deletions waiting on confirmation, where key = user ID
+	userDataDeletions     = make(map[string]*UserDataDeletion)
+	userDataDeletionsLock sync.Mutex
+)
+
+// UserDataDeletion holds a data deletion a user has requested but not yet confirmed
+type UserDataDeletion struct {
+	Code    string    //The code the user must send back to confirm
+	Expires time.Time //When the request expires and must be made again
+}
+
+// UserDataExport holds everything stored about a user
+type UserDataExport struct {
+	UserID               string                    `json:"userID"`
+	Exported             time.Time                 `json:"exported"`
+	Settings             *UserSettings             `json:"settings,omitempty"`
+	Reminders            []RemindEntry             `json:"reminders,omitempty"`
+	StarboardEntries     []*UserDataStarboardEntry `json:"starboardEntries,omitempty"`     //Messages by the user that were posted to a starboard
+	WolframConversations []*UserDataConversation   `json:"wolframConversations,omitempty"` //The last Wolfram|Alpha conversation in each server or DM
+	SearchSessions       []*UserDataSearch         `json:"searchSessions,omitempty"`       //Ongoing YouTube and Spotify searches
+	Queries              []*UserDataQuery          `json:"queries,omitempty"`              //Messages the bot responded to, kept so edits and deletions update the responses
+	Schedules            []*UserDataSchedule       `json:"schedules,omitempty"`            //Commands scheduled to run as the user
+	ServerReferences     []string                  `json:"serverReferences,omitempty"`     //Server settings set by admins that name the user
+}
+
+// UserDataStarboardEntry holds a starboard entry and the server it's in
+type UserDataStarboardEntry struct {
+	GuildID string         `json:"guildID"`
+	Entry   StarboardEntry `json:"entry"`
+}
+
+// UserDataConversation holds a Wolfram|Alpha conversation and where it took place
+type UserDataConversation struct {
+	DataID       string                `json:"dataID"` //The server ID, or the channel ID in direct messages
+	Conversation *wolfram.Conversation `json:"conversation"`
+}
+
+// UserDataSearch holds a search session and where it took place
+type UserDataSearch struct {
+	DataID  string `json:"dataID"`
+	Service string `json:"service"`
+	Query   string `json:"query"`
+}
+
+// UserDataQuery holds a query record and where it took place
+type UserDataQuery struct {
+	DataID    string `json:"dataID"`
+	MessageID string `json:"messageID"`
+	*Query
+}
+
+// UserDataSchedule holds a scheduled command and the server it's in
+type UserDataSchedule struct {
+	GuildID  string            `json:"guildID"`
+	Schedule *ScheduledCommand `json:"schedule"`
+}
+
+// AuditEntry holds a request a user made about their data, kept so the bot owner can show the requests were handled
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	UserID  string    `json:"userID"`
+	Action  string    `json:"action"`  //AuditActionExport or AuditActionDelete
+	Details []string  `json:"details"` //What was exported or deleted, without the data itself
+}
+
+// addAuditEntry records a request in the audit trail
+func addAuditEntry(userID, action string, details []string) {
+	entry := &AuditEntry{Time: time.Now().UTC(), UserID: userID, Action: action, Details: details}
+	Info.Printf("User data %s requested by %s: %s\n", action, userID, strings.Join(details, ", "))
+
+	if stateStore == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		Error.Printf("Error encoding audit entry: %s\n", err)
+		return
+	}
+	key := entry.Time.Format("20060102T150405.000000000") + "-" + userID + "-" + action //Sorts by time
+	if err := stateStore.Save(StateBucketAudit, map[string][]byte{key: data}); err != nil {
+		Error.Printf("Error saving audit entry: %s\n", err)
+	}
+}
+
+// getAuditEntries returns every entry in the audit trail, newest first
+func getAuditEntries() ([]*AuditEntry, error) {
+	entries := make([]*AuditEntry, 0)
+	if stateStore == nil {
+		return entries, nil
+	}
+	err := stateStore.Load(StateBucketAudit, func(key string, data []byte) error {
+		entry := &AuditEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Time.After(entries[j].Time)
+	})
+	return entries, err
+}
+
+// getStarboardEntryAuthor returns the author of a starboard entry's source message, looking it up and keeping it for entries made before authors were kept
+func getStarboardEntryAuthor(guildID string, entry StarboardEntry) string {
+	if entry.AuthorID != "" {
+		return entry.AuthorID
+	}
+	message, err := getBotData().DiscordSession.ChannelMessage(entry.SourceChannelID, entry.SourceMessageID)
+	if err != nil || message.Author == nil {
+		return ""
+	}
+	starboards.Update(guildID, func(starboard *Starboard) {
+		for i := range starboard.StarboardEntries {
+			if starboard.StarboardEntries[i].SourceMessageID == entry.SourceMessageID {
+				starboard.StarboardEntries[i].AuthorID = message.Author.ID
+			}
+		}
+	})
+	return message.Author.ID
+}
+
+// isUserDataCommand returns whether or not the arguments of the user command export or delete the user's data, which
+// runs without the guild locked as every guild's data is locked in turn instead
+func isUserDataCommand(args []string) bool {
+	return len(args) > 0 && args[0] == "data"
+}
+
+// exportUserData returns everything stored about a user
+//
+// Each guild's data is locked while it's read, so the caller must not hold the lock of any guild's data.
+func exportUserData(userID string) *UserDataExport {
+	export := &UserDataExport{UserID: userID, Exported: time.Now()}
+	if settings, exists := userSettings.Snapshot(userID); exists {
+		export.Settings = settings
+	}
+	for _, entry := range remindEntries.All() {
+		if entry.UserID == userID {
+			export.Reminders = append(export.Reminders, entry)
+		}
+	}
+
+	for dataID, data := range guildData.All() {
+		data.Lock()
+		for messageID, query := range data.Queries {
+			if query.AuthorID == userID {
+				export.Queries = append(export.Queries, &UserDataQuery{DataID: dataID, MessageID: messageID, Query: query})
+			}
+		}
+		data.Unlock()
+
+		if starboard, exists := starboards.Snapshot(dataID); exists {
+			for _, entry := range starboard.StarboardEntries {
+				if entry.AuthorID = getStarboardEntryAuthor(dataID, entry); entry.AuthorID == userID {
+					export.StarboardEntries = append(export.StarboardEntries, &UserDataStarboardEntry{GuildID: dataID, Entry: entry})
+				}
+			}
+		}
+
+		if conversation := data.GetWolframConversation(userID); conversation != nil {
+			export.WolframConversations = append(export.WolframConversations, &UserDataConversation{DataID: dataID, Conversation: conversation})
+		}
+		if result := data.GetYouTubeResult(userID); result != nil {
+			export.SearchSessions = append(export.SearchSessions, &UserDataSearch{DataID: dataID, Service: "YouTube", Query: result.Query})
+		}
+		if result := data.GetSpotifyResult(userID); result != nil {
+			export.SearchSessions = append(export.SearchSessions, &UserDataSearch{DataID: dataID, Service: "Spotify", Query: result.Query})
+		}
+	}
+
+	for guildID := range guildSettings.All() {
+		settings, exists := guildSettings.Snapshot(guildID)
+		if !exists {
+			continue
+		}
+		for _, schedule := range settings.Schedules {
+			if schedule.ExecutorID == userID {
+				export.Schedules = append(export.Schedules, &UserDataSchedule{GuildID: guildID, Schedule: schedule})
+			}
+		}
+		export.ServerReferences = append(export.ServerReferences, getUserServerReferences(guildID, settings, userID)...)
+	}
+	return export
+}
+
+// getUserServerReferences returns a description of each setting in a server that names a user
+func getUserServerReferences(guildID string, settings *GuildSettings, userID string) []string {
+	references := make([]string, 0)
+	if containsString(settings.BotAdminUsers, userID) {
+		references = append(references, "Bot admin in server "+guildID)
+	}
+	commandNames := make([]string, 0)
+	for commandName := range settings.CommandPermissions {
+		commandNames = append(commandNames, commandName)
+	}
+	sort.Strings(commandNames)
+	for _, commandName := range commandNames {
+		permissions := settings.CommandPermissions[commandName]
+		if containsString(permissions.AllowedUsers, userID) {
+			references = append(references, "Allowed to use "+commandName+" in server "+guildID)
+		}
+		if containsString(permissions.DeniedUsers, userID) {
+			references = append(references, "Denied from using "+commandName+" in server "+guildID)
+		}
+	}
+	if starboard, exists := starboards.Snapshot(guildID); exists && containsString(starboard.BlacklistUsers, userID) {
+		references = append(references, "Excluded from the starboard in server "+guildID)
+	}
+	return references
+}
+
+// Summary returns a count of each kind of data in an export, for the audit trail
+func (export *UserDataExport) Summary() []string {
+	summary := make([]string, 0)
+	add := func(count int, kind string) {
+		if count > 0 {
+			summary = append(summary, strconv.Itoa(count)+" "+kind)
+		}
+	}
+	if export.Settings != nil {
+		summary = append(summary, "settings")
+	}
+	add(len(export.Reminders), "reminder(s)")
+	add(len(export.StarboardEntries), "starboard entry(s)")
+	add(len(export.WolframConversations), "Wolfram|Alpha conversation(s)")
+	add(len(export.SearchSessions), "search session(s)")
+	add(len(export.Queries), "query record(s)")
+	add(len(export.Schedules), "scheduled command(s)")
+	add(len(export.ServerReferences), "server reference(s)")
+	if len(summary) == 0 {
+		summary = append(summary, "nothing stored")
+	}
+	return summary
+}
+
+// deleteUserData removes everything stored about a user, returning a count of each kind of data that was removed
+//
+// Server settings that deny the user something, such as command denials and starboard exclusions, are kept so a
+// deletion can't be used to get around them. Each guild is saved as soon as the user's data is removed from it, and as
+// with exportUserData, the caller must not hold the lock of any guild's data.
+func deleteUserData(userID string) []string {
+	export := exportUserData(userID) //Counts what's about to be removed
+	deleted := export.Summary()
+
+	userSettings.Delete(userID)
+	if stateStore != nil {
+		if err := stateDeleteMissing(StateBucketUserSettings, func(key string) bool { _, exists := userSettings.Load(key); return exists }); err != nil {
+			Error.Printf("Error deleting userSettings state for %s: %s\n", userID, err)
+		}
+	}
+
+	remindEntries.Remove(func(entry RemindEntry) bool {
+		return entry.UserID == userID
+	})
+	stateSaveReminds()
+
+	for dataID, data := range guildData.All() {
+		data.Lock()
+		for messageID, query := range data.Queries {
+			if query.AuthorID == userID {
+				delete(data.Queries, messageID)
+			}
+		}
+
+		removedEntries := make([]StarboardEntry, 0)
+		starboards.Update(dataID, func(starboard *Starboard) {
+			keptEntries := make([]StarboardEntry, 0)
+			for _, entry := range starboard.StarboardEntries {
+				if entry.AuthorID == userID {
+					removedEntries = append(removedEntries, entry)
+					continue
+				}
+				keptEntries = append(keptEntries, entry)
+			}
+			starboard.StarboardEntries = keptEntries
+		})
+
+		data.SetYouTubeResult(userID, nil)
+		data.SetSpotifyResult(userID, nil)
+		data.conversationsLock.Lock()
+		delete(data.WolframConversations, userID)
+		data.conversationsLock.Unlock()
+
+		stateSaveGuild(dataID, "")
+		data.Unlock()
+
+		for _, entry := range removedEntries {
+			getBotData().DiscordSession.ChannelMessageDelete(entry.StarboardChannelID, entry.StarboardMessageID)
+		}
+	}
+
+	removedSchedules := false
+	for guildID := range guildSettings.All() {
+		guildSettings.Update(guildID, func(settings *GuildSettings) {
+			keptSchedules := make([]*ScheduledCommand, 0)
+			for _, schedule := range settings.Schedules {
+				if schedule.ExecutorID == userID {
+					removedSchedules = true
+					continue
+				}
+				keptSchedules = append(keptSchedules, schedule)
+			}
+			settings.Schedules = keptSchedules
+
+			settings.BotAdminUsers = remove(settings.BotAdminUsers, userID)
+			for _, permissions := range settings.CommandPermissions {
+				permissions.AllowedUsers = remove(permissions.AllowedUsers, userID)
+			}
+		})
+
+		if data, exists := guildData.Load(guildID); exists {
+			data.Lock()
+			stateSaveGuild(guildID, "")
+			data.Unlock()
+		} else {
+			stateSaveGuild(guildID, "")
+		}
+	}
+	if removedSchedules {
+		scheduler.Rebuild()
+	}
+	return deleted
+}
+
+// newUserDataDeletion starts a data deletion for a user, returning the code they must send back to confirm it
+func newUserDataDeletion(userID string) string {
+	codeBytes := make([]byte, 3)
+	rand.Read(codeBytes)
+	code := strings.ToUpper(hex.EncodeToString(codeBytes))
+
+	userDataDeletionsLock.Lock()
+	defer userDataDeletionsLock.Unlock()
+	userDataDeletions[userID] = &UserDataDeletion{Code: code, Expires: time.Now().Add(UserDataConfirmTimeout)}
+	return code
+}
+
+// confirmUserDataDeletion returns whether or not the code confirms the user's pending data deletion, which is used up either way
+func confirmUserDataDeletion(userID, code string) bool {
+	userDataDeletionsLock.Lock()
+	defer userDataDeletionsLock.Unlock()
+	deletion, exists := userDataDeletions[userID]
+	if !exists {
+		return false
+	}
+	delete(userDataDeletions, userID)
+	return time.Now().Before(deletion.Expires) && strings.EqualFold(deletion.Code, code)
+}
+
+// commandSettingsUserResponse handles the user settings that respond with more than an embed, such as exporting data
+func commandSettingsUserResponse(args []string, env *CommandEnvironment) *CommandResponse {
+	if args[0] != "data" {
+		return NewEmbedResponse(commandSettingsUser(args, env))
+	}
+	if len(args) < 2 {
+		return NewEmbedResponse(getCommandUsage(env.Command, "User Settings - Command Usage", env))
+	}
+
+	switch args[1] {
+	case "export":
+		export := exportUserData(env.User.ID)
+		exportJSON, err := json.MarshalIndent(export, "", "\t")
+		if err != nil {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "User Settings - Data Export Error", "Unable to encode your data."))
+		}
+		addAuditEntry(env.User.ID, AuditActionExport, export.Summary())
+		return &CommandResponse{
+			DirectMessage: true,
+			Embeds:        []*discordgo.MessageEmbed{NewGenericEmbed(env.Locale(), "User Settings - Data Export", "Here's everything "+getBotData().BotName+" stores about you.")},
+			Files:         []*discordgo.File{{Name: "clinet-" + env.User.ID + ".json", ContentType: "application/json", Reader: bytes.NewReader(exportJSON)}},
+		}
+	case "delete":
+		if len(args) < 4 || args[2] != "confirm" {
+			code := newUserDataDeletion(env.User.ID)
+			return NewEmbedResponse(NewEmbed().
+				SetTitle("User Settings - Data Deletion").
+				SetDescription("This will permanently delete everything " + getBotData().BotName + " stores about you, including your balance, reminders, starboard entries and commands scheduled to run as you. " +
+					"Server settings that deny you something are kept.\n\nTo confirm, run ``" + env.BotPrefix + env.Command + " data delete confirm " + code + "`` within " + strconv.Itoa(int(UserDataConfirmTimeout.Minutes())) + " minutes.").
+				SetColor(0x1C1C1C).MessageEmbed)
+		}
+		if !confirmUserDataDeletion(env.User.ID, args[3]) {
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "User Settings - Data Deletion Error", "That confirmation code is invalid or has expired, run ``"+env.BotPrefix+env.Command+" data delete`` to get a new one."))
+		}
+		deleted := deleteUserData(env.User.ID)
+		addAuditEntry(env.User.ID, AuditActionDelete, deleted)
+		return NewEmbedResponse(NewGenericEmbed(env.Locale(), "User Settings - Data Deletion", "Successfully deleted your data. Snapshots of older state are removed as they expire."))
+	}
+	return NewEmbedResponse(getCommandUsage(env.Command, "User Settings - Command Usage", env))
+}
+
+func commandAudit(args []string, env *CommandEnvironment) *discordgo.MessageEmbed {
+	pageNumber := 1
+	if env.Arguments.Has("page") {
+		pageNumber = env.Arguments.Int("page")
+	}
+
+	entries, err := getAuditEntries()
+	if err != nil {
+		return NewErrorEmbed(env.Locale(), "Audit Error", "Unable to read the audit trail: "+err.Error())
+	}
+	if len(entries) == 0 {
+		return NewErrorEmbed(env.Locale(), "Audit Error", "No user data requests have been made.")
+	}
+
+	auditList := make([]*discordgo.MessageEmbedField, 0)
+	for _, entry := range entries {
+		auditList = append(auditList, &discordgo.MessageEmbedField{
+			Name:  strings.Title(entry.Action) + " by " + entry.UserID,
+			Value: entry.Time.Format(time.RFC1123) + "\n" + strings.Join(entry.Details, ", "),
+		})
+	}
+	auditEmbed, totalPages, err := page(auditList, pageNumber, getBotData().BotOptions.HelpMaxResults)
+	if err != nil {
+		return NewErrorEmbed(env.Locale(), "Audit Error", err.Error())
+	}
+	return auditEmbed.
+		SetTitle("Audit").
+		SetDescription("User data export and deletion requests, newest first.").
+		SetFooter("Page " + strconv.Itoa(pageNumber) + " of " + strconv.Itoa(totalPages) + " | " + env.BotPrefix + env.Command + " {page}").
+		SetColor(0x1C1C1C).MessageEmbed
+}
@@ -68,7 +68,7 @@ func (voice *Voice) Connect(guildID, vChannelID string) error {
 	}
 
 	//Join the voice channel
-	voiceConnection, err := botData.DiscordSession.ChannelVoiceJoin(guildID, vChannelID, voice.Muted, voice.Deafened)
+	voiceConnection, err := getBotData().DiscordSession.ChannelVoiceJoin(guildID, vChannelID, voice.Muted, voice.Deafened)
 	if err != nil {
 		//There was an error joining the voice channel
 		return errVoiceJoinChannel
@@ -126,7 +126,7 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 		//If we are streaming, add to the queue instead
 		voice.QueueAdd(queueEntry)
 		if announceQueueAdded {
-			botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetAddedEmbed(queueEntry))
+			getBotData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetAddedEmbed(queueEntry))
 		}
 		return nil
 	}
@@ -142,7 +142,7 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 	voice.NowPlaying = &VoiceNowPlaying{Entry: queueEntry}
 
 	//Tell the world we're now playing this entry
-	botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetNowPlayingEmbed(queueEntry))
+	getBotData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, voice.GetNowPlayingEmbed(queueEntry))
 
 	//Create a channel to signal when the voice stream is finished or stopped
 	voice.done = make(chan error)
@@ -176,7 +176,7 @@ func (voice *Voice) Play(queueEntry *QueueEntry, announceQueueAdded bool) error
 		voice.NowPlaying = nil
 		if len(voice.Entries) <= 0 {
 			voice.Disconnect()
-			botData.DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, NewGenericEmbed("Voice", "Finished playing the queue."))
+			getBotData().DiscordSession.ChannelMessageSendEmbed(voice.TextChannelID, NewGenericEmbed(getChannelLocale(voice.TextChannelID), "Voice", "Finished playing the queue."))
 			return nil
 		}
 		nextQueueEntry = voice.QueueGet(0)
@@ -428,11 +428,7 @@ func (voice *Voice) AssistantStop() {
 
 // VoiceInit initializes a voice object for the given guild
 func VoiceInit(guildID string) {
-	if voiceData[guildID] != nil {
-		return
-	}
-
-	voiceData[guildID] = &Voice{
-		EncodingOptions: botData.BotOptions.AudioEncoding,
-	}
+	voiceData.LoadOrStore(guildID, &Voice{
+		EncodingOptions: getBotData().BotOptions.AudioEncoding,
+	})
 }
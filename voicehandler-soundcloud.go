@@ -30,7 +30,7 @@ func (*SoundCloud) TestURL(url string) (bool, error) {
 
 // GetMetadata returns the metadata for a given SoundCloud track URL
 func (*SoundCloud) GetMetadata(url string) (*Metadata, error) {
-	trackInfo, err := botData.BotClients.SoundCloud.GetTrackInfo(url)
+	trackInfo, err := getBotData().BotClients.SoundCloud.GetTrackInfo(url)
 	if err != nil {
 		return nil, err
 	}
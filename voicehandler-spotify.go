@@ -36,7 +36,7 @@ func (*Spotify) GetMetadata(url string) (*Metadata, error) {
 		url = newURL
 	}
 
-	trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(url)
+	trackInfo, err := getBotData().BotClients.Spotify.GetTrackInfo(url)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +99,7 @@ func (page *SpotifyResultNav) Search(query string) error {
 	}
 
 	if page.MaxResults == 0 {
-		page.MaxResults = botData.BotOptions.SpotifyMaxResults
+		page.MaxResults = getBotData().BotOptions.SpotifyMaxResults
 	}
 
 	page.Query = ""
@@ -112,7 +112,7 @@ func (page *SpotifyResultNav) Search(query string) error {
 	page.PlaylistUserID = ""
 	page.TotalPages = 0
 
-	searchResults, err := botData.BotClients.Spotify.Search(query)
+	searchResults, err := getBotData().BotClients.Spotify.Search(query)
 	if err != nil {
 		return err
 	}
@@ -150,7 +150,7 @@ func (page *SpotifyResultNav) Playlist(url string) error {
 	}
 
 	if page.MaxResults == 0 {
-		page.MaxResults = botData.BotOptions.SpotifyMaxResults
+		page.MaxResults = getBotData().BotOptions.SpotifyMaxResults
 	}
 
 	page.Query = ""
@@ -163,7 +163,7 @@ func (page *SpotifyResultNav) Playlist(url string) error {
 	page.PlaylistUserID = ""
 	page.TotalPages = 0
 
-	playlist, err := botData.BotClients.Spotify.GetPlaylist(url)
+	playlist, err := getBotData().BotClients.Spotify.GetPlaylist(url)
 	if err != nil {
 		return err
 	}
@@ -173,10 +173,6 @@ func (page *SpotifyResultNav) Playlist(url string) error {
 
 	playlistItems := make([]spotigo.SpotigoSearchHit, 0)
 	for i := 0; i < len(playlist.Contents.Items); i++ {
-		//Give a chance for other commands waiting in line to execute
-		guildData[page.GuildID].Unlock()
-		guildData[page.GuildID].Lock()
-
 		if page.Cancelled {
 			page.Cancelled = false
 			break
@@ -186,7 +182,7 @@ func (page *SpotifyResultNav) Playlist(url string) error {
 		hit := spotigo.SpotigoSearchHit{}
 
 		if i < page.MaxResults {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(item.TrackURI)
+			trackInfo, err := getBotData().BotClients.Spotify.GetTrackInfo(item.TrackURI)
 			if err != nil {
 				continue
 			}
@@ -249,7 +245,7 @@ func (page *SpotifyResultNav) Prev() error {
 
 	for i := 0; i < len(page.Results); i++ {
 		if strings.HasPrefix(page.Results[i].URI, "spotify:track:") {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
+			trackInfo, err := getBotData().BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
 			if err != nil {
 				continue
 			}
@@ -289,7 +285,7 @@ func (page *SpotifyResultNav) Next() error {
 
 	for i := 0; i < len(page.Results); i++ {
 		if strings.HasPrefix(page.Results[i].URI, "spotify:track:") {
-			trackInfo, err := botData.BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
+			trackInfo, err := getBotData().BotClients.Spotify.GetTrackInfo(page.Results[i].URI)
 			if err != nil {
 				continue
 			}
@@ -53,7 +53,7 @@ func (*YouTube) GetMetadata(url string) (*Metadata, error) {
 		return nil, err
 	}
 
-	ytCall := youtube.NewVideosService(botData.BotClients.YouTube).
+	ytCall := youtube.NewVideosService(getBotData().BotClients.YouTube).
 		List("snippet,contentDetails").
 		Id(videoInfo.ID)
 
@@ -86,7 +86,7 @@ func (*YouTube) GetMetadata(url string) (*Metadata, error) {
 }
 
 func YouTubeGetQuery(query string) (string, error) {
-	call := botData.BotClients.YouTube.Search.List("id").
+	call := getBotData().BotClients.YouTube.Search.List("id").
 		Q(query).
 		MaxResults(50)
 
@@ -128,7 +128,7 @@ func (page *YouTubeResultNav) Prev() error {
 		return errors.New("No pages exist before current page")
 	}
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := getBotData().BotClients.YouTube.Search.
 		List("id").
 		Q(page.Query).
 		MaxResults(page.MaxResults).
@@ -154,7 +154,7 @@ func (page *YouTubeResultNav) Next() error {
 		return errors.New("No pages exist after current page")
 	}
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := getBotData().BotClients.YouTube.Search.
 		List("id").
 		Q(page.Query).
 		MaxResults(page.MaxResults).
@@ -180,7 +180,7 @@ func (page *YouTubeResultNav) GetResults() ([]*youtube.SearchResult, error) {
 }
 func (page *YouTubeResultNav) Search(query string) error {
 	if page.MaxResults == 0 {
-		page.MaxResults = int64(botData.BotOptions.YouTubeMaxResults)
+		page.MaxResults = int64(getBotData().BotOptions.YouTubeMaxResults)
 	}
 
 	page.Query = ""
@@ -190,7 +190,7 @@ func (page *YouTubeResultNav) Search(query string) error {
 	page.PrevPageToken = ""
 	page.NextPageToken = ""
 
-	searchCall := botData.BotClients.YouTube.Search.
+	searchCall := getBotData().BotClients.YouTube.Search.
 		List("id").
 		Q(query).
 		MaxResults(page.MaxResults).
@@ -21,18 +21,18 @@ type VoiceService interface {
 	//Search(query string) (results *SearchResults, err error)
 }
 
-func initVoiceServices() {
-	botData.VoiceServices = make([]VoiceService, 0)
+func initVoiceServices(config *BotData) {
+	config.VoiceServices = make([]VoiceService, 0)
 
-	botData.VoiceServices = append(botData.VoiceServices, &YouTube{})
-	botData.VoiceServices = append(botData.VoiceServices, &SoundCloud{})
-	botData.VoiceServices = append(botData.VoiceServices, &Spotify{})
-	botData.VoiceServices = append(botData.VoiceServices, &Bandcamp{})
-	botData.VoiceServices = append(botData.VoiceServices, &Direct{})
+	config.VoiceServices = append(config.VoiceServices, &YouTube{})
+	config.VoiceServices = append(config.VoiceServices, &SoundCloud{})
+	config.VoiceServices = append(config.VoiceServices, &Spotify{})
+	config.VoiceServices = append(config.VoiceServices, &Bandcamp{})
+	config.VoiceServices = append(config.VoiceServices, &Direct{})
 }
 
 func createQueueEntry(url string) (*QueueEntry, error) {
-	for _, service := range botData.VoiceServices {
+	for _, service := range getBotData().VoiceServices {
 		test, err := service.TestURL(url)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// DefaultCommandTimeout contains how long a command may run before it's cancelled, unless the command sets its own Timeout
+	DefaultCommandTimeout = 30 * time.Second
+
+	// QueryTimeout contains how long a natural language query may run before it's cancelled
+	QueryTimeout = 30 * time.Second
+
+	// FollowUpTimeout contains how long the follow-up of a response may run before it's cancelled
+	FollowUpTimeout = 5 * time.Minute
+
+	// DefaultGuildWorkers contains how many commands may run at once in a guild if BotOptions.MaxGuildWorkers isn't set
+	DefaultGuildWorkers = 3
+)
+
+var (
+	commandWorkers     = &WorkerPool{slots: make(map[string]chan struct{})}
+	guildSettingsLocks = &WorkerPool{size: 1, slots: make(map[string]chan struct{})}
+)
+
+// WorkerPool bounds how many commands may run at once for each key
+type WorkerPool struct {
+	sync.Mutex
+
+	size  int //How many workers each key has, or 0 to use BotOptions.MaxGuildWorkers
+	slots map[string]chan struct{}
+}
+
+// Acquire waits for a free worker for the key, returning a function to free it again, or an error if the context ends first
+func (pool *WorkerPool) Acquire(ctx context.Context, key string) (release func(), err error) {
+	pool.Lock()
+	slots, exists := pool.slots[key]
+	if !exists {
+		size := pool.size
+		if size <= 0 {
+			size = getBotData().BotOptions.MaxGuildWorkers
+		}
+		if size <= 0 {
+			size = DefaultGuildWorkers
+		}
+		slots = make(chan struct{}, size)
+		pool.slots[key] = slots
+	}
+	pool.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lockGuildSettings waits until no one else is changing the settings of a guild, returning a function to let others change them again,
+// or an error if the context ends first
+//
+// Settings commands run while holding it, so anything else that changes several of a guild's settings at once should hold it too.
+func lockGuildSettings(ctx context.Context, guildID string) (unlock func(), err error) {
+	return guildSettingsLocks.Acquire(ctx, guildID)
+}
+
+// runCommandWorker runs a command on one of the guild's workers with a deadline, returning early if the deadline passes
+//
+// The command is given its own copy of env with the context set, as it may still be running after this returns.
+// The worker isn't freed until the command actually returns, so commands that ignore their context still count against the guild.
+// If exclusive is set, the guild's settings are locked while the command runs, for commands that change them.
+func runCommandWorker(env *CommandEnvironment, timeout time.Duration, exclusive bool, run func(env *CommandEnvironment) *CommandResponse) *CommandResponse {
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dataID := getEnvironmentDataID(env)
+	release, err := commandWorkers.Acquire(ctx, dataID)
+	if err != nil {
+		return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Command Error - Busy (BY)", "Too many commands are running here right now, try again in a moment."))
+	}
+	unlock := func() {}
+	if exclusive {
+		if unlock, err = lockGuildSettings(ctx, dataID); err != nil {
+			release()
+			return NewEmbedResponse(NewErrorEmbed(env.Locale(), "Command Error - Busy (BY)", "The settings here are still being changed, try again in a moment."))
+		}
+	}
+
+	runEnv := *env
+	runEnv.Context = ctx
+	result := make(chan *CommandResponse, 1)
+	go func() {
+		var response *CommandResponse
+		defer release()
+		defer unlock()
+		defer func() { result <- response }()
+		defer recoverPanic()
+
+		response = run(&runEnv)
+	}()
+
+	select {
+	case response := <-result:
+		return response
+	case <-ctx.Done():
+		return NewEmbedResponse(getTimeoutEmbed(env))
+	}
+}
+
+// runCommand runs a command's function on one of the guild's workers, or directly if it's being ran from within another command
+func runCommand(command *Command, args []string, env *CommandEnvironment, run func(env *CommandEnvironment) *CommandResponse) *CommandResponse {
+	if env.Context != nil {
+		return run(env) //Already running on a worker, which may hold the guild settings lock
+	}
+	exclusive := command.Category == CommandCategorySettings && (command.RunsUnlocked == nil || !command.RunsUnlocked(args))
+	return runCommandWorker(env, command.Timeout, exclusive, run)
+}
+
+// runFollowUp runs the follow-up of a response on one of the guild's workers, returning nil if it doesn't finish in time
+func runFollowUp(dataID string, followUp func(ctx context.Context) *CommandResponse) *CommandResponse {
+	ctx, cancel := context.WithTimeout(context.Background(), FollowUpTimeout)
+	defer cancel()
+
+	release, err := commandWorkers.Acquire(ctx, dataID)
+	if err != nil {
+		return nil
+	}
+	defer release()
+	return followUp(ctx)
+}
+
+// getTimeoutEmbed returns the embed to respond with when a command or query runs out of time
+func getTimeoutEmbed(env *CommandEnvironment) *discordgo.MessageEmbed {
+	if env.Command == "" {
+		return NewErrorEmbed(env.Locale(), "Query Error - Timed Out (TO)", "Your query took too long to process, try again later.")
+	}
+	return NewErrorEmbed(env.Locale(), "Command Error - Timed Out (TO)", "``%s`` took too long to finish, try again later.", env.Command)
+}
+
+// httpGetContext is http.Get, cancelled along with the context
+func httpGetContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req.WithContext(ctx))
+}